@@ -0,0 +1,1013 @@
+// Package aihr wires the audio, STT, GPT, TTS and playback components into an
+// interview engine from configuration, with every component overridable
+package aihr
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/d1nch8g/aihr/analysis"
+	"github.com/d1nch8g/aihr/audio"
+	"github.com/d1nch8g/aihr/audit"
+	"github.com/d1nch8g/aihr/cassette"
+	"github.com/d1nch8g/aihr/config"
+	"github.com/d1nch8g/aihr/earcon"
+	"github.com/d1nch8g/aihr/embed"
+	"github.com/d1nch8g/aihr/engine"
+	"github.com/d1nch8g/aihr/eval"
+	"github.com/d1nch8g/aihr/experiment"
+	"github.com/d1nch8g/aihr/gpt"
+	"github.com/d1nch8g/aihr/i18n"
+	"github.com/d1nch8g/aihr/observe"
+	"github.com/d1nch8g/aihr/plugins"
+	"github.com/d1nch8g/aihr/prompts"
+	"github.com/d1nch8g/aihr/questions"
+	"github.com/d1nch8g/aihr/realtime"
+	"github.com/d1nch8g/aihr/safety"
+	"github.com/d1nch8g/aihr/screening"
+	"github.com/d1nch8g/aihr/session"
+	"github.com/d1nch8g/aihr/sound"
+	"github.com/d1nch8g/aihr/stt"
+	"github.com/d1nch8g/aihr/tts"
+	"github.com/d1nch8g/aihr/turnlog"
+)
+
+// Components holds the providers used by an interview
+type Components struct {
+	AudioStreamer audio.AudioStreamer
+	STT           stt.STTClient
+	GPT           gpt.GPTClient
+	TTS           tts.Synthesizer
+	Player        sound.Player
+
+	// FastGPT writes the first sentence of every response, it is optional
+	FastGPT gpt.GPTClient
+
+	// FallbackSTT and FallbackTTS replace the providers while they are
+	// unavailable, they are optional
+	FallbackSTT stt.STTClient
+	FallbackTTS tts.Synthesizer
+
+	// Realtime replaces STT, GPT responses and TTS in the realtime engine mode
+	Realtime realtime.Client
+
+	// Embedder and Store are optional, they are used for duplicate
+	// detection and for keeping finished sessions
+	Embedder embed.Embedder
+	Store    session.Store
+}
+
+// Interview is a fully wired interview engine
+type Interview struct {
+	engine.Interviewer
+	Components Components
+	Config     *config.Config
+
+	// Experiment and Variant are set when the session takes part in an A/B test
+	Experiment string
+	Variant    *experiment.Variant
+
+	// prompts are the system prompts used so far, see session.Record.Prompts
+	prompts      []session.PromptVersion
+	promptsMutex sync.Mutex
+}
+
+// Option configures the interview created by New
+type Option func(*builder)
+
+type builder struct {
+	config        *config.Config
+	components    Components
+	greeting      string
+	engineOptions []engine.Option
+	textMode      engine.Option // Engine option selecting text mode, nil for audio
+	typed         bool          // Text mode answers are typed, responses are still spoken
+}
+
+// WithConfig uses the given configuration instead of loading it from the environment
+func WithConfig(cfg *config.Config) Option {
+	return func(b *builder) {
+		b.config = cfg
+	}
+}
+
+// WithAudioStreamer overrides the microphone capture component
+func WithAudioStreamer(audioStreamer audio.AudioStreamer) Option {
+	return func(b *builder) {
+		b.components.AudioStreamer = audioStreamer
+	}
+}
+
+// WithSTT overrides the speech recognition client
+func WithSTT(sttClient stt.STTClient) Option {
+	return func(b *builder) {
+		b.components.STT = sttClient
+	}
+}
+
+// WithGPT overrides the language model client
+func WithGPT(gptClient gpt.GPTClient) Option {
+	return func(b *builder) {
+		b.components.GPT = gptClient
+	}
+}
+
+// WithFastGPT sets the model writing the first sentence of responses,
+// instead of the FAST_GPT_MODEL one
+func WithFastGPT(fastGPT gpt.GPTClient) Option {
+	return func(b *builder) {
+		b.components.FastGPT = fastGPT
+	}
+}
+
+// WithTTS overrides the speech synthesis client
+func WithTTS(ttsClient tts.Synthesizer) Option {
+	return func(b *builder) {
+		b.components.TTS = ttsClient
+	}
+}
+
+// WithFallbackSTT sets the recognizer used when the STT provider is unavailable,
+// instead of the STT_FALLBACK_COMMAND one
+func WithFallbackSTT(fallback stt.STTClient) Option {
+	return func(b *builder) {
+		b.components.FallbackSTT = fallback
+	}
+}
+
+// WithFallbackTTS sets the synthesizer used when the TTS provider is unavailable,
+// instead of the TTS_FALLBACK_COMMAND one
+func WithFallbackTTS(fallback tts.Synthesizer) Option {
+	return func(b *builder) {
+		b.components.FallbackTTS = fallback
+	}
+}
+
+// WithPlayer overrides the audio playback component
+func WithPlayer(player sound.Player) Option {
+	return func(b *builder) {
+		b.components.Player = player
+	}
+}
+
+// WithRealtime overrides the speech-to-speech client used in the realtime engine mode
+func WithRealtime(client realtime.Client) Option {
+	return func(b *builder) {
+		b.components.Realtime = client
+	}
+}
+
+// WithEmbedder overrides the text embedding client used for duplicate detection
+func WithEmbedder(embedder embed.Embedder) Option {
+	return func(b *builder) {
+		b.components.Embedder = embedder
+	}
+}
+
+// WithStore overrides the storage of finished sessions
+func WithStore(store session.Store) Option {
+	return func(b *builder) {
+		b.components.Store = store
+	}
+}
+
+// WithTextIO runs the interview with typed answers read from input and
+// responses printed to output. Audio, STT and TTS components are not created
+func WithTextIO(input io.Reader, output io.Writer) Option {
+	return func(b *builder) {
+		b.textMode = engine.WithTextIO(input, output)
+	}
+}
+
+// WithTextExchange runs the interview in text mode with a custom exchange,
+// e.g. a simulated candidate
+func WithTextExchange(textIO engine.TextIO) Option {
+	return func(b *builder) {
+		b.textMode = engine.WithTextExchange(textIO)
+	}
+}
+
+// WithTypedIO runs the interview for candidates who cannot speak: answers
+// are typed into input, one line per turn, and responses are printed to
+// output and spoken. The microphone and STT are not created
+func WithTypedIO(input io.Reader, output io.Writer) Option {
+	return func(b *builder) {
+		b.textMode = engine.WithTypedIO(input, output)
+		b.typed = true
+	}
+}
+
+// WithTypedExchange runs the interview with answers typed into a custom
+// exchange, e.g. a web form, which also receives the spoken responses
+func WithTypedExchange(textIO engine.TextIO) Option {
+	return func(b *builder) {
+		b.textMode = engine.WithTypedAnswers(textIO)
+		b.typed = true
+	}
+}
+
+// WithGreeting sets the message spoken when the interview starts, unless GREETING is configured
+func WithGreeting(greeting string) Option {
+	return func(b *builder) {
+		b.greeting = greeting
+	}
+}
+
+// WithEngineOptions passes additional options to the engine, applied after the configuration
+func WithEngineOptions(opts ...engine.Option) Option {
+	return func(b *builder) {
+		b.engineOptions = append(b.engineOptions, opts...)
+	}
+}
+
+// New creates an interview. Components that are not overridden are created
+// from the configuration, which is loaded from the environment when not given
+func New(opts ...Option) (*Interview, error) {
+	b := &builder{}
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	if b.config == nil {
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load config: %w", err)
+		}
+		b.config = cfg
+	}
+
+	var experimentName string
+	var variant *experiment.Variant
+	if path := b.config.ExperimentFile; path != "" {
+		exp, err := experiment.Load(path)
+		if err != nil {
+			return nil, err
+		}
+		assigned := exp.Assign()
+		experimentName, variant = exp.Name, &assigned
+		b.config = applyVariant(b.config, variant)
+		log.Printf("Session assigned to variant %s of experiment %s", variant.Name, exp.Name)
+	}
+
+	loaded, err := plugins.LoadDir(b.config.Providers.PluginDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range loaded {
+		log.Printf("Loaded plugin %s", name)
+	}
+
+	if err := b.buildComponents(); err != nil {
+		return nil, err
+	}
+
+	engineConfig, err := NewEngineConfig(b.config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure engine: %w", err)
+	}
+	if engineConfig.Greeting == "" {
+		engineConfig.Greeting = b.greeting
+	}
+	if engineConfig.Greeting == "" {
+		engineConfig.Greeting = engineConfig.Messages.Get(i18n.Greeting)
+	}
+
+	// Replayed demo sessions take all speech from the cassette
+	if dir := b.config.SpeechCacheDir; dir != "" && b.config.DemoCassette == "" && (b.textMode == nil || b.typed) {
+		cache, err := tts.NewAudioCache(filepath.Join(dir, b.config.Providers.TTS))
+		if err != nil {
+			log.Printf("Speech cache is disabled: %v", err)
+		} else {
+			engineConfig.SpeechCache = cache
+		}
+	}
+
+	if source := b.config.Engine.Earcons; source != "" && b.textMode == nil {
+		earcons, err := earcon.Load(source)
+		if err != nil {
+			return nil, err
+		}
+		engineConfig.Earcons = earcons
+	}
+
+	if path := b.config.Engine.SafetyAuditLog; path != "" && engineConfig.SafetyFilter != nil {
+		auditLog, err := safety.NewAuditLog(path)
+		if err != nil {
+			return nil, err
+		}
+		engineConfig.SafetyAuditor = auditLog
+	}
+
+	if path := b.config.Engine.TurnLog; path != "" {
+		turnLogger, err := turnlog.NewFileLogger(path)
+		if err != nil {
+			return nil, err
+		}
+		engineConfig.TurnLogger = turnLogger
+	}
+
+	if b.config.Observability.Provider != "" {
+		variantName := ""
+		if variant != nil {
+			variantName = variant.Name
+		}
+		exporter, err := observe.NewExporter(observe.Config{
+			Provider:  b.config.Observability.Provider,
+			Endpoint:  b.config.Observability.Endpoint,
+			PublicKey: b.config.Observability.PublicKey,
+			SecretKey: b.config.Observability.SecretKey,
+			Model:     b.config.GPTModel,
+			Variant:   variantName,
+		})
+		if err != nil {
+			return nil, err
+		}
+		engineConfig.Exporter = exporter
+	}
+
+	if eventLog, ok := b.components.Store.(session.EventLog); ok {
+		engineConfig.EventLog = eventLog
+	}
+
+	engineOptions := append([]engine.Option{
+		engine.WithConfig(engineConfig),
+		engine.WithAudioStreamer(b.components.AudioStreamer),
+		engine.WithSTT(b.components.STT),
+		engine.WithGPT(b.components.GPT),
+		engine.WithTTS(b.components.TTS),
+		engine.WithPlayer(b.components.Player),
+	}, b.engineOptions...)
+	if b.components.FallbackSTT != nil {
+		engineOptions = append(engineOptions, engine.WithFallbackSTT(b.components.FallbackSTT))
+	}
+	if b.components.FallbackTTS != nil {
+		engineOptions = append(engineOptions, engine.WithFallbackTTS(b.components.FallbackTTS))
+	}
+	if b.components.FastGPT != nil {
+		engineOptions = append(engineOptions, engine.WithFastGPT(b.components.FastGPT))
+	}
+	if b.components.Realtime != nil {
+		engineOptions = append(engineOptions, engine.WithRealtime(b.components.Realtime))
+	}
+	if b.config.Engine.Captions {
+		engineOptions = append(engineOptions, engine.WithCaptions(b.config.Console()))
+	}
+	if b.textMode != nil {
+		engineOptions = append(engineOptions, b.textMode)
+	}
+
+	interviewer, err := engine.New(engineOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	interview := &Interview{
+		Interviewer: interviewer,
+		Components:  b.components,
+		Config:      b.config,
+		Experiment:  experimentName,
+		Variant:     variant,
+	}
+	interview.usePrompt(engineConfig.SystemPrompt)
+	return interview, nil
+}
+
+// buildComponents creates every component that was not overridden
+func (b *builder) buildComponents() error {
+	cfg := b.config
+	typing := b.textMode != nil
+	text := typing && !b.typed
+
+	// The realtime session recognizes and synthesizes speech itself
+	if !typing && cfg.Engine.Mode == "realtime" && b.components.Realtime == nil {
+		b.components.Realtime = NewRealtime(cfg)
+	}
+	// Typed answers are not recognized, but responses are synthesized
+	speech := !text && (typing || b.components.Realtime == nil)
+	listen := speech && !typing
+
+	if !text && ((!typing && b.components.AudioStreamer == nil) || b.components.Player == nil) {
+		audioStreamer, player, err := newAudio(cfg)
+		if err != nil {
+			return err
+		}
+		if b.components.AudioStreamer == nil && !typing {
+			b.components.AudioStreamer = audioStreamer
+		}
+		if b.components.Player == nil {
+			b.components.Player = player
+		}
+	}
+
+	// A demo cassette that was already recorded replaces the providers, a new
+	// one records the providers created below
+	var demo *cassette.Cassette
+	if cfg.DemoCassette != "" {
+		if b.components.Realtime != nil {
+			return fmt.Errorf("demo cassettes are not supported in the realtime mode")
+		}
+		var err error
+		if demo, err = cassette.Open(cfg.DemoCassette); err != nil {
+			return err
+		}
+		if demo.Replaying() {
+			log.Printf("Replaying demo cassette %s", cfg.DemoCassette)
+			if listen && b.components.STT == nil {
+				b.components.STT = cassette.NewSTT(demo, nil)
+			}
+			if speech && b.components.TTS == nil {
+				b.components.TTS = cassette.NewTTS(demo, nil)
+			}
+			if b.components.GPT == nil {
+				b.components.GPT = cassette.NewGPT(demo, nil)
+			}
+			if b.components.FastGPT == nil && cfg.FastGPTModel != "" {
+				b.components.FastGPT = cassette.NewGPT(demo, nil)
+			}
+		} else {
+			log.Printf("Recording demo cassette %s", cfg.DemoCassette)
+		}
+	}
+
+	if listen && b.components.STT == nil {
+		sttClient, err := NewSTT(cfg)
+		if err != nil {
+			return fmt.Errorf("failed to create STT client: %w", err)
+		}
+		b.components.STT = sttClient
+	}
+
+	if listen && b.components.FallbackSTT == nil && cfg.Providers.STTFallbackCommand != "" {
+		fallback, err := stt.NewCommandRecognizer(cfg.Providers.STTFallbackCommand)
+		if err != nil {
+			return fmt.Errorf("failed to create fallback STT: %w", err)
+		}
+		b.components.FallbackSTT = fallback
+	}
+
+	if speech && b.components.TTS == nil {
+		ttsClient, err := NewTTS(cfg)
+		if err != nil {
+			return fmt.Errorf("failed to create TTS client: %w", err)
+		}
+		b.components.TTS = ttsClient
+	}
+
+	if speech && b.components.FallbackTTS == nil && cfg.Providers.TTSFallbackCommand != "" {
+		fallback, err := tts.NewCommandSynthesizer(cfg.Providers.TTSFallbackCommand)
+		if err != nil {
+			return fmt.Errorf("failed to create fallback TTS: %w", err)
+		}
+		b.components.FallbackTTS = fallback
+	}
+
+	if b.components.GPT == nil {
+		gptClient, err := NewGPT(cfg)
+		if err != nil {
+			return fmt.Errorf("failed to create GPT client: %w", err)
+		}
+		b.components.GPT = gptClient
+	}
+
+	if b.components.FastGPT == nil && cfg.FastGPTModel != "" && b.components.Realtime == nil {
+		fastGPT, err := NewFastGPT(cfg)
+		if err != nil {
+			return fmt.Errorf("failed to create fast GPT client: %w", err)
+		}
+		b.components.FastGPT = fastGPT
+	}
+
+	if demo != nil && !demo.Replaying() {
+		if b.components.STT != nil {
+			b.components.STT = cassette.NewSTT(demo, b.components.STT)
+		}
+		if b.components.TTS != nil {
+			b.components.TTS = cassette.NewTTS(demo, b.components.TTS)
+		}
+		b.components.GPT = cassette.NewGPT(demo, b.components.GPT)
+		if b.components.FastGPT != nil {
+			b.components.FastGPT = cassette.NewGPT(demo, b.components.FastGPT)
+		}
+	}
+
+	if b.components.Store == nil && cfg.Storage.SessionDir != "" {
+		store, err := session.NewFileStore(cfg.Storage.SessionDir)
+		if err != nil {
+			return err
+		}
+		b.components.Store = store
+	}
+
+	if b.components.Embedder == nil && cfg.Report.DuplicateDetection && (demo == nil || !demo.Replaying()) {
+		b.components.Embedder = embed.NewYandexEmbedder(cfg.FolderID, cfg.IamToken)
+	}
+
+	return nil
+}
+
+// NewSTT creates the configured speech recognition provider
+func NewSTT(cfg *config.Config) (stt.STTClient, error) {
+	if cfg.Providers.STT != "" && cfg.Providers.STT != "yandex" {
+		return plugins.NewSTT(cfg.Providers.STT, cfg)
+	}
+	return stt.NewYandexSTTClient(stt.YandexConfig{
+		IamToken:   cfg.IamToken,
+		FolderID:   cfg.FolderID,
+		Language:   cfg.Audio.Language,
+		Languages:  cfg.Audio.Languages,
+		SampleRate: int32(cfg.Audio.SampleRate),
+
+		Model:           cfg.Providers.STTModel,
+		Normalization:   cfg.Providers.STTNormalization,
+		ProfanityFilter: cfg.Providers.STTProfanityFilter,
+		LiteratureText:  cfg.Providers.STTLiteratureText,
+	})
+}
+
+// NewTTS creates the configured speech synthesis provider
+func NewTTS(cfg *config.Config) (tts.Synthesizer, error) {
+	if cfg.Providers.TTS == "command" {
+		return tts.NewCommandSynthesizer(cfg.Providers.TTSCommand)
+	}
+	if cfg.Providers.TTS == "elevenlabs" {
+		return tts.NewElevenLabsClient(cfg.Providers.ElevenLabsAPIKey, cfg.Providers.ElevenLabsModel), nil
+	}
+	if cfg.Providers.TTS != "" && cfg.Providers.TTS != "yandex" {
+		return plugins.NewTTS(cfg.Providers.TTS, cfg)
+	}
+	return tts.NewYandexTTSClient(tts.YandexConfig{
+		IamToken: cfg.IamToken,
+		FolderID: cfg.FolderID,
+	})
+}
+
+// NewRealtime creates the OpenAI Realtime API client for the realtime engine mode
+func NewRealtime(cfg *config.Config) realtime.Client {
+	client := realtime.NewOpenAIClient(cfg.Providers.RealtimeAPIKey)
+	client.Model = cfg.Providers.RealtimeModel
+	client.Voice = cfg.Providers.RealtimeVoice
+	client.Language, _, _ = strings.Cut(cfg.Audio.Language, "-")
+	return client
+}
+
+// NewGPT creates the configured language model provider
+func NewGPT(cfg *config.Config) (gpt.GPTClient, error) {
+	if cfg.Providers.GPT != "" && cfg.Providers.GPT != "yandex" {
+		return plugins.NewGPT(cfg.Providers.GPT, cfg)
+	}
+	client := gpt.NewYandexGPTClient(cfg.FolderID, cfg.IamToken)
+	if cfg.GPTModel != "" {
+		client.ModelURI = "gpt://" + cfg.FolderID + "/" + cfg.GPTModel
+	}
+	return client, nil
+}
+
+// NewFastGPT creates the client of the model writing the first sentence of responses
+func NewFastGPT(cfg *config.Config) (gpt.GPTClient, error) {
+	if cfg.Providers.GPT != "" && cfg.Providers.GPT != "yandex" {
+		return nil, fmt.Errorf("FAST_GPT_MODEL requires the yandex GPT provider")
+	}
+	client := gpt.NewYandexGPTClient(cfg.FolderID, cfg.IamToken)
+	client.ModelURI = "gpt://" + cfg.FolderID + "/" + cfg.FastGPTModel
+	return client, nil
+}
+
+// NewScreener creates the evaluator of recorded screening answers from the
+// configured STT and language model, scoring on the configured rubric
+func NewScreener(cfg *config.Config) (*screening.Screener, error) {
+	sttClient, err := NewSTT(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create STT client: %w", err)
+	}
+	gptClient, err := NewGPT(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GPT client: %w", err)
+	}
+
+	screener := &screening.Screener{
+		STT:                  sttClient,
+		SampleRate:           int64(cfg.Audio.SampleRate),
+		Evaluator:            eval.NewGPTEvaluator(gptClient, cfg.Engine.Rubric),
+		Bank:                 cfg.Engine.QuestionBank,
+		NormalizeTranscripts: cfg.Engine.NormalizeTranscripts,
+	}
+	if cfg.Engine.SentimentAnalysis {
+		screener.Analyzer = analysis.NewGPTAnalyzer(gptClient)
+	}
+	return screener, nil
+}
+
+// tokenSetter is implemented by clients that support credential rotation
+type tokenSetter interface {
+	SetIamToken(iamToken string)
+}
+
+// SetIamToken passes a refreshed IAM token to every component that supports it
+func (i *Interview) SetIamToken(iamToken string) {
+	for _, component := range []interface{}{i.Components.STT, i.Components.GPT, i.Components.FastGPT, i.Components.TTS, i.Components.Embedder} {
+		if setter, ok := component.(tokenSetter); ok {
+			setter.SetIamToken(iamToken)
+		}
+	}
+}
+
+// GetRecord returns the record of the current or last interview, an empty
+// record when the interviewer keeps none
+func (i *Interview) GetRecord() session.Record {
+	if recorder, ok := i.Interviewer.(engine.Recorder); ok {
+		return recorder.GetRecord()
+	}
+	return session.Record{}
+}
+
+// RequestClosing ends the interview with the closing message. It returns
+// false when the interviewer cannot close gracefully and must be stopped
+func (i *Interview) RequestClosing() bool {
+	closer, ok := i.Interviewer.(engine.Closer)
+	if ok {
+		closer.RequestClosing()
+	}
+	return ok
+}
+
+// Instruct replaces the system prompt or adds an instruction to it from the
+// next turn on, and records the change in the audit log when one is configured
+func (i *Interview) Instruct(text string, replace bool) error {
+	instructor, ok := i.Interviewer.(engine.Instructor)
+	if !ok {
+		return fmt.Errorf("the interviewer does not take instructions")
+	}
+	if err := instructor.Instruct(text, replace); err != nil {
+		return err
+	}
+	if replace {
+		i.usePrompt(text)
+	}
+	if i.Config == nil || i.Config.Storage.AuditLog == "" {
+		return nil
+	}
+
+	detail := "add: " + text
+	if replace {
+		detail = "replace: " + text
+	}
+	auditLog, err := audit.NewFileLog(i.Config.Storage.AuditLog)
+	if err == nil {
+		err = auditLog.Append(audit.NewEntry(audit.ActionPromptChange, i.GetRecord().ID, detail))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to record instruction in audit log: %w", err)
+	}
+	return nil
+}
+
+// Finish completes the record of the interview. Answers nearly identical to
+// answers of stored sessions are flagged when an embedder is configured, the
+// record is signed when a signing key is configured and it is saved when a
+// store is configured
+func (i *Interview) Finish() (session.Record, error) {
+	record := i.GetRecord()
+	store := i.Components.Store
+
+	if i.Variant != nil {
+		record.Experiment = i.Experiment
+		record.Variant = i.Variant.Name
+	}
+	i.promptsMutex.Lock()
+	record.Prompts = slices.Clone(i.prompts)
+	i.promptsMutex.Unlock()
+
+	if i.Components.Embedder != nil && store != nil {
+		if err := i.flagDuplicates(&record); err != nil {
+			log.Printf("Failed to check answers for duplicates: %v", err)
+		}
+	}
+
+	if i.Config != nil && i.Config.Storage.SigningKey != "" {
+		// A session that cannot be signed is still kept, marked as unsigned
+		if err := session.Sign(&record, []byte(i.Config.Storage.SigningKey), sessionAudio(i.Config)); err != nil {
+			log.Printf("Failed to sign session: %v", err)
+			record.Unsigned = err.Error()
+		}
+	}
+
+	if store != nil {
+		if err := store.Save(record); err != nil {
+			return record, fmt.Errorf("failed to save session: %w", err)
+		}
+	}
+
+	return record, nil
+}
+
+// sessionAudio returns the audio files of the interview: the candidate audio
+// and the AI speech of a headless session. Pipes and stdio cannot be read
+// again for verification and are left out. Live sessions keep no audio, so
+// their signature covers the transcript only
+func sessionAudio(cfg *config.Config) []string {
+	if !cfg.Audio.Headless {
+		return nil
+	}
+	var files []string
+	for _, path := range []string{cfg.Audio.HeadlessInput, cfg.Audio.HeadlessOutput} {
+		if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+			files = append(files, path)
+		}
+	}
+	return files
+}
+
+// flagDuplicates compares the answers with the answers of stored sessions
+func (i *Interview) flagDuplicates(record *session.Record) error {
+	if err := session.Embed(record, i.Components.Embedder); err != nil {
+		return fmt.Errorf("failed to embed answers: %w", err)
+	}
+
+	others, err := i.Components.Store.List()
+	if err != nil {
+		return err
+	}
+
+	if found := session.FindDuplicates(record, others, i.Config.Report.DuplicateThreshold); found > 0 {
+		log.Printf("%d answers are nearly identical to answers of other candidates", found)
+	}
+	return nil
+}
+
+// ApplyConfig applies the runtime settings of a reloaded configuration,
+// keeping the overrides of the assigned experiment variant
+func (i *Interview) ApplyConfig(cfg *config.Config) error {
+	engineConfig, err := NewEngineConfig(applyVariant(cfg, i.Variant))
+	if err != nil {
+		return err
+	}
+
+	i.UpdateConfig(engineConfig)
+	i.usePrompt(engineConfig.SystemPrompt)
+	return nil
+}
+
+// usePrompt records the system prompt the interview continues with, named
+// after the registered template version when it is one
+func (i *Interview) usePrompt(text string) {
+	if text == "" {
+		return
+	}
+	prompt := session.PromptVersion{Hash: prompts.Hash(text)}
+	if i.Config != nil {
+		registry, err := prompts.Load(i.Config.PromptDir)
+		if err != nil {
+			log.Printf("Failed to load prompt registry: %v", err)
+		} else if version, ok := registry.Identify(text); ok {
+			prompt.Name, prompt.Version = version.Name, version.Version
+		}
+	}
+
+	i.promptsMutex.Lock()
+	defer i.promptsMutex.Unlock()
+	if n := len(i.prompts); n > 0 && i.prompts[n-1].Hash == prompt.Hash {
+		return
+	}
+	i.prompts = append(i.prompts, prompt)
+}
+
+// applyVariant returns a copy of the configuration with the variant overrides
+func applyVariant(cfg *config.Config, variant *experiment.Variant) *config.Config {
+	if variant == nil {
+		return cfg
+	}
+
+	result := *cfg
+	if variant.SystemPrompt != "" {
+		result.Engine.SystemPrompt = variant.SystemPrompt
+	}
+	if variant.Voice != "" {
+		result.Engine.Voice = variant.Voice
+	}
+	if variant.Speed != 0 {
+		result.Engine.Speed = variant.Speed
+	}
+	if variant.Model != "" {
+		result.GPTModel = variant.Model
+	}
+	return &result
+}
+
+// NewEngineConfig maps the loaded configuration onto the engine configuration
+func NewEngineConfig(cfg *config.Config) (engine.EngineConfig, error) {
+	engineConfig := engine.EngineConfig{
+		SystemPrompt:   cfg.Engine.SystemPrompt,
+		Greeting:       cfg.Engine.Greeting,
+		Farewell:       cfg.Engine.Farewell,
+		SampleRate:     int64(cfg.Audio.SampleRate),
+		SilenceTimeout: cfg.Engine.SilenceTimeout,
+		MinConfidence:  cfg.Engine.MinConfidence,
+		Voice:          cfg.Engine.Voice,
+		Speed:          cfg.Engine.Speed,
+		Role:           cfg.Engine.Role,
+		GreetingRole:   cfg.Engine.GreetingRole,
+		ClosingRole:    cfg.Engine.ClosingRole,
+		LogLevel:       cfg.Engine.LogLevel,
+
+		StreamBufferBytes: cfg.Audio.StreamBuffer,
+		TrimSilence:       cfg.Audio.TrimSilence,
+		NormalizeLoudness: cfg.Audio.NormalizeLoudness,
+		SilenceThreshold:  cfg.Audio.SilenceThreshold,
+		HighPassCutoff:    cfg.Audio.HighPassCutoff,
+		SentimentAnalysis: cfg.Engine.SentimentAnalysis,
+		NotesInterval:     cfg.Engine.NotesInterval,
+		VoiceCommands:     cfg.Engine.VoiceCommands,
+		ProsodyMarkup:     cfg.Engine.ProsodyMarkup,
+		TTSRetries:        cfg.Providers.TTSRetries,
+
+		Closing:        cfg.Engine.Closing,
+		ClosingTimeout: cfg.Engine.ClosingTimeout,
+		CandidateName:  cfg.Engine.CandidateName,
+		MaxDuration:    cfg.Engine.MaxDuration,
+		StallTimeout:   cfg.Engine.StallTimeout,
+		WarmUp:         cfg.Engine.WarmUp,
+		Reactions:      cfg.Engine.Reactions,
+
+		NormalizeTranscripts: cfg.Engine.NormalizeTranscripts,
+		AdaptiveSilence:      cfg.Engine.AdaptiveSilence,
+		MergeWindow:          cfg.Engine.MergeWindow,
+		TimeAnnouncements:    cfg.Engine.TimeAnnouncements,
+		CaptionFile:          cfg.Engine.CaptionFile,
+
+		QuestionBank: cfg.Engine.QuestionBank,
+		QuestionPlan: questions.Plan{
+			Topics:   cfg.Engine.QuestionTopics,
+			Tags:     cfg.Engine.QuestionTags,
+			PerTopic: cfg.Engine.QuestionsPerTopic,
+			Budget:   cfg.Engine.MaxDuration,
+		},
+		MaxFollowUps: cfg.Engine.MaxFollowUps,
+		Personas:     cfg.Engine.Personas,
+	}
+
+	messages, err := i18n.Load(cfg.Audio.Language, cfg.LocaleDir)
+	if err != nil {
+		return engine.EngineConfig{}, fmt.Errorf("failed to load phrases: %w", err)
+	}
+	engineConfig.Messages = messages
+
+	nextSteps := cfg.Engine.NextSteps
+	if nextSteps == "" {
+		nextSteps = messages.Get(i18n.NextSteps)
+	}
+	nextSteps, err = RenderTemplate(nextSteps, TemplateVars(cfg))
+	if err != nil {
+		return engine.EngineConfig{}, fmt.Errorf("invalid NEXT_STEPS: %w", err)
+	}
+	engineConfig.NextSteps = nextSteps
+	engineConfig.GreetingVariables = TemplateVars(cfg)
+
+	if cfg.Engine.DifficultyStrategy != "" {
+		strategy, err := engine.NewDifficultyStrategy(cfg.Engine.DifficultyStrategy)
+		if err != nil {
+			return engine.EngineConfig{}, err
+		}
+		engineConfig.DifficultyStrategy = strategy
+		engineConfig.Rubric = cfg.Engine.Rubric
+	}
+
+	filter, err := safety.NewFilter(cfg.Engine.SafetyFilter, cfg.Engine.SafetyJurisdictions)
+	if err != nil {
+		return engine.EngineConfig{}, err
+	}
+	if filter != nil {
+		engineConfig.SafetyFilter = filter
+
+		rules, err := safety.RulesForPacks(cfg.Engine.SafetyJurisdictions)
+		if err != nil {
+			return engine.EngineConfig{}, err
+		}
+		engineConfig.ProhibitedTopics = safety.Categories(rules)
+	}
+
+	deferral, err := safety.NewDeferralFilter(cfg.Engine.Deferral)
+	if err != nil {
+		return engine.EngineConfig{}, err
+	}
+	engineConfig.Deferral = deferral
+
+	return engineConfig, nil
+}
+
+// TemplateVars returns the configured template variables. The candidate
+// variable holds the candidate name and is empty when it is not known, company
+// and role are set from COMPANY and POSITION unless TEMPLATE_VARS has them
+func TemplateVars(cfg *config.Config) map[string]string {
+	vars := make(map[string]string, len(cfg.Engine.TemplateVars)+3)
+	if cfg.Engine.Company != "" {
+		vars["company"] = cfg.Engine.Company
+	}
+	if cfg.Engine.Position != "" {
+		vars["role"] = cfg.Engine.Position
+	}
+	for key, value := range cfg.Engine.TemplateVars {
+		vars[key] = value
+	}
+	vars["candidate"] = cfg.Engine.CandidateName
+	return vars
+}
+
+// RenderTemplate fills a text/template with variables. Unknown variables are an error
+func RenderTemplate(text string, vars map[string]string) (string, error) {
+	tmpl, err := template.New("").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var rendered strings.Builder
+	if err := tmpl.Execute(&rendered, vars); err != nil {
+		return "", err
+	}
+	return rendered.String(), nil
+}
+
+// newAudio creates capture and playback for the configured audio mode
+func newAudio(cfg *config.Config) (audio.AudioStreamer, sound.Player, error) {
+	// Initialize audio streamer for recording
+	audioConfig := audio.PortaudioConfig{
+		SampleRate:      cfg.Audio.SampleRate,
+		FramesPerBuffer: cfg.Audio.FramesPerBuffer,
+		InputChannels:   cfg.Audio.InputChannels,
+		OutputChannels:  cfg.Audio.OutputChannels,
+	}
+
+	// Initialize audio player for TTS playback
+	playerConfig := sound.PlayerConfig{
+		SampleRate:        22050.0,
+		FramesPerBuffer:   2048,
+		InputChannels:     0,
+		OutputChannels:    1,
+		PrebufferDuration: cfg.Audio.PlaybackPrebuffer,
+	}
+
+	if cfg.Audio.Headless {
+		return newHeadlessAudio(cfg, audioConfig, playerConfig)
+	}
+	return newDeviceAudio(cfg, audioConfig, playerConfig)
+}
+
+// newDeviceAudio creates capture and playback on local devices using the configured backend
+func newDeviceAudio(cfg *config.Config, audioConfig audio.PortaudioConfig, playerConfig sound.PlayerConfig) (audio.AudioStreamer, sound.Player, error) {
+	audioStreamer, captureBackend, err := audio.NewStreamer(cfg.Audio.Backend, audioConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	player, playbackBackend, err := sound.NewPlayer(cfg.Audio.Backend, playerConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	log.Printf("Using %s capture and %s playback", captureBackend, playbackBackend)
+	return audioStreamer, player, nil
+}
+
+// newHeadlessAudio creates capture and playback over raw PCM streams,
+// so the process runs in containers without any audio devices
+func newHeadlessAudio(cfg *config.Config, audioConfig audio.PortaudioConfig, playerConfig sound.PlayerConfig) (audio.AudioStreamer, sound.Player, error) {
+	var input io.Reader = strings.NewReader("")
+	pace := false
+	switch path := cfg.Audio.HeadlessInput; path {
+	case "":
+	case "-":
+		input = os.Stdin
+	default:
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to open headless audio input: %w", err)
+		}
+		input = file
+		// Regular files are read faster than real time, named pipes are live sources
+		if info, err := file.Stat(); err == nil && info.Mode().IsRegular() {
+			pace = true
+		}
+	}
+
+	var output io.Writer = io.Discard
+	switch path := cfg.Audio.HeadlessOutput; path {
+	case "":
+	case "-":
+		output = os.Stdout
+	default:
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to open headless audio output: %w", err)
+		}
+		output = file
+	}
+
+	log.Printf("Running headless, local audio devices are disabled")
+	return audio.NewReaderStreamer(input, audioConfig, pace), sound.NewWriterPlayer(output, playerConfig), nil
+}
@@ -0,0 +1,15 @@
+// Package analysis estimates the emotional state of the candidate from their answers
+package analysis
+
+// Sentiment describes how the candidate sounded while giving an answer
+type Sentiment struct {
+	Sentiment  float64 `json:"sentiment"`  // From -1 (negative) to 1 (positive)
+	Confidence float64 `json:"confidence"` // From 0 (hesitant) to 1 (confident)
+	Stressed   bool    `json:"stressed"`   // Evident stress worth human review
+	Note       string  `json:"note,omitempty"`
+}
+
+// Analyzer defines the interface for sentiment and stress analysis
+type Analyzer interface {
+	AnalyzeAnswer(question, answer string) (Sentiment, error)
+}
@@ -0,0 +1,190 @@
+package analysis
+
+import (
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/d1nch8g/aihr/stt"
+)
+
+// minPause is the shortest gap between words counted as a pause,
+// shorter gaps are part of normal articulation
+const minPause = 250 * time.Millisecond
+
+// fillerPhrases are filler words and phrases in English and Russian
+var fillerPhrases = [][]string{
+	{"um"}, {"umm"}, {"uh"}, {"uhm"}, {"er"}, {"erm"}, {"hmm"}, {"like"}, {"basically"},
+	{"you", "know"}, {"i", "mean"}, {"sort", "of"}, {"kind", "of"},
+	{"эм"}, {"ээ"}, {"эээ"}, {"мм"}, {"ну"}, {"значит"}, {"типа"}, {"короче"}, {"вот"},
+	{"как", "бы"}, {"это", "самое"}, {"в", "общем"}, {"так", "сказать"},
+}
+
+// ambiguousFillers are fillers that are also ordinary words, as in "I like
+// Go" or "this kind of index". They only count when set off from the rest
+// of the sentence by punctuation or pauses on both sides, as in "it was,
+// like, fast"
+var ambiguousFillers = map[string]bool{
+	"like": true, "kind of": true, "sort of": true, "i mean": true,
+	"вот": true, "в общем": true, "так сказать": true,
+}
+
+// Fluency holds speech statistics of one or more answers
+type Fluency struct {
+	Words          int            `json:"words"`
+	FillerWords    int            `json:"filler_words"`
+	Fillers        map[string]int `json:"fillers,omitempty"`
+	SpeakingTime   time.Duration  `json:"speaking_time,omitempty"`
+	Pauses         int            `json:"pauses"`
+	PauseTime      time.Duration  `json:"pause_time,omitempty"`
+	WordsPerMinute float64        `json:"words_per_minute,omitempty"`
+	AveragePause   time.Duration  `json:"average_pause,omitempty"`
+}
+
+// AnalyzeFluency computes speech statistics from a transcript and its word
+// timings. Without timings only the word and filler counts are filled in
+func AnalyzeFluency(text string, words []stt.Word) Fluency {
+	tokens, breaks := tokenize(text)
+	// Timings of the same words also mark the pauses around fillers
+	if len(words) == len(tokens) {
+		for i := 1; i < len(words); i++ {
+			if words[i].Start-words[i-1].End >= minPause {
+				breaks[i] = true
+			}
+		}
+	}
+	result := Fluency{
+		Words:   len(tokens),
+		Fillers: countFillers(tokens, breaks),
+	}
+	for _, count := range result.Fillers {
+		result.FillerWords += count
+	}
+
+	if len(words) > 0 {
+		result.SpeakingTime = words[len(words)-1].End - words[0].Start
+		for i := 1; i < len(words); i++ {
+			if gap := words[i].Start - words[i-1].End; gap >= minPause {
+				result.Pauses++
+				result.PauseTime += gap
+			}
+		}
+	}
+
+	result.derive()
+	return result
+}
+
+// MergeFluency combines the statistics of several answers
+func MergeFluency(items ...Fluency) Fluency {
+	var result Fluency
+	for _, item := range items {
+		result.Words += item.Words
+		result.FillerWords += item.FillerWords
+		result.SpeakingTime += item.SpeakingTime
+		result.Pauses += item.Pauses
+		result.PauseTime += item.PauseTime
+		for filler, count := range item.Fillers {
+			if result.Fillers == nil {
+				result.Fillers = make(map[string]int)
+			}
+			result.Fillers[filler] += count
+		}
+	}
+
+	result.derive()
+	return result
+}
+
+// derive computes the rates from the totals
+func (f *Fluency) derive() {
+	f.WordsPerMinute = 0
+	if f.SpeakingTime > 0 {
+		f.WordsPerMinute = float64(f.Words) / f.SpeakingTime.Minutes()
+	}
+	f.AveragePause = 0
+	if f.Pauses > 0 {
+		f.AveragePause = f.PauseTime / time.Duration(f.Pauses)
+	}
+}
+
+// FillerRatio returns the share of filler words among all words
+func (f Fluency) FillerRatio() float64 {
+	if f.Words == 0 {
+		return 0
+	}
+	return float64(f.FillerWords) / float64(f.Words)
+}
+
+// countFillers counts filler phrases, preferring the longest match at each
+// position. Ambiguous fillers need a break before and after them
+func countFillers(tokens []string, breaks []bool) map[string]int {
+	fillers := make(map[string]int)
+	for i := 0; i < len(tokens); {
+		matched := 0
+		for _, phrase := range fillerPhrases {
+			if len(phrase) <= matched || !hasPrefix(tokens[i:], phrase) {
+				continue
+			}
+			if ambiguousFillers[strings.Join(phrase, " ")] && (!breaks[i] || !breaks[i+len(phrase)]) {
+				continue
+			}
+			matched = len(phrase)
+		}
+		if matched == 0 {
+			i++
+			continue
+		}
+		fillers[strings.Join(tokens[i:i+matched], " ")]++
+		i += matched
+	}
+	if len(fillers) == 0 {
+		return nil
+	}
+	return fillers
+}
+
+func hasPrefix(tokens, phrase []string) bool {
+	if len(tokens) < len(phrase) {
+		return false
+	}
+	for i, word := range phrase {
+		if tokens[i] != word {
+			return false
+		}
+	}
+	return true
+}
+
+// tokenize splits text into lower case words without punctuation. It also
+// returns a break for every boundary between words, breaks[i] is set when
+// punctuation precedes word i. The start and the end of the text are breaks
+func tokenize(text string) ([]string, []bool) {
+	var (
+		tokens  []string
+		breaks  = []bool{true}
+		current strings.Builder
+	)
+	punctuated := false
+	for _, r := range strings.ToLower(text) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '\'' {
+			if current.Len() == 0 && len(tokens) > 0 {
+				breaks = append(breaks, punctuated)
+			}
+			current.WriteRune(r)
+			punctuated = false
+			continue
+		}
+		if current.Len() > 0 {
+			tokens = append(tokens, current.String())
+			current.Reset()
+		}
+		if !unicode.IsSpace(r) {
+			punctuated = true
+		}
+	}
+	if current.Len() > 0 {
+		tokens = append(tokens, current.String())
+	}
+	return tokens, append(breaks, true)
+}
@@ -0,0 +1,52 @@
+package analysis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/d1nch8g/aihr/stt"
+)
+
+func TestAnalyzeFluencyFillers(t *testing.T) {
+	tests := []struct {
+		text    string
+		fillers int
+	}{
+		{"I like Go", 0},
+		{"this kind of index is faster", 0},
+		{"it was, like, really fast", 1},
+		{"um I built, you know, a cache", 2},
+		{"Like, I built it, I mean, mostly alone", 2},
+		{"вот проект, который я сделал", 0},
+		{"ну я сделал сервис, вот", 2},
+		{"в общем случае это O(n)", 0},
+		{"это, в общем, сложно", 1},
+		{"", 0},
+	}
+
+	for _, test := range tests {
+		if got := AnalyzeFluency(test.text, nil).FillerWords; got != test.fillers {
+			t.Errorf("AnalyzeFluency(%q) counts %d fillers, want %d", test.text, got, test.fillers)
+		}
+	}
+}
+
+func TestAnalyzeFluencyPauses(t *testing.T) {
+	// "like" between pauses is a filler without punctuation
+	words := []stt.Word{
+		{Text: "it", Start: 0, End: 200 * time.Millisecond},
+		{Text: "was", Start: 250 * time.Millisecond, End: 400 * time.Millisecond},
+		{Text: "like", Start: 900 * time.Millisecond, End: 1100 * time.Millisecond},
+		{Text: "fast", Start: 1600 * time.Millisecond, End: 1900 * time.Millisecond},
+	}
+	result := AnalyzeFluency("it was like fast", words)
+	if result.FillerWords != 1 {
+		t.Errorf("counts %d fillers, want 1", result.FillerWords)
+	}
+	if result.Pauses != 2 || result.PauseTime != time.Second {
+		t.Errorf("counts %d pauses of %s, want 2 of 1s", result.Pauses, result.PauseTime)
+	}
+	if result.SpeakingTime != 1900*time.Millisecond {
+		t.Errorf("speaking time is %s, want 1.9s", result.SpeakingTime)
+	}
+}
@@ -0,0 +1,50 @@
+package analysis
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/d1nch8g/aihr/gpt"
+)
+
+const followUpPrompt = `You review a job interview. Decide whether the interviewer's new message is a follow-up
+on the topic of the previous question, digging further into the same area, or moves on to a different topic.
+Reply with one word: "follow-up" or "new".`
+
+// FollowUpDetector defines the interface for telling whether a question of
+// the interviewer stays on the topic of the previous one
+type FollowUpDetector interface {
+	IsFollowUp(previous, question string) (bool, error)
+}
+
+// GPTFollowUpDetector classifies questions by asking the GPT model
+type GPTFollowUpDetector struct {
+	client gpt.GPTClient
+}
+
+// Ensure GPTFollowUpDetector implements FollowUpDetector interface
+var _ FollowUpDetector = (*GPTFollowUpDetector)(nil)
+
+// NewGPTFollowUpDetector creates a new follow-up detector backed by a GPT client
+func NewGPTFollowUpDetector(client gpt.GPTClient) *GPTFollowUpDetector {
+	return &GPTFollowUpDetector{client: client}
+}
+
+// IsFollowUp asks the model whether the question follows up on the previous one
+func (d *GPTFollowUpDetector) IsFollowUp(previous, question string) (bool, error) {
+	userMessage := fmt.Sprintf("Previous question: %s\nNew message: %s", previous, question)
+	reply, err := d.client.Complete(followUpPrompt, userMessage)
+	if err != nil {
+		return false, fmt.Errorf("failed to classify question: %w", err)
+	}
+
+	reply = strings.ToLower(strings.Trim(strings.TrimSpace(reply), `."'`))
+	switch {
+	case strings.HasPrefix(reply, "follow"):
+		return true, nil
+	case strings.HasPrefix(reply, "new"):
+		return false, nil
+	default:
+		return false, fmt.Errorf("unexpected classification: %q", reply)
+	}
+}
@@ -0,0 +1,90 @@
+package analysis
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/d1nch8g/aihr/gpt"
+)
+
+const analysisPrompt = `You analyze the emotional state of a job candidate from the transcript of their answer.
+Reply with exactly one line in the format:
+sentiment=<number from -1 to 1>; confidence=<number from 0 to 1>; stressed=<yes or no>; note=<few words>
+Mark stressed=yes only for evident stress: panic, repeated apologies, giving up, long self-corrections.`
+
+// GPTAnalyzer analyzes answers by asking the GPT model
+type GPTAnalyzer struct {
+	client gpt.GPTClient
+}
+
+// Ensure GPTAnalyzer implements Analyzer interface
+var _ Analyzer = (*GPTAnalyzer)(nil)
+
+// NewGPTAnalyzer creates a new analyzer backed by a GPT client
+func NewGPTAnalyzer(client gpt.GPTClient) *GPTAnalyzer {
+	return &GPTAnalyzer{client: client}
+}
+
+// AnalyzeAnswer asks the model to rate the answer and parses its reply
+func (a *GPTAnalyzer) AnalyzeAnswer(question, answer string) (Sentiment, error) {
+	userMessage := fmt.Sprintf("Question: %s\nAnswer: %s", question, answer)
+
+	reply, err := a.client.Complete(analysisPrompt, userMessage)
+	if err != nil {
+		return Sentiment{}, fmt.Errorf("failed to request analysis: %w", err)
+	}
+
+	return parseSentiment(reply)
+}
+
+// parseSentiment reads the key=value pairs of the model reply
+func parseSentiment(reply string) (Sentiment, error) {
+	var result Sentiment
+	found := false
+
+	for _, part := range strings.Split(reply, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "sentiment":
+			if number, err := parseNumber(value); err == nil {
+				result.Sentiment = clamp(number, -1, 1)
+				found = true
+			}
+		case "confidence":
+			if number, err := parseNumber(value); err == nil {
+				result.Confidence = clamp(number, 0, 1)
+				found = true
+			}
+		case "stressed":
+			result.Stressed = strings.HasPrefix(strings.ToLower(value), "y")
+		case "note":
+			result.Note = value
+		}
+	}
+
+	if !found {
+		return Sentiment{}, fmt.Errorf("no analysis found in reply: %q", reply)
+	}
+	return result, nil
+}
+
+func parseNumber(value string) (float64, error) {
+	return strconv.ParseFloat(strings.ReplaceAll(value, ",", "."), 64)
+}
+
+func clamp(value, min, max float64) float64 {
+	if value < min {
+		return min
+	}
+	if value > max {
+		return max
+	}
+	return value
+}
@@ -0,0 +1,89 @@
+package analysis
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/d1nch8g/aihr/gpt"
+)
+
+const notesPrompt = `You take notes for the hiring team during a job interview.
+From the questions and answers below write what the candidate said, one note per line, each starting with its kind:
+claim: <something the candidate states about their experience or work>
+skill: <a skill the candidate demonstrated in the answers, not just named>
+red flag: <a contradiction, an evasive or fabricated answer, or another concern>
+Keep every note under 15 words. Write nothing but the notes, leave a kind out when there is nothing for it.`
+
+// Exchange is a question of the interviewer and the candidate's answer
+type Exchange struct {
+	Question string
+	Answer   string
+}
+
+// Notes are the structured notes on a part of the interview
+type Notes struct {
+	Claims   []string `json:"claims,omitempty"`    // What the candidate stated about their experience
+	Skills   []string `json:"skills,omitempty"`    // Skills demonstrated in the answers
+	RedFlags []string `json:"red_flags,omitempty"` // Contradictions and other concerns
+}
+
+// Empty reports whether there are no notes
+func (n Notes) Empty() bool {
+	return len(n.Claims) == 0 && len(n.Skills) == 0 && len(n.RedFlags) == 0
+}
+
+// NoteTaker defines the interface for taking notes on a part of the interview
+type NoteTaker interface {
+	TakeNotes(exchanges []Exchange) (Notes, error)
+}
+
+// GPTNoteTaker takes notes by asking the GPT model
+type GPTNoteTaker struct {
+	client gpt.GPTClient
+}
+
+// Ensure GPTNoteTaker implements NoteTaker interface
+var _ NoteTaker = (*GPTNoteTaker)(nil)
+
+// NewGPTNoteTaker creates a new note taker backed by a GPT client
+func NewGPTNoteTaker(client gpt.GPTClient) *GPTNoteTaker {
+	return &GPTNoteTaker{client: client}
+}
+
+// TakeNotes asks the model for notes on the exchanges and parses its reply
+func (n *GPTNoteTaker) TakeNotes(exchanges []Exchange) (Notes, error) {
+	var userMessage strings.Builder
+	for _, exchange := range exchanges {
+		fmt.Fprintf(&userMessage, "Question: %s\nAnswer: %s\n\n", exchange.Question, exchange.Answer)
+	}
+
+	reply, err := n.client.Complete(notesPrompt, userMessage.String())
+	if err != nil {
+		return Notes{}, fmt.Errorf("failed to request notes: %w", err)
+	}
+
+	return parseNotes(reply), nil
+}
+
+// parseNotes reads the "kind: note" lines of the model reply, lines of an
+// unknown kind are skipped
+func parseNotes(reply string) Notes {
+	var notes Notes
+	for _, line := range strings.Split(reply, "\n") {
+		kind, note, ok := strings.Cut(strings.TrimLeft(line, "-*• \t"), ":")
+		note = strings.TrimSpace(note)
+		if !ok || note == "" {
+			continue
+		}
+
+		switch strings.ToLower(strings.TrimSpace(kind)) {
+		case "claim":
+			notes.Claims = append(notes.Claims, note)
+		case "skill":
+			notes.Skills = append(notes.Skills, note)
+		case "red flag":
+			notes.RedFlags = append(notes.RedFlags, note)
+		}
+	}
+	return notes
+}
@@ -0,0 +1,168 @@
+// Package analytics aggregates stored sessions to help tune the question bank
+package analytics
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/d1nch8g/aihr/session"
+)
+
+// FailScore is the score below which an answer counts as failed
+const FailScore = 5.0
+
+// QuestionStats holds aggregated results of one question
+type QuestionStats struct {
+	Question      string
+	Asked         int
+	AverageWords  float64
+	AverageScore  float64 // Over scored answers only
+	Scored        int
+	Failed        int     // Scored answers below FailScore
+	HiredScore    float64 // Average score of hired candidates
+	RejectedScore float64 // Average score of rejected candidates
+
+	// Discrimination is the difference between the average scores of hired
+	// and rejected candidates, it is only set when both groups answered
+	Discrimination *float64
+
+	hiredCount    int
+	rejectedCount int
+}
+
+// FailRate returns the share of scored answers that failed
+func (q QuestionStats) FailRate() float64 {
+	if q.Scored == 0 {
+		return 0
+	}
+	return float64(q.Failed) / float64(q.Scored)
+}
+
+// Summary is the result of aggregating sessions
+type Summary struct {
+	Sessions  int
+	Hired     int
+	Rejected  int
+	Stalls    int // Turns restarted by the engine watchdog
+	Questions []QuestionStats
+}
+
+// Aggregate groups the answers of all sessions by question. Questions are
+// compared case and punctuation insensitively
+func Aggregate(records []session.Record) Summary {
+	summary := Summary{Sessions: len(records)}
+	byKey := make(map[string]*QuestionStats)
+	var order []string
+
+	for _, record := range records {
+		summary.Stalls += record.Stalls
+		switch record.Outcome {
+		case session.OutcomeHired:
+			summary.Hired++
+		case session.OutcomeRejected:
+			summary.Rejected++
+		}
+
+		for _, answer := range record.Answers {
+			key := normalize(answer.Question)
+			if key == "" || answer.Unclear {
+				continue
+			}
+			stats, ok := byKey[key]
+			if !ok {
+				stats = &QuestionStats{Question: strings.TrimSpace(answer.Question)}
+				byKey[key] = stats
+				order = append(order, key)
+			}
+			stats.add(answer, record.Outcome)
+		}
+	}
+
+	for _, key := range order {
+		stats := byKey[key]
+		stats.finish()
+		summary.Questions = append(summary.Questions, *stats)
+	}
+
+	// Most discriminating questions first, then the most asked ones
+	sort.SliceStable(summary.Questions, func(i, j int) bool {
+		a, b := summary.Questions[i], summary.Questions[j]
+		if (a.Discrimination == nil) != (b.Discrimination == nil) {
+			return a.Discrimination != nil
+		}
+		if a.Discrimination != nil && *a.Discrimination != *b.Discrimination {
+			return *a.Discrimination > *b.Discrimination
+		}
+		return a.Asked > b.Asked
+	})
+
+	return summary
+}
+
+// MostFailed returns up to limit questions with the highest fail rate
+func (s Summary) MostFailed(limit int) []QuestionStats {
+	var failed []QuestionStats
+	for _, q := range s.Questions {
+		if q.Failed > 0 {
+			failed = append(failed, q)
+		}
+	}
+	sort.SliceStable(failed, func(i, j int) bool {
+		return failed[i].FailRate() > failed[j].FailRate()
+	})
+	if len(failed) > limit {
+		failed = failed[:limit]
+	}
+	return failed
+}
+
+// add accumulates an answer, averages are divided out in finish
+func (q *QuestionStats) add(answer session.Answer, outcome string) {
+	q.Asked++
+	q.AverageWords += float64(len(strings.Fields(answer.Text)))
+
+	if answer.Score == nil {
+		return
+	}
+	score := *answer.Score
+	q.Scored++
+	q.AverageScore += score
+	if score < FailScore {
+		q.Failed++
+	}
+
+	switch outcome {
+	case session.OutcomeHired:
+		q.HiredScore += score
+		q.hiredCount++
+	case session.OutcomeRejected:
+		q.RejectedScore += score
+		q.rejectedCount++
+	}
+}
+
+// finish turns the accumulated sums into averages
+func (q *QuestionStats) finish() {
+	q.AverageWords /= float64(q.Asked)
+	if q.Scored > 0 {
+		q.AverageScore /= float64(q.Scored)
+	}
+	if q.hiredCount > 0 {
+		q.HiredScore /= float64(q.hiredCount)
+	}
+	if q.rejectedCount > 0 {
+		q.RejectedScore /= float64(q.rejectedCount)
+	}
+	if q.hiredCount > 0 && q.rejectedCount > 0 {
+		discrimination := q.HiredScore - q.RejectedScore
+		q.Discrimination = &discrimination
+	}
+}
+
+// normalize lower cases the question and drops punctuation and extra spaces
+func normalize(question string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(question), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
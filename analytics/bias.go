@@ -0,0 +1,249 @@
+package analytics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/d1nch8g/aihr/session"
+)
+
+// Cohorts sessions can be grouped by in a bias audit
+const (
+	// CohortLanguage groups sessions by the language most answers were recognized in
+	CohortLanguage = "language"
+
+	// CohortConfidence groups sessions by the average recognition confidence
+	// of the answers, a proxy for accents the recognizer handles poorly
+	CohortConfidence = "confidence"
+
+	// CohortTime groups sessions by the time of day the interview started
+	CohortTime = "time"
+)
+
+// Cohorts lists every cohort in the order they are reported
+var Cohorts = []string{CohortLanguage, CohortConfidence, CohortTime}
+
+// minGroupSessions is the fewest scored sessions on either side of a
+// comparison for its disparity to be tested
+const minGroupSessions = 5
+
+// unknownGroup holds the sessions a cohort cannot be determined for
+const unknownGroup = "unknown"
+
+// GroupStats holds the scores of the sessions of one group of a cohort,
+// compared with the scored sessions outside of the group
+type GroupStats struct {
+	Cohort       string
+	Group        string
+	Sessions     int // Scored sessions
+	AverageScore float64
+	Hired        int
+	Rejected     int
+
+	// Difference is the average score of the group minus the one of the
+	// other sessions
+	Difference float64
+
+	// PValue is the two-sided p-value of the Mann-Whitney U test comparing
+	// the scores with the other sessions, nil when either side has fewer
+	// than minGroupSessions sessions
+	PValue *float64
+
+	// Flagged marks a disparity significant at the level of the audit
+	Flagged bool
+}
+
+// AuditBias compares the session scores of every group of the cohorts with
+// the rest of the scored sessions. The significance level alpha is divided by
+// the number of tests (Bonferroni), so auditing many groups does not flag
+// chance differences. Sessions without scored answers are left out
+func AuditBias(records []session.Record, cohorts []string, alpha float64) ([]GroupStats, error) {
+	for _, cohort := range cohorts {
+		if cohortGroup(session.Record{}, cohort) == "" {
+			return nil, fmt.Errorf("unknown cohort %q", cohort)
+		}
+	}
+
+	type scoredSession struct {
+		record session.Record
+		score  float64
+	}
+	var scored []scoredSession
+	for _, record := range records {
+		if score, ok := averageScore(record); ok {
+			scored = append(scored, scoredSession{record, score})
+		}
+	}
+
+	var groups []GroupStats
+	var scores [][2][]float64 // Scores in and outside of every group
+	for _, cohort := range cohorts {
+		byGroup := make(map[string][]scoredSession)
+		for _, s := range scored {
+			group := cohortGroup(s.record, cohort)
+			byGroup[group] = append(byGroup[group], s)
+		}
+		names := make([]string, 0, len(byGroup))
+		for name := range byGroup {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			stats := GroupStats{Cohort: cohort, Group: name}
+			var in, out []float64
+			for _, s := range scored {
+				if cohortGroup(s.record, cohort) != name {
+					out = append(out, s.score)
+					continue
+				}
+				in = append(in, s.score)
+				switch s.record.Outcome {
+				case session.OutcomeHired:
+					stats.Hired++
+				case session.OutcomeRejected:
+					stats.Rejected++
+				}
+			}
+			stats.Sessions = len(in)
+			stats.AverageScore = mean(in)
+			if len(out) > 0 {
+				stats.Difference = stats.AverageScore - mean(out)
+			}
+			groups = append(groups, stats)
+			scores = append(scores, [2][]float64{in, out})
+		}
+	}
+
+	tests := 0
+	for i := range groups {
+		in, out := scores[i][0], scores[i][1]
+		if len(in) < minGroupSessions || len(out) < minGroupSessions {
+			continue
+		}
+		p := mannWhitney(in, out)
+		groups[i].PValue = &p
+		tests++
+	}
+	for i := range groups {
+		if p := groups[i].PValue; p != nil && *p < alpha/float64(tests) {
+			groups[i].Flagged = true
+		}
+	}
+	return groups, nil
+}
+
+// cohortGroup returns the group of the record in the cohort, empty for an unknown cohort
+func cohortGroup(record session.Record, cohort string) string {
+	switch cohort {
+	case CohortLanguage:
+		counts := make(map[string]int)
+		language := ""
+		for _, answer := range record.Answers {
+			for _, l := range answer.Languages {
+				counts[l]++
+				if counts[l] > counts[language] || (counts[l] == counts[language] && l < language) {
+					language = l
+				}
+			}
+		}
+		if language == "" {
+			return unknownGroup
+		}
+		return language
+
+	case CohortConfidence:
+		var total float64
+		measured := 0
+		for _, answer := range record.Answers {
+			if answer.Confidence > 0 {
+				total += answer.Confidence
+				measured++
+			}
+		}
+		switch average := total / float64(max(measured, 1)); {
+		case measured == 0:
+			return unknownGroup
+		case average < 0.7:
+			return "low (<0.70)"
+		case average < 0.85:
+			return "medium (0.70-0.85)"
+		default:
+			return "high (>=0.85)"
+		}
+
+	case CohortTime:
+		if record.StartedAt.IsZero() {
+			return unknownGroup
+		}
+		switch hour := record.StartedAt.In(time.Local).Hour(); {
+		case hour < 6:
+			return "night (00-06)"
+		case hour < 12:
+			return "morning (06-12)"
+		case hour < 18:
+			return "afternoon (12-18)"
+		default:
+			return "evening (18-24)"
+		}
+	}
+	return ""
+}
+
+// mannWhitney returns the two-sided p-value of the Mann-Whitney U test of two
+// samples, using the normal approximation with the correction for ties
+func mannWhitney(a, b []float64) float64 {
+	type value struct {
+		score float64
+		first bool
+	}
+	values := make([]value, 0, len(a)+len(b))
+	for _, score := range a {
+		values = append(values, value{score, true})
+	}
+	for _, score := range b {
+		values = append(values, value{score, false})
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i].score < values[j].score })
+
+	// Tied scores share the average of their ranks
+	var rankSum, ties float64
+	for i := 0; i < len(values); {
+		j := i
+		for j < len(values) && values[j].score == values[i].score {
+			j++
+		}
+		rank := float64(i+j+1) / 2
+		for k := i; k < j; k++ {
+			if values[k].first {
+				rankSum += rank
+			}
+		}
+		t := float64(j - i)
+		ties += t*t*t - t
+		i = j
+	}
+
+	n1, n2 := float64(len(a)), float64(len(b))
+	n := n1 + n2
+	u := rankSum - n1*(n1+1)/2
+	variance := n1 * n2 / 12 * ((n + 1) - ties/(n*(n-1)))
+	if variance <= 0 {
+		return 1
+	}
+	z := (u - n1*n2/2) / math.Sqrt(variance)
+	return math.Erfc(math.Abs(z) / math.Sqrt2)
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	var total float64
+	for _, value := range values {
+		total += value
+	}
+	return total / float64(len(values))
+}
@@ -0,0 +1,138 @@
+package analytics
+
+import (
+	"math"
+	"sort"
+
+	"github.com/d1nch8g/aihr/session"
+)
+
+// minCorrelationSessions is the fewest sessions a correlation is computed for
+const minCorrelationSessions = 3
+
+// DimensionCalibration compares the AI and human scores of one dimension over
+// the sessions both scored
+type DimensionCalibration struct {
+	Dimension  string
+	Sessions   int
+	AIScore    float64 // Average AI score
+	HumanScore float64 // Average human score
+
+	// Bias is the AI score minus the human score on average, positive when
+	// the AI is more lenient than the interviewers
+	Bias float64
+
+	// MeanAbsDiff is the average distance between the scores of a session
+	MeanAbsDiff float64
+
+	// Correlation is the Pearson correlation of the scores, nil with fewer
+	// than minCorrelationSessions sessions or when either side never varies
+	Correlation *float64
+}
+
+// Calibration is the comparison of the AI evaluation with human interviewers
+type Calibration struct {
+	Sessions   int // Sessions with human scores
+	Dimensions []DimensionCalibration
+}
+
+// Calibrate compares the AI scores of sessions with the human scores imported
+// for them. The scores of several interviewers are averaged, the overall human
+// score is compared with the average AI score of the answers
+func Calibrate(records []session.Record) Calibration {
+	var calibration Calibration
+	ai := make(map[string][]float64)
+	human := make(map[string][]float64)
+
+	for _, record := range records {
+		if len(record.HumanScores) == 0 {
+			continue
+		}
+		calibration.Sessions++
+
+		aiScores := record.RubricScores()
+		if overall, ok := averageScore(record); ok {
+			aiScores[session.OverallDimension] = overall
+		}
+		for dimension, humanScore := range averageHumanScores(record.HumanScores) {
+			if aiScore, ok := aiScores[dimension]; ok {
+				ai[dimension] = append(ai[dimension], aiScore)
+				human[dimension] = append(human[dimension], humanScore)
+			}
+		}
+	}
+
+	for dimension := range ai {
+		calibration.Dimensions = append(calibration.Dimensions, calibrate(dimension, ai[dimension], human[dimension]))
+	}
+	sort.Slice(calibration.Dimensions, func(i, j int) bool {
+		a, b := calibration.Dimensions[i].Dimension, calibration.Dimensions[j].Dimension
+		if (a == session.OverallDimension) != (b == session.OverallDimension) {
+			return a == session.OverallDimension
+		}
+		return a < b
+	})
+	return calibration
+}
+
+// calibrate compares the paired scores of one dimension
+func calibrate(dimension string, ai, human []float64) DimensionCalibration {
+	result := DimensionCalibration{Dimension: dimension, Sessions: len(ai)}
+	for i := range ai {
+		result.AIScore += ai[i]
+		result.HumanScore += human[i]
+		result.MeanAbsDiff += math.Abs(ai[i] - human[i])
+	}
+	n := float64(len(ai))
+	result.AIScore /= n
+	result.HumanScore /= n
+	result.MeanAbsDiff /= n
+	result.Bias = result.AIScore - result.HumanScore
+
+	if len(ai) >= minCorrelationSessions {
+		var covariance, aiVariance, humanVariance float64
+		for i := range ai {
+			da, dh := ai[i]-result.AIScore, human[i]-result.HumanScore
+			covariance += da * dh
+			aiVariance += da * da
+			humanVariance += dh * dh
+		}
+		if aiVariance > 0 && humanVariance > 0 {
+			correlation := covariance / math.Sqrt(aiVariance*humanVariance)
+			result.Correlation = &correlation
+		}
+	}
+	return result
+}
+
+// averageScore returns the average AI score of the scored answers
+func averageScore(record session.Record) (float64, bool) {
+	var total float64
+	scored := 0
+	for _, answer := range record.Answers {
+		if answer.Score != nil {
+			total += *answer.Score
+			scored++
+		}
+	}
+	if scored == 0 {
+		return 0, false
+	}
+	return total / float64(scored), true
+}
+
+// averageHumanScores returns the average score of every dimension over the interviewers
+func averageHumanScores(scores []session.HumanScore) map[string]float64 {
+	totals := make(map[string]float64)
+	counts := make(map[string]int)
+	for _, score := range scores {
+		for dimension, value := range score.Scores {
+			totals[dimension] += value
+			counts[dimension]++
+		}
+	}
+	for dimension := range totals {
+		totals[dimension] /= float64(counts[dimension])
+	}
+	return totals
+}
@@ -0,0 +1,176 @@
+package analytics
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+)
+
+// WriteSummary prints the question bank analytics as tables
+func WriteSummary(w io.Writer, summary Summary) {
+	fmt.Fprintf(w, "Sessions: %d (hired %d, rejected %d, no outcome %d)\n",
+		summary.Sessions, summary.Hired, summary.Rejected, summary.Sessions-summary.Hired-summary.Rejected)
+	if summary.Stalls > 0 {
+		fmt.Fprintf(w, "Stalled turns: %d\n", summary.Stalls)
+	}
+
+	if len(summary.Questions) == 0 {
+		fmt.Fprintln(w, "No answers recorded yet")
+		return
+	}
+
+	fmt.Fprintf(w, "\nQuestions:\n")
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "ASKED\tAVG WORDS\tAVG SCORE\tHIRED\tREJECTED\tDISCRIMINATION\tQUESTION")
+	for _, q := range summary.Questions {
+		fmt.Fprintf(table, "%d\t%.0f\t%s\t%s\t%s\t%s\t%s\n",
+			q.Asked, q.AverageWords,
+			scoreOrDash(q.AverageScore, q.Scored > 0),
+			scoreOrDash(q.HiredScore, q.hiredCount > 0),
+			scoreOrDash(q.RejectedScore, q.rejectedCount > 0),
+			discrimination(q.Discrimination),
+			truncate(q.Question, 70))
+	}
+	table.Flush()
+
+	if failed := summary.MostFailed(5); len(failed) > 0 {
+		fmt.Fprintf(w, "\nMost failed:\n")
+		table = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(table, "FAILED\tQUESTION")
+		for _, q := range failed {
+			fmt.Fprintf(table, "%.0f%% (%d/%d)\t%s\n", q.FailRate()*100, q.Failed, q.Scored, truncate(q.Question, 70))
+		}
+		table.Flush()
+	}
+}
+
+// WriteVariants prints outcome metrics per experiment variant
+func WriteVariants(w io.Writer, variants []VariantStats) {
+	if len(variants) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "\nExperiments:\n")
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "EXPERIMENT\tVARIANT\tSESSIONS\tHIRE RATE\tAVG SCORE\tAVG ANSWERS\tAVG DURATION\tSTRESSED")
+	for _, v := range variants {
+		fmt.Fprintf(table, "%s\t%s\t%d\t%s\t%s\t%.1f\t%s\t%d\n",
+			v.Experiment, v.Variant, v.Sessions,
+			hireRate(v),
+			scoreOrDash(v.AverageScore, v.scored > 0),
+			v.AverageAnswers, v.AverageDuration.Round(time.Second), v.StressedAnswers)
+	}
+	table.Flush()
+}
+
+func hireRate(v VariantStats) string {
+	if v.Hired+v.Rejected == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.0f%% (%d/%d)", v.HireRate()*100, v.Hired, v.Hired+v.Rejected)
+}
+
+func scoreOrDash(score float64, ok bool) string {
+	if !ok {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f", score)
+}
+
+func discrimination(value *float64) string {
+	if value == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%+.1f", *value)
+}
+
+func truncate(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit-3]) + "..."
+}
+
+// WriteCalibration prints the comparison of AI and human scores by dimension
+func WriteCalibration(w io.Writer, calibration Calibration) {
+	fmt.Fprintf(w, "Sessions with human scores: %d\n", calibration.Sessions)
+	if len(calibration.Dimensions) == 0 {
+		fmt.Fprintln(w, "No dimensions scored by both the AI and the interviewers yet")
+		return
+	}
+
+	fmt.Fprintln(w)
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "DIMENSION\tSESSIONS\tAI\tHUMAN\tBIAS\tMEAN DIFF\tCORRELATION\tAGREEMENT")
+	for _, d := range calibration.Dimensions {
+		fmt.Fprintf(table, "%s\t%d\t%.1f\t%.1f\t%+.1f\t%.1f\t%s\t%s\n",
+			d.Dimension, d.Sessions, d.AIScore, d.HumanScore, d.Bias, d.MeanAbsDiff,
+			correlation(d.Correlation), agreement(d.Correlation))
+	}
+	table.Flush()
+
+	fmt.Fprintf(w, "\nBias is the AI score minus the human score, positive when the AI is more lenient.\n")
+	fmt.Fprintf(w, "Dimensions with weak agreement should not be trusted without human review.\n")
+}
+
+func correlation(value *float64) string {
+	if value == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%+.2f", *value)
+}
+
+// agreement names the strength of the correlation
+func agreement(value *float64) string {
+	switch {
+	case value == nil:
+		return "not enough data"
+	case *value >= 0.7:
+		return "strong"
+	case *value >= 0.4:
+		return "moderate"
+	default:
+		return "weak"
+	}
+}
+
+// WriteBias prints the scores of every cohort group and the disparities
+// flagged for review
+func WriteBias(w io.Writer, groups []GroupStats, alpha float64) {
+	if len(groups) == 0 {
+		fmt.Fprintln(w, "No scored sessions yet")
+		return
+	}
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "COHORT\tGROUP\tSESSIONS\tAVG SCORE\tDIFFERENCE\tP-VALUE\tHIRED\tREJECTED\t")
+	flagged := 0
+	for _, g := range groups {
+		mark := ""
+		if g.Flagged {
+			mark = "REVIEW"
+			flagged++
+		}
+		fmt.Fprintf(table, "%s\t%s\t%d\t%.1f\t%+.1f\t%s\t%d\t%d\t%s\n",
+			g.Cohort, g.Group, g.Sessions, g.AverageScore, g.Difference, pValue(g.PValue), g.Hired, g.Rejected, mark)
+	}
+	table.Flush()
+
+	fmt.Fprintf(w, "\nDifference is the average score of the group minus the one of the other sessions.\n")
+	fmt.Fprintf(w, "P-values are computed for groups with at least %d sessions on both sides.\n", minGroupSessions)
+	if flagged == 0 {
+		fmt.Fprintf(w, "No disparity is significant at the %.2f level.\n", alpha)
+		return
+	}
+	fmt.Fprintf(w, "%d groups are scored significantly differently at the %.2f level (Bonferroni corrected)\n", flagged, alpha)
+	fmt.Fprintf(w, "and should be reviewed. A disparity is a reason to look at the interviews, not proof of bias.\n")
+}
+
+func pValue(value *float64) string {
+	if value == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%.3f", *value)
+}
@@ -0,0 +1,94 @@
+package analytics
+
+import (
+	"sort"
+	"time"
+
+	"github.com/d1nch8g/aihr/session"
+)
+
+// VariantStats holds outcome metrics of the sessions assigned to one experiment variant
+type VariantStats struct {
+	Experiment      string
+	Variant         string
+	Sessions        int
+	Hired           int
+	Rejected        int
+	AverageScore    float64 // Over scored answers, 0 when none were scored
+	AverageAnswers  float64
+	AverageDuration time.Duration
+	StressedAnswers int
+
+	scored int
+}
+
+// HireRate returns the share of hired candidates among sessions with an outcome
+func (v VariantStats) HireRate() float64 {
+	if v.Hired+v.Rejected == 0 {
+		return 0
+	}
+	return float64(v.Hired) / float64(v.Hired+v.Rejected)
+}
+
+// ByVariant groups sessions by experiment and variant. Sessions outside of
+// experiments are skipped
+func ByVariant(records []session.Record) []VariantStats {
+	type key struct{ experiment, variant string }
+	byKey := make(map[key]*VariantStats)
+	durationSum := make(map[key]time.Duration)
+	durationCount := make(map[key]int)
+
+	for _, record := range records {
+		if record.Experiment == "" {
+			continue
+		}
+		k := key{record.Experiment, record.Variant}
+		stats, ok := byKey[k]
+		if !ok {
+			stats = &VariantStats{Experiment: record.Experiment, Variant: record.Variant}
+			byKey[k] = stats
+		}
+
+		stats.Sessions++
+		stats.AverageAnswers += float64(len(record.Answers))
+		switch record.Outcome {
+		case session.OutcomeHired:
+			stats.Hired++
+		case session.OutcomeRejected:
+			stats.Rejected++
+		}
+		if !record.EndedAt.IsZero() {
+			durationSum[k] += record.EndedAt.Sub(record.StartedAt)
+			durationCount[k]++
+		}
+		for _, answer := range record.Answers {
+			if answer.Score != nil {
+				stats.AverageScore += *answer.Score
+				stats.scored++
+			}
+			if answer.Sentiment != nil && answer.Sentiment.Stressed {
+				stats.StressedAnswers++
+			}
+		}
+	}
+
+	result := make([]VariantStats, 0, len(byKey))
+	for k, stats := range byKey {
+		stats.AverageAnswers /= float64(stats.Sessions)
+		if stats.scored > 0 {
+			stats.AverageScore /= float64(stats.scored)
+		}
+		if durationCount[k] > 0 {
+			stats.AverageDuration = durationSum[k] / time.Duration(durationCount[k])
+		}
+		result = append(result, *stats)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Experiment != result[j].Experiment {
+			return result[i].Experiment < result[j].Experiment
+		}
+		return result[i].Variant < result[j].Variant
+	})
+	return result
+}
@@ -0,0 +1,180 @@
+// Package ats pushes interview results to applicant tracking systems
+package ats
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/d1nch8g/aihr/session"
+)
+
+// Result fields that can be mapped onto ATS candidate fields
+const (
+	FieldSession    = "session"
+	FieldScore      = "score"
+	FieldRubric     = "rubric" // JSON object of the average score by rubric dimension
+	FieldAnswers    = "answers"
+	FieldDuration   = "duration"
+	FieldDuplicates = "duplicates"
+	FieldTranscript = "transcript"
+	FieldReport     = "report"
+	FieldRecording  = "recording"
+)
+
+// Result is the outcome of one interview as sent to the ATS
+type Result struct {
+	Record       session.Record
+	Transcript   string
+	Report       string
+	RecordingURL string // Link to the stored recording, empty when there is none
+}
+
+// NewResult prepares the result of a finished session
+func NewResult(record session.Record) Result {
+	var transcript, report bytes.Buffer
+	session.WriteTranscript(&transcript, record)
+	session.WriteReport(&report, record)
+	return Result{
+		Record:     record,
+		Transcript: transcript.String(),
+		Report:     report.String(),
+	}
+}
+
+// Fields returns the result as named values. Empty values are left out
+func (r Result) Fields() map[string]string {
+	fields := map[string]string{
+		FieldSession:    r.Record.ID,
+		FieldAnswers:    strconv.Itoa(len(r.Record.Answers)),
+		FieldTranscript: r.Transcript,
+		FieldReport:     r.Report,
+		FieldRecording:  r.RecordingURL,
+	}
+	if !r.Record.EndedAt.IsZero() {
+		fields[FieldDuration] = r.Record.EndedAt.Sub(r.Record.StartedAt).Round(time.Second).String()
+	}
+
+	var total float64
+	scored, duplicates := 0, 0
+	for _, answer := range r.Record.Answers {
+		if answer.Score != nil {
+			total += *answer.Score
+			scored++
+		}
+		if answer.Duplicate != nil {
+			duplicates++
+		}
+	}
+	if scored > 0 {
+		fields[FieldScore] = strconv.FormatFloat(total/float64(scored), 'f', 1, 64)
+	}
+	fields[FieldDuplicates] = strconv.Itoa(duplicates)
+	fields[FieldRubric] = rubricScores(r.Record)
+
+	for name, value := range fields {
+		if value == "" {
+			delete(fields, name)
+		}
+	}
+	return fields
+}
+
+// rubricScores returns the average score of every rubric dimension as a
+// JSON object, empty when no answer was assessed
+func rubricScores(record session.Record) string {
+	scores := record.RubricScores()
+	if len(scores) == 0 {
+		return ""
+	}
+	for name, score := range scores {
+		scores[name] = math.Round(score*10) / 10
+	}
+	content, err := json.Marshal(scores)
+	if err != nil {
+		return ""
+	}
+	return string(content)
+}
+
+// Connector pushes interview results to a candidate profile in an ATS
+type Connector interface {
+	Push(candidateID string, result Result) error
+}
+
+// Config selects and configures the ATS connector
+type Config struct {
+	Provider string // "greenhouse" or "lever"
+	APIKey   string
+	UserID   string // ATS user the notes are written on behalf of
+
+	// Mapping maps result fields to ATS field keys. Greenhouse receives them
+	// as candidate custom fields, Lever as labelled lines of the note
+	Mapping map[string]string
+}
+
+// NewConnector creates the connector for the configured provider
+func NewConnector(config Config) (Connector, error) {
+	if config.APIKey == "" {
+		return nil, fmt.Errorf("ATS API key is required")
+	}
+	for field := range config.Mapping {
+		if !knownField(field) {
+			return nil, fmt.Errorf("unknown ATS result field %q", field)
+		}
+	}
+
+	switch config.Provider {
+	case "greenhouse":
+		if config.UserID == "" {
+			return nil, fmt.Errorf("Greenhouse requires the user ID notes are written on behalf of")
+		}
+		return NewGreenhouseConnector(config.APIKey, config.UserID, config.Mapping), nil
+	case "lever":
+		return NewLeverConnector(config.APIKey, config.UserID, config.Mapping), nil
+	default:
+		return nil, fmt.Errorf("unknown ATS provider %q", config.Provider)
+	}
+}
+
+// summary returns the note text posted to the candidate profile
+func summary(result Result) string {
+	fields := result.Fields()
+	var note strings.Builder
+	fmt.Fprintf(&note, "AI interview %s\n", result.Record.ID)
+	for _, name := range []string{FieldAnswers, FieldDuration, FieldScore, FieldRubric, FieldDuplicates, FieldRecording} {
+		if value, ok := fields[name]; ok {
+			fmt.Fprintf(&note, "%s: %s\n", name, value)
+		}
+	}
+	note.WriteString("\n")
+	note.WriteString(result.Transcript)
+	return note.String()
+}
+
+// mappedFields returns the mapped result fields sorted by ATS key
+func mappedFields(result Result, mapping map[string]string) [][2]string {
+	fields := result.Fields()
+	var mapped [][2]string
+	for field, key := range mapping {
+		if value, ok := fields[field]; ok {
+			mapped = append(mapped, [2]string{key, value})
+		}
+	}
+	sort.Slice(mapped, func(i, j int) bool { return mapped[i][0] < mapped[j][0] })
+	return mapped
+}
+
+func knownField(field string) bool {
+	switch field {
+	case FieldSession, FieldScore, FieldRubric, FieldAnswers, FieldDuration, FieldDuplicates,
+		FieldTranscript, FieldReport, FieldRecording:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package ats
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+const (
+	GreenhouseEndpoint = "https://harvest.greenhouse.io/v1"
+)
+
+// greenhouseNote is the body of the activity feed note API
+type greenhouseNote struct {
+	UserID     string `json:"user_id"`
+	Body       string `json:"body"`
+	Visibility string `json:"visibility"`
+}
+
+// greenhouseCandidate is the body of the candidate update API
+type greenhouseCandidate struct {
+	CustomFields []greenhouseCustomField `json:"custom_fields"`
+}
+
+type greenhouseCustomField struct {
+	NameKey string `json:"name_key"`
+	Value   string `json:"value"`
+}
+
+// GreenhouseConnector writes results to candidates through the Greenhouse Harvest API
+type GreenhouseConnector struct {
+	APIKey     string
+	UserID     string
+	Mapping    map[string]string
+	Endpoint   string
+	HTTPClient *http.Client
+}
+
+// Ensure GreenhouseConnector implements Connector interface
+var _ Connector = (*GreenhouseConnector)(nil)
+
+// NewGreenhouseConnector creates a Greenhouse Harvest API client
+func NewGreenhouseConnector(apiKey, userID string, mapping map[string]string) *GreenhouseConnector {
+	return &GreenhouseConnector{
+		APIKey:     apiKey,
+		UserID:     userID,
+		Mapping:    mapping,
+		Endpoint:   GreenhouseEndpoint,
+		HTTPClient: &http.Client{},
+	}
+}
+
+// Push adds the interview summary and transcript as a private note and
+// updates the mapped custom fields of the candidate
+func (c *GreenhouseConnector) Push(candidateID string, result Result) error {
+	note := greenhouseNote{UserID: c.UserID, Body: summary(result), Visibility: "private"}
+	if err := c.do("POST", "/candidates/"+url.PathEscape(candidateID)+"/activity_feed/notes", note); err != nil {
+		return fmt.Errorf("failed to add Greenhouse note: %w", err)
+	}
+
+	mapped := mappedFields(result, c.Mapping)
+	if len(mapped) == 0 {
+		return nil
+	}
+	var update greenhouseCandidate
+	for _, field := range mapped {
+		update.CustomFields = append(update.CustomFields, greenhouseCustomField{NameKey: field[0], Value: field[1]})
+	}
+	if err := c.do("PATCH", "/candidates/"+url.PathEscape(candidateID), update); err != nil {
+		return fmt.Errorf("failed to update Greenhouse custom fields: %w", err)
+	}
+	return nil
+}
+
+// do sends an authenticated JSON request on behalf of the configured user
+func (c *GreenhouseConnector) do(method, path string, body interface{}) error {
+	reqBody, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequest(method, c.Endpoint+path, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("On-Behalf-Of", c.UserID)
+	httpReq.SetBasicAuth(c.APIKey, "")
+
+	resp, err := c.HTTPClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
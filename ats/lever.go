@@ -0,0 +1,93 @@
+package ats
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	LeverEndpoint = "https://api.lever.co/v1"
+)
+
+// leverNote is the body of the opportunity note API
+type leverNote struct {
+	Value string `json:"value"`
+}
+
+// LeverConnector writes results to opportunities through the Lever API
+type LeverConnector struct {
+	APIKey     string
+	UserID     string // Optional, notes are attributed to the API key owner without it
+	Mapping    map[string]string
+	Endpoint   string
+	HTTPClient *http.Client
+}
+
+// Ensure LeverConnector implements Connector interface
+var _ Connector = (*LeverConnector)(nil)
+
+// NewLeverConnector creates a Lever API client
+func NewLeverConnector(apiKey, userID string, mapping map[string]string) *LeverConnector {
+	return &LeverConnector{
+		APIKey:     apiKey,
+		UserID:     userID,
+		Mapping:    mapping,
+		Endpoint:   LeverEndpoint,
+		HTTPClient: &http.Client{},
+	}
+}
+
+// Push adds a note with the mapped fields, the interview summary and the
+// transcript to the opportunity. Lever has no API for custom candidate
+// fields, so mapped fields are written as labelled lines at the top of the note
+func (c *LeverConnector) Push(opportunityID string, result Result) error {
+	var note strings.Builder
+	for _, field := range mappedFields(result, c.Mapping) {
+		fmt.Fprintf(&note, "%s: %s\n", field[0], field[1])
+	}
+	if note.Len() > 0 {
+		note.WriteString("\n")
+	}
+	note.WriteString(summary(result))
+
+	path := "/opportunities/" + url.PathEscape(opportunityID) + "/notes"
+	if c.UserID != "" {
+		path += "?perform_as=" + url.QueryEscape(c.UserID)
+	}
+	if err := c.post(path, leverNote{Value: note.String()}); err != nil {
+		return fmt.Errorf("failed to add Lever note: %w", err)
+	}
+	return nil
+}
+
+// post sends an authenticated JSON request
+func (c *LeverConnector) post(path string, body interface{}) error {
+	reqBody, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequest("POST", c.Endpoint+path, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.SetBasicAuth(c.APIKey, "")
+
+	resp, err := c.HTTPClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
@@ -1,6 +1,32 @@
 package audio
 
-import "context"
+import (
+	"context"
+	"errors"
+
+	"github.com/d1nch8g/aihr/stream"
+)
+
+// ErrDeviceLost is returned by StartCapture when the input device stops
+// delivering audio, e.g. because a USB headset was unplugged
+var ErrDeviceLost = errors.New("audio input device lost")
+
+// PortaudioConfig represents the configuration for audio capture
+type PortaudioConfig struct {
+	SampleRate      float64
+	FramesPerBuffer int
+	InputChannels   int
+	OutputChannels  int
+}
+
+func GetDefaultConfig() PortaudioConfig {
+	return PortaudioConfig{
+		SampleRate:      44100,
+		FramesPerBuffer: 1024,
+		InputChannels:   1,
+		OutputChannels:  0,
+	}
+}
 
 // AudioStreamer defines the interface for audio streaming implementations
 type AudioStreamer interface {
@@ -20,3 +46,19 @@ type AudioStreamer interface {
 	// The method blocks until the context is cancelled
 	StartCapture(ctx context.Context, audioData chan<- []byte) error
 }
+
+// BufferPooler is implemented by streamers that capture into buffers from a
+// pool. A consumer done with a chunk returns it to the pool, chunks that are
+// kept, e.g. by a recognizer, are left to the garbage collector
+type BufferPooler interface {
+	// BufferPool returns the pool of the captured chunks
+	BufferPool() *stream.Pool
+}
+
+// Reconnector is implemented by streamers that can switch to the default input
+// device after the selected one was lost
+type Reconnector interface {
+	// Reconnect reopens capture on the current default input device and checks
+	// that it delivers audio. It returns an error while no device is available
+	Reconnect() error
+}
@@ -0,0 +1,73 @@
+package audio
+
+import (
+	"fmt"
+	"sort"
+)
+
+// StreamConfig is the backend independent capture configuration
+type StreamConfig = PortaudioConfig
+
+// BackendFactory creates an AudioStreamer for one audio system
+type BackendFactory func(config StreamConfig) (AudioStreamer, error)
+
+type backend struct {
+	name     string
+	priority int
+	factory  BackendFactory
+}
+
+var backends = map[string]backend{}
+
+// RegisterBackend makes a capture backend available for selection by name.
+// With "auto" selection the registered backend with the highest priority is used
+func RegisterBackend(name string, priority int, factory BackendFactory) {
+	backends[name] = backend{name: name, priority: priority, factory: factory}
+}
+
+// Backends returns the names of the registered backends, preferred first
+func Backends() []string {
+	sorted := sortedBackends()
+	names := make([]string, len(sorted))
+	for i, b := range sorted {
+		names[i] = b.name
+	}
+	return names
+}
+
+// NewStreamer creates a streamer using the named backend, or the preferred
+// registered backend when name is "auto" or empty
+func NewStreamer(name string, config StreamConfig) (AudioStreamer, string, error) {
+	if name == "" || name == "auto" {
+		sorted := sortedBackends()
+		if len(sorted) == 0 {
+			return nil, "", fmt.Errorf("no audio capture backend available in this build")
+		}
+		name = sorted[0].name
+	}
+
+	b, ok := backends[name]
+	if !ok {
+		return nil, "", fmt.Errorf("unknown audio capture backend %q, available: %v", name, Backends())
+	}
+
+	streamer, err := b.factory(config)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create %s capture: %w", name, err)
+	}
+	return streamer, name, nil
+}
+
+func sortedBackends() []backend {
+	sorted := make([]backend, 0, len(backends))
+	for _, b := range backends {
+		sorted = append(sorted, b)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].priority != sorted[j].priority {
+			return sorted[i].priority > sorted[j].priority
+		}
+		return sorted[i].name < sorted[j].name
+	})
+	return sorted
+}
@@ -0,0 +1,198 @@
+//go:build linux
+
+package audio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/d1nch8g/aihr/stream"
+)
+
+// reconnectTimeout bounds the check that a recorder delivers audio again
+const reconnectTimeout = 2 * time.Second
+
+// commandBackend describes a recorder program that writes raw 16-bit PCM to stdout
+type commandBackend struct {
+	name     string
+	program  string
+	args     func(sampleRate, channels string) []string
+	priority int
+}
+
+// Linux audio systems driven through their command line tools run as
+// subprocesses, a fallback rather than native access to the audio system. No
+// cgo is required, but the tools must be installed, e.g. from the
+// pipewire-bin, pulseaudio-utils or alsa-utils packages
+var commandBackends = []commandBackend{
+	{
+		name:    "pipewire",
+		program: "pw-record",
+		args: func(rate, channels string) []string {
+			return []string{"--format", "s16", "--rate", rate, "--channels", channels, "-"}
+		},
+		priority: -1,
+	},
+	{
+		name:    "pulse",
+		program: "parec",
+		args: func(rate, channels string) []string {
+			return []string{"--raw", "--format=s16le", "--rate=" + rate, "--channels=" + channels}
+		},
+		priority: -2,
+	},
+	{
+		name:    "alsa",
+		program: "arecord",
+		args: func(rate, channels string) []string {
+			return []string{"-q", "-t", "raw", "-f", "S16_LE", "-r", rate, "-c", channels}
+		},
+		priority: -3,
+	},
+}
+
+func init() {
+	for _, backend := range commandBackends {
+		if _, err := exec.LookPath(backend.program); err != nil {
+			continue
+		}
+		backend := backend
+		RegisterBackend(backend.name, backend.priority, func(config StreamConfig) (AudioStreamer, error) {
+			return NewCommandStreamer(backend, config), nil
+		})
+	}
+}
+
+// CommandStreamer captures audio by running a recorder program and reading its output
+type CommandStreamer struct {
+	backend commandBackend
+	config  StreamConfig
+
+	pool *stream.Pool
+
+	cmd      *exec.Cmd
+	cmdMutex sync.Mutex
+}
+
+// NewCommandStreamer creates a streamer for the given recorder program
+func NewCommandStreamer(backend commandBackend, config StreamConfig) *CommandStreamer {
+	return &CommandStreamer{
+		backend: backend,
+		config:  config,
+		pool:    stream.NewPool(config.FramesPerBuffer * config.InputChannels * 2),
+	}
+}
+
+// BufferPool returns the pool of the captured chunks
+func (c *CommandStreamer) BufferPool() *stream.Pool {
+	return c.pool
+}
+
+func (c *CommandStreamer) Initialize() error {
+	_, err := exec.LookPath(c.backend.program)
+	return err
+}
+
+func (c *CommandStreamer) Terminate() {
+	c.Close()
+}
+
+// Open is a no-op, the recorder process is started by StartCapture
+func (c *CommandStreamer) Open() error {
+	return nil
+}
+
+// Close stops the recorder process if it is running
+func (c *CommandStreamer) Close() error {
+	c.cmdMutex.Lock()
+	defer c.cmdMutex.Unlock()
+
+	if c.cmd != nil && c.cmd.Process != nil {
+		return c.cmd.Process.Kill()
+	}
+	return nil
+}
+
+func (c *CommandStreamer) StartCapture(ctx context.Context, audioData chan<- []byte) error {
+	args := c.backend.args(
+		strconv.Itoa(int(c.config.SampleRate)),
+		strconv.Itoa(c.config.InputChannels),
+	)
+	cmd := exec.CommandContext(ctx, c.backend.program, args...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start %s: %w", c.backend.program, err)
+	}
+
+	c.cmdMutex.Lock()
+	c.cmd = cmd
+	c.cmdMutex.Unlock()
+
+	defer func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+		c.cmdMutex.Lock()
+		c.cmd = nil
+		c.cmdMutex.Unlock()
+	}()
+
+	chunkSize := c.config.FramesPerBuffer * c.config.InputChannels * 2
+	for {
+		chunk := c.pool.Get()[:chunkSize]
+		if _, err := io.ReadFull(stdout, chunk); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return fmt.Errorf("%w: %s exited unexpectedly", ErrDeviceLost, c.backend.program)
+			}
+			return err
+		}
+
+		select {
+		case audioData <- chunk:
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+			// Drop audio if channel is full
+			c.pool.Put(chunk)
+		}
+	}
+}
+
+// Ensure CommandStreamer implements Reconnector interface
+var _ Reconnector = (*CommandStreamer)(nil)
+
+// Reconnect runs the recorder until it delivers one buffer. The recorder
+// always captures from the current default device, so nothing else changes
+func (c *CommandStreamer) Reconnect() error {
+	ctx, cancel := context.WithTimeout(context.Background(), reconnectTimeout)
+	defer cancel()
+
+	audioData := make(chan []byte, 1)
+	result := make(chan error, 1)
+	go func() {
+		result <- c.StartCapture(ctx, audioData)
+	}()
+
+	select {
+	case <-audioData:
+		cancel()
+		<-result
+		return nil
+	case err := <-result:
+		if ctx.Err() != nil {
+			return fmt.Errorf("%s delivered no audio", c.backend.program)
+		}
+		return err
+	}
+}
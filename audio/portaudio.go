@@ -1,35 +1,56 @@
+//go:build cgo
+
 package audio
 
 import (
-	"bytes"
 	"context"
 	"encoding/binary"
 	"errors"
+	"fmt"
 	"log"
+	"time"
 
+	"github.com/d1nch8g/aihr/pcm"
+	"github.com/d1nch8g/aihr/sound"
+	"github.com/d1nch8g/aihr/stream"
 	"github.com/gordonklaus/portaudio"
 )
 
-type PortaudioConfig struct {
-	SampleRate      float64
-	FramesPerBuffer int
-	InputChannels   int
-	OutputChannels  int
+func init() {
+	RegisterBackend("portaudio", 0, func(config StreamConfig) (AudioStreamer, error) {
+		return NewPortaudioStreamer(config), nil
+	})
 }
 
+const (
+	// maxReadFailures is the number of consecutive failed reads after which
+	// the input device is considered lost
+	maxReadFailures = 10
+	readRetryDelay  = 50 * time.Millisecond
+)
+
 type PortaudioStreamer struct {
 	stream      *portaudio.Stream
 	audioBuffer []int32
 	config      PortaudioConfig
+	channels    int              // Channels captured from the device
+	resampler   *sound.Resampler // Set when the device captures at another rate
+	pool        *stream.Pool     // Buffers of the captured chunks
 }
 
 func NewPortaudioStreamer(config PortaudioConfig) *PortaudioStreamer {
 	return &PortaudioStreamer{
 		config:      config,
 		audioBuffer: make([]int32, config.FramesPerBuffer),
+		pool:        stream.NewPool(config.FramesPerBuffer * config.InputChannels * 2),
 	}
 }
 
+// BufferPool returns the pool of the captured chunks
+func (a *PortaudioStreamer) BufferPool() *stream.Pool {
+	return a.pool
+}
+
 func (a *PortaudioStreamer) Initialize() error {
 	return portaudio.Initialize()
 }
@@ -38,11 +59,33 @@ func (a *PortaudioStreamer) Terminate() {
 	portaudio.Terminate()
 }
 
+// Open opens the default input device. A rate or channel count the device
+// does not support is replaced with the nearest supported one, and the
+// captured audio is converted to the configured format
 func (a *PortaudioStreamer) Open() error {
+	rate, channels, err := sound.ProbeFormat(true, a.config.InputChannels, a.config.SampleRate, a.config.FramesPerBuffer, a.audioBuffer)
+	if err != nil {
+		return err
+	}
+	if channels != a.config.InputChannels && channels != 1 {
+		return fmt.Errorf("input device supports %d channel(s), %d requested", channels, a.config.InputChannels)
+	}
+	if rate != a.config.SampleRate || channels != a.config.InputChannels {
+		log.Printf("Input device does not support %.0f Hz, %d channel(s), capturing %.0f Hz, %d channel(s)",
+			a.config.SampleRate, a.config.InputChannels, rate, channels)
+	}
+
+	a.channels = channels
+	a.resampler = nil
+	if rate != a.config.SampleRate {
+		a.resampler = sound.NewResampler(rate, a.config.SampleRate, channels)
+	}
+	a.audioBuffer = make([]int32, a.config.FramesPerBuffer*channels)
+
 	stream, err := portaudio.OpenDefaultStream(
-		a.config.InputChannels,
+		channels,
 		a.config.OutputChannels,
-		a.config.SampleRate,
+		rate,
 		a.config.FramesPerBuffer,
 		a.audioBuffer,
 	)
@@ -70,15 +113,26 @@ func (a *PortaudioStreamer) StartCapture(ctx context.Context, audioData chan<- [
 	}
 	defer a.stream.Stop()
 
+	failures := 0
 	for {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
 		default:
 			if err := a.stream.Read(); err != nil {
+				if errors.Is(err, portaudio.InputOverflowed) {
+					// Some audio was lost, the device itself is fine
+					continue
+				}
+				failures++
+				if failures >= maxReadFailures {
+					return fmt.Errorf("%w: %v", ErrDeviceLost, err)
+				}
 				log.Printf("Error reading audio: %v", err)
+				time.Sleep(readRetryDelay)
 				continue
 			}
+			failures = 0
 
 			// Convert int32 samples to bytes (16-bit PCM)
 			audioBytes := a.convertToBytes()
@@ -89,26 +143,54 @@ func (a *PortaudioStreamer) StartCapture(ctx context.Context, audioData chan<- [
 				return ctx.Err()
 			default:
 				// Drop audio if channel is full
+				a.pool.Put(audioBytes)
 			}
 		}
 	}
 }
 
+// Ensure PortaudioStreamer implements Reconnector interface
+var _ Reconnector = (*PortaudioStreamer)(nil)
+
+// Reconnect opens the default input device again and reads one buffer from
+// it. PortAudio lists devices once per process and the player keeps it
+// initialized, so only devices known at startup are found, e.g. a headset
+// that comes back after a dropout; a device plugged in later needs a restart
+func (a *PortaudioStreamer) Reconnect() error {
+	if err := a.Close(); err != nil {
+		log.Printf("Failed to close lost input stream: %v", err)
+	}
+	a.stream = nil
+
+	if err := a.Open(); err != nil {
+		return fmt.Errorf("failed to open input device: %w", err)
+	}
+
+	if err := a.stream.Start(); err != nil {
+		return fmt.Errorf("failed to start input device: %w", err)
+	}
+	defer a.stream.Stop()
+	if err := a.stream.Read(); err != nil && !errors.Is(err, portaudio.InputOverflowed) {
+		return fmt.Errorf("failed to read from input device: %w", err)
+	}
+	return nil
+}
+
 func (a *PortaudioStreamer) convertToBytes() []byte {
-	var buf bytes.Buffer
-	for _, sample := range a.audioBuffer {
+	if a.resampler == nil && a.channels == a.config.InputChannels {
 		// Convert 32-bit to 16-bit
-		sample16 := int16(sample >> 16)
-		binary.Write(&buf, binary.LittleEndian, sample16)
+		return pcm.AppendInt32As16(binary.LittleEndian, a.pool.Get(), a.audioBuffer)
 	}
-	return buf.Bytes()
-}
 
-func GetDefaultConfig() PortaudioConfig {
-	return PortaudioConfig{
-		SampleRate:      44100,
-		FramesPerBuffer: 1024,
-		InputChannels:   1,
-		OutputChannels:  0,
+	samples := make([]int16, len(a.audioBuffer))
+	for i, sample := range a.audioBuffer {
+		samples[i] = int16(sample >> 16)
+	}
+	if a.resampler != nil {
+		samples = a.resampler.Process(samples)
+	}
+	if a.channels != a.config.InputChannels {
+		samples = pcm.FromMono(samples, a.config.InputChannels)
 	}
+	return pcm.AppendInt16(binary.LittleEndian, a.pool.Get(), samples)
 }
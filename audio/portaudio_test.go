@@ -0,0 +1,43 @@
+//go:build cgo
+
+package audio
+
+import (
+	"testing"
+
+	"github.com/d1nch8g/aihr/sound"
+)
+
+// newBenchmarkStreamer returns a streamer with a captured buffer of a
+// ramp, as Open leaves it for a device of the given rate. The benchmarks
+// return every chunk to the pool like the capture consumers do
+func newBenchmarkStreamer(deviceRate float64) *PortaudioStreamer {
+	config := GetDefaultConfig()
+	a := NewPortaudioStreamer(config)
+	a.channels = config.InputChannels
+	if deviceRate != config.SampleRate {
+		a.resampler = sound.NewResampler(deviceRate, config.SampleRate, a.channels)
+	}
+	for i := range a.audioBuffer {
+		a.audioBuffer[i] = int32(i%512-256) << 23
+	}
+	return a
+}
+
+func BenchmarkConvertToBytes(b *testing.B) {
+	a := newBenchmarkStreamer(44100)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(a.audioBuffer) * 4))
+	for b.Loop() {
+		a.pool.Put(a.convertToBytes())
+	}
+}
+
+func BenchmarkConvertToBytesResampled(b *testing.B) {
+	a := newBenchmarkStreamer(48000)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(a.audioBuffer) * 4))
+	for b.Loop() {
+		a.pool.Put(a.convertToBytes())
+	}
+}
@@ -0,0 +1,102 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"time"
+
+	"github.com/d1nch8g/aihr/stream"
+)
+
+// ReaderStreamer captures raw 16-bit PCM from an io.Reader instead of a
+// device, e.g. a named pipe or file in headless deployments
+type ReaderStreamer struct {
+	reader io.Reader
+	config StreamConfig
+	pace   bool // Deliver chunks in real time, for readers that are not live sources
+	pool   *stream.Pool
+}
+
+// Ensure ReaderStreamer implements AudioStreamer interface
+var _ AudioStreamer = (*ReaderStreamer)(nil)
+
+// NewReaderStreamer creates a streamer reading PCM from reader. When pace is
+// set, chunks are delivered no faster than real time
+func NewReaderStreamer(reader io.Reader, config StreamConfig, pace bool) *ReaderStreamer {
+	channels := max(config.InputChannels, 1)
+	return &ReaderStreamer{
+		reader: reader,
+		config: config,
+		pace:   pace,
+		pool:   stream.NewPool(config.FramesPerBuffer * channels * 2),
+	}
+}
+
+// BufferPool returns the pool of the captured chunks
+func (r *ReaderStreamer) BufferPool() *stream.Pool {
+	return r.pool
+}
+
+func (r *ReaderStreamer) Initialize() error { return nil }
+
+func (r *ReaderStreamer) Terminate() {}
+
+func (r *ReaderStreamer) Open() error { return nil }
+
+// Close closes the reader, standard input is left open for the process
+func (r *ReaderStreamer) Close() error {
+	if r.reader == os.Stdin {
+		return nil
+	}
+	if closer, ok := r.reader.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+func (r *ReaderStreamer) StartCapture(ctx context.Context, audioData chan<- []byte) error {
+	channels := max(r.config.InputChannels, 1)
+	chunkSize := r.config.FramesPerBuffer * channels * 2
+	chunkDuration := time.Duration(float64(r.config.FramesPerBuffer) / r.config.SampleRate * float64(time.Second))
+
+	next := time.Now()
+	for {
+		chunk := r.pool.Get()[:chunkSize]
+		n, err := io.ReadFull(r.reader, chunk)
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			if n > 0 {
+				r.send(ctx, audioData, chunk[:n])
+			}
+			// Input is exhausted, behave like a silent microphone
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		if err != nil {
+			return err
+		}
+
+		if r.pace {
+			next = next.Add(chunkDuration)
+			select {
+			case <-time.After(time.Until(next)):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		if err := r.send(ctx, audioData, chunk); err != nil {
+			return err
+		}
+	}
+}
+
+func (r *ReaderStreamer) send(ctx context.Context, audioData chan<- []byte, chunk []byte) error {
+	select {
+	case audioData <- chunk:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
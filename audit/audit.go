@@ -0,0 +1,71 @@
+// Package audit records administrative and data-access actions in an
+// append-only log for compliance reviews
+package audit
+
+import (
+	"os"
+	"os/user"
+	"time"
+)
+
+// ActorEnv overrides the name recorded as the actor of an action
+const ActorEnv = "AIHR_ACTOR"
+
+// Actions recorded in the log
+const (
+	ActionSessionCreate  = "session.create"
+	ActionSessionList    = "session.list"
+	ActionSessionView    = "session.view"
+	ActionSessionOutcome = "session.outcome"
+	ActionSessionScore   = "session.score"
+	ActionSessionExport  = "session.export"
+	ActionSessionDelete  = "session.delete"
+	ActionSessionUpload  = "session.upload"
+	ActionReportMail     = "report.mail"
+	ActionATSPush        = "ats.push"
+	ActionAnalyticsView  = "analytics.view"
+	ActionConfigView     = "config.view"
+	ActionConfigReload   = "config.reload"
+	ActionPromptChange   = "prompt.change"
+	ActionAuditExport    = "audit.export"
+)
+
+// Entry is one recorded action
+type Entry struct {
+	Time   time.Time `json:"time"`
+	Actor  string    `json:"actor"`
+	Action string    `json:"action"`
+	Target string    `json:"target,omitempty"` // Session ID or other object the action applies to
+	Detail string    `json:"detail,omitempty"`
+}
+
+// Log defines the interface for the audit trail. Entries can only be added
+type Log interface {
+	// Append records an entry
+	Append(entry Entry) error
+
+	// List returns all entries in the order they were recorded
+	List() ([]Entry, error)
+}
+
+// NewEntry creates an entry for the current actor
+func NewEntry(action, target, detail string) Entry {
+	return Entry{
+		Time:   time.Now().UTC(),
+		Actor:  CurrentActor(),
+		Action: action,
+		Target: target,
+		Detail: detail,
+	}
+}
+
+// CurrentActor returns the name from AIHR_ACTOR, or the operating system user
+func CurrentActor() string {
+	if actor := os.Getenv(ActorEnv); actor != "" {
+		return actor
+	}
+	if current, err := user.Current(); err == nil && current.Username != "" {
+		return current.Username
+	}
+	return "unknown"
+}
@@ -0,0 +1,59 @@
+package audit
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Export formats
+const (
+	FormatJSONL = "jsonl"
+	FormatCSV   = "csv"
+)
+
+// Filter returns the entries recorded at or after since, a zero time keeps all of them
+func Filter(entries []Entry, since time.Time) []Entry {
+	if since.IsZero() {
+		return entries
+	}
+	var filtered []Entry
+	for _, entry := range entries {
+		if !entry.Time.Before(since) {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
+}
+
+// Export writes the entries in the given format
+func Export(w io.Writer, entries []Entry, format string) error {
+	switch format {
+	case FormatJSONL:
+		encoder := json.NewEncoder(w)
+		for _, entry := range entries {
+			if err := encoder.Encode(entry); err != nil {
+				return fmt.Errorf("failed to export audit log: %w", err)
+			}
+		}
+		return nil
+
+	case FormatCSV:
+		writer := csv.NewWriter(w)
+		writer.Write([]string{"time", "actor", "action", "target", "detail"})
+		for _, entry := range entries {
+			writer.Write([]string{entry.Time.Format(time.RFC3339), entry.Actor, entry.Action, entry.Target,
+				entry.Detail})
+		}
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return fmt.Errorf("failed to export audit log: %w", err)
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("unsupported export format %q, use %s or %s", format, FormatJSONL, FormatCSV)
+	}
+}
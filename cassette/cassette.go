@@ -0,0 +1,154 @@
+// Package cassette records the responses of the speech recognition, language
+// model and speech synthesis providers on the first run and replays them on
+// later runs, so demos work without network, credentials or variability
+package cassette
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/d1nch8g/aihr/gpt"
+	"github.com/d1nch8g/aihr/stt"
+)
+
+// File is the index of a cassette inside its directory, synthesized audio is
+// kept next to it
+const File = "cassette.json"
+
+// Completion is a recorded language model response
+type Completion struct {
+	Key      string     `json:"key"` // Hash of the system and user messages
+	User     string     `json:"user"`
+	Response string     `json:"response"`
+	Usage    *gpt.Usage `json:"usage,omitempty"`
+}
+
+// Synthesis is recorded speech, Audio names the file with the audio stream
+type Synthesis struct {
+	Key   string `json:"key"` // Hash of the text and the synthesis options
+	Text  string `json:"text"`
+	Audio string `json:"audio"`
+}
+
+// Recognition is one recorded recognition stream
+type Recognition struct {
+	Utterances []Utterance `json:"utterances"`
+}
+
+// Utterance is a recognition result and its time from the start of the stream
+type Utterance struct {
+	AtMs int64 `json:"at_ms"`
+	stt.Utterance
+}
+
+// Cassette holds the recorded provider responses. A cassette that exists on
+// disk replays them, a new one records them
+type Cassette struct {
+	GPT []Completion  `json:"gpt"`
+	TTS []Synthesis   `json:"tts"`
+	STT []Recognition `json:"stt"`
+
+	dir       string
+	replaying bool
+	used      map[string][]bool // Replayed entries per kind
+	mutex     sync.Mutex
+}
+
+// Open loads the cassette in dir for replay, or prepares a new one for
+// recording when there is none yet
+func Open(dir string) (*Cassette, error) {
+	c := &Cassette{dir: dir, used: make(map[string][]bool)}
+	data, err := os.ReadFile(filepath.Join(dir, File))
+	if errors.Is(err, fs.ErrNotExist) {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create cassette directory: %w", err)
+		}
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("failed to decode cassette %s: %w", dir, err)
+	}
+	c.replaying = true
+	c.used["gpt"] = make([]bool, len(c.GPT))
+	c.used["tts"] = make([]bool, len(c.TTS))
+	c.used["stt"] = make([]bool, len(c.STT))
+	return c, nil
+}
+
+// Exists returns whether dir holds a recorded cassette
+func Exists(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, File))
+	return err == nil
+}
+
+// Replaying returns whether the cassette replays recorded responses
+func (c *Cassette) Replaying() bool {
+	return c.replaying
+}
+
+// save writes the index, it is called with the mutex held after every
+// recorded response so an interrupted demo keeps what was recorded
+func (c *Cassette) save() error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cassette: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(c.dir, File), data, 0o644); err != nil {
+		return fmt.Errorf("failed to save cassette: %w", err)
+	}
+	return nil
+}
+
+// next returns the index of the first unused entry of a kind with the key.
+// When the conversation took a different turn than the recording and no
+// entry matches, the first unused entry is replayed instead
+func (c *Cassette) next(kind string, keys []string, key string) (int, bool) {
+	used := c.used[kind]
+	fallback := -1
+	for i := range keys {
+		if used[i] {
+			continue
+		}
+		if keys[i] == key {
+			used[i] = true
+			return i, true
+		}
+		if fallback < 0 {
+			fallback = i
+		}
+	}
+	if fallback < 0 {
+		return 0, false
+	}
+	log.Printf("No recorded %s response matches, replaying the next one", kind)
+	used[fallback] = true
+	return fallback, true
+}
+
+// hash returns the key of the parts
+func hash(parts ...string) string {
+	digest := sha256.New()
+	for _, part := range parts {
+		digest.Write([]byte(part))
+		digest.Write([]byte{0})
+	}
+	return hex.EncodeToString(digest.Sum(nil)[:8])
+}
+
+// since returns the milliseconds from start
+func since(start time.Time) int64 {
+	return time.Since(start).Milliseconds()
+}
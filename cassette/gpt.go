@@ -0,0 +1,76 @@
+package cassette
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/d1nch8g/aihr/gpt"
+)
+
+// GPT records the completions of a language model client, or replays them
+// when the cassette is replaying
+type GPT struct {
+	cassette *Cassette
+	client   gpt.GPTClient // Nil when replaying
+}
+
+// Ensure GPT implements GPTClient interface
+var _ gpt.GPTClient = (*GPT)(nil)
+
+// Ensure GPT implements UsageReporter interface
+var _ gpt.UsageReporter = (*GPT)(nil)
+
+// NewGPT wraps client, which may be nil when the cassette is replaying
+func NewGPT(cassette *Cassette, client gpt.GPTClient) *GPT {
+	return &GPT{cassette: cassette, client: client}
+}
+
+// Complete returns the recorded or the live response
+func (g *GPT) Complete(systemMessage, userMessage string) (string, error) {
+	text, _, err := g.CompleteWithUsage(systemMessage, userMessage)
+	return text, err
+}
+
+// CompleteWithUsage returns the recorded or the live response with its token usage
+func (g *GPT) CompleteWithUsage(systemMessage, userMessage string) (string, gpt.Usage, error) {
+	key := hash(systemMessage, userMessage)
+	c := g.cassette
+	if c.Replaying() {
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+		keys := make([]string, len(c.GPT))
+		for i, completion := range c.GPT {
+			keys[i] = completion.Key
+		}
+		i, ok := c.next("gpt", keys, key)
+		if !ok {
+			return "", gpt.Usage{}, fmt.Errorf("no recorded response left for %q", userMessage)
+		}
+		var usage gpt.Usage
+		if c.GPT[i].Usage != nil {
+			usage = *c.GPT[i].Usage
+		}
+		return c.GPT[i].Response, usage, nil
+	}
+
+	completion := Completion{Key: key, User: userMessage}
+	var usage gpt.Usage
+	var err error
+	if reporter, ok := g.client.(gpt.UsageReporter); ok {
+		completion.Response, usage, err = reporter.CompleteWithUsage(systemMessage, userMessage)
+		completion.Usage = &usage
+	} else {
+		completion.Response, err = g.client.Complete(systemMessage, userMessage)
+	}
+	if err != nil {
+		return completion.Response, usage, err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.GPT = append(c.GPT, completion)
+	if err := c.save(); err != nil {
+		log.Printf("Failed to record response: %v", err)
+	}
+	return completion.Response, usage, nil
+}
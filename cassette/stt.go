@@ -0,0 +1,143 @@
+package cassette
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/d1nch8g/aihr/stt"
+)
+
+// STT records the results of a recognizer, or replays them when the cassette
+// is replaying. Replayed streams ignore the captured audio and send the
+// recorded results of the stream at their recorded times
+type STT struct {
+	cassette *Cassette
+	client   stt.STTClient // Nil when replaying
+}
+
+// Ensure STT implements STTClient interface
+var _ stt.STTClient = (*STT)(nil)
+
+// Ensure STT implements WordRecognizer interface
+var _ stt.WordRecognizer = (*STT)(nil)
+
+// NewSTT wraps client, which may be nil when the cassette is replaying
+func NewSTT(cassette *Cassette, client stt.STTClient) *STT {
+	return &STT{cassette: cassette, client: client}
+}
+
+// StreamRecognize sends the text of the recorded or the live results
+func (s *STT) StreamRecognize(ctx context.Context, audioData <-chan []byte, results chan<- string, sampleRate int64) error {
+	utterances := make(chan stt.Utterance)
+	go func() {
+		defer close(results)
+		for utterance := range utterances {
+			results <- utterance.Text
+		}
+	}()
+	return s.StreamRecognizeWords(ctx, audioData, utterances, sampleRate)
+}
+
+// StreamRecognizeWords sends the recorded or the live results with word timings
+func (s *STT) StreamRecognizeWords(ctx context.Context, audioData <-chan []byte, results chan<- stt.Utterance, sampleRate int64) error {
+	if s.cassette.Replaying() {
+		return s.replay(ctx, audioData, results)
+	}
+
+	start := time.Now()
+	live := make(chan stt.Utterance)
+	done := make(chan error, 1)
+	go func() {
+		done <- s.recognize(ctx, audioData, live, sampleRate)
+	}()
+
+	var recognition Recognition
+	for utterance := range live {
+		recognition.Utterances = append(recognition.Utterances, Utterance{AtMs: since(start), Utterance: utterance})
+		select {
+		case results <- utterance:
+		case <-ctx.Done():
+		}
+	}
+	close(results)
+	err := <-done
+
+	// Every stream is recorded, even without results, so replayed streams
+	// line up with the turns of the interview
+	c := s.cassette
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.STT = append(c.STT, recognition)
+	if saveErr := c.save(); saveErr != nil {
+		log.Printf("Failed to record recognition: %v", saveErr)
+	}
+	return err
+}
+
+// recognize streams audio to the wrapped client. Its results channel is
+// closed when recognition ends
+func (s *STT) recognize(ctx context.Context, audioData <-chan []byte, results chan<- stt.Utterance, sampleRate int64) error {
+	if recognizer, ok := s.client.(stt.WordRecognizer); ok {
+		return recognizer.StreamRecognizeWords(ctx, audioData, results, sampleRate)
+	}
+
+	texts := make(chan string)
+	go func() {
+		defer close(results)
+		for text := range texts {
+			results <- stt.Utterance{Text: text}
+		}
+	}()
+	return s.client.StreamRecognize(ctx, audioData, texts, sampleRate)
+}
+
+// replay sends the results of the next recorded stream while the captured
+// audio is drained, then waits until the audio ends or the stream is canceled
+func (s *STT) replay(ctx context.Context, audioData <-chan []byte, results chan<- stt.Utterance) error {
+	defer close(results)
+
+	c := s.cassette
+	c.mutex.Lock()
+	keys := make([]string, len(c.STT))
+	i, ok := c.next("stt", keys, "")
+	var recognition Recognition
+	if ok {
+		recognition = c.STT[i]
+	}
+	c.mutex.Unlock()
+
+	start := time.Now()
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	next := 0
+	for {
+		select {
+		case _, ok := <-audioData:
+			if !ok {
+				return nil
+			}
+		case <-timer.C:
+			for next < len(recognition.Utterances) && recognition.Utterances[next].AtMs <= since(start) {
+				select {
+				case results <- recognition.Utterances[next].Utterance:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+				next++
+			}
+			if next < len(recognition.Utterances) {
+				timer.Reset(time.Duration(recognition.Utterances[next].AtMs-since(start)) * time.Millisecond)
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (s *STT) Close() error {
+	if s.client == nil {
+		return nil
+	}
+	return s.client.Close()
+}
@@ -0,0 +1,127 @@
+package cassette
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/d1nch8g/aihr/tts"
+)
+
+// replayChunkSize is the size of the audio chunks replayed speech is sent in
+const replayChunkSize = 8192
+
+// TTS records the audio of a synthesizer, or replays it when the cassette is replaying
+type TTS struct {
+	cassette    *Cassette
+	synthesizer tts.Synthesizer // Nil when replaying
+}
+
+// Ensure TTS implements Synthesizer interface
+var _ tts.Synthesizer = (*TTS)(nil)
+
+// Ensure TTS implements MarkupRenderer interface
+var _ tts.MarkupRenderer = (*TTS)(nil)
+
+// NewTTS wraps synthesizer, which may be nil when the cassette is replaying
+func NewTTS(cassette *Cassette, synthesizer tts.Synthesizer) *TTS {
+	return &TTS{cassette: cassette, synthesizer: synthesizer}
+}
+
+// RenderMarkup passes prosody markup to the recorded synthesizer when it
+// supports it. When replaying, the plain text does not match the recorded
+// markup and speech is replayed in the recorded order
+func (t *TTS) RenderMarkup(markup tts.Markup) string {
+	if renderer, ok := t.synthesizer.(tts.MarkupRenderer); ok {
+		return renderer.RenderMarkup(markup)
+	}
+	return markup.Plain()
+}
+
+// SynthesizeToStreamWithContext sends the recorded or the live audio to audioData
+func (t *TTS) SynthesizeToStreamWithContext(ctx context.Context, text string, options tts.SynthesisOptions, audioData chan<- []byte) error {
+	key := hash(text, options.Voice, options.Role, strconv.FormatFloat(options.Speed, 'f', -1, 64))
+	c := t.cassette
+	if c.Replaying() {
+		defer close(audioData)
+		audio, err := t.recorded(key, text)
+		if err != nil {
+			return err
+		}
+		for len(audio) > 0 {
+			chunk := audio[:min(replayChunkSize, len(audio))]
+			audio = audio[len(chunk):]
+			select {
+			case audioData <- chunk:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		return nil
+	}
+
+	live := make(chan []byte)
+	done := make(chan error, 1)
+	go func() {
+		done <- t.synthesizer.SynthesizeToStreamWithContext(ctx, text, options, live)
+	}()
+
+	defer close(audioData)
+	var audio bytes.Buffer
+	for chunk := range live {
+		audio.Write(chunk)
+		select {
+		case audioData <- chunk:
+		case <-ctx.Done():
+		}
+	}
+	if err := <-done; err != nil || ctx.Err() != nil {
+		// Incomplete speech is not recorded
+		return err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	name := fmt.Sprintf("tts-%03d.audio", len(c.TTS)+1)
+	if err := os.WriteFile(filepath.Join(c.dir, name), audio.Bytes(), 0o644); err != nil {
+		log.Printf("Failed to record speech: %v", err)
+		return nil
+	}
+	c.TTS = append(c.TTS, Synthesis{Key: key, Text: text, Audio: name})
+	if err := c.save(); err != nil {
+		log.Printf("Failed to record speech: %v", err)
+	}
+	return nil
+}
+
+// recorded returns the recorded audio of the text
+func (t *TTS) recorded(key, text string) ([]byte, error) {
+	c := t.cassette
+	c.mutex.Lock()
+	keys := make([]string, len(c.TTS))
+	for i, synthesis := range c.TTS {
+		keys[i] = synthesis.Key
+	}
+	i, ok := c.next("tts", keys, key)
+	c.mutex.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("no recorded speech left for %q", text)
+	}
+
+	audio, err := os.ReadFile(filepath.Join(c.dir, filepath.Base(c.TTS[i].Audio)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recorded speech: %w", err)
+	}
+	return audio, nil
+}
+
+func (t *TTS) Close() error {
+	if t.synthesizer == nil {
+		return nil
+	}
+	return t.synthesizer.Close()
+}
@@ -0,0 +1,1062 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/d1nch8g/aihr/aihr"
+	"github.com/d1nch8g/aihr/analytics"
+	"github.com/d1nch8g/aihr/audit"
+	"github.com/d1nch8g/aihr/config"
+	"github.com/d1nch8g/aihr/doctor"
+	"github.com/d1nch8g/aihr/export"
+	"github.com/d1nch8g/aihr/gpt"
+	"github.com/d1nch8g/aihr/prompts"
+	"github.com/d1nch8g/aihr/redact"
+	"github.com/d1nch8g/aihr/replay"
+	"github.com/d1nch8g/aihr/screening"
+	"github.com/d1nch8g/aihr/session"
+	"github.com/d1nch8g/aihr/simulator"
+	"github.com/d1nch8g/aihr/stt"
+	"github.com/d1nch8g/aihr/tts"
+)
+
+// runCommand executes a subcommand and returns the process exit code
+func runCommand(args []string) int {
+	switch args[0] {
+	case "run":
+		return runRunCommand(args[1:])
+	case "simulate":
+		return runSimulateCommand(args[1:])
+	case "load":
+		return runLoadCommand(args[1:])
+	case "replay":
+		return runReplayCommand(args[1:])
+	case "screen":
+		return runScreenCommand(args[1:])
+	case "config":
+		return runConfigCommand(args[1:])
+	case "session":
+		return runSessionCommand(args[1:])
+	case "export":
+		return runExportCommand(args[1:])
+	case "analytics":
+		return runAnalyticsCommand(args[1:])
+	case "audit":
+		return runAuditCommand(args[1:])
+	case "doctor":
+		return runDoctorCommand(args[1:])
+	case "prompts":
+		return runPromptsCommand(args[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command: %s\n", args[0])
+		return 2
+	}
+}
+
+// sessionOverrides maps the "aihr run" flags that override the configuration
+// of one interview to the variables they replace
+var sessionOverrides = []struct {
+	flag, variable, usage string
+}{
+	{"prompt-file", "SYSTEM_PROMPT_FILE", "interview template (system prompt) file"},
+	{"language", "LANGUAGE", "interview language, e.g. ru-RU or en-US"},
+	{"voice", "VOICE", "TTS voice"},
+	{"max-duration", "MAX_DURATION", "close the interview after this long, e.g. 45m"},
+	{"stt", "STT_PROVIDER", "speech recognition provider"},
+	{"tts", "TTS_PROVIDER", "speech synthesis provider"},
+	{"gpt", "GPT_PROVIDER", "language model provider"},
+}
+
+// runRunCommand handles "aihr run [--text|--typed] [overrides]". In text mode
+// answers are typed and responses printed, without STT, TTS or audio devices.
+// With --typed answers are typed and responses printed and spoken. The override
+// flags replace the configured template, language, voice, duration limit and
+// providers for this interview only
+func runRunCommand(args []string) int {
+	flags := flag.NewFlagSet("run", flag.ContinueOnError)
+	text := flags.Bool("text", false, "type answers and print responses instead of using audio")
+	typed := flags.Bool("typed", false, "type answers, responses are printed and spoken")
+	values := make([]*string, len(sessionOverrides))
+	for i, override := range sessionOverrides {
+		values[i] = flags.String(override.flag, "", override.usage+", overrides "+override.variable)
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() > 0 || (*text && *typed) {
+		fmt.Fprintln(os.Stderr, "Usage: aihr run [--text|--typed] [--prompt-file file] [--language code] [--voice name] "+
+			"[--max-duration duration] [--stt provider] [--tts provider] [--gpt provider]")
+		return 2
+	}
+
+	for i, override := range sessionOverrides {
+		if *values[i] != "" {
+			config.Override(override.variable, *values[i])
+		}
+	}
+
+	switch {
+	case *text:
+		runInterview(aihr.WithTextIO(os.Stdin, os.Stdout))
+	case *typed:
+		runInterview(aihr.WithTypedIO(os.Stdin, os.Stdout))
+	default:
+		runInterview()
+	}
+	return 0
+}
+
+// runSimulateCommand handles "aihr simulate <script.json>", which plays a
+// simulated candidate against the engine in text mode and reports response
+// latency and check results. It fails when a check fails
+func runSimulateCommand(args []string) int {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "Usage: aihr simulate <script.json>")
+		return 2
+	}
+
+	script, err := simulator.LoadScript(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Configuration is invalid: %v\n", err)
+		return 1
+	}
+
+	// The candidate needs the GPT client, so the simulator is attached once the interview is built
+	exchange := &simulatorExchange{}
+	interview, err := aihr.New(
+		aihr.WithConfig(cfg),
+		aihr.WithTextExchange(exchange),
+	)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize interview: %v\n", err)
+		return 1
+	}
+	defer interview.Stop()
+
+	sim := simulator.New(script.Candidate(interview.Components.GPT), script.MaxTurns)
+	exchange.Simulator = sim
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := interview.Start(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "Simulation stopped: %v\n", err)
+		return 1
+	}
+
+	turns := sim.Turns()
+	for i, turn := range turns {
+		fmt.Printf("%d. AI: %s\n   Candidate: %s\n   (response in %s)\n", i+1, turn.Question, turn.Answer,
+			turn.Latency.Round(time.Millisecond))
+	}
+	if len(turns) > 0 {
+		fmt.Printf("   AI: %s\n", turns[len(turns)-1].Response)
+	}
+
+	latency := simulator.Latencies(turns)
+	fmt.Printf("\nResponse latency over %d turns: avg %s, median %s, p95 %s, max %s\n", latency.Count,
+		latency.Average.Round(time.Millisecond), latency.Median.Round(time.Millisecond),
+		latency.P95.Round(time.Millisecond), latency.Max.Round(time.Millisecond))
+
+	failed := 0
+	for _, result := range script.Evaluate(turns) {
+		status := "PASS"
+		if !result.Passed {
+			status = "FAIL"
+			failed++
+		}
+		expectation := "matches"
+		if result.Check.Absent {
+			expectation = "does not match"
+		}
+		fmt.Printf("%s turn %d response %s %q\n", status, result.Turn, expectation, result.Check.Pattern)
+	}
+
+	if failed > 0 {
+		fmt.Printf("%d checks failed\n", failed)
+		return 1
+	}
+	return 0
+}
+
+// loadSession is the outcome of one simulated session of a load test
+type loadSession struct {
+	turns    []simulator.Turn
+	duration time.Duration
+	err      error
+}
+
+// runLoadCommand handles "aihr load [--sessions n] [--ramp duration] <script.json>",
+// which runs simulated sessions concurrently and reports the response latency
+// per session and overall with the resource usage of the process, to check
+// the capacity of the providers and the host before interview days
+func runLoadCommand(args []string) int {
+	flags := flag.NewFlagSet("load", flag.ContinueOnError)
+	sessions := flags.Int("sessions", 10, "number of concurrent simulated sessions")
+	ramp := flags.Duration("ramp", 0, "delay between the starts of two sessions")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 || *sessions < 1 || *ramp < 0 {
+		fmt.Fprintln(os.Stderr, "Usage: aihr load [--sessions n] [--ramp duration] <script.json>")
+		return 2
+	}
+
+	script, err := simulator.LoadScript(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Configuration is invalid: %v\n", err)
+		return 1
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	monitor := simulator.StartMonitor(100 * time.Millisecond)
+	results := make([]loadSession, *sessions)
+	var wg sync.WaitGroup
+	for i := range results {
+		if i > 0 && *ramp > 0 {
+			select {
+			case <-time.After(*ramp):
+			case <-ctx.Done():
+			}
+		}
+		if ctx.Err() != nil {
+			results[i].err = ctx.Err()
+			continue
+		}
+
+		wg.Add(1)
+		go func(result *loadSession) {
+			defer wg.Done()
+			started := time.Now()
+			result.turns, result.err = runLoadSession(ctx, cfg, script)
+			result.duration = time.Since(started)
+		}(&results[i])
+	}
+	wg.Wait()
+	resources := monitor.Stop()
+
+	var all []simulator.Turn
+	failed := 0
+	table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "SESSION\tTURNS\tMEDIAN\tP95\tDURATION\tERROR")
+	for i, result := range results {
+		latency := simulator.Latencies(result.turns)
+		errText := ""
+		if result.err != nil {
+			errText = result.err.Error()
+			failed++
+		}
+		fmt.Fprintf(table, "%d\t%d\t%s\t%s\t%s\t%s\n", i+1, latency.Count, latency.Median.Round(time.Millisecond),
+			latency.P95.Round(time.Millisecond), result.duration.Round(time.Millisecond), errText)
+		all = append(all, result.turns...)
+	}
+	table.Flush()
+
+	latency := simulator.Latencies(all)
+	fmt.Printf("\nResponse latency over %d turns of %d sessions: avg %s, median %s, p95 %s, max %s\n", latency.Count,
+		*sessions, latency.Average.Round(time.Millisecond), latency.Median.Round(time.Millisecond),
+		latency.P95.Round(time.Millisecond), latency.Max.Round(time.Millisecond))
+	fmt.Printf("Resources over %s: peak %d goroutines, peak heap %d MiB, CPU time %s\n",
+		resources.Elapsed.Round(time.Millisecond), resources.PeakGoroutines, resources.PeakHeap>>20,
+		resources.CPUTime.Round(time.Millisecond))
+
+	if failed > 0 {
+		fmt.Printf("%d of %d sessions failed\n", failed, *sessions)
+		return 1
+	}
+	return 0
+}
+
+// runLoadSession runs one simulated session of a load test and returns its turns
+func runLoadSession(ctx context.Context, cfg *config.Config, script *simulator.Script) ([]simulator.Turn, error) {
+	exchange := &simulatorExchange{}
+	interview, err := aihr.New(
+		aihr.WithConfig(cfg),
+		aihr.WithTextExchange(exchange),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize interview: %w", err)
+	}
+	defer interview.Stop()
+
+	sim := simulator.New(script.Candidate(interview.Components.GPT), script.MaxTurns)
+	exchange.Simulator = sim
+	err = interview.Start(ctx)
+	return sim.Turns(), err
+}
+
+// simulatorExchange forwards the text exchange to a simulator attached after construction
+type simulatorExchange struct {
+	*simulator.Simulator
+}
+
+// runReplayCommand handles "aihr replay [--update] <fixture-dir>...", which
+// plays recorded fixtures through the engine and compares the conversation
+// with the golden transcripts. --update rewrites the golden transcripts
+func runReplayCommand(args []string) int {
+	flags := flag.NewFlagSet("replay", flag.ContinueOnError)
+	update := flags.Bool("update", false, "rewrite golden transcripts with the actual conversation")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "Usage: aihr replay [--update] <fixture-dir>...")
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	failed := 0
+	for _, dir := range flags.Args() {
+		if err := replayFixture(ctx, dir, *update); err != nil {
+			fmt.Printf("FAIL %s\n%v\n", dir, err)
+			failed++
+			continue
+		}
+		fmt.Printf("ok   %s\n", dir)
+	}
+
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+// replayFixture runs one fixture, using real providers for the parts the fixture does not mock
+func replayFixture(ctx context.Context, dir string, update bool) error {
+	fixture, err := replay.LoadFixture(dir)
+	if err != nil {
+		return err
+	}
+
+	var sttClient stt.STTClient
+	var gptClient gpt.GPTClient
+	if len(fixture.Utterances) == 0 || len(fixture.Responses) == 0 {
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("fixture needs real providers, configuration is invalid: %w", err)
+		}
+		if len(fixture.Utterances) == 0 {
+			cfg.Audio.SampleRate = fixture.SampleRate
+			if sttClient, err = aihr.NewSTT(cfg); err != nil {
+				return err
+			}
+			defer sttClient.Close()
+		}
+		if len(fixture.Responses) == 0 {
+			if gptClient, err = aihr.NewGPT(cfg); err != nil {
+				return err
+			}
+		}
+	}
+
+	turns, err := replay.Run(ctx, fixture, sttClient, gptClient)
+	if err != nil {
+		return err
+	}
+
+	if update {
+		return replay.WriteGolden(dir, replay.Golden{Turns: turns})
+	}
+
+	golden, err := replay.LoadGolden(dir)
+	if err != nil {
+		return err
+	}
+	if diffs := replay.Compare(golden, turns, len(fixture.Responses) > 0); len(diffs) > 0 {
+		return errors.New(strings.Join(diffs, "\n"))
+	}
+	return nil
+}
+
+// runScreenCommand handles "aihr screen <submission-dir>...", which transcribes
+// and scores the recorded answers of asynchronous screenings. Every submission
+// is saved and reported like an interview
+func runScreenCommand(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "Usage: aihr screen <submission-dir>...")
+		return 2
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load config: %v\n", err)
+		return 1
+	}
+	screener, err := aihr.NewScreener(cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer screener.STT.Close()
+
+	var store session.Store
+	if cfg.Storage.SessionDir != "" {
+		if store, err = session.NewFileStore(cfg.Storage.SessionDir); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	failed := 0
+	for _, dir := range args {
+		submission, err := screening.LoadSubmission(dir)
+		if err == nil {
+			var record session.Record
+			if record, err = screener.Evaluate(ctx, submission); err == nil {
+				err = saveScreening(cfg, store, record, submission)
+			}
+		}
+		if err != nil {
+			fmt.Printf("FAIL %s\n%v\n", dir, err)
+			failed++
+			continue
+		}
+		fmt.Printf("ok   %s\n", dir)
+	}
+
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+// saveScreening saves and delivers the record of a screening like the record
+// of an interview, signed with the recordings of the submission
+func saveScreening(cfg *config.Config, store session.Store, record session.Record, submission *screening.Submission) error {
+	if cfg.Storage.SigningKey != "" {
+		files := make([]string, len(submission.Answers))
+		for i, recording := range submission.Answers {
+			files[i] = submission.AudioPath(recording)
+		}
+		if err := session.Sign(&record, []byte(cfg.Storage.SigningKey), files); err != nil {
+			log.Printf("Failed to sign session: %v", err)
+		}
+	}
+	if store != nil {
+		if err := store.Save(record); err != nil {
+			return fmt.Errorf("failed to save session: %w", err)
+		}
+		recordAudit(cfg.Storage.AuditLog, audit.ActionSessionCreate, record.ID, session.ModeScreening)
+	}
+	deliverSession(cfg, record)
+	return nil
+}
+
+// runConfigCommand handles "aihr config check", which prints the resolved configuration
+func runConfigCommand(args []string) int {
+	if len(args) == 0 || args[0] != "check" {
+		fmt.Fprintln(os.Stderr, "Usage: aihr config check")
+		return 2
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Configuration is invalid: %v\n", err)
+		return 1
+	}
+
+	recordAudit(cfg.Storage.AuditLog, audit.ActionConfigView, "", "")
+	config.WriteSummary(os.Stdout, cfg)
+	return 0
+}
+
+// runSessionCommand handles "aihr session list", "aihr session show [--anonymize] <id>",
+// "aihr session outcome <id> <hired|rejected>", "aihr session scores <file.csv>",
+// "aihr session delete <id>" and "aihr session verify <id>" for stored
+// sessions. Every access is recorded in the audit log
+func runSessionCommand(args []string) int {
+	usage := "Usage: aihr session list | aihr session show [--anonymize] <id> | aihr session events <id> | aihr session outcome <id> <hired|rejected> | " +
+		"aihr session scores <file.csv> | aihr session delete <id> | aihr session verify <id>"
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+
+	store, storage, err := openSessionStore()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	switch {
+	case args[0] == "list" && len(args) == 1:
+		records, err := store.List()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to list sessions: %v\n", err)
+			return 1
+		}
+		table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(table, "ID\tSTARTED\tANSWERS\tOUTCOME")
+		for _, record := range records {
+			answers := strconv.Itoa(len(record.Answers))
+			if record.Aborted {
+				answers += " (partial)"
+			}
+			fmt.Fprintf(table, "%s\t%s\t%s\t%s\n", record.ID, record.StartedAt.Format("2006-01-02 15:04"),
+				answers, record.Outcome)
+		}
+		table.Flush()
+		recordAudit(storage.AuditLog, audit.ActionSessionList, "", fmt.Sprintf("%d sessions", len(records)))
+		return 0
+
+	case args[0] == "show" && len(args) == 2:
+		record, err := store.Load(args[1])
+		if errors.Is(err, session.ErrNotFound) {
+			fmt.Fprintf(os.Stderr, "Session %s not found\n", args[1])
+			return 1
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		recordAudit(storage.AuditLog, audit.ActionSessionView, record.ID, "transcript")
+		session.WriteTranscript(os.Stdout, record)
+		return 0
+
+	case args[0] == "show" && len(args) == 3 && args[1] == "--anonymize":
+		record, err := store.Load(args[2])
+		if errors.Is(err, session.ErrNotFound) {
+			fmt.Fprintf(os.Stderr, "Session %s not found\n", args[2])
+			return 1
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		anonymized, err := anonymizeSession(record)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		recordAudit(storage.AuditLog, audit.ActionSessionView, record.ID, "anonymized transcript "+anonymized.ID)
+		session.WriteTranscript(os.Stdout, anonymized)
+		return 0
+
+	case args[0] == "events" && len(args) == 2:
+		eventLog, ok := store.(session.EventLog)
+		if !ok {
+			fmt.Fprintln(os.Stderr, "Session store does not keep events")
+			return 1
+		}
+		events, err := eventLog.Events(args[1])
+		if errors.Is(err, session.ErrNotFound) {
+			fmt.Fprintf(os.Stderr, "No events recorded for session %s\n", args[1])
+			return 1
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		recordAudit(storage.AuditLog, audit.ActionSessionView, args[1], "events")
+		session.WriteEvents(os.Stdout, events)
+		return 0
+
+	case args[0] == "outcome" && len(args) == 3:
+		outcome := args[2]
+		if outcome != session.OutcomeHired && outcome != session.OutcomeRejected {
+			fmt.Fprintln(os.Stderr, usage)
+			return 2
+		}
+		record, err := store.Load(args[1])
+		if errors.Is(err, session.ErrNotFound) {
+			fmt.Fprintf(os.Stderr, "Session %s not found\n", args[1])
+			return 1
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		record.Outcome = outcome
+		if err := store.Save(record); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		recordAudit(storage.AuditLog, audit.ActionSessionOutcome, record.ID, outcome)
+		fmt.Printf("Session %s marked as %s\n", record.ID, outcome)
+		return 0
+
+	case args[0] == "scores" && len(args) == 2:
+		file, err := os.Open(args[1])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		scores, err := session.ReadHumanScores(file, time.Now())
+		file.Close()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+
+		status := 0
+		for id, interviewers := range scores {
+			record, err := store.Load(id)
+			if errors.Is(err, session.ErrNotFound) {
+				fmt.Fprintf(os.Stderr, "Session %s not found\n", id)
+				status = 1
+				continue
+			}
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return 1
+			}
+			names := make([]string, len(interviewers))
+			for i, score := range interviewers {
+				record.SetHumanScore(score)
+				names[i] = score.Interviewer
+			}
+			if err := store.Save(record); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return 1
+			}
+			recordAudit(storage.AuditLog, audit.ActionSessionScore, record.ID, strings.Join(names, ", "))
+			fmt.Printf("Session %s scored by %s\n", record.ID, strings.Join(names, ", "))
+		}
+		return status
+
+	case args[0] == "verify" && len(args) == 2:
+		record, err := store.Load(args[1])
+		if errors.Is(err, session.ErrNotFound) {
+			fmt.Fprintf(os.Stderr, "Session %s not found\n", args[1])
+			return 1
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		if storage.SigningKey == "" {
+			fmt.Fprintln(os.Stderr, "SIGNING_KEY is not set")
+			return 1
+		}
+		recordAudit(storage.AuditLog, audit.ActionSessionView, record.ID, "verify")
+		if err := session.Verify(record, []byte(storage.SigningKey)); err != nil {
+			fmt.Fprintf(os.Stderr, "Session %s failed verification: %v\n", record.ID, err)
+			return 1
+		}
+		fmt.Printf("Session %s is unmodified, signed %s\n", record.ID, record.Signature.SignedAt.Format(time.RFC3339))
+		for _, file := range record.Signature.Files {
+			fmt.Printf("  %s  %s\n", file.SHA256, file.Path)
+		}
+		return 0
+
+	case args[0] == "delete" && len(args) == 2:
+		err := store.Delete(args[1])
+		if errors.Is(err, session.ErrNotFound) {
+			fmt.Fprintf(os.Stderr, "Session %s not found\n", args[1])
+			return 1
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		recordAudit(storage.AuditLog, audit.ActionSessionDelete, args[1], "")
+		fmt.Printf("Session %s deleted\n", args[1])
+		return 0
+
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+}
+
+// runAnalyticsCommand handles "aihr analytics", which aggregates stored
+// sessions to show how well questions work, "aihr analytics calibration",
+// which compares the AI scores with the imported human scores, and "aihr
+// analytics bias", which compares the scores of candidate cohorts
+func runAnalyticsCommand(args []string) int {
+	usage := "Usage: aihr analytics | aihr analytics calibration | aihr analytics bias [--cohorts list] [--alpha level]"
+	calibration := len(args) == 1 && args[0] == "calibration"
+	bias := len(args) > 0 && args[0] == "bias"
+	if len(args) > 0 && !calibration && !bias {
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+
+	var cohorts []string
+	var alpha float64
+	if bias {
+		flags := flag.NewFlagSet("analytics bias", flag.ContinueOnError)
+		cohortsFlag := flags.String("cohorts", strings.Join(analytics.Cohorts, ","), "cohorts to compare, comma separated")
+		flags.Float64Var(&alpha, "alpha", 0.05, "significance level of flagged disparities")
+		if err := flags.Parse(args[1:]); err != nil {
+			return 2
+		}
+		if flags.NArg() > 0 || alpha <= 0 || alpha >= 1 {
+			fmt.Fprintln(os.Stderr, usage)
+			return 2
+		}
+		for _, cohort := range strings.Split(*cohortsFlag, ",") {
+			if cohort = strings.TrimSpace(cohort); cohort != "" {
+				if !slices.Contains(analytics.Cohorts, cohort) {
+					fmt.Fprintf(os.Stderr, "Unknown cohort %q, expected %s\n", cohort, strings.Join(analytics.Cohorts, ", "))
+					return 2
+				}
+				cohorts = append(cohorts, cohort)
+			}
+		}
+	}
+
+	store, storage, err := openSessionStore()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	records, err := store.List()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to list sessions: %v\n", err)
+		return 1
+	}
+
+	recordAudit(storage.AuditLog, audit.ActionAnalyticsView, "", fmt.Sprintf("%d sessions", len(records)))
+	if calibration {
+		analytics.WriteCalibration(os.Stdout, analytics.Calibrate(records))
+		return 0
+	}
+	if bias {
+		groups, err := analytics.AuditBias(records, cohorts, alpha)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+		analytics.WriteBias(os.Stdout, groups, alpha)
+		return 0
+	}
+	analytics.WriteSummary(os.Stdout, analytics.Aggregate(records))
+	analytics.WriteVariants(os.Stdout, analytics.ByVariant(records))
+	return 0
+}
+
+// runPromptsCommand handles "aihr prompts list", "aihr prompts show <ref>",
+// "aihr prompts diff <ref> <ref>" and "aihr prompts pin <name>[@<version>]"
+// for the versioned interview templates. A reference is a name or name@version
+func runPromptsCommand(args []string) int {
+	usage := "Usage: aihr prompts list | aihr prompts show <ref> | aihr prompts diff <ref> <ref> | " +
+		"aihr prompts pin <name>[@<version>]"
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+
+	registry, err := config.LoadPromptRegistry()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	switch {
+	case args[0] == "list" && len(args) == 1:
+		table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(table, "NAME\tVERSION\tHASH\tSOURCE\tPINNED")
+		for _, name := range registry.Names() {
+			pinned := registry.Pinned(name)
+			for _, version := range registry.Versions(name) {
+				source, pin := "PROMPT_DIR", ""
+				if version.Builtin {
+					source = "embedded"
+				}
+				if version.Version == pinned {
+					pin = "yes"
+				}
+				fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\n", name, version.Version, version.Hash, source, pin)
+			}
+		}
+		table.Flush()
+		return 0
+
+	case args[0] == "show" && len(args) == 2:
+		version, err := registry.Resolve(args[1])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		fmt.Printf("# %s (%s)\n%s\n", version.Ref(), version.Hash, version.Text)
+		return 0
+
+	case args[0] == "diff" && len(args) == 3:
+		from, err := registry.Resolve(args[1])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		to, err := registry.Resolve(args[2])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		prompts.WriteDiff(os.Stdout, from, to)
+		return 0
+
+	case args[0] == "pin" && len(args) == 2:
+		name, version, _ := strings.Cut(args[1], "@")
+		if err := registry.Pin(name, version); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		if storage, err := config.LoadStorageConfig(); err == nil {
+			recordAudit(storage.AuditLog, audit.ActionPromptChange, "", "pin: "+name+"@"+registry.Pinned(name))
+		}
+		if version == "" {
+			fmt.Printf("Prompt %s unpinned, the newest version is used\n", name)
+		} else {
+			fmt.Printf("Prompt %s pinned to %s\n", name, registry.Pinned(name))
+		}
+		return 0
+
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+}
+
+// runAuditCommand handles "aihr audit export [--format jsonl|csv] [--since YYYY-MM-DD]",
+// which prints the audit log for compliance reviews
+func runAuditCommand(args []string) int {
+	usage := "Usage: aihr audit export [--format jsonl|csv] [--since YYYY-MM-DD]"
+	if len(args) == 0 || args[0] != "export" {
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+
+	flags := flag.NewFlagSet("audit export", flag.ContinueOnError)
+	format := flags.String("format", audit.FormatJSONL, "export format, jsonl or csv")
+	sinceFlag := flags.String("since", "", "only export entries recorded on or after this date")
+	if err := flags.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if flags.NArg() > 0 || (*format != audit.FormatJSONL && *format != audit.FormatCSV) {
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+
+	var since time.Time
+	if *sinceFlag != "" {
+		var err error
+		if since, err = time.ParseInLocation("2006-01-02", *sinceFlag, time.Local); err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid --since date: %v\n", err)
+			return 2
+		}
+	}
+
+	storage, err := config.LoadStorageConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Configuration is invalid: %v\n", err)
+		return 1
+	}
+	if storage.AuditLog == "" {
+		fmt.Fprintln(os.Stderr, "AUDIT_LOG or SESSION_DIR is not set")
+		return 1
+	}
+
+	auditLog, err := audit.NewFileLog(storage.AuditLog)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	entries, err := auditLog.List()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	// The export itself is an access to the log, record it before writing so a failed write still leaves a trace
+	recordAudit(storage.AuditLog, audit.ActionAuditExport, "", *format)
+	if err := audit.Export(os.Stdout, audit.Filter(entries, since), *format); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// runDoctorCommand handles "aihr doctor", which sends a lightweight request to
+// each configured STT, TTS and GPT provider and reports whether it answered,
+// how fast, and how to fix common failures. It fails when a check fails
+func runDoctorCommand(args []string) int {
+	if len(args) > 0 {
+		fmt.Fprintln(os.Stderr, "Usage: aihr doctor")
+		return 2
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Configuration is invalid: %v\n", err)
+		return 1
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var results []doctor.Result
+	if sttClient, err := aihr.NewSTT(cfg); err != nil {
+		results = append(results, doctor.Result{Component: "stt", Provider: cfg.Providers.STT, Err: err, Hint: doctor.Hint(err)})
+	} else {
+		results = append(results, doctor.CheckSTT(ctx, cfg.Providers.STT, sttClient, int64(cfg.Audio.SampleRate)))
+		sttClient.Close()
+	}
+
+	if ttsClient, err := aihr.NewTTS(cfg); err != nil {
+		results = append(results, doctor.Result{Component: "tts", Provider: cfg.Providers.TTS, Err: err, Hint: doctor.Hint(err)})
+	} else {
+		options := tts.GetDefaultSynthesisOptions()
+		options.Voice = cfg.Engine.Voice
+		results = append(results, doctor.CheckTTS(ctx, cfg.Providers.TTS, ttsClient, options))
+		ttsClient.Close()
+	}
+
+	if gptClient, err := aihr.NewGPT(cfg); err != nil {
+		results = append(results, doctor.Result{Component: "gpt", Provider: cfg.Providers.GPT, Err: err, Hint: doctor.Hint(err)})
+	} else {
+		results = append(results, doctor.CheckGPT(ctx, cfg.Providers.GPT, gptClient))
+	}
+
+	doctor.WriteReport(os.Stdout, results)
+	for _, result := range results {
+		if !result.OK() {
+			return 1
+		}
+	}
+	return 0
+}
+
+// runExportCommand handles "aihr export [--output file] <id>", which bundles
+// the artifacts of a stored session into a zip archive
+func runExportCommand(args []string) int {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	output := flags.String("output", "", "archive to write, <id>.zip by default")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "Usage: aihr export [--output file] <id>")
+		return 2
+	}
+
+	store, storage, err := openSessionStore()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	record, err := store.Load(flags.Arg(0))
+	if errors.Is(err, session.ErrNotFound) {
+		fmt.Fprintf(os.Stderr, "Session %s not found\n", flags.Arg(0))
+		return 1
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	bundle := export.Bundle{Record: record, SigningKey: []byte(storage.SigningKey)}
+	if eventLog, ok := store.(session.EventLog); ok {
+		events, err := eventLog.Events(record.ID)
+		if err != nil && !errors.Is(err, session.ErrNotFound) {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		bundle.Events = append([]session.Event{}, events...)
+	}
+
+	path := *output
+	if path == "" {
+		path = record.ID + ".zip"
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create archive: %v\n", err)
+		return 1
+	}
+	manifest, err := export.Write(file, bundle)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		fmt.Fprintf(os.Stderr, "Failed to export session: %v\n", err)
+		return 1
+	}
+
+	recordAudit(storage.AuditLog, audit.ActionSessionExport, record.ID, path)
+	fmt.Printf("Session %s exported to %s: %d files, signature %s\n", record.ID, path, len(manifest.Files), manifest.Signature)
+	for _, missing := range manifest.Missing {
+		fmt.Fprintf(os.Stderr, "Audio file %s no longer exists and was left out\n", missing)
+	}
+	return 0
+}
+
+// anonymizeSession redacts the contact details of the session with the rules,
+// then the names, employers and schools with the configured model
+func anonymizeSession(record session.Record) (session.Record, error) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return session.Record{}, fmt.Errorf("anonymization needs the model, configuration is invalid: %w", err)
+	}
+	gptClient, err := aihr.NewGPT(cfg)
+	if err != nil {
+		return session.Record{}, err
+	}
+	redactor := redact.Chain{redact.RuleRedactor{}, redact.NewGPTRedactor(gptClient)}
+	return session.Anonymize(record, redactor)
+}
+
+// openSessionStore opens the configured session directory
+func openSessionStore() (session.Store, *config.StorageConfig, error) {
+	storage, err := config.LoadStorageConfig()
+	if err != nil {
+		return nil, nil, fmt.Errorf("configuration is invalid: %w", err)
+	}
+	if storage.SessionDir == "" {
+		return nil, nil, fmt.Errorf("SESSION_DIR is not set")
+	}
+	store, err := session.NewFileStore(storage.SessionDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	return store, storage, nil
+}
+
+// recordAudit appends an entry to the audit log at path, an empty path disables auditing.
+// Failures are logged rather than returned so they never block the action itself
+func recordAudit(path, action, target, detail string) {
+	if path == "" {
+		return
+	}
+	auditLog, err := audit.NewFileLog(path)
+	if err == nil {
+		err = auditLog.Append(audit.NewEntry(action, target, detail))
+	}
+	if err != nil {
+		log.Printf("Failed to record %s in audit log: %v", action, err)
+	}
+}
@@ -1,52 +1,1393 @@
 package config
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/d1nch8g/aihr/cassette"
+	"github.com/d1nch8g/aihr/eval"
+	"github.com/d1nch8g/aihr/i18n"
+	"github.com/d1nch8g/aihr/persona"
+	"github.com/d1nch8g/aihr/prompts"
+	"github.com/d1nch8g/aihr/questions"
+	"github.com/d1nch8g/aihr/secrets"
+	"github.com/d1nch8g/aihr/subtitle"
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	IamToken string
-	FolderID string
-	Audio    AudioConfig
+	Profile   string
+	IamToken  string
+	FolderID  string
+	Audio     AudioConfig
+	Engine    EngineConfig
+	Secrets   SecretsConfig
+	Providers ProvidersConfig
+	Report    ReportConfig
+	Storage   StorageConfig
+	Resources ResourcesConfig
+	Mail      MailConfig
+	ATS       ATSConfig
+	Upload    UploadConfig
+
+	Observability ObservabilityConfig
+
+	GPTModel       string // Model name appended to the folder, e.g. "yandexgpt/rc"
+	FastGPTModel   string // Faster model writing the first sentence of responses, empty disables it
+	ExperimentFile string // A/B test definition, empty disables experiments
+	PromptDir      string // Registry of versioned interview templates, empty for the embedded ones only
+	RecruiterPipe  string // Named pipe a recruiter writes hidden instructions to, empty disables it
+	FeedAddr       string // Address serving the live transcript as Server-Sent Events, empty disables it
+	FeedToken      string // Token the feed clients must send, empty allows any client
+	Indicator      string // Kiosk lamp showing the engine state, gpio:GREEN,BLUE,YELLOW or serial:PATH, empty disables it
+	DemoCassette   string // Directory recording provider responses on the first run and replaying them later
+	SpeechCacheDir string // Directory keeping the audio of the greeting and the farewell, empty disables it
+	LocaleDir      string // Directory of <locale>.json files replacing the built-in phrases
 }
 
 type AudioConfig struct {
+	Backend         string // Audio system used for capture and playback, "auto" picks the preferred one
 	SampleRate      float64
 	FramesPerBuffer int
 	InputChannels   int
 	OutputChannels  int
 	Language        string
+
+	// Languages are recognized together with Language, e.g. English for
+	// Russian-speaking candidates using English terminology. Language is first
+	Languages []string
+
+	// PlaybackPrebuffer is the amount of TTS audio buffered before playback starts
+	PlaybackPrebuffer time.Duration
+
+	// StreamBuffer is the maximum number of bytes queued between two audio stages
+	StreamBuffer int
+
+	// TrimSilence and NormalizeLoudness condition captured audio before
+	// recognition, SilenceThreshold is the level in dBFS below which audio is silence
+	TrimSilence       bool
+	NormalizeLoudness bool
+	SilenceThreshold  float64
+
+	// HighPassCutoff is the cutoff in Hz of the capture high-pass filter, zero disables it
+	HighPassCutoff float64
+
+	// Headless disables local audio devices. Candidate audio is read from
+	// HeadlessInput and AI speech is written to HeadlessOutput as raw PCM,
+	// "-" means stdin/stdout and an empty path means silence/discard
+	Headless       bool
+	HeadlessInput  string
+	HeadlessOutput string
+}
+
+// EngineConfig holds interview settings that can be changed on a running engine
+type EngineConfig struct {
+	Mode           string // "pipeline" chains STT, GPT and TTS, "realtime" uses one speech-to-speech session
+	SystemPrompt   string
+	Greeting       string // Template with {name} slots filled from TemplateVars, empty for the one of the language
+	Farewell       string // Spoken when closing is disabled or fails, empty for the one of the language
+	Voice          string
+	Speed          float64
+	SilenceTimeout time.Duration
+	MinConfidence  float64 // Recognition confidence below which the candidate is asked to repeat, zero disables it
+
+	// NormalizeTranscripts writes technical speech like "big o of n squared" as "O(n²)"
+	NormalizeTranscripts bool
+
+	// AdaptiveSilence counts SilenceTimeout from the end of speech and waits
+	// for speech still being recognized by the measured STT tail latency
+	AdaptiveSilence bool
+
+	// MergeWindow holds the turn open for the final of a phrase the
+	// recognizer reported a partial result of within it, zero disables it
+	MergeWindow time.Duration
+
+	// Captions prints responses to stdout word by word as they are spoken
+	Captions bool
+
+	// CaptionFile receives WebVTT or SRT captions of the session, by its
+	// extension, empty disables them
+	CaptionFile string
+
+	// Earcons are the sounds marking when listening starts and stops and when
+	// a turn fails: "tones", "bundled" or a directory of WAV files, empty
+	// disables them
+	Earcons string
+
+	// Role is the TTS emotion of questions, GreetingRole and ClosingRole the
+	// tone of the greeting and the closing message; empty uses Role or the voice default
+	Role         string
+	GreetingRole string
+	ClosingRole  string
+
+	LogLevel           string
+	DifficultyStrategy string
+	Rubric             eval.Rubric // Criteria answers are scored on, read from RUBRIC_FILE, nil for the default
+	SafetyFilter       string      // Moderation applied to AI responses, "rules" or "off"
+	Deferral           string      // Candidate questions left to the recruiter, "rules" or "off"
+	SentimentAnalysis  bool
+	NotesInterval      int  // Answers in a stage of the interview that is noted, zero disables notes
+	VoiceCommands      bool // Control phrases like "repeat the question" or "I'm done"
+	ProsodyMarkup      bool // The model marks pauses and emphasis for synthesis
+
+	// QuestionBank holds the questions the interviewer asks, read from
+	// QUESTION_BANK, nil lets the model write its own. QuestionTopics are the
+	// stages of the interview and QuestionTags select the questions of the role
+	QuestionBank      questions.Bank
+	QuestionTopics    []string
+	QuestionTags      []string
+	QuestionsPerTopic int
+
+	// MaxFollowUps is the number of follow-up questions in a row allowed on
+	// one topic, zero means no limit
+	MaxFollowUps int
+
+	// Personas are the panel of interviewers taking turns, read from
+	// PERSONAS_FILE, nil interviews with one voice
+	Personas persona.Panel
+
+	// SafetyJurisdictions selects the packs of prohibited topics, e.g. "us" or "eu".
+	// Blocked generations are appended to SafetyAuditLog when it is set
+	SafetyJurisdictions []string
+	SafetyAuditLog      string
+
+	// TurnLog receives one JSON line per turn, "-" writes them to stdout
+	TurnLog string
+
+	// Closing makes the interviewer summarize the conversation, explain the next
+	// steps and thank the candidate before exiting. NextSteps is a text/template
+	// rendered with TemplateVars, where "candidate" is set to CandidateName,
+	// empty for the one of the language
+	Closing        bool
+	ClosingTimeout time.Duration
+	NextSteps      string
+	CandidateName  string
+	TemplateVars   map[string]string
+
+	// Company and Position fill the {company} and {role} slots of the greeting and the farewell
+	Company  string
+	Position string
+
+	// MaxDuration closes the interview once it has run this long, zero means no limit
+	MaxDuration time.Duration
+
+	// TimeAnnouncements are the times left before MaxDuration at which the
+	// candidate is told how much time remains
+	TimeAnnouncements []time.Duration
+
+	// StallTimeout restarts a turn that made no progress for this long, zero disables it
+	StallTimeout time.Duration
+
+	// WarmUp connects the providers with small requests when the interview starts
+	WarmUp bool
+
+	// Reactions plays a short reaction of the locale while the response is generated
+	Reactions bool
+}
+
+// SecretsConfig describes where credentials are pulled from instead of the .env file
+type SecretsConfig struct {
+	Provider        string // vault, aws or lockbox, empty when disabled
+	RefreshInterval time.Duration
+}
+
+// ProvidersConfig selects the STT, TTS and GPT implementations, either the
+// built-in "yandex" one or a plugin name
+type ProvidersConfig struct {
+	PluginDir string
+	STT       string
+	TTS       string
+	GPT       string
+
+	TTSCommand string // Local synthesizer used by the "command" TTS provider, e.g. "espeak-ng --stdout"
+
+	// ElevenLabs settings used by the "elevenlabs" TTS provider, which also
+	// speaks the cloned voices of the personas
+	ElevenLabsAPIKey string
+	ElevenLabsModel  string
+
+	// TTSFallbackCommand is a local synthesizer that speaks when the TTS
+	// provider still fails after TTSRetries retries, empty to disable it
+	TTSFallbackCommand string
+	TTSRetries         int
+
+	// STTFallbackCommand is a local recognizer used when the recognition
+	// stream of the STT provider cannot be established, empty to disable it
+	STTFallbackCommand string
+
+	// Yandex STT recognition options, their defaults depend on the language
+	STTModel           string // e.g. "general" or "general:rc"
+	STTNormalization   bool   // Numbers, dates and units are written in digits and symbols
+	STTProfanityFilter bool   // Profanity is masked in transcripts
+	STTLiteratureText  bool   // Transcripts are rewritten in literary style with punctuation
+
+	// OpenAI Realtime API settings used in the realtime engine mode
+	RealtimeAPIKey string
+	RealtimeModel  string
+	RealtimeVoice  string
+}
+
+// ReportConfig controls the report written when the interview ends
+type ReportConfig struct {
+	Path string // File for the session report, "-" for stdout, empty to skip it
+
+	// DuplicateDetection compares answer embeddings with stored sessions and
+	// flags answers with a similarity of at least DuplicateThreshold
+	DuplicateDetection bool
+	DuplicateThreshold float64
+}
+
+// ResourcesConfig limits the resources used by the process, e.g. on a kiosk
+// device. Zero values leave the Go runtime defaults
+type ResourcesConfig struct {
+	MaxProcs    int   // Maximum number of CPUs executing Go code at once
+	MemoryLimit int64 // Soft memory limit in bytes
 }
 
+// MailConfig describes how the report is emailed when the interview ends.
+// Subject and Body are text/templates filled like NEXT_STEPS
+type MailConfig struct {
+	Provider string // smtp or sendgrid, empty disables email
+	From     string
+	To       []string
+	Subject  string
+	Body     string
+
+	SMTPHost       string
+	SMTPPort       int
+	SMTPUsername   string
+	SMTPPassword   string
+	SendGridAPIKey string
+}
+
+// ATSConfig describes the applicant tracking system that receives the results
+type ATSConfig struct {
+	Provider     string // greenhouse or lever, empty disables the push
+	APIKey       string
+	UserID       string            // ATS user the notes are written on behalf of
+	CandidateID  string            // Candidate (Greenhouse) or opportunity (Lever) of this interview
+	FieldMapping map[string]string // Result field to ATS field key
+}
+
+// UploadConfig describes the S3-compatible bucket that receives session artifacts
+type UploadConfig struct {
+	Bucket          string // Empty disables uploads
+	Endpoint        string
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	Prefix          string // Objects are stored under Prefix/<session ID>/
+	Encryption      string // Server-side encryption, AES256 or aws:kms
+	KMSKeyID        string
+	Retries         int
+
+	// DeleteLocal removes the stored session and the report file once they are uploaded
+	DeleteLocal bool
+}
+
+// ObservabilityConfig describes the LLM observability tool that receives every
+// prompt and response pair
+type ObservabilityConfig struct {
+	Provider  string // langfuse or webhook, empty disables the export
+	Endpoint  string // Langfuse host or the webhook URL
+	PublicKey string // Langfuse public key
+	SecretKey string // Langfuse secret key, or the bearer token of the webhook
+}
+
+// StorageConfig describes where finished sessions are kept
+type StorageConfig struct {
+	SessionDir string // Directory of session records, empty disables storage
+	AuditLog   string // Append-only log of administrative and data-access actions, empty disables it
+	SigningKey string // Key of the session signatures, empty disables signing
+}
+
+const (
+	defaultMailSubject = "Interview report{{with .candidate}}: {{.}}{{end}}"
+	defaultMailBody    = "The interview {{.session}} has finished with {{.answers}} answers. " +
+		"The report and the transcript are attached."
+)
+
+// ProfileEnv selects the named profile whose variables override the defaults
+const ProfileEnv = "AIHR_PROFILE"
+
+// EnvFileEnv overrides the path of the optional .env file
+const EnvFileEnv = "AIHR_ENV_FILE"
+
+// processEnv remembers which variables were set by the process environment
+// before any .env file was applied, so they keep priority on reload
+var processEnv map[string]bool
+
+// LoadConfig resolves the configuration. Values are taken, from highest to lowest priority, from:
+// the secrets provider, profile-prefixed variables, the process environment,
+// the optional .env file and built-in defaults
 func LoadConfig() (*Config, error) {
-	err := godotenv.Load()
+	if err := loadEnvFile(); err != nil {
+		return nil, err
+	}
+
+	return buildConfig()
+}
+
+// ReloadConfig re-reads the .env file so that edits made while the process
+// is running take effect, keeping the same precedence as LoadConfig
+func ReloadConfig() (*Config, error) {
+	return LoadConfig()
+}
+
+// loadEnvFile applies variables from the .env file that are not set by the process environment.
+// A missing file is not an error
+func loadEnvFile() error {
+	if processEnv == nil {
+		processEnv = make(map[string]bool)
+		for _, entry := range os.Environ() {
+			key, _, _ := strings.Cut(entry, "=")
+			processEnv[key] = true
+		}
+	}
+
+	path := getEnvOrDefault(EnvFileEnv, ".env")
+	values, err := godotenv.Read(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
 	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	for key, value := range values {
+		if processEnv[key] {
+			continue
+		}
+		if err := os.Setenv(key, value); err != nil {
+			return fmt.Errorf("failed to set %s: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+// LoadStorageConfig resolves only the storage settings, so commands that
+// work with stored sessions do not need provider credentials
+func LoadStorageConfig() (*StorageConfig, error) {
+	if err := loadEnvFile(); err != nil {
+		return nil, err
+	}
+	if profile := strings.TrimSpace(os.Getenv(ProfileEnv)); profile != "" {
+		applyProfile(profile)
+	}
+	applyOverrides()
+
+	return loadStorageConfig(), nil
+}
+
+// LoadPromptRegistry loads the prompt registry from PROMPT_DIR without the rest
+// of the configuration
+func LoadPromptRegistry() (*prompts.Registry, error) {
+	if err := loadEnvFile(); err != nil {
 		return nil, err
 	}
+	if profile := strings.TrimSpace(os.Getenv(ProfileEnv)); profile != "" {
+		applyProfile(profile)
+	}
+	applyOverrides()
+
+	return prompts.Load(os.Getenv("PROMPT_DIR"))
+}
+
+// loadStorageConfig keeps the audit log next to the sessions unless AUDIT_LOG is set
+func loadStorageConfig() *StorageConfig {
+	storage := &StorageConfig{
+		SessionDir: os.Getenv("SESSION_DIR"),
+		AuditLog:   os.Getenv("AUDIT_LOG"),
+		SigningKey: os.Getenv("SIGNING_KEY"),
+	}
+	if storage.AuditLog == "" && storage.SessionDir != "" {
+		storage.AuditLog = filepath.Join(storage.SessionDir, "audit.log")
+	}
+	return storage
+}
+
+func buildConfig() (*Config, error) {
+	profile := strings.TrimSpace(os.Getenv(ProfileEnv))
+	if profile != "" {
+		applyProfile(profile)
+	}
+	applyOverrides()
+
+	secretsConfig, err := loadSecrets()
+	if err != nil {
+		return nil, err
+	}
+
+	playbackPrebuffer, err := time.ParseDuration(getEnvOrDefault("PLAYBACK_PREBUFFER", "200ms"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PLAYBACK_PREBUFFER: %w", err)
+	}
+
+	streamBuffer, err := strconv.Atoi(getEnvOrDefault("AUDIO_STREAM_BUFFER", "262144"))
+	if err != nil || streamBuffer <= 0 {
+		return nil, fmt.Errorf("invalid AUDIO_STREAM_BUFFER: must be a positive number of bytes")
+	}
+
+	sampleRate, err := strconv.ParseFloat(getEnvOrDefault("AUDIO_SAMPLE_RATE", "44100"), 64)
+	if err != nil || sampleRate <= 0 {
+		return nil, fmt.Errorf("invalid AUDIO_SAMPLE_RATE: must be a positive number of Hz")
+	}
+
+	framesPerBuffer, err := strconv.Atoi(getEnvOrDefault("AUDIO_FRAMES_PER_BUFFER", "1024"))
+	if err != nil || framesPerBuffer <= 0 {
+		return nil, fmt.Errorf("invalid AUDIO_FRAMES_PER_BUFFER: must be a positive number")
+	}
+
+	if _, err := i18n.Load(getEnvOrDefault("LANGUAGE", "en-US"), os.Getenv("LOCALE_DIR")); err != nil {
+		return nil, fmt.Errorf("invalid LOCALE_DIR: %w", err)
+	}
+
+	highPassCutoff, err := strconv.ParseFloat(getEnvOrDefault("HIGHPASS_CUTOFF", "0"), 64)
+	if err != nil || highPassCutoff < 0 || highPassCutoff >= sampleRate/2 {
+		return nil, fmt.Errorf("invalid HIGHPASS_CUTOFF: must be zero or a frequency below half the sample rate")
+	}
+
+	silenceThreshold, err := strconv.ParseFloat(getEnvOrDefault("SILENCE_THRESHOLD", "-45"), 64)
+	if err != nil || silenceThreshold >= 0 {
+		return nil, fmt.Errorf("invalid SILENCE_THRESHOLD: must be a negative level in dBFS")
+	}
 
 	// Set default audio config
 	audioConfig := AudioConfig{
-		SampleRate:      44100,
-		FramesPerBuffer: 1024,
-		InputChannels:   1,
-		OutputChannels:  0,
-		Language:        getEnvOrDefault("LANGUAGE", "en-US"),
+		Backend:           getEnvOrDefault("AUDIO_BACKEND", "auto"),
+		SampleRate:        sampleRate,
+		FramesPerBuffer:   framesPerBuffer,
+		InputChannels:     1,
+		OutputChannels:    0,
+		Language:          getEnvOrDefault("LANGUAGE", "en-US"),
+		Languages:         recognitionLanguages(getEnvOrDefault("LANGUAGE", "en-US"), os.Getenv("STT_LANGUAGES")),
+		PlaybackPrebuffer: playbackPrebuffer,
+		StreamBuffer:      streamBuffer,
+		TrimSilence:       getEnvOrDefault("TRIM_SILENCE", "false") == "true",
+		NormalizeLoudness: getEnvOrDefault("NORMALIZE_LOUDNESS", "false") == "true",
+		SilenceThreshold:  silenceThreshold,
+		HighPassCutoff:    highPassCutoff,
+		Headless:          getEnvOrDefault("HEADLESS", "false") == "true",
+		HeadlessInput:     os.Getenv("HEADLESS_AUDIO_IN"),
+		HeadlessOutput:    os.Getenv("HEADLESS_AUDIO_OUT"),
 	}
 
-	if os.Getenv("IAM_TOKEN") == "" || os.Getenv("FOLDER_ID") == "" {
-		return nil, fmt.Errorf("IAM_TOKEN and FOLDER_ID must be set in .env file")
+	// A recorded demo cassette replays every provider without credentials
+	replaying := os.Getenv("DEMO_CASSETTE") != "" && cassette.Exists(os.Getenv("DEMO_CASSETTE"))
+	if !replaying && (os.Getenv("IAM_TOKEN") == "" || os.Getenv("FOLDER_ID") == "") {
+		return nil, fmt.Errorf("IAM_TOKEN and FOLDER_ID must be set in the environment, .env file or secrets provider")
+	}
+
+	engineConfig, err := loadEngineConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	reportConfig, err := loadReportConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	// Audio written to stdout would be corrupted by lines written there too
+	if audioConfig.Headless && audioConfig.HeadlessOutput == "-" && (engineConfig.TurnLog == "-" || reportConfig.Path == "-") {
+		return nil, fmt.Errorf("TURN_LOG and REPORT_FILE cannot be \"-\" while HEADLESS_AUDIO_OUT writes audio to stdout")
+	}
+
+	resources, err := loadResources()
+	if err != nil {
+		return nil, err
+	}
+
+	mailConfig, err := loadMailConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	uploadConfig, err := loadUploadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	observability, err := loadObservabilityConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	providers, err := loadProviders()
+	if err != nil {
+		return nil, err
+	}
+
+	fieldMapping, err := parseVars(os.Getenv("ATS_FIELD_MAPPING"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid ATS_FIELD_MAPPING: %w", err)
 	}
 
 	return &Config{
-		IamToken: os.Getenv("IAM_TOKEN"),
-		FolderID: os.Getenv("FOLDER_ID"),
-		Audio:    audioConfig,
+		Profile:   profile,
+		IamToken:  os.Getenv("IAM_TOKEN"),
+		FolderID:  os.Getenv("FOLDER_ID"),
+		Audio:     audioConfig,
+		Engine:    *engineConfig,
+		Secrets:   *secretsConfig,
+		Providers: *providers,
+		Report:    *reportConfig,
+		Storage:   *loadStorageConfig(),
+		Resources: *resources,
+		Mail:      *mailConfig,
+		Upload:    *uploadConfig,
+		ATS: ATSConfig{
+			Provider:     os.Getenv("ATS_PROVIDER"),
+			APIKey:       os.Getenv("ATS_API_KEY"),
+			UserID:       os.Getenv("ATS_USER_ID"),
+			CandidateID:  os.Getenv("ATS_CANDIDATE_ID"),
+			FieldMapping: fieldMapping,
+		},
+
+		Observability: *observability,
+
+		GPTModel:       getEnvOrDefault("GPT_MODEL", "yandexgpt/rc"),
+		FastGPTModel:   os.Getenv("FAST_GPT_MODEL"),
+		ExperimentFile: os.Getenv("EXPERIMENT_FILE"),
+		PromptDir:      os.Getenv("PROMPT_DIR"),
+		RecruiterPipe:  os.Getenv("RECRUITER_PIPE"),
+		FeedAddr:       os.Getenv("FEED_ADDR"),
+		FeedToken:      os.Getenv("FEED_TOKEN"),
+		Indicator:      os.Getenv("INDICATOR"),
+		DemoCassette:   os.Getenv("DEMO_CASSETTE"),
+		SpeechCacheDir: speechCacheDir(),
+		LocaleDir:      os.Getenv("LOCALE_DIR"),
+	}, nil
+}
+
+// loadSecrets exports credentials from the configured secrets provider into the environment
+func loadSecrets() (*SecretsConfig, error) {
+	refreshInterval, err := time.ParseDuration(getEnvOrDefault("SECRETS_REFRESH_INTERVAL", "1h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SECRETS_REFRESH_INTERVAL: %w", err)
+	}
+
+	provider, err := secrets.NewProviderFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	if provider == nil {
+		return &SecretsConfig{}, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if _, err := secrets.Apply(ctx, provider); err != nil {
+		return nil, err
+	}
+
+	return &SecretsConfig{
+		Provider:        provider.Name(),
+		RefreshInterval: refreshInterval,
 	}, nil
 }
 
+func loadEngineConfig() (*EngineConfig, error) {
+	speed, err := strconv.ParseFloat(getEnvOrDefault("VOICE_SPEED", "1.0"), 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid VOICE_SPEED: %w", err)
+	}
+
+	silenceTimeout, err := time.ParseDuration(getEnvOrDefault("SILENCE_TIMEOUT", "3s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SILENCE_TIMEOUT: %w", err)
+	}
+
+	mergeWindow, err := time.ParseDuration(getEnvOrDefault("STT_MERGE_WINDOW", "1s"))
+	if err != nil || mergeWindow < 0 {
+		return nil, fmt.Errorf("invalid STT_MERGE_WINDOW: must be a non-negative duration")
+	}
+
+	closingTimeout, err := time.ParseDuration(getEnvOrDefault("CLOSING_TIMEOUT", "30s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CLOSING_TIMEOUT: %w", err)
+	}
+
+	mode := getEnvOrDefault("ENGINE_MODE", "pipeline")
+	if mode != "pipeline" && mode != "realtime" {
+		return nil, fmt.Errorf("invalid ENGINE_MODE: must be pipeline or realtime")
+	}
+	if mode == "realtime" && os.Getenv("OPENAI_API_KEY") == "" {
+		return nil, fmt.Errorf("OPENAI_API_KEY must be set for the realtime engine mode")
+	}
+
+	minConfidence, err := strconv.ParseFloat(getEnvOrDefault("MIN_CONFIDENCE", "0"), 64)
+	if err != nil || minConfidence < 0 || minConfidence > 1 {
+		return nil, fmt.Errorf("invalid MIN_CONFIDENCE: must be between 0 and 1")
+	}
+
+	maxDuration, err := time.ParseDuration(getEnvOrDefault("MAX_DURATION", "0s"))
+	if err != nil || maxDuration < 0 {
+		return nil, fmt.Errorf("invalid MAX_DURATION: must be a non-negative duration")
+	}
+
+	var timeAnnouncements []time.Duration
+	for _, item := range splitList(os.Getenv("TIME_ANNOUNCEMENTS")) {
+		remaining, err := time.ParseDuration(item)
+		if err != nil || remaining <= 0 {
+			return nil, fmt.Errorf("invalid TIME_ANNOUNCEMENTS: %q must be a positive duration", item)
+		}
+		if maxDuration > 0 && remaining >= maxDuration {
+			return nil, fmt.Errorf("invalid TIME_ANNOUNCEMENTS: %s must be shorter than MAX_DURATION", remaining)
+		}
+		timeAnnouncements = append(timeAnnouncements, remaining)
+	}
+
+	captionFile := os.Getenv("CAPTIONS_FILE")
+	if captionFile != "" {
+		if _, err := subtitle.FormatOf(captionFile); err != nil {
+			return nil, fmt.Errorf("invalid CAPTIONS_FILE: %w", err)
+		}
+	}
+
+	earcons := os.Getenv("EARCONS")
+	if earcons != "" && earcons != "tones" && earcons != "bundled" {
+		if info, err := os.Stat(earcons); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("invalid EARCONS: must be tones, bundled or a directory of WAV files")
+		}
+	}
+
+	stallTimeout, err := time.ParseDuration(getEnvOrDefault("STALL_TIMEOUT", "60s"))
+	if err != nil || stallTimeout < 0 {
+		return nil, fmt.Errorf("invalid STALL_TIMEOUT: must be a non-negative duration")
+	}
+
+	var rubric eval.Rubric
+	if path := os.Getenv("RUBRIC_FILE"); path != "" {
+		if rubric, err = eval.LoadRubric(path); err != nil {
+			return nil, err
+		}
+	}
+
+	var bank questions.Bank
+	questionTopics := splitList(os.Getenv("QUESTION_TOPICS"))
+	questionTags := splitList(os.Getenv("QUESTION_TAGS"))
+	if path := os.Getenv("QUESTION_BANK"); path != "" {
+		if bank, err = questions.LoadBank(path); err != nil {
+			return nil, err
+		}
+		known := bank.Topics(questionTags)
+		if len(known) == 0 {
+			return nil, fmt.Errorf("invalid QUESTION_TAGS: no question of the bank has all of them")
+		}
+		for _, topic := range questionTopics {
+			if !slices.Contains(known, topic) {
+				return nil, fmt.Errorf("invalid QUESTION_TOPICS: no question on %q with the tags", topic)
+			}
+		}
+	}
+
+	questionsPerTopic, err := strconv.Atoi(getEnvOrDefault("QUESTIONS_PER_TOPIC", "2"))
+	if err != nil || questionsPerTopic < 1 {
+		return nil, fmt.Errorf("invalid QUESTIONS_PER_TOPIC: must be a positive number")
+	}
+
+	var personas persona.Panel
+	if path := os.Getenv("PERSONAS_FILE"); path != "" {
+		if personas, err = persona.Load(path); err != nil {
+			return nil, err
+		}
+		for _, p := range personas {
+			for _, topic := range p.Topics {
+				if !slices.Contains(bank.Topics(questionTags), topic) {
+					return nil, fmt.Errorf("invalid PERSONAS_FILE: %s leads %q, which is not a topic of QUESTION_BANK", p.Name, topic)
+				}
+			}
+		}
+	}
+
+	maxFollowUps, err := strconv.Atoi(getEnvOrDefault("MAX_FOLLOW_UPS", "0"))
+	if err != nil || maxFollowUps < 0 {
+		return nil, fmt.Errorf("invalid MAX_FOLLOW_UPS: must be a non-negative number")
+	}
+
+	notesInterval, err := strconv.Atoi(getEnvOrDefault("NOTES_INTERVAL", "0"))
+	if err != nil || notesInterval < 0 {
+		return nil, fmt.Errorf("invalid NOTES_INTERVAL: must be a non-negative number")
+	}
+
+	templateVars, err := parseVars(os.Getenv("TEMPLATE_VARS"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid TEMPLATE_VARS: %w", err)
+	}
+
+	systemPrompt := os.Getenv("SYSTEM_PROMPT")
+	if systemPrompt == "" {
+		registry, err := prompts.Load(os.Getenv("PROMPT_DIR"))
+		if err != nil {
+			return nil, err
+		}
+		prompt, err := registry.Resolve(getEnvOrDefault("PROMPT", prompts.DefaultName))
+		if err != nil {
+			return nil, fmt.Errorf("invalid PROMPT: %w", err)
+		}
+		systemPrompt = prompt.Text
+	}
+	greeting, err := readScript("GREETING")
+	if err != nil {
+		return nil, err
+	}
+	farewell, err := readScript("FAREWELL")
+	if err != nil {
+		return nil, err
+	}
+
+	if path := os.Getenv("SYSTEM_PROMPT_FILE"); path != "" {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read SYSTEM_PROMPT_FILE: %w", err)
+		}
+		systemPrompt = string(content)
+	}
+
+	return &EngineConfig{
+		Mode:               mode,
+		SystemPrompt:       systemPrompt,
+		Greeting:           greeting,
+		Farewell:           farewell,
+		Voice:              getEnvOrDefault("VOICE", "marina"),
+		Role:               os.Getenv("VOICE_ROLE"),
+		GreetingRole:       os.Getenv("GREETING_ROLE"),
+		ClosingRole:        os.Getenv("CLOSING_ROLE"),
+		Speed:              speed,
+		SilenceTimeout:     silenceTimeout,
+		MergeWindow:        mergeWindow,
+		MinConfidence:      minConfidence,
+		LogLevel:           getEnvOrDefault("LOG_LEVEL", "info"),
+		DifficultyStrategy: os.Getenv("DIFFICULTY_STRATEGY"),
+		Rubric:             rubric,
+		SafetyFilter:       getEnvOrDefault("SAFETY_FILTER", "rules"),
+		Deferral:           getEnvOrDefault("DEFERRAL", "rules"),
+		SentimentAnalysis:  getEnvOrDefault("SENTIMENT_ANALYSIS", "false") == "true",
+		NotesInterval:      notesInterval,
+		VoiceCommands:      getEnvOrDefault("VOICE_COMMANDS", "true") == "true",
+		ProsodyMarkup:      getEnvOrDefault("PROSODY_MARKUP", "false") == "true",
+
+		QuestionBank:      bank,
+		QuestionTopics:    questionTopics,
+		QuestionTags:      questionTags,
+		QuestionsPerTopic: questionsPerTopic,
+		MaxFollowUps:      maxFollowUps,
+		Personas:          personas,
+
+		SafetyJurisdictions: splitList(getEnvOrDefault("SAFETY_JURISDICTIONS", "us,eu,ru")),
+		SafetyAuditLog:      os.Getenv("SAFETY_AUDIT_LOG"),
+
+		TurnLog: os.Getenv("TURN_LOG"),
+
+		Closing:        getEnvOrDefault("CLOSING", "true") == "true",
+		ClosingTimeout: closingTimeout,
+		NextSteps:      os.Getenv("NEXT_STEPS"),
+		CandidateName:  os.Getenv("CANDIDATE_NAME"),
+		TemplateVars:   templateVars,
+		Company:        os.Getenv("COMPANY"),
+		Position:       os.Getenv("POSITION"),
+		MaxDuration:    maxDuration,
+		StallTimeout:   stallTimeout,
+		WarmUp:         getEnvOrDefault("WARM_UP", "true") == "true",
+		Reactions:      getEnvOrDefault("REACTIONS", "false") == "true",
+
+		TimeAnnouncements:    timeAnnouncements,
+		NormalizeTranscripts: getEnvOrDefault("NORMALIZE_TRANSCRIPTS", "false") == "true",
+		AdaptiveSilence:      getEnvOrDefault("ADAPTIVE_SILENCE", "false") == "true",
+		Captions:             getEnvOrDefault("CAPTIONS", "false") == "true",
+		CaptionFile:          captionFile,
+		Earcons:              earcons,
+	}, nil
+}
+
+// readScript returns the phrase set in the variable, or read from the file
+// in the variable with the _FILE suffix, which takes precedence
+func readScript(key string) (string, error) {
+	path := os.Getenv(key + "_FILE")
+	if path == "" {
+		return os.Getenv(key), nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s_FILE: %w", key, err)
+	}
+	return strings.TrimSpace(string(content)), nil
+}
+
+// speechCacheDir returns SPEECH_CACHE_DIR, by default a directory in the
+// user cache directory. "off" disables the cache
+func speechCacheDir() string {
+	dir := os.Getenv("SPEECH_CACHE_DIR")
+	if dir == "off" {
+		return ""
+	}
+	if dir == "" {
+		cache, err := os.UserCacheDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(cache, "aihr", "speech")
+	}
+	return dir
+}
+
+func loadReportConfig() (*ReportConfig, error) {
+	threshold, err := strconv.ParseFloat(getEnvOrDefault("DUPLICATE_THRESHOLD", "0.95"), 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid DUPLICATE_THRESHOLD: %w", err)
+	}
+
+	return &ReportConfig{
+		Path:               os.Getenv("REPORT_FILE"),
+		DuplicateDetection: getEnvOrDefault("DUPLICATE_DETECTION", "false") == "true",
+		DuplicateThreshold: threshold,
+	}, nil
+}
+
+func loadResources() (*ResourcesConfig, error) {
+	maxProcs, err := strconv.Atoi(getEnvOrDefault("MAX_PROCS", "0"))
+	if err != nil || maxProcs < 0 {
+		return nil, fmt.Errorf("invalid MAX_PROCS: must be a non-negative number")
+	}
+
+	memoryLimit, err := parseSize(getEnvOrDefault("MEMORY_LIMIT", "0"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid MEMORY_LIMIT: %w", err)
+	}
+
+	return &ResourcesConfig{MaxProcs: maxProcs, MemoryLimit: memoryLimit}, nil
+}
+
+func loadMailConfig() (*MailConfig, error) {
+	provider := os.Getenv("MAIL_PROVIDER")
+	if provider == "" {
+		return &MailConfig{}, nil
+	}
+	if provider != "smtp" && provider != "sendgrid" {
+		return nil, fmt.Errorf("invalid MAIL_PROVIDER: must be smtp or sendgrid")
+	}
+
+	port, err := strconv.Atoi(getEnvOrDefault("SMTP_PORT", "587"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SMTP_PORT: %w", err)
+	}
+
+	mailConfig := &MailConfig{
+		Provider: provider,
+		From:     os.Getenv("MAIL_FROM"),
+		To:       splitList(os.Getenv("MAIL_TO")),
+		Subject:  getEnvOrDefault("MAIL_SUBJECT", defaultMailSubject),
+		Body:     getEnvOrDefault("MAIL_BODY", defaultMailBody),
+
+		SMTPHost:       os.Getenv("SMTP_HOST"),
+		SMTPPort:       port,
+		SMTPUsername:   os.Getenv("SMTP_USERNAME"),
+		SMTPPassword:   os.Getenv("SMTP_PASSWORD"),
+		SendGridAPIKey: os.Getenv("SENDGRID_API_KEY"),
+	}
+	if mailConfig.From == "" || len(mailConfig.To) == 0 {
+		return nil, fmt.Errorf("MAIL_FROM and MAIL_TO must be set when MAIL_PROVIDER is set")
+	}
+	return mailConfig, nil
+}
+
+func loadUploadConfig() (*UploadConfig, error) {
+	bucket := os.Getenv("UPLOAD_BUCKET")
+	if bucket == "" {
+		return &UploadConfig{}, nil
+	}
+
+	retries, err := strconv.Atoi(getEnvOrDefault("UPLOAD_RETRIES", "3"))
+	if err != nil || retries < 0 {
+		return nil, fmt.Errorf("invalid UPLOAD_RETRIES: must be a non-negative number")
+	}
+
+	uploadConfig := &UploadConfig{
+		Bucket:          bucket,
+		Endpoint:        getEnvOrDefault("UPLOAD_ENDPOINT", "https://storage.yandexcloud.net"),
+		Region:          getEnvOrDefault("UPLOAD_REGION", "ru-central1"),
+		AccessKeyID:     os.Getenv("UPLOAD_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("UPLOAD_SECRET_ACCESS_KEY"),
+		Prefix:          getEnvOrDefault("UPLOAD_PREFIX", "sessions"),
+		Encryption:      os.Getenv("UPLOAD_ENCRYPTION"),
+		KMSKeyID:        os.Getenv("UPLOAD_KMS_KEY_ID"),
+		Retries:         retries,
+		DeleteLocal:     getEnvOrDefault("UPLOAD_DELETE_LOCAL", "false") == "true",
+	}
+	if uploadConfig.AccessKeyID == "" || uploadConfig.SecretAccessKey == "" {
+		return nil, fmt.Errorf("UPLOAD_ACCESS_KEY_ID and UPLOAD_SECRET_ACCESS_KEY must be set when UPLOAD_BUCKET is set")
+	}
+	return uploadConfig, nil
+}
+
+func loadObservabilityConfig() (*ObservabilityConfig, error) {
+	provider := os.Getenv("OBSERVABILITY_PROVIDER")
+	if provider == "" {
+		return &ObservabilityConfig{}, nil
+	}
+
+	observability := &ObservabilityConfig{
+		Provider:  provider,
+		Endpoint:  os.Getenv("OBSERVABILITY_ENDPOINT"),
+		PublicKey: os.Getenv("LANGFUSE_PUBLIC_KEY"),
+		SecretKey: os.Getenv("OBSERVABILITY_TOKEN"),
+	}
+	switch provider {
+	case "langfuse":
+		observability.SecretKey = os.Getenv("LANGFUSE_SECRET_KEY")
+		observability.Endpoint = getEnvOrDefault("OBSERVABILITY_ENDPOINT", getEnvOrDefault("LANGFUSE_HOST", "https://cloud.langfuse.com"))
+		if observability.PublicKey == "" || observability.SecretKey == "" {
+			return nil, fmt.Errorf("LANGFUSE_PUBLIC_KEY and LANGFUSE_SECRET_KEY must be set when OBSERVABILITY_PROVIDER is langfuse")
+		}
+	case "webhook":
+		if observability.Endpoint == "" {
+			return nil, fmt.Errorf("OBSERVABILITY_ENDPOINT must be set when OBSERVABILITY_PROVIDER is webhook")
+		}
+	default:
+		return nil, fmt.Errorf("invalid OBSERVABILITY_PROVIDER: must be langfuse or webhook")
+	}
+	return observability, nil
+}
+
+// sttOptions are the default Yandex STT options of a language
+type sttOptions struct {
+	model           string
+	normalization   bool
+	profanityFilter bool
+	literatureText  bool
+}
+
+// sttDefaults are the Yandex STT options by language. Russian transcripts are
+// written in literary style, as spoken Russian is hard to read otherwise,
+// English uses the release candidate model. Languages not listed use "en"
+var sttDefaults = map[string]sttOptions{
+	"ru": {model: "general", normalization: true, literatureText: true},
+	"en": {model: "general:rc", normalization: true},
+}
+
+func loadProviders() (*ProvidersConfig, error) {
+	ttsRetries, err := strconv.Atoi(getEnvOrDefault("TTS_RETRIES", "2"))
+	if err != nil || ttsRetries < 0 {
+		return nil, fmt.Errorf("invalid TTS_RETRIES: must be a non-negative number")
+	}
+
+	tts := getEnvOrDefault("TTS_PROVIDER", "yandex")
+	if tts == "elevenlabs" && os.Getenv("ELEVENLABS_API_KEY") == "" {
+		return nil, fmt.Errorf("ELEVENLABS_API_KEY must be set when TTS_PROVIDER is elevenlabs")
+	}
+
+	language, _, _ := strings.Cut(strings.ToLower(getEnvOrDefault("LANGUAGE", "en-US")), "-")
+	defaults, ok := sttDefaults[language]
+	if !ok {
+		defaults = sttDefaults["en"]
+	}
+
+	return &ProvidersConfig{
+		PluginDir: os.Getenv("PLUGIN_DIR"),
+		STT:       getEnvOrDefault("STT_PROVIDER", "yandex"),
+		TTS:       tts,
+		GPT:       getEnvOrDefault("GPT_PROVIDER", "yandex"),
+
+		TTSCommand:         os.Getenv("TTS_COMMAND"),
+		ElevenLabsAPIKey:   os.Getenv("ELEVENLABS_API_KEY"),
+		ElevenLabsModel:    getEnvOrDefault("ELEVENLABS_MODEL", "eleven_multilingual_v2"),
+		TTSFallbackCommand: os.Getenv("TTS_FALLBACK_COMMAND"),
+		TTSRetries:         ttsRetries,
+		STTFallbackCommand: os.Getenv("STT_FALLBACK_COMMAND"),
+
+		STTModel:           getEnvOrDefault("STT_MODEL", defaults.model),
+		STTNormalization:   getEnvOrDefault("STT_NORMALIZATION", strconv.FormatBool(defaults.normalization)) == "true",
+		STTProfanityFilter: getEnvOrDefault("STT_PROFANITY_FILTER", strconv.FormatBool(defaults.profanityFilter)) == "true",
+		STTLiteratureText:  getEnvOrDefault("STT_LITERATURE_TEXT", strconv.FormatBool(defaults.literatureText)) == "true",
+
+		RealtimeAPIKey: os.Getenv("OPENAI_API_KEY"),
+		RealtimeModel:  getEnvOrDefault("REALTIME_MODEL", "gpt-4o-realtime-preview"),
+		RealtimeVoice:  getEnvOrDefault("REALTIME_VOICE", "alloy"),
+	}, nil
+}
+
+// overrides holds the variables set with Override
+var overrides = make(map[string]string)
+
+// Override sets a variable for the current session that takes precedence over
+// profiles, the process environment and the .env file, also across reloads
+func Override(key, value string) {
+	overrides[key] = value
+}
+
+func applyOverrides() {
+	for key, value := range overrides {
+		os.Setenv(key, value)
+	}
+}
+
+// profileDefaults are the variables the built-in profiles set when neither
+// the plain nor the prefixed variable is. The dev profile runs on a demo
+// cassette, so after one recorded session it works without credentials
+var profileDefaults = map[string]map[string]string{
+	"dev": {
+		"LOG_LEVEL":     "debug",
+		"DEMO_CASSETTE": "cassettes/dev",
+	},
+	"staging": {
+		"LOG_LEVEL": "debug",
+	},
+	"prod": {
+		"LOG_LEVEL": "info",
+	},
+}
+
+// applyProfile copies variables prefixed with the profile name over the
+// unprefixed ones, e.g. with profile "dev" DEV_LOG_LEVEL overrides LOG_LEVEL,
+// then fills in the defaults of a built-in profile
+func applyProfile(profile string) {
+	prefix := strings.ToUpper(profile) + "_"
+	for _, entry := range os.Environ() {
+		key, value, ok := strings.Cut(entry, "=")
+		if !ok || !strings.HasPrefix(key, prefix) || key == prefix {
+			continue
+		}
+		os.Setenv(strings.TrimPrefix(key, prefix), value)
+	}
+	for key, value := range profileDefaults[strings.ToLower(profile)] {
+		if _, ok := os.LookupEnv(key); !ok {
+			os.Setenv(key, value)
+		}
+	}
+}
+
+// Console returns where status lines and captions are written: stdout, or
+// stderr when headless audio is written to stdout
+func (c *Config) Console() io.Writer {
+	if c.Audio.Headless && c.Audio.HeadlessOutput == "-" {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// WriteSummary prints the resolved configuration with secrets masked
+func WriteSummary(w io.Writer, c *Config) {
+	profile := c.Profile
+	if profile == "" {
+		profile = "(none)"
+	}
+	secretsProvider := c.Secrets.Provider
+	if secretsProvider == "" {
+		secretsProvider = "(none)"
+	}
+
+	fmt.Fprintf(w, "Profile:             %s\n", profile)
+	fmt.Fprintf(w, "IAM token:           %s\n", Mask(c.IamToken))
+	fmt.Fprintf(w, "Folder ID:           %s\n", c.FolderID)
+	fmt.Fprintf(w, "Secrets provider:    %s\n", secretsProvider)
+	fmt.Fprintf(w, "Providers:           stt=%s tts=%s gpt=%s\n", c.Providers.STT, c.Providers.TTS, c.Providers.GPT)
+	if c.Engine.Mode == "realtime" {
+		fmt.Fprintf(w, "Engine mode:         realtime (model %s, voice %s, key %s)\n", c.Providers.RealtimeModel,
+			c.Providers.RealtimeVoice, Mask(c.Providers.RealtimeAPIKey))
+	} else {
+		fmt.Fprintf(w, "Engine mode:         pipeline\n")
+	}
+	if c.Providers.TTS == "command" {
+		fmt.Fprintf(w, "TTS command:         %s\n", c.Providers.TTSCommand)
+	}
+	if c.Providers.TTS == "elevenlabs" {
+		fmt.Fprintf(w, "ElevenLabs:          model %s, key %s\n", c.Providers.ElevenLabsModel, Mask(c.Providers.ElevenLabsAPIKey))
+	}
+	if c.Providers.STT == "yandex" {
+		fmt.Fprintf(w, "STT options:         model %s, normalization %t, profanity filter %t, literature text %t\n",
+			c.Providers.STTModel, c.Providers.STTNormalization, c.Providers.STTProfanityFilter, c.Providers.STTLiteratureText)
+	}
+	if c.Providers.STTFallbackCommand != "" {
+		fmt.Fprintf(w, "STT fallback:        %s\n", c.Providers.STTFallbackCommand)
+	}
+	if c.Providers.TTSFallbackCommand != "" {
+		fmt.Fprintf(w, "TTS fallback:        %s (after %d retries)\n", c.Providers.TTSFallbackCommand, c.Providers.TTSRetries)
+	}
+	if c.Providers.PluginDir != "" {
+		fmt.Fprintf(w, "Plugin directory:    %s\n", c.Providers.PluginDir)
+	}
+	fmt.Fprintf(w, "GPT model:           %s\n", c.GPTModel)
+	fmt.Fprintf(w, "First sentence by:   %s\n", getOrDefault(c.FastGPTModel, "(main model)"))
+	fmt.Fprintf(w, "Experiment:          %s\n", getOrDefault(c.ExperimentFile, "(none)"))
+	fmt.Fprintf(w, "Recruiter pipe:      %s\n", getOrDefault(c.RecruiterPipe, "(disabled)"))
+	switch {
+	case c.FeedAddr == "":
+		fmt.Fprintf(w, "Live feed:           (disabled)\n")
+	case c.FeedToken == "":
+		fmt.Fprintf(w, "Live feed:           %s (no token)\n", c.FeedAddr)
+	default:
+		fmt.Fprintf(w, "Live feed:           %s (token %s)\n", c.FeedAddr, Mask(c.FeedToken))
+	}
+	fmt.Fprintf(w, "Indicator:           %s\n", getOrDefault(c.Indicator, "(disabled)"))
+	switch {
+	case c.DemoCassette == "":
+		fmt.Fprintf(w, "Demo cassette:       (disabled)\n")
+	case cassette.Exists(c.DemoCassette):
+		fmt.Fprintf(w, "Demo cassette:       %s (replay)\n", c.DemoCassette)
+	default:
+		fmt.Fprintf(w, "Demo cassette:       %s (record)\n", c.DemoCassette)
+	}
+	if len(c.Audio.Languages) > 1 {
+		fmt.Fprintf(w, "Language:            %s (recognized: %s)\n", c.Audio.Language, strings.Join(c.Audio.Languages, ", "))
+	} else {
+		fmt.Fprintf(w, "Language:            %s\n", c.Audio.Language)
+	}
+	if c.Audio.Headless {
+		fmt.Fprintf(w, "Audio backend:       headless (in: %s, out: %s)\n",
+			getOrDefault(c.Audio.HeadlessInput, "silence"), getOrDefault(c.Audio.HeadlessOutput, "discard"))
+	} else {
+		fmt.Fprintf(w, "Audio backend:       %s\n", c.Audio.Backend)
+	}
+	fmt.Fprintf(w, "Sample rate:         %.0f Hz, %d frames per buffer\n", c.Audio.SampleRate, c.Audio.FramesPerBuffer)
+	fmt.Fprintf(w, "Playback prebuffer:  %s\n", c.Audio.PlaybackPrebuffer)
+	fmt.Fprintf(w, "Stream buffer:       %d KiB per stage\n", c.Audio.StreamBuffer/1024)
+	fmt.Fprintf(w, "Before recognition:  trim silence %t (below %.0f dBFS), normalize %t\n",
+		c.Audio.TrimSilence, c.Audio.SilenceThreshold, c.Audio.NormalizeLoudness)
+	if c.Audio.HighPassCutoff > 0 {
+		fmt.Fprintf(w, "High-pass filter:    %.0f Hz\n", c.Audio.HighPassCutoff)
+	} else {
+		fmt.Fprintf(w, "High-pass filter:    (disabled)\n")
+	}
+	fmt.Fprintf(w, "Voice:               %s (speed %.2f)\n", c.Engine.Voice, c.Engine.Speed)
+	fmt.Fprintf(w, "Voice roles:         questions %s, greeting %s, closing %s\n",
+		getOrDefault(c.Engine.Role, "(voice default)"), getOrDefault(c.Engine.GreetingRole, "(same)"),
+		getOrDefault(c.Engine.ClosingRole, "(same)"))
+	fmt.Fprintf(w, "Greeting:            %s\n", getOrDefault(c.Engine.Greeting, "(built-in)"))
+	fmt.Fprintf(w, "Farewell:            %s\n", getOrDefault(c.Engine.Farewell, "(built-in)"))
+	fmt.Fprintf(w, "Speech cache:        %s\n", getOrDefault(c.SpeechCacheDir, "(disabled)"))
+	locale := i18n.Locale(c.Audio.Language)
+	if slices.Contains(i18n.Locales(c.LocaleDir), locale) {
+		fmt.Fprintf(w, "Phrases:             %s\n", locale)
+	} else {
+		fmt.Fprintf(w, "Phrases:             %s (no locale, %s is used)\n", locale, i18n.DefaultLocale)
+	}
+	fmt.Fprintf(w, "Prosody markup:      %t\n", c.Engine.ProsodyMarkup)
+	fmt.Fprintf(w, "Silence timeout:     %s\n", c.Engine.SilenceTimeout)
+	fmt.Fprintf(w, "Adaptive silence:    %t\n", c.Engine.AdaptiveSilence)
+	fmt.Fprintf(w, "STT merge window:    %s\n", c.Engine.MergeWindow)
+	fmt.Fprintf(w, "Normalize answers:   %t\n", c.Engine.NormalizeTranscripts)
+	fmt.Fprintf(w, "Captions:            %t\n", c.Engine.Captions)
+	fmt.Fprintf(w, "Caption file:        %s\n", getOrDefault(c.Engine.CaptionFile, "(disabled)"))
+	fmt.Fprintf(w, "Earcons:             %s\n", getOrDefault(c.Engine.Earcons, "(disabled)"))
+	if c.Engine.MinConfidence > 0 {
+		fmt.Fprintf(w, "Min confidence:      %.2f\n", c.Engine.MinConfidence)
+	} else {
+		fmt.Fprintf(w, "Min confidence:      (disabled)\n")
+	}
+	fmt.Fprintf(w, "Log level:           %s\n", c.Engine.LogLevel)
+	fmt.Fprintf(w, "Difficulty strategy: %s\n", getOrDefault(c.Engine.DifficultyStrategy, "(disabled)"))
+	if c.Engine.DifficultyStrategy != "" {
+		if c.Engine.Rubric != nil {
+			fmt.Fprintf(w, "Scoring rubric:      %s\n", strings.Join(c.Engine.Rubric.Names(), ", "))
+		} else {
+			fmt.Fprintf(w, "Scoring rubric:      %s (default)\n", strings.Join(eval.DefaultRubric.Names(), ", "))
+		}
+	}
+	fmt.Fprintf(w, "Safety filter:       %s (%s)\n", c.Engine.SafetyFilter, strings.Join(c.Engine.SafetyJurisdictions, ", "))
+	fmt.Fprintf(w, "Safety audit log:    %s\n", getOrDefault(c.Engine.SafetyAuditLog, "(disabled)"))
+	fmt.Fprintf(w, "Deferral:            %s\n", c.Engine.Deferral)
+	fmt.Fprintf(w, "Turn log:            %s\n", getOrDefault(c.Engine.TurnLog, "(disabled)"))
+	fmt.Fprintf(w, "Sentiment analysis:  %t\n", c.Engine.SentimentAnalysis)
+	if c.Engine.NotesInterval > 0 {
+		fmt.Fprintf(w, "Interim notes:       every %d answers\n", c.Engine.NotesInterval)
+	} else {
+		fmt.Fprintf(w, "Interim notes:       (disabled)\n")
+	}
+	if bank := c.Engine.QuestionBank; bank != nil {
+		topics := c.Engine.QuestionTopics
+		if len(topics) == 0 {
+			topics = bank.Topics(c.Engine.QuestionTags)
+		}
+		fmt.Fprintf(w, "Question bank:       %d questions, %d per topic on %s\n", len(bank), c.Engine.QuestionsPerTopic, strings.Join(topics, ", "))
+		if len(c.Engine.QuestionTags) > 0 {
+			fmt.Fprintf(w, "Question tags:       %s\n", strings.Join(c.Engine.QuestionTags, ", "))
+		}
+	} else {
+		fmt.Fprintf(w, "Question bank:       (disabled)\n")
+	}
+	if c.Engine.MaxFollowUps > 0 {
+		fmt.Fprintf(w, "Follow-up limit:     %d in a row\n", c.Engine.MaxFollowUps)
+	} else {
+		fmt.Fprintf(w, "Follow-up limit:     (disabled)\n")
+	}
+	if len(c.Engine.Personas) > 0 {
+		var panel []string
+		for _, p := range c.Engine.Personas {
+			if p.ClonedVoice != "" {
+				panel = append(panel, fmt.Sprintf("%s (%s, cloned voice %s, consent by %s on %s)",
+					p.Name, p.Title, p.ClonedVoice, p.Consent.GivenBy, p.Consent.Date))
+			} else {
+				panel = append(panel, fmt.Sprintf("%s (%s)", p.Name, p.Title))
+			}
+		}
+		fmt.Fprintf(w, "Personas:            %s\n", strings.Join(panel, ", "))
+	} else {
+		fmt.Fprintf(w, "Personas:            (disabled)\n")
+	}
+	fmt.Fprintf(w, "Voice commands:      %t\n", c.Engine.VoiceCommands)
+	if c.Engine.Closing {
+		fmt.Fprintf(w, "Closing:             within %s, candidate %s\n", c.Engine.ClosingTimeout,
+			getOrDefault(c.Engine.CandidateName, "(from the conversation)"))
+	} else {
+		fmt.Fprintf(w, "Closing:             (disabled)\n")
+	}
+	if c.Engine.MaxDuration > 0 {
+		fmt.Fprintf(w, "Max duration:        %s\n", c.Engine.MaxDuration)
+		if len(c.Engine.TimeAnnouncements) > 0 {
+			left := make([]string, len(c.Engine.TimeAnnouncements))
+			for i, remaining := range c.Engine.TimeAnnouncements {
+				left[i] = remaining.String()
+			}
+			fmt.Fprintf(w, "Time announcements:  %s left\n", strings.Join(left, ", "))
+		}
+	} else {
+		fmt.Fprintf(w, "Max duration:        (unlimited)\n")
+	}
+	if c.Engine.StallTimeout > 0 {
+		fmt.Fprintf(w, "Stall timeout:       %s\n", c.Engine.StallTimeout)
+	} else {
+		fmt.Fprintf(w, "Stall timeout:       (disabled)\n")
+	}
+	fmt.Fprintf(w, "Provider warm-up:    %t\n", c.Engine.WarmUp)
+	fmt.Fprintf(w, "Reactions:           %t\n", c.Engine.Reactions)
+	fmt.Fprintf(w, "Report file:         %s\n", getOrDefault(c.Report.Path, "(disabled)"))
+	fmt.Fprintf(w, "Session directory:   %s\n", getOrDefault(c.Storage.SessionDir, "(disabled)"))
+	fmt.Fprintf(w, "Audit log:           %s\n", getOrDefault(c.Storage.AuditLog, "(disabled)"))
+	if c.Storage.SigningKey != "" {
+		fmt.Fprintf(w, "Session signing:     hmac-sha256 (key %s)\n", Mask(c.Storage.SigningKey))
+	} else {
+		fmt.Fprintf(w, "Session signing:     (disabled)\n")
+	}
+	switch c.Mail.Provider {
+	case "smtp":
+		fmt.Fprintf(w, "Email report:        smtp %s:%d (user %s, password %s) to %s\n", c.Mail.SMTPHost, c.Mail.SMTPPort,
+			getOrDefault(c.Mail.SMTPUsername, "(none)"), Mask(c.Mail.SMTPPassword), strings.Join(c.Mail.To, ", "))
+	case "sendgrid":
+		fmt.Fprintf(w, "Email report:        sendgrid (key %s) to %s\n", Mask(c.Mail.SendGridAPIKey), strings.Join(c.Mail.To, ", "))
+	default:
+		fmt.Fprintf(w, "Email report:        (disabled)\n")
+	}
+	if c.Upload.Bucket != "" {
+		fmt.Fprintf(w, "Upload:              %s/%s/%s (key %s, encryption %s, delete local %t)\n", c.Upload.Endpoint,
+			c.Upload.Bucket, c.Upload.Prefix, Mask(c.Upload.AccessKeyID), getOrDefault(c.Upload.Encryption, "none"),
+			c.Upload.DeleteLocal)
+	} else {
+		fmt.Fprintf(w, "Upload:              (disabled)\n")
+	}
+	if c.ATS.Provider != "" {
+		fmt.Fprintf(w, "ATS:                 %s (key %s), candidate %s, %d mapped fields\n", c.ATS.Provider,
+			Mask(c.ATS.APIKey), getOrDefault(c.ATS.CandidateID, "(not set)"), len(c.ATS.FieldMapping))
+	} else {
+		fmt.Fprintf(w, "ATS:                 (disabled)\n")
+	}
+	if c.Observability.Provider != "" {
+		fmt.Fprintf(w, "Observability:       %s %s (key %s)\n", c.Observability.Provider, c.Observability.Endpoint,
+			Mask(c.Observability.SecretKey))
+	} else {
+		fmt.Fprintf(w, "Observability:       (disabled)\n")
+	}
+	if c.Report.DuplicateDetection {
+		fmt.Fprintf(w, "Duplicate detection: similarity >= %.2f\n", c.Report.DuplicateThreshold)
+	} else {
+		fmt.Fprintf(w, "Duplicate detection: (disabled)\n")
+	}
+	if c.Resources.MaxProcs > 0 || c.Resources.MemoryLimit > 0 {
+		fmt.Fprintf(w, "Resource limits:     %d CPUs, %d MiB memory (0 = unlimited)\n",
+			c.Resources.MaxProcs, c.Resources.MemoryLimit>>20)
+	}
+	prompt := prompts.Hash(c.Engine.SystemPrompt)
+	if registry, err := prompts.Load(c.PromptDir); err == nil {
+		if version, ok := registry.Identify(c.Engine.SystemPrompt); ok {
+			prompt = version.Ref() + " (" + version.Hash + ")"
+		}
+	}
+	fmt.Fprintf(w, "System prompt:       %d characters, %s\n", len([]rune(c.Engine.SystemPrompt)), prompt)
+}
+
+// Mask hides all but the last four characters of a secret value
+func Mask(secret string) string {
+	if secret == "" {
+		return "(not set)"
+	}
+	runes := []rune(secret)
+	if len(runes) <= 8 {
+		return "****"
+	}
+	return "****" + string(runes[len(runes)-4:])
+}
+
+// recognitionLanguages returns the interview language followed by the other
+// languages to recognize, without duplicates
+func recognitionLanguages(language, others string) []string {
+	languages := []string{language}
+	for _, other := range splitList(others) {
+		if !slices.ContainsFunc(languages, func(l string) bool { return strings.EqualFold(l, other) }) {
+			languages = append(languages, other)
+		}
+	}
+	return languages
+}
+
+// splitList parses a comma separated list, skipping empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseVars parses a comma separated list of key=value pairs
+func parseVars(value string) (map[string]string, error) {
+	vars := make(map[string]string)
+	for _, pair := range splitList(value) {
+		key, val, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("%q is not a key=value pair", pair)
+		}
+		vars[strings.TrimSpace(key)] = strings.TrimSpace(val)
+	}
+	return vars, nil
+}
+
+// parseSize parses a byte count with an optional KiB, MiB or GiB suffix
+func parseSize(value string) (int64, error) {
+	multiplier := int64(1)
+	for suffix, size := range map[string]int64{"KiB": 1 << 10, "MiB": 1 << 20, "GiB": 1 << 30} {
+		if number, ok := strings.CutSuffix(value, suffix); ok {
+			value, multiplier = number, size
+			break
+		}
+	}
+
+	size, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil || size < 0 {
+		return 0, fmt.Errorf("%q is not a size like 512MiB", value)
+	}
+	return size * multiplier, nil
+}
+
+func getOrDefault(value, defaultValue string) string {
+	if value != "" {
+		return value
+	}
+	return defaultValue
+}
+
 func getEnvOrDefault(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -0,0 +1,32 @@
+package config
+
+import (
+	"os"
+	"testing"
+)
+
+func TestHeadlessStdoutKeepsTextOffStdout(t *testing.T) {
+	t.Setenv("IAM_TOKEN", "token")
+	t.Setenv("FOLDER_ID", "folder")
+	t.Setenv("HEADLESS", "true")
+	t.Setenv("HEADLESS_AUDIO_OUT", "-")
+	t.Setenv("TURN_LOG", "")
+	t.Setenv("REPORT_FILE", "")
+
+	cfg, err := buildConfig()
+	if err != nil {
+		t.Fatalf("buildConfig: %v", err)
+	}
+	if cfg.Console() != os.Stderr {
+		t.Errorf("status lines go to stdout with the audio")
+	}
+
+	for _, key := range []string{"TURN_LOG", "REPORT_FILE"} {
+		t.Run(key, func(t *testing.T) {
+			t.Setenv(key, "-")
+			if _, err := buildConfig(); err == nil {
+				t.Errorf("%s=- is accepted while the audio is written to stdout", key)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"os"
+	"testing"
+)
+
+func TestApplyProfile(t *testing.T) {
+	t.Setenv("LOG_LEVEL", "info")
+	t.Setenv("DEV_LOG_LEVEL", "warn")
+	t.Setenv("DEV_STT_PROVIDER", "command")
+	// Set first, so the test restores them
+	for _, key := range []string{"STT_PROVIDER", "DEMO_CASSETTE"} {
+		t.Setenv(key, "")
+		os.Unsetenv(key)
+	}
+
+	applyProfile("dev")
+
+	for key, want := range map[string]string{
+		"LOG_LEVEL":     "warn",          // The prefixed variable wins
+		"STT_PROVIDER":  "command",       // Providers are chosen per profile
+		"DEMO_CASSETTE": "cassettes/dev", // Unset variables take the profile default
+	} {
+		if got := os.Getenv(key); got != want {
+			t.Errorf("%s = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestApplyProfileKeepsSetVariables(t *testing.T) {
+	t.Setenv("LOG_LEVEL", "info")
+	t.Setenv("DEMO_CASSETTE", "demo")
+
+	applyProfile("dev")
+
+	if got := os.Getenv("LOG_LEVEL"); got != "info" {
+		t.Errorf("LOG_LEVEL = %q, want the variable that was set", got)
+	}
+	if got := os.Getenv("DEMO_CASSETTE"); got != "demo" {
+		t.Errorf("DEMO_CASSETTE = %q, want the variable that was set", got)
+	}
+}
@@ -0,0 +1,37 @@
+// Package correlation carries the ID of an interview session through
+// contexts, so the logs, files and provider requests of one interview can be
+// found by a single ID
+package correlation
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// RequestIDHeader is the header, or gRPC metadata key, that sends the session
+// ID with provider requests. Yandex Cloud keeps it in its request logs
+const RequestIDHeader = "x-client-request-id"
+
+type sessionKey struct{}
+
+// NewID returns a random identifier in the UUID format
+func NewID() string {
+	var id [16]byte
+	rand.Read(id[:])
+	id[6] = id[6]&0x0f | 0x40
+	id[8] = id[8]&0x3f | 0x80
+	text := hex.EncodeToString(id[:])
+	return text[:8] + "-" + text[8:12] + "-" + text[12:16] + "-" + text[16:20] + "-" + text[20:]
+}
+
+// WithSession returns a context carrying the session ID
+func WithSession(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, sessionKey{}, id)
+}
+
+// Session returns the session ID of the context, empty when it has none
+func Session(ctx context.Context) string {
+	id, _ := ctx.Value(sessionKey{}).(string)
+	return id
+}
@@ -0,0 +1,172 @@
+package doctor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/d1nch8g/aihr/gpt"
+	"github.com/d1nch8g/aihr/stt"
+	"github.com/d1nch8g/aihr/tts"
+)
+
+// Timeout limits every check, a provider slower than this is reported as unreachable
+const Timeout = 15 * time.Second
+
+// probeText is what the GPT and TTS checks send, kept short to spend little quota
+const probeText = "Reply with OK."
+
+// Result is the outcome of checking one provider
+type Result struct {
+	Component string // stt, tts or gpt
+	Provider  string
+	Latency   time.Duration
+	Err       error
+	Hint      string // Suggested fix when the check failed
+}
+
+// OK reports whether the provider answered
+func (r Result) OK() bool {
+	return r.Err == nil
+}
+
+// CheckGPT sends a minimal completion request
+func CheckGPT(ctx context.Context, provider string, client gpt.GPTClient) Result {
+	return run(ctx, "gpt", provider, func(ctx context.Context) error {
+		// Complete takes no context, the result is abandoned on timeout
+		done := make(chan error, 1)
+		go func() {
+			_, err := client.Complete("You are a connectivity check.", probeText)
+			done <- err
+		}()
+		select {
+		case err := <-done:
+			return err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+}
+
+// CheckTTS synthesizes a short phrase and discards the audio
+func CheckTTS(ctx context.Context, provider string, synthesizer tts.Synthesizer, options tts.SynthesisOptions) Result {
+	return run(ctx, "tts", provider, func(ctx context.Context) error {
+		audioData := make(chan []byte)
+		done := make(chan error, 1)
+		go func() {
+			done <- synthesizer.SynthesizeToStreamWithContext(ctx, "OK", options, audioData)
+		}()
+
+		received := 0
+		for chunk := range audioData {
+			received += len(chunk)
+		}
+		if err := <-done; err != nil {
+			return err
+		}
+		if received == 0 {
+			return errors.New("no audio was returned")
+		}
+		return nil
+	})
+}
+
+// CheckSTT streams a second of silence, which must be accepted without an error
+func CheckSTT(ctx context.Context, provider string, client stt.STTClient, sampleRate int64) Result {
+	return run(ctx, "stt", provider, func(ctx context.Context) error {
+		audioData := make(chan []byte, 1)
+		audioData <- make([]byte, sampleRate*2) // One second of 16-bit mono silence
+		close(audioData)
+
+		results := make(chan string)
+		done := make(chan error, 1)
+		go func() {
+			done <- client.StreamRecognize(ctx, audioData, results, sampleRate)
+		}()
+		for range results {
+		}
+		return <-done
+	})
+}
+
+// run times one check and attaches a hint when it fails
+func run(ctx context.Context, component, provider string, check func(ctx context.Context) error) Result {
+	ctx, cancel := context.WithTimeout(ctx, Timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check(ctx)
+	result := Result{Component: component, Provider: provider, Latency: time.Since(start), Err: err}
+	if err != nil {
+		result.Hint = Hint(err)
+	}
+	return result
+}
+
+// Hint suggests a fix for a failed check based on its error, or returns an
+// empty string when the error is not recognized
+func Hint(err error) string {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Sprintf("the provider did not answer within %s, check the network, proxy and firewall", Timeout)
+	}
+
+	message := strings.ToLower(err.Error())
+	switch {
+	case containsAny(message, "status 401", "unauthenticated", "expired", "invalid token", "iam token"):
+		return "the IAM token is invalid or expired, IAM tokens live at most 12 hours: " +
+			"issue a new one with `yc iam create-token` or configure SECRETS_PROVIDER to refresh it"
+	case containsAny(message, "status 403", "permissiondenied", "permission denied"):
+		return "the account has no access to the service: check FOLDER_ID and that the service account " +
+			"has the ai.speechkit-stt.user, ai.speechkit-tts.user and ai.languageModels.user roles"
+	case containsAny(message, "status 429", "resourceexhausted", "quota"):
+		return "the quota or rate limit is exhausted, request a quota increase or wait for it to reset"
+	case containsAny(message, "status 404", "not found") && !strings.Contains(message, "executable"):
+		return "the model or endpoint was not found, check GPT_MODEL and FOLDER_ID"
+	case containsAny(message, "executable file not found", "command not found"):
+		return "the local command is not installed or not on PATH"
+	case containsAny(message, "no such host", "connection refused", "network is unreachable", "i/o timeout", "unavailable"):
+		return "the provider is unreachable, check the network, DNS, proxy and firewall"
+	case containsAny(message, "certificate", "x509"):
+		return "TLS verification failed, check the system CA certificates and any intercepting proxy"
+	}
+	return ""
+}
+
+// containsAny reports whether s contains any of the substrings
+func containsAny(s string, substrings ...string) bool {
+	for _, substring := range substrings {
+		if strings.Contains(s, substring) {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteReport prints the results as a table followed by the hints for failed checks
+func WriteReport(w io.Writer, results []Result) {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "COMPONENT\tPROVIDER\tSTATUS\tLATENCY")
+	for _, result := range results {
+		status := "ok"
+		if !result.OK() {
+			status = "FAILED"
+		}
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\n", result.Component, result.Provider, status,
+			result.Latency.Round(time.Millisecond))
+	}
+	table.Flush()
+
+	for _, result := range results {
+		if result.OK() {
+			continue
+		}
+		fmt.Fprintf(w, "\n%s: %v\n", result.Component, result.Err)
+		if result.Hint != "" {
+			fmt.Fprintf(w, "  fix: %s\n", result.Hint)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+// Package earcon provides the short sounds that mark turn-taking: the
+// interviewer starts listening, stops listening, or something went wrong
+package earcon
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+
+	"github.com/d1nch8g/aihr/pcm"
+	"github.com/d1nch8g/aihr/sound"
+	"github.com/d1nch8g/aihr/tts"
+)
+
+// Cues marked by a sound, also the names of their WAV files
+const (
+	ListenStart = "listen-start" // The candidate can talk
+	ListenStop  = "listen-stop"  // The answer was heard
+	Error       = "error"        // The turn failed or the microphone was lost
+)
+
+// Sources of the sounds besides a directory of WAV files
+const (
+	SourceTones   = "tones"   // Sine tones synthesized at startup
+	SourceBundled = "bundled" // Chimes embedded in the binary
+)
+
+// cues are the cues of a set
+var cues = []string{ListenStart, ListenStop, Error}
+
+// SampleRate is the rate of every sound, the one speech is played at by
+// default, so the sounds need no format of their own
+const SampleRate = 22050
+
+//go:embed sounds
+var bundled embed.FS
+
+// Set is the audio of the cues as mono 16-bit little-endian PCM
+type Set struct {
+	Source string
+	sounds map[string][]byte
+}
+
+// Load returns the sounds of a source: SourceTones, SourceBundled or a
+// directory of listen-start.wav, listen-stop.wav and error.wav files. Cues
+// without a file in the directory are marked by tones
+func Load(source string) (*Set, error) {
+	set := &Set{Source: source, sounds: make(map[string][]byte, len(cues))}
+	for _, cue := range cues {
+		var (
+			audio []byte
+			err   error
+		)
+		switch source {
+		case SourceTones:
+			audio = pcm.Encode(tone(cue))
+		case SourceBundled:
+			audio, err = readWAV(bundled, "sounds/"+cue+".wav")
+		default:
+			audio, err = readWAV(os.DirFS(source), cue+".wav")
+			if errors.Is(err, fs.ErrNotExist) {
+				audio, err = pcm.Encode(tone(cue)), nil
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the %s earcon: %w", cue, err)
+		}
+		set.sounds[cue] = audio
+	}
+	return set, nil
+}
+
+// Sound returns the audio of the cue, false when the set has none
+func (s *Set) Sound(cue string) ([]byte, bool) {
+	audio, ok := s.sounds[cue]
+	return audio, ok
+}
+
+// readWAV reads a 16-bit PCM WAV file and converts it to mono at SampleRate
+func readWAV(fsys fs.FS, name string) ([]byte, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	format, offset, err := tts.ParseWAVHeader(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", filepath.Base(name), err)
+	}
+	if format.BitsPerSample != 16 || format.Channels < 1 {
+		return nil, fmt.Errorf("%s must be 16-bit PCM", filepath.Base(name))
+	}
+	samples := pcm.ToMono(pcm.Decode(data[offset:]), format.Channels)
+	if format.SampleRate != SampleRate {
+		samples = sound.NewResampler(float64(format.SampleRate), SampleRate, 1).Process(samples)
+	}
+	return pcm.Encode(samples), nil
+}
+
+// note is a sine tone of a synthesized cue
+type note struct {
+	frequency float64
+	duration  float64 // Seconds
+}
+
+// tone synthesizes the cue: two rising notes to start listening, two falling
+// ones to stop and two low ones on errors
+func tone(cue string) []int16 {
+	var notes []note
+	switch cue {
+	case ListenStart:
+		notes = []note{{660, 0.07}, {880, 0.09}}
+	case ListenStop:
+		notes = []note{{880, 0.07}, {660, 0.09}}
+	default:
+		notes = []note{{330, 0.12}, {0, 0.06}, {330, 0.12}}
+	}
+
+	// Notes fade in and out so they do not click
+	const amplitude, fade = 0.3 * 32767, 0.005
+	var samples []int16
+	for _, n := range notes {
+		count := int(n.duration * SampleRate)
+		for i := range count {
+			t := float64(i) / SampleRate
+			gain := min(1, t/fade, (n.duration-t)/fade)
+			samples = append(samples, pcm.Clamp16(amplitude*gain*math.Sin(2*math.Pi*n.frequency*t)))
+		}
+	}
+	return samples
+}
@@ -0,0 +1,29 @@
+// Package embed turns text into vectors for semantic similarity
+package embed
+
+import "math"
+
+// Embedder defines the interface for text embedding implementations
+type Embedder interface {
+	// Embed returns the embedding vector of the text
+	Embed(text string) ([]float64, error)
+}
+
+// CosineSimilarity returns the cosine of the angle between two vectors,
+// 1 for identical directions. Vectors of different length have similarity 0
+func CosineSimilarity(a, b []float64) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+
+	var dot, normA, normB float64
+	for i := range a {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
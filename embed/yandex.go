@@ -0,0 +1,100 @@
+package embed
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+const (
+	YandexEmbeddingEndpoint = "https://llm.api.cloud.yandex.net/foundationModels/v1/textEmbedding"
+)
+
+// embeddingRequest represents the request to the Yandex embedding API
+type embeddingRequest struct {
+	ModelURI string `json:"modelUri"`
+	Text     string `json:"text"`
+}
+
+// embeddingResponse represents the response from the Yandex embedding API
+type embeddingResponse struct {
+	Embedding    []float64 `json:"embedding"`
+	NumTokens    string    `json:"numTokens"`
+	ModelVersion string    `json:"modelVersion"`
+}
+
+// YandexEmbedder is a client for the Yandex text embedding API
+type YandexEmbedder struct {
+	FolderID   string
+	IAMToken   string
+	HTTPClient *http.Client
+	ModelURI   string
+
+	tokenMutex sync.RWMutex
+}
+
+// Ensure YandexEmbedder implements Embedder interface
+var _ Embedder = (*YandexEmbedder)(nil)
+
+// NewYandexEmbedder creates a new Yandex embedding client
+func NewYandexEmbedder(folderID, iamToken string) *YandexEmbedder {
+	return &YandexEmbedder{
+		FolderID:   folderID,
+		IAMToken:   iamToken,
+		HTTPClient: &http.Client{},
+		ModelURI:   "emb://" + folderID + "/text-search-doc/latest",
+	}
+}
+
+// Embed requests the embedding vector of the text
+func (c *YandexEmbedder) Embed(text string) ([]float64, error) {
+	reqBody, err := json.Marshal(embeddingRequest{
+		ModelURI: c.ModelURI,
+		Text:     text,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequest("POST", YandexEmbeddingEndpoint, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	c.tokenMutex.RLock()
+	httpReq.Header.Set("Authorization", "Bearer "+c.IAMToken)
+	c.tokenMutex.RUnlock()
+	httpReq.Header.Set("x-folder-id", c.FolderID)
+
+	resp, err := c.HTTPClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var response embeddingResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if len(response.Embedding) == 0 {
+		return nil, fmt.Errorf("empty embedding in response")
+	}
+
+	return response.Embedding, nil
+}
+
+// SetIamToken replaces the IAM token used for subsequent requests
+func (c *YandexEmbedder) SetIamToken(iamToken string) {
+	c.tokenMutex.Lock()
+	defer c.tokenMutex.Unlock()
+	c.IAMToken = iamToken
+}
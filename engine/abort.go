@@ -0,0 +1,65 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+
+	"github.com/d1nch8g/aihr/i18n"
+	"github.com/d1nch8g/aihr/session"
+	"github.com/d1nch8g/aihr/tts"
+)
+
+// confirmationWords are the English and Russian words that confirm the
+// candidate has to stop, any other reply continues the interview
+var confirmationWords = []string{
+	"yes", "yeah", "yep", "sure", "correct", "right",
+	"да", "конечно", "верно", "ага", "точно",
+}
+
+// errInterviewAborted stops the conversation loop when the candidate has to
+// stop before the end of the interview
+var errInterviewAborted = errors.New("interview aborted by the candidate")
+
+// abort asks the candidate to confirm they have to stop. A confirmed stop
+// marks the record as aborted, so it is reported as a partial interview to
+// reschedule, and ends the interview with the i18n.AbortFarewell phrase
+// instead of the closing message
+func (e *Engine) abort(ctx context.Context) error {
+	if err := e.speakResponse(ctx, e.phrase(i18n.AbortConfirm)); err != nil {
+		return fmt.Errorf("failed to confirm stop: %w", err)
+	}
+	input, err := e.captureUserInput(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to capture confirmation: %w", err)
+	}
+	log.Printf("User said: %s", input.text)
+
+	if !confirmed(input.text) {
+		e.emit(session.EventAbortCanceled, "")
+		if err := e.speakResponse(ctx, e.phrase(i18n.AbortCanceled)); err != nil {
+			return fmt.Errorf("failed to speak response: %w", err)
+		}
+		return nil
+	}
+
+	log.Printf("Candidate stopped the interview after %d answers", len(e.GetRecord().Answers))
+	e.emit(session.EventAborted, "")
+	e.markAborted()
+
+	config := e.currentConfig()
+	farewell := tts.Template{Text: e.phrase(i18n.AbortFarewell), Variables: config.GreetingVariables}
+	log.Printf("AI response: %s", farewell.Render())
+	if err := e.speakTemplate(ctx, farewell, roleOrDefault(config.ClosingRole, config.Role)); err != nil {
+		log.Printf("Failed to speak farewell: %v", err)
+	}
+	return errInterviewAborted
+}
+
+// confirmed returns whether the reply starts with a confirmation word
+func confirmed(reply string) bool {
+	words := normalizeWords(reply)
+	return len(words) > 0 && slices.Contains(confirmationWords, words[0])
+}
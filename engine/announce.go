@@ -0,0 +1,56 @@
+package engine
+
+import (
+	"log"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/d1nch8g/aihr/i18n"
+	"github.com/d1nch8g/aihr/session"
+	"github.com/d1nch8g/aihr/tts"
+)
+
+// scheduleAnnouncements arms the checkpoints of TimeAnnouncements for an
+// interview that ends at the deadline. A checkpoint that passed is announced
+// before the next response, so the announcement never cuts into speech. The
+// returned function stops the timers
+func (e *Engine) scheduleAnnouncements(deadline time.Time) func() {
+	e.deadline = deadline
+	e.announcePending.Store(false)
+
+	var timers []*time.Timer
+	for _, remaining := range e.currentConfig().TimeAnnouncements {
+		wait := time.Until(deadline) - remaining
+		if remaining <= 0 || wait <= 0 {
+			continue
+		}
+		timers = append(timers, time.AfterFunc(wait, func() {
+			e.announcePending.Store(true)
+		}))
+	}
+	return func() {
+		for _, timer := range timers {
+			timer.Stop()
+		}
+	}
+}
+
+// announceTime tells the candidate how many minutes are left, before the
+// response being spoken, when a checkpoint passed since the last announcement
+func (e *Engine) announceTime(speech *speech) {
+	if !e.announcePending.Swap(false) {
+		return
+	}
+	minutes := max(1, int(math.Round(time.Until(e.deadline).Minutes())))
+	template := tts.Template{
+		Text:      e.phrase(i18n.TimeRemaining),
+		Variables: map[string]string{"minutes": strconv.Itoa(minutes)},
+	}
+
+	log.Printf("AI response: %s", template.Render())
+	e.emitf(session.EventTimeAnnounced, "%d minutes left", minutes)
+	if err := speech.say(speechSegment{template: template, text: template.Render(), aside: true}); err != nil {
+		log.Printf("Failed to announce the time left: %v", err)
+	}
+}
@@ -0,0 +1,138 @@
+package engine
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/d1nch8g/aihr/pcm"
+	"github.com/d1nch8g/aihr/tts"
+)
+
+const (
+	// captionCharsPerSecond is the speaking rate assumed at speed 1 until the
+	// length of the audio is known
+	captionCharsPerSecond = 14.0
+
+	// captionInterval is how often the captions catch up with playback
+	captionInterval = 50 * time.Millisecond
+)
+
+// captioner writes the text of a response word by word while its audio plays.
+// Word times are estimated by spreading the duration of the audio over the
+// words in proportion to their length
+type captioner struct {
+	words  []string
+	starts []float64 // Fraction of the text before each word
+	speed  float64
+	mutex  sync.Mutex // Guards the words, which grow as segments are queued
+
+	bytesPerSecond float64 // Zero when the audio format is unknown
+	estimate       time.Duration
+
+	started   atomic.Int64 // Unix nanoseconds of the first audio chunk, zero before it
+	delivered atomic.Int64 // Bytes of audio passed to the player
+	finished  atomic.Bool  // Set once all audio was passed to the player
+}
+
+// newCaptioner prepares the captions of text spoken at the given speed
+func newCaptioner(text string, format tts.AudioFormat, hasFormat bool, speed float64) *captioner {
+	if speed <= 0 {
+		speed = 1
+	}
+	c := &captioner{speed: speed}
+	if hasFormat {
+		c.bytesPerSecond = float64(format.SampleRate * format.Channels * pcm.SampleSize)
+	}
+	c.extend(text)
+	return c
+}
+
+// extend adds the text of a segment queued after playback started
+func (c *captioner) extend(text string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.words = append(c.words, strings.Fields(text)...)
+	total := 0
+	for _, word := range c.words {
+		total += len([]rune(word)) + 1
+	}
+	c.starts = c.starts[:0]
+	position := 0
+	for _, word := range c.words {
+		c.starts = append(c.starts, float64(position)/float64(max(total, 1)))
+		position += len([]rune(word)) + 1
+	}
+	c.estimate = time.Duration(float64(total) / (captionCharsPerSecond * c.speed) * float64(time.Second))
+}
+
+// add records audio passed to the player, the first chunk starts the clock
+func (c *captioner) add(size int) {
+	c.started.CompareAndSwap(0, time.Now().UnixNano())
+	c.delivered.Add(int64(size))
+}
+
+// finish records that all audio was passed to the player
+func (c *captioner) finish() {
+	c.finished.Store(true)
+}
+
+// duration returns the estimated length of the whole audio, the caller holds the mutex
+func (c *captioner) duration() time.Duration {
+	if c.bytesPerSecond == 0 {
+		return c.estimate
+	}
+	delivered := time.Duration(float64(c.delivered.Load()) / c.bytesPerSecond * float64(time.Second))
+	if c.finished.Load() {
+		return delivered
+	}
+	return max(delivered, c.estimate)
+}
+
+// run writes the words as their estimated time comes until playback ends and
+// done receives whether the audio played to the end, the remaining words are
+// then written at once
+func (c *captioner) run(w io.Writer, done <-chan bool) {
+	ticker := time.NewTicker(captionInterval)
+	defer ticker.Stop()
+
+	fmt.Fprint(w, "AI:")
+	next := 0
+	for {
+		select {
+		case complete := <-done:
+			if complete {
+				c.mutex.Lock()
+				next = c.write(w, next, len(c.words))
+				c.mutex.Unlock()
+			}
+			fmt.Fprintln(w)
+			return
+		case <-ticker.C:
+			started := c.started.Load()
+			if started == 0 {
+				continue
+			}
+			c.mutex.Lock()
+			elapsed := float64(time.Since(time.Unix(0, started))) / float64(max(c.duration(), 1))
+			due := next
+			for due < len(c.words) && c.starts[due] <= elapsed {
+				due++
+			}
+			next = c.write(w, next, due)
+			c.mutex.Unlock()
+		}
+	}
+}
+
+// write writes the words from one index up to another and returns the latter
+func (c *captioner) write(w io.Writer, from, to int) int {
+	for _, word := range c.words[from:to] {
+		fmt.Fprint(w, " "+word)
+	}
+	return to
+}
@@ -0,0 +1,92 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/d1nch8g/aihr/observe"
+	"github.com/d1nch8g/aihr/session"
+	"github.com/d1nch8g/aihr/tts"
+)
+
+// closingInstruction asks the model to end the interview instead of asking another question
+const closingInstruction = "\n\nThe interview is over, do not ask any more questions. " +
+	"Say goodbye to the candidate in a few sentences: briefly summarize what you discussed, " +
+	"explain the next steps and thank the candidate by name."
+
+// closingInput is sent as the user message of the closing request
+const closingInput = "(The interview has ended.)"
+
+// RequestClosing asks a running interview to wrap up: the current turn is
+// interrupted, the closing message is spoken and Start returns
+func (e *Engine) RequestClosing() {
+	e.closeOnce.Do(func() {
+		e.emit(session.EventClosing, "")
+		close(e.closeRequested)
+	})
+}
+
+// closingRequested returns whether RequestClosing was called
+func (e *Engine) closingRequested() bool {
+	select {
+	case <-e.closeRequested:
+		return true
+	default:
+		return false
+	}
+}
+
+// conclude speaks the closing message, or the farewell when closing is disabled
+func (e *Engine) conclude(ctx context.Context) {
+	config := e.currentConfig()
+	if config.ClosingTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, config.ClosingTimeout)
+		defer cancel()
+	}
+
+	role := roleOrDefault(config.ClosingRole, config.Role)
+	speak := e.speakTemplate
+	message := e.farewell()
+	if config.Closing {
+		message = tts.Template{Text: e.closingMessage(config)}
+	} else {
+		speak = e.speakScript
+	}
+
+	log.Printf("AI response: %s", message.Render())
+	if err := speak(ctx, message, role); err != nil {
+		log.Printf("Failed to speak closing message: %v", err)
+	}
+}
+
+// closingMessage asks the model for a summary and goodbye. The fixed farewell
+// and next steps are used when the model fails
+func (e *Engine) closingMessage(config EngineConfig) string {
+	fallback := strings.TrimSpace(e.farewell().Render() + " " + config.NextSteps)
+
+	var instruction strings.Builder
+	instruction.WriteString(closingInstruction)
+	if config.NextSteps != "" {
+		instruction.WriteString(fmt.Sprintf(" Next steps: %s", config.NextSteps))
+	}
+	if config.CandidateName != "" {
+		instruction.WriteString(fmt.Sprintf(" The candidate's name is %s.", config.CandidateName))
+	} else {
+		instruction.WriteString(" Use the name the candidate introduced themselves with, if any.")
+	}
+
+	systemMessage := e.buildSystemMessage() + instruction.String()
+	response, err := e.complete(observe.StageClosing, systemMessage, closingInput)
+	if err != nil {
+		log.Printf("Failed to generate closing message: %v", err)
+		return fallback
+	}
+	response, err = e.moderateResponse(systemMessage, closingInput, response)
+	if err != nil || strings.TrimSpace(response) == "" {
+		return fallback
+	}
+	return response
+}
@@ -0,0 +1,207 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/d1nch8g/aihr/observe"
+)
+
+// Command is a control phrase the candidate can say instead of answering
+type Command int
+
+const (
+	CommandNone     Command = iota
+	CommandRepeat           // Repeat the last question
+	CommandRephrase         // Ask the last question in other words
+	CommandSkip             // Move on to a different question
+	CommandFinish           // End the interview
+	CommandAbort            // Stop before the end, e.g. to reschedule
+)
+
+// String returns the command name used in logs
+func (c Command) String() string {
+	switch c {
+	case CommandRepeat:
+		return "repeat"
+	case CommandRephrase:
+		return "rephrase"
+	case CommandSkip:
+		return "skip"
+	case CommandFinish:
+		return "finish"
+	case CommandAbort:
+		return "abort"
+	default:
+		return "none"
+	}
+}
+
+// DefaultCommandPhrases are the English and Russian control phrases
+// recognized when voice commands are enabled
+var DefaultCommandPhrases = map[Command][]string{
+	CommandRepeat: {
+		"repeat the question", "repeat that", "say that again", "could you repeat",
+		"повторите вопрос", "повтори вопрос", "повторите пожалуйста",
+	},
+	CommandRephrase: {
+		"rephrase the question", "rephrase that", "what do you mean", "i don't understand the question",
+		"переформулируйте вопрос", "не понял вопрос", "не поняла вопрос", "что вы имеете в виду",
+	},
+	CommandSkip: {
+		"skip this question", "skip the question", "next question",
+		"пропустить вопрос", "пропустим вопрос", "следующий вопрос",
+	},
+	CommandFinish: {
+		"i'm done", "i am done", "end the interview", "stop the interview",
+		"я закончил", "я закончила", "закончим собеседование",
+	},
+	CommandAbort: {
+		"i need to stop", "i have to stop", "i have to go", "i need to go", "can we reschedule", "i need to reschedule",
+		"мне нужно идти", "мне нужно прерваться", "мне надо идти", "давайте перенесём", "давайте перенесем",
+		"можно перенести собеседование",
+	},
+}
+
+// commandSlack is how many words an utterance may have beyond the phrase,
+// so "could you please repeat the question" still counts but an answer that
+// merely mentions a phrase does not
+const commandSlack = 3
+
+// rephrasePrompt asks for a simpler wording of a question. It is sent without the
+// conversation history to keep the round trip short
+const rephrasePrompt = "You are an interviewer. The candidate did not understand the question below. " +
+	"Rephrase it in simpler words, keeping its meaning and language. Reply with the rephrased question only."
+
+// skipInstruction replaces the answer when the candidate skips a question
+const skipInstruction = "(The candidate asked to skip this question. Briefly acknowledge it and ask a different question.)"
+
+// errInterviewFinished stops the conversation loop when the candidate ends the interview
+var errInterviewFinished = errors.New("interview finished by the candidate")
+
+// matchCommand returns the control command said in the utterance, if any
+func (e *Engine) matchCommand(userInput string) Command {
+	config := e.currentConfig()
+	if !config.VoiceCommands {
+		return CommandNone
+	}
+	phrases := config.CommandPhrases
+	if phrases == nil {
+		phrases = DefaultCommandPhrases
+	}
+
+	words := normalizeWords(userInput)
+	for _, command := range []Command{CommandFinish, CommandAbort, CommandSkip, CommandRephrase, CommandRepeat} {
+		for _, phrase := range phrases[command] {
+			phraseWords := normalizeWords(phrase)
+			if len(phraseWords) > 0 && len(words) <= len(phraseWords)+commandSlack && containsWords(words, phraseWords) {
+				return command
+			}
+		}
+	}
+	return CommandNone
+}
+
+// handleCommand performs the action of a control command
+func (e *Engine) handleCommand(ctx context.Context, command Command) error {
+	log.Printf("Candidate command: %s", command)
+
+	switch command {
+	case CommandRepeat:
+		question := e.lastQuestion()
+		if question == "" {
+			return nil
+		}
+		// Replaying the cached audio answers instantly, without another TTS round trip
+		replayed, err := e.replaySpeech(ctx, question)
+		if err == nil && !replayed {
+			err = e.speakResponse(ctx, question)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to repeat question: %w", err)
+		}
+		return nil
+
+	case CommandRephrase:
+		question := e.lastQuestion()
+		if question == "" {
+			return nil
+		}
+		rephrased, err := e.complete(observe.StageRephrase, rephrasePrompt, question)
+		if err != nil {
+			return fmt.Errorf("failed to rephrase question: %w", err)
+		}
+		log.Printf("AI response: %s", rephrased)
+		if err := e.speakResponse(ctx, rephrased); err != nil {
+			return fmt.Errorf("failed to speak response: %w", err)
+		}
+		return nil
+
+	case CommandSkip:
+		e.awaitFollowUp()
+		e.planQuestion()
+		response, err := e.generateResponse(skipInstruction, false)
+		if err != nil {
+			return fmt.Errorf("failed to generate AI response: %w", err)
+		}
+		e.checkFollowUp("", response) // Skipping always moves on
+		response, spoken := e.prepareSpeech(response)
+		log.Printf("AI response: %s", response)
+		if err := e.speakMarkup(ctx, response, spoken); err != nil {
+			return fmt.Errorf("failed to speak response: %w", err)
+		}
+		e.addToHistory(ConversationEntry{
+			UserInput:  "(skipped the question)",
+			AIResponse: response,
+			Timestamp:  time.Now(),
+		})
+		return nil
+
+	case CommandFinish:
+		e.conclude(ctx)
+		return errInterviewFinished
+
+	case CommandAbort:
+		return e.abort(ctx)
+	}
+	return nil
+}
+
+// lastQuestion returns the question the candidate is answering
+func (e *Engine) lastQuestion() string {
+	if question := e.lastAIResponse(); question != "" {
+		return question
+	}
+	return e.greeting().Render()
+}
+
+// normalizeWords lowercases the text and splits it into words, dropping
+// punctuation so "I'm done." and "im done" compare equal
+func normalizeWords(text string) []string {
+	text = strings.NewReplacer("'", "", "’", "").Replace(strings.ToLower(text))
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// containsWords returns whether phrase occurs in words as a contiguous sequence
+func containsWords(words, phrase []string) bool {
+	for i := 0; i+len(phrase) <= len(words); i++ {
+		match := true
+		for j := range phrase {
+			if words[i+j] != phrase[j] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
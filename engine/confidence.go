@@ -0,0 +1,36 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/d1nch8g/aihr/i18n"
+	"github.com/d1nch8g/aihr/session"
+)
+
+// maxRepeatRequests is the number of times in a row the candidate is asked to
+// repeat an unclear answer, the next answer is accepted whatever its confidence
+const maxRepeatRequests = 2
+
+// unclear reports whether an answer with the given recognition confidence
+// should be repeated rather than evaluated
+func (e *Engine) unclear(confidence float64) bool {
+	if e.config.MinConfidence <= 0 || confidence <= 0 || confidence >= e.config.MinConfidence {
+		return false
+	}
+	return e.unclearTurns < maxRepeatRequests
+}
+
+// askToRepeat records the unclear answer and asks the candidate to repeat it.
+// The answer is not sent to the model and does not enter the history
+func (e *Engine) askToRepeat(ctx context.Context, answer session.Answer) error {
+	e.unclearTurns++
+	e.emitf(session.EventAnswerUnclear, "confidence %.2f, repeat request %d", answer.Confidence, e.unclearTurns)
+	log.Printf("Recognition confidence %.2f is below %.2f, asking to repeat", answer.Confidence, e.config.MinConfidence)
+	e.recordAnswer(answer)
+	if err := e.speakResponse(ctx, e.phrase(i18n.RepeatRequest)); err != nil {
+		return fmt.Errorf("failed to ask to repeat: %w", err)
+	}
+	return nil
+}
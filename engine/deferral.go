@@ -0,0 +1,49 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/d1nch8g/aihr/i18n"
+	"github.com/d1nch8g/aihr/session"
+)
+
+// deferralInstruction covers the questions the deferral rules miss
+const deferralInstruction = "\n\nDo not answer questions of the candidate about salary, compensation, " +
+	"visa sponsorship or legal terms of employment. Say that the recruiter will follow up on them after the interview."
+
+// deferredTopic returns the subject of a candidate question only the
+// recruiter may answer, empty when there is none
+func (e *Engine) deferredTopic(userInput string) string {
+	if e.config.Deferral == nil {
+		return ""
+	}
+	verdict, err := e.config.Deferral.Check(userInput)
+	if err != nil {
+		log.Printf("Failed to check for deferred questions: %v", err)
+		return ""
+	}
+	if verdict.Allowed {
+		return ""
+	}
+	return verdict.Category
+}
+
+// deferQuestion answers the candidate's question with the scripted
+// i18n.Deferral phrase and flags it in the record for the recruiter. The
+// question is not sent to the model and does not enter the history
+func (e *Engine) deferQuestion(ctx context.Context, topic, userInput string) error {
+	log.Printf("Deferring %s question to the recruiter", topic)
+	e.emitf(session.EventQuestionDeferred, "%s: %q", topic, userInput)
+	e.recordDeferred(session.DeferredQuestion{
+		Time:     time.Now(),
+		Topic:    topic,
+		Question: userInput,
+	})
+	if err := e.speakResponse(ctx, e.phrase(i18n.Deferral)); err != nil {
+		return fmt.Errorf("failed to defer question: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package engine
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/d1nch8g/aihr/audio"
+	"github.com/d1nch8g/aihr/earcon"
+	"github.com/d1nch8g/aihr/i18n"
+	"github.com/d1nch8g/aihr/session"
+	"github.com/d1nch8g/aihr/stream"
+)
+
+// inputRetryInterval is the pause between attempts to reconnect a lost input device
+const inputRetryInterval = 2 * time.Second
+
+// recoverInput pauses the interview after the input device was lost. The
+// candidate is told about it and capture is reconnected to the default device
+// until it delivers audio again, then the last question is repeated. It
+// returns early without an error when closing is requested
+func (e *Engine) recoverInput(ctx context.Context, cause error) error {
+	log.Printf("Pausing the interview: %v", cause)
+	e.emit(session.EventInputLost, cause.Error())
+	e.playEarcon(ctx, earcon.Error)
+	if err := e.speakResponse(ctx, e.phrase(i18n.InputLost)); err != nil {
+		log.Printf("Failed to speak notice: %v", err)
+	}
+
+	reconnector, ok := e.audioStreamer.(audio.Reconnector)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-e.closeRequested:
+			return nil
+		case <-time.After(inputRetryInterval):
+		}
+
+		// Streamers that cannot reconnect are retried by starting capture again
+		if !ok {
+			break
+		}
+		if err := reconnector.Reconnect(); err != nil {
+			e.debugf("Audio input still unavailable: %v", err)
+			continue
+		}
+		break
+	}
+
+	log.Println("Audio input is available again, resuming the interview")
+	e.emit(session.EventInputRestored, "")
+	if err := e.speakResponse(ctx, e.phrase(i18n.InputRestored)); err != nil {
+		log.Printf("Failed to speak notice: %v", err)
+	}
+	if err := e.handleCommand(ctx, CommandRepeat); err != nil {
+		log.Printf("Failed to resume the interview: %v", err)
+	}
+	return nil
+}
+
+// capturePool returns the pool the audio streamer captures into, nil when it
+// allocates every chunk
+func (e *Engine) capturePool() *stream.Pool {
+	if pooled, ok := e.audioStreamer.(audio.BufferPooler); ok {
+		return pooled.BufferPool()
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Difficulty represents the level of questions the AI should ask
+type Difficulty int
+
+const (
+	DifficultyEasy Difficulty = iota + 1
+	DifficultyMedium
+	DifficultyHard
+	DifficultyExpert
+)
+
+// String returns the human readable name of the difficulty level
+func (d Difficulty) String() string {
+	switch d {
+	case DifficultyEasy:
+		return "easy"
+	case DifficultyMedium:
+		return "medium"
+	case DifficultyHard:
+		return "hard"
+	case DifficultyExpert:
+		return "expert"
+	default:
+		return fmt.Sprintf("difficulty(%d)", int(d))
+	}
+}
+
+// ParseDifficulty converts a level name into a Difficulty
+func ParseDifficulty(name string) (Difficulty, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "easy":
+		return DifficultyEasy, nil
+	case "medium":
+		return DifficultyMedium, nil
+	case "hard":
+		return DifficultyHard, nil
+	case "expert":
+		return DifficultyExpert, nil
+	default:
+		return 0, fmt.Errorf("unknown difficulty: %s", name)
+	}
+}
+
+func (d Difficulty) clamp() Difficulty {
+	if d < DifficultyEasy {
+		return DifficultyEasy
+	}
+	if d > DifficultyExpert {
+		return DifficultyExpert
+	}
+	return d
+}
+
+// DifficultyStrategy decides the next difficulty level from the answer scores collected so far
+type DifficultyStrategy interface {
+	// Next returns the difficulty for the upcoming question.
+	// scores holds all answer scores of the session, oldest first
+	Next(current Difficulty, scores []float64) Difficulty
+}
+
+// FixedStrategy keeps the difficulty unchanged for the whole session
+type FixedStrategy struct{}
+
+// Next always returns the current difficulty
+func (FixedStrategy) Next(current Difficulty, scores []float64) Difficulty {
+	return current
+}
+
+// StepStrategy moves the difficulty one level up or down based on the
+// average of the most recent scores
+type StepStrategy struct {
+	Window     int     // Number of recent scores to average
+	RaiseAbove float64 // Average score above which difficulty goes up
+	LowerBelow float64 // Average score below which difficulty goes down
+}
+
+// NewStepStrategy creates a step strategy with default thresholds
+func NewStepStrategy() *StepStrategy {
+	return &StepStrategy{
+		Window:     2,
+		RaiseAbove: 7.5,
+		LowerBelow: 4.0,
+	}
+}
+
+// Next raises or lowers the difficulty by one level when the recent average crosses a threshold
+func (s *StepStrategy) Next(current Difficulty, scores []float64) Difficulty {
+	window := s.Window
+	if window <= 0 {
+		window = 1
+	}
+	if len(scores) < window {
+		return current
+	}
+
+	var sum float64
+	for _, score := range scores[len(scores)-window:] {
+		sum += score
+	}
+	average := sum / float64(window)
+
+	switch {
+	case average > s.RaiseAbove:
+		return (current + 1).clamp()
+	case average < s.LowerBelow:
+		return (current - 1).clamp()
+	default:
+		return current
+	}
+}
+
+// NewDifficultyStrategy creates a strategy by name ("fixed" or "step")
+func NewDifficultyStrategy(name string) (DifficultyStrategy, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "fixed":
+		return FixedStrategy{}, nil
+	case "step":
+		return NewStepStrategy(), nil
+	default:
+		return nil, fmt.Errorf("unknown difficulty strategy: %s", name)
+	}
+}
@@ -0,0 +1,35 @@
+package engine
+
+import (
+	"context"
+	"log"
+
+	"github.com/d1nch8g/aihr/earcon"
+	"github.com/d1nch8g/aihr/sound"
+)
+
+// playEarcon plays the sound of a turn-taking cue when earcons are
+// configured. Cues are not played in text mode, where turns end on submit
+func (e *Engine) playEarcon(ctx context.Context, cue string) {
+	earcons := e.config.Earcons
+	if earcons == nil || e.textIO != nil || ctx.Err() != nil {
+		return
+	}
+	audio, ok := earcons.Sound(cue)
+	if !ok {
+		return
+	}
+
+	if configurer, ok := e.soundPlayer.(sound.FormatConfigurer); ok {
+		if err := configurer.SetInputFormat(earcon.SampleRate, 1); err != nil {
+			log.Printf("Failed to configure playback format of the %s earcon: %v", cue, err)
+			return
+		}
+	}
+	chunks := make(chan []byte, 1)
+	chunks <- audio
+	close(chunks)
+	if err := e.soundPlayer.PlayStream(ctx, chunks); err != nil && ctx.Err() == nil {
+		log.Printf("Failed to play the %s earcon: %v", cue, err)
+	}
+}
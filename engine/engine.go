@@ -2,17 +2,37 @@ package engine
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/d1nch8g/aihr/analysis"
 	"github.com/d1nch8g/aihr/audio"
+	"github.com/d1nch8g/aihr/correlation"
+	"github.com/d1nch8g/aihr/earcon"
+	"github.com/d1nch8g/aihr/eval"
+	"github.com/d1nch8g/aihr/feed"
 	"github.com/d1nch8g/aihr/gpt"
+	"github.com/d1nch8g/aihr/i18n"
+	"github.com/d1nch8g/aihr/indicator"
+	"github.com/d1nch8g/aihr/observe"
+	"github.com/d1nch8g/aihr/persona"
+	"github.com/d1nch8g/aihr/questions"
+	"github.com/d1nch8g/aihr/realtime"
+	"github.com/d1nch8g/aihr/safety"
+	"github.com/d1nch8g/aihr/session"
 	"github.com/d1nch8g/aihr/sound"
+	"github.com/d1nch8g/aihr/stream"
 	"github.com/d1nch8g/aihr/stt"
+	"github.com/d1nch8g/aihr/subtitle"
 	"github.com/d1nch8g/aihr/tts"
+	"github.com/d1nch8g/aihr/turnlog"
 )
 
 // ConversationEntry represents a single exchange in the conversation
@@ -28,22 +48,316 @@ type EngineConfig struct {
 	MaxHistorySize int
 	SampleRate     int64
 	SilenceTimeout time.Duration
+	Greeting       string // Spoken once when the engine starts
+	Farewell       string // Spoken when closing is disabled or fails, empty for the one of Messages
+	Voice          string
+	Speed          float64
+	LogLevel       string // "debug" enables verbose logging
+
+	// MinConfidence is the recognition confidence below which the candidate is
+	// asked to repeat the answer instead of it being sent to the model, zero
+	// disables the check. Recognizers that report no confidence are trusted
+	MinConfidence float64
+
+	// NormalizeTranscripts rewrites technical content of answers in written
+	// form before they reach the model and the record, see stt.NormalizeTechnical
+	NormalizeTranscripts bool
+
+	// AdaptiveSilence counts SilenceTimeout from the end of the speech instead
+	// of the last recognized phrase, and lets the turn wait for speech still
+	// being recognized as long as the measured tail latency of STT suggests.
+	// Speech is audio above SilenceThreshold
+	AdaptiveSilence bool
+
+	// MergeWindow holds the turn open while the recognizer reported a partial
+	// result within it and the final has not arrived, so the fragments of one
+	// answer are merged instead of the turn ending between them. Zero ends
+	// turns on the silence timeout alone
+	MergeWindow time.Duration
+
+	// Role is the TTS emotion used for questions, e.g. neutral, good or strict.
+	// GreetingRole and ClosingRole set the tone of the greeting and the
+	// closing message, falling back to Role
+	Role         string
+	GreetingRole string
+	ClosingRole  string
+
+	// GreetingVariables fill the {name} slots of the greeting and the farewell.
+	// They are synthesized as templates so providers with pattern-based
+	// synthesis reuse their fixed part
+	GreetingVariables map[string]string
+
+	// Messages are the phrases spoken outside of model responses, like the
+	// request to repeat an answer, nil speaks them in English
+	Messages i18n.Messages
+
+	// SpeechCache keeps the audio of the greeting and the farewell across
+	// sessions, nil synthesizes them every time
+	SpeechCache *tts.AudioCache
+
+	// ProsodyMarkup asks the model to mark pauses and emphasis in its answers,
+	// see tts.ParseMarkup. Responses with invalid markup are spoken as plain text
+	ProsodyMarkup bool
+
+	// TTSRetries is the number of times a failed synthesis request is retried
+	// before the fallback voice speaks the text, see WithFallbackTTS
+	TTSRetries int
+
+	// CrossfadeDuration is the overlap used to join consecutive speech segments
+	CrossfadeDuration time.Duration
+
+	// TrimSilence holds back silence in captured audio before it is sent to
+	// STT and NormalizeLoudness amplifies quiet speakers, see
+	// sound.SpeechConditioner. SilenceThreshold is the level in dBFS below
+	// which audio is silence
+	TrimSilence       bool
+	NormalizeLoudness bool
+	SilenceThreshold  float64
+
+	// HighPassCutoff removes captured audio below this frequency in Hz, e.g.
+	// desk thumps and plosive pops, zero disables the filter
+	HighPassCutoff float64
+
+	// StreamBufferBytes bounds the audio queued between two pipeline stages.
+	// Captured audio drops the oldest chunks when STT falls behind, while
+	// synthesis waits for playback to catch up
+	StreamBufferBytes int
+
+	// Rubric holds the criteria answers are scored on by the default
+	// evaluator, eval.DefaultRubric when empty
+	Rubric eval.Rubric
+
+	// DifficultyStrategy adjusts question difficulty from answer scores.
+	// When nil, answers are not scored and difficulty is not mentioned in the prompt
+	DifficultyStrategy DifficultyStrategy
+	InitialDifficulty  Difficulty
+
+	// SafetyFilter blocks discriminatory or legally risky responses before they are spoken.
+	// Blocked responses are regenerated up to SafetyRetries times, then SafetyFallback, or
+	// the phrase of Messages when it is empty, is used.
+	// Every blocked generation is recorded by SafetyAuditor when set
+	SafetyFilter   safety.Filter
+	SafetyRetries  int
+	SafetyFallback string
+	SafetyAuditor  safety.Auditor
+
+	// Deferral matches candidate questions only the recruiter may answer,
+	// e.g. on compensation, visa sponsorship or legal terms. They are answered
+	// with the phrase of Messages instead of the model and flagged in the record
+	Deferral safety.Filter
+
+	// Personas are the panel of interviewers who take turns asking the
+	// questions, each with its own voice and prompt. The interview passes to
+	// the next one at the stages they lead, nil interviews with one voice
+	Personas persona.Panel
+
+	// TurnLogger receives a structured record of every turn when set
+	TurnLogger turnlog.Logger
+
+	// CaptionFile receives WebVTT or SRT captions of the candidate and the
+	// interviewer, by its extension, timed from the start of the session.
+	// {session} is replaced with the session ID, empty disables captions
+	CaptionFile string
+
+	// Earcons are the sounds played when listening starts and stops and when
+	// a turn fails, nil plays none
+	Earcons *earcon.Set
+
+	// Exporter receives every prompt and response pair of the model when set
+	Exporter observe.Exporter
+
+	// EventLog keeps every decision of the engine per session when set,
+	// e.g. when an answer ended, retries and fallbacks, see session.Event
+	EventLog session.EventLog
+
+	// SentimentAnalysis scores the sentiment and confidence of every answer
+	// for the session report
+	SentimentAnalysis bool
+
+	// QuestionBank holds the questions the interviewer is told to ask, picked
+	// by QuestionPlan at the current difficulty. The model writes its own
+	// questions when it is empty or the plan is complete. Realtime sessions
+	// do not use the bank
+	QuestionBank questions.Bank
+	QuestionPlan questions.Plan
+
+	// MaxFollowUps is the number of follow-up questions in a row the model
+	// may ask on one topic before it is told to move on, zero means no limit.
+	// With a question bank follow-ups on its questions are only allowed when
+	// it is set. Responses are classified with the fast model when there is one
+	MaxFollowUps int
+
+	// NotesInterval is the number of answers in a stage of the interview.
+	// After every stage notes on its answers are taken in the background for
+	// the session report, zero disables them
+	NotesInterval int
+
+	// ProhibitedTopics are listed in the system prompt as topics the interviewer must avoid
+	ProhibitedTopics []string
+
+	// VoiceCommands lets the candidate say control phrases like "repeat the question",
+	// "skip this question" or "I'm done" instead of answering. CommandPhrases
+	// overrides DefaultCommandPhrases
+	VoiceCommands  bool
+	CommandPhrases map[Command][]string
+
+	// Closing makes the interviewer summarize the conversation, explain NextSteps
+	// and thank the candidate when the interview ends, within ClosingTimeout.
+	// CandidateName is used when known, otherwise the model takes the name from
+	// the conversation
+	Closing        bool
+	ClosingTimeout time.Duration
+	NextSteps      string
+	CandidateName  string
+
+	// MaxDuration closes the interview once it has run this long, zero means no limit
+	MaxDuration time.Duration
+
+	// TimeAnnouncements are the times left before MaxDuration at which the
+	// candidate is told how much time remains, e.g. 10 and 5 minutes
+	TimeAnnouncements []time.Duration
+
+	// StallTimeout cancels a turn that made no progress for this long and
+	// listens again, zero disables the watchdog
+	StallTimeout time.Duration
+
+	// WarmUp connects the providers when the engine starts instead of on the first turn
+	WarmUp bool
+
+	// Reactions plays a short reaction of the locale, e.g. "I see.", as soon
+	// as an answer ends, while the response is generated. The reactions are
+	// synthesized while the candidate speaks
+	Reactions bool
 }
 
 // Engine orchestrates the AI-HR conversation flow
 type Engine struct {
 	config        EngineConfig
+	configMutex   sync.RWMutex
+	instructions  []string    // Added during the session with Instruct, guarded by configMutex
+	instructed    atomic.Bool // Set by Instruct until the realtime session instructions are updated
 	audioStreamer audio.AudioStreamer
 	sttClient     stt.STTClient
+	fallbackSTT   stt.STTClient
 	gptClient     gpt.GPTClient
+	fastGPT       gpt.GPTClient // Writes the first sentence of responses, optional
 	ttsClient     tts.Synthesizer
+	fallbackTTS   tts.Synthesizer
 	soundPlayer   sound.Player
+	evaluator     eval.Evaluator
+	analyzer      analysis.Analyzer
+	noteTaker     analysis.NoteTaker
+
+	followUpDetector analysis.FollowUpDetector
 
 	history      []ConversationEntry
 	historyMutex sync.RWMutex
 
 	isRunning    bool
 	runningMutex sync.RWMutex
+
+	difficulty      Difficulty
+	scores          []float64
+	difficultyMutex sync.RWMutex
+
+	record      session.Record
+	eventSeq    int // Sequence number of the last event of the session
+	notedAnswer int // Index of the first answer of the next stage
+	notedStages int // Stages the notes were taken on
+	recordMutex sync.RWMutex
+
+	// notes tracks the notes on stages still being taken
+	notes sync.WaitGroup
+
+	// planner picks the questions of the session from the bank. offered is
+	// the one the model is told to ask next and question the one the
+	// candidate was last asked, nil when it was not planned. followUps counts
+	// the responses in a row that stayed on the topic, followUpCheck is
+	// closed once the last response was classified
+	planner       *questions.Planner
+	question      *questions.Question
+	offered       *questions.Question
+	followUps     int
+	followUpCheck chan struct{}
+	planMutex     sync.Mutex
+
+	// textIO replaces audio, STT and TTS in text mode. With typedAnswers it
+	// only replaces the microphone and STT, responses are also spoken
+	textIO       TextIO
+	typedAnswers bool
+
+	// realtimeClient replaces STT, GPT responses and TTS in realtime mode
+	realtimeClient realtime.Client
+
+	// lastSpeech is the audio of the last response, replayed when the candidate asks to repeat it
+	lastSpeech  *speechCache
+	speechMutex sync.Mutex
+
+	// speculation holds the reactions synthesized while the candidate answers,
+	// reactionIndex is the next reaction to play so they take turns
+	speculation      *speculation
+	reactionIndex    int
+	speculationMutex sync.Mutex
+
+	// captions receives the text of responses as their audio plays, nil disables it
+	captions io.Writer
+
+	// feed receives the live transcript and the state of the engine, nil
+	// disables it. feedState is the state it was told last
+	feed      feed.Publisher
+	feedState atomic.Value
+
+	// tailNanos is the smoothed delay of recognition finals after the end of
+	// speech, zero until measured, see tailLatency
+	tailNanos atomic.Int64
+
+	// indicator shows the state of the engine on kiosk hardware, nil disables it
+	indicator indicator.Indicator
+
+	// captionTrack receives the captions of the session, nil without CaptionFile
+	captionTrack atomic.Pointer[subtitle.Track]
+
+	// lastCaptioned is when the last phrase of the candidate was captioned,
+	// it is only used by the capturing turn
+	lastCaptioned time.Time
+
+	// usage counts the tokens of the current turn, usageReported is set once
+	// the GPT client reported any
+	usage         gpt.Usage
+	usageReported bool
+	usageMutex    sync.Mutex
+	turns         int // Turns logged so far
+
+	// exports tracks generations still being sent to the exporter
+	exports sync.WaitGroup
+
+	// tasks tracks the goroutines of the session, see verifyTeardown
+	tasks tasks
+
+	watchdog watchdog
+
+	// unclearTurns counts the answers in a row the candidate was asked to repeat
+	unclearTurns int
+
+	// deadline is when MaxDuration ends the interview, announcePending is set
+	// once a checkpoint of TimeAnnouncements passed until the time left is told
+	deadline        time.Time
+	announcePending atomic.Bool
+
+	// persona is the index of the persona of the panel leading the interview,
+	// personaAnswers counts the answers given so far and introducing is set
+	// until the response after a hand-off introduced the new persona
+	persona        atomic.Int32
+	personaAnswers int
+	introducing    atomic.Bool
+
+	// replacedVoices are the cloned voices that failed in this session, their
+	// personas speak with the stock voice instead
+	replacedVoices map[string]bool
+	voiceMutex     sync.Mutex
+
+	closeRequested chan struct{} // Closed by RequestClosing
+	closeOnce      sync.Once
 }
 
 // NewEngine creates a new AI-HR engine instance
@@ -55,24 +369,67 @@ func NewEngine(
 	ttsClient tts.Synthesizer,
 	soundPlayer sound.Player,
 ) *Engine {
-	if config.MaxHistorySize == 0 {
-		config.MaxHistorySize = 10 // Default to last 10 exchanges
-	}
-	if config.SilenceTimeout == 0 {
-		config.SilenceTimeout = 3 * time.Second // Default 3 seconds
-	}
-	if config.SampleRate == 0 {
-		config.SampleRate = 44100 // Default sample rate
-	}
-
-	return &Engine{
+	engine := &Engine{
 		config:        config,
 		audioStreamer: audioStreamer,
 		sttClient:     sttClient,
 		gptClient:     gptClient,
 		ttsClient:     ttsClient,
 		soundPlayer:   soundPlayer,
-		history:       make([]ConversationEntry, 0),
+	}
+	engine.init()
+	return engine
+}
+
+// init applies configuration defaults and prepares the engine state
+func (e *Engine) init() {
+	if e.config.MaxHistorySize == 0 {
+		e.config.MaxHistorySize = 10 // Default to last 10 exchanges
+	}
+	if e.config.SilenceTimeout == 0 {
+		e.config.SilenceTimeout = 3 * time.Second // Default 3 seconds
+	}
+	if e.config.SampleRate == 0 {
+		e.config.SampleRate = 44100 // Default sample rate
+	}
+	if e.config.Voice == "" {
+		e.config.Voice = "marina" // Default voice
+	}
+	if e.config.Speed == 0 {
+		e.config.Speed = 1.0
+	}
+	if e.config.CrossfadeDuration == 0 {
+		e.config.CrossfadeDuration = 10 * time.Millisecond
+	}
+	if e.config.StreamBufferBytes == 0 {
+		e.config.StreamBufferBytes = 256 * 1024
+	}
+	if e.config.SafetyRetries == 0 {
+		e.config.SafetyRetries = 2
+	}
+	if e.config.InitialDifficulty == 0 {
+		e.config.InitialDifficulty = DifficultyMedium
+	}
+
+	e.history = make([]ConversationEntry, 0)
+	e.difficulty = e.config.InitialDifficulty.clamp()
+	e.closeRequested = make(chan struct{})
+
+	if e.config.DifficultyStrategy != nil && e.evaluator == nil {
+		e.evaluator = eval.NewGPTEvaluator(e.gptClient, e.config.Rubric)
+	}
+	if e.config.SentimentAnalysis && e.analyzer == nil {
+		e.analyzer = analysis.NewGPTAnalyzer(e.gptClient)
+	}
+	if e.config.NotesInterval > 0 && e.noteTaker == nil {
+		e.noteTaker = analysis.NewGPTNoteTaker(e.gptClient)
+	}
+	if e.config.MaxFollowUps > 0 && e.followUpDetector == nil {
+		client := e.gptClient
+		if e.fastGPT != nil {
+			client = e.fastGPT
+		}
+		e.followUpDetector = analysis.NewGPTFollowUpDetector(client)
 	}
 }
 
@@ -92,16 +449,62 @@ func (e *Engine) Start(ctx context.Context) error {
 		e.runningMutex.Unlock()
 	}()
 
-	// Initialize audio system
-	if err := e.audioStreamer.Initialize(); err != nil {
-		return fmt.Errorf("failed to initialize audio streamer: %w", err)
+	// The session ID is the caller's when the context has one, it is sent
+	// with every provider request made with the context
+	id := correlation.Session(ctx)
+	if id == "" {
+		id = session.NewID()
+		ctx = correlation.WithSession(ctx, id)
+	}
+	e.startRecord(id)
+	e.startPlan()
+	e.startPanel()
+	e.startCaptionFile(id)
+	log.Printf("Session %s started", id)
+	e.emit(session.EventSessionStarted, "")
+	defer func() {
+		e.takeNotes(true)
+		e.notes.Wait()
+		e.finishRecord()
+		e.finishCaptionFile()
+		e.emit(session.EventSessionEnded, "")
+		e.publishState(feed.StateEnded)
+		e.exports.Wait()
+		e.verifyTeardown()
+	}()
+
+	if e.config.WarmUp && e.realtimeClient == nil {
+		warmUpCtx, stopWarmUp := context.WithCancel(ctx)
+		defer stopWarmUp()
+		e.warmUp(warmUpCtx)
 	}
-	defer e.audioStreamer.Terminate()
 
-	if err := e.audioStreamer.Open(); err != nil {
-		return fmt.Errorf("failed to open audio stream: %w", err)
+	if limit := e.currentConfig().MaxDuration; limit > 0 {
+		timer := time.AfterFunc(limit, func() {
+			log.Printf("Interview reached the %s limit", limit)
+			e.RequestClosing()
+		})
+		defer timer.Stop()
+		stopAnnouncements := e.scheduleAnnouncements(time.Now().Add(limit))
+		defer stopAnnouncements()
+	}
+
+	if e.textIO != nil && !e.typedAnswers {
+		return e.run(ctx)
+	}
+
+	// Initialize audio system, typed answers need no microphone
+	if e.textIO == nil {
+		if err := e.audioStreamer.Initialize(); err != nil {
+			return fmt.Errorf("failed to initialize audio streamer: %w", err)
+		}
+		defer e.audioStreamer.Terminate()
+
+		if err := e.audioStreamer.Open(); err != nil {
+			return fmt.Errorf("failed to open audio stream: %w", err)
+		}
+		defer e.audioStreamer.Close()
 	}
-	defer e.audioStreamer.Close()
 
 	// Initialize sound player
 	if err := e.soundPlayer.Initialize(); err != nil {
@@ -109,150 +512,539 @@ func (e *Engine) Start(ctx context.Context) error {
 	}
 	defer e.soundPlayer.Terminate()
 
+	if err := e.soundPlayer.Open(); err != nil {
+		return fmt.Errorf("failed to open sound player: %w", err)
+	}
+	defer e.soundPlayer.Close()
+
+	if e.realtimeClient != nil && e.textIO == nil {
+		return e.runRealtime(ctx)
+	}
+	return e.run(ctx)
+}
+
+// run speaks the greeting and runs conversation cycles until the context is
+// cancelled or the text input ends
+func (e *Engine) run(ctx context.Context) error {
+	config := e.currentConfig()
+	if greeting := e.greeting(); greeting.Text != "" {
+		log.Printf("AI response: %s", greeting.Render())
+		if err := e.speakScript(ctx, greeting, roleOrDefault(config.GreetingRole, config.Role)); err != nil {
+			log.Printf("Failed to speak greeting: %v", err)
+		}
+	}
+
 	log.Println("AI-HR Engine started. Listening for user input...")
 
+	failing := false
 	for {
 		select {
 		case <-ctx.Done():
 			log.Println("Engine stopping due to context cancellation")
 			return ctx.Err()
+		case <-e.closeRequested:
+			log.Println("Closing the interview")
+			e.conclude(ctx)
+			return nil
 		default:
-			if err := e.processConversationCycle(ctx); err != nil {
+			if err := e.processCycle(ctx); err != nil {
+				if e.closingRequested() {
+					continue
+				}
+				if errors.Is(err, io.EOF) {
+					log.Println("Input finished, engine stopping")
+					return nil
+				}
+				if errors.Is(err, errInterviewFinished) {
+					log.Println("Candidate ended the interview, engine stopping")
+					return nil
+				}
+				if errors.Is(err, errInterviewAborted) {
+					log.Println("Candidate stopped the interview, engine stopping")
+					return nil
+				}
+				if errors.Is(err, errTurnStalled) {
+					continue
+				}
+				if errors.Is(err, audio.ErrDeviceLost) {
+					if err := e.recoverInput(ctx, err); err != nil {
+						return err
+					}
+					continue
+				}
 				log.Printf("Error in conversation cycle: %v", err)
 				// Continue running unless it's a context cancellation
 				if ctx.Err() != nil {
 					return ctx.Err()
 				}
+				// A failure that repeats, e.g. while STT is down, is cued once
+				if !failing {
+					e.playEarcon(ctx, earcon.Error)
+				}
+				failing = true
+				continue
 			}
+			failing = false
 		}
 	}
 }
 
+// greeting returns the greeting template with its variables
+func (e *Engine) greeting() tts.Template {
+	config := e.currentConfig()
+	return tts.Template{Text: config.Greeting, Variables: config.GreetingVariables}
+}
+
+// processCycle runs one conversation cycle that is interrupted when closing is
+// requested or when the watchdog finds it stalled
+func (e *Engine) processCycle(ctx context.Context) error {
+	cycleCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	go func() {
+		select {
+		case <-e.closeRequested:
+			cancel(nil)
+		case <-cycleCtx.Done():
+		}
+	}()
+
+	return e.watchTurn(cycleCtx, cancel, e.processConversationCycle)
+}
+
 // processConversationCycle handles one complete conversation cycle
-func (e *Engine) processConversationCycle(ctx context.Context) error {
+func (e *Engine) processConversationCycle(ctx context.Context) (err error) {
+	turn := &turnlog.Turn{Time: time.Now(), Kind: turnlog.KindAnswer, Stage: turnlog.StageListen}
+	e.resetUsage()
+	defer func() {
+		e.logTurn(turn, err)
+	}()
+	defer e.recoverTurn(ctx, turn, &err)
+	defer e.discardSpeculation()
+
 	// Capture user audio input
-	userInput, err := e.captureUserInput(ctx)
+	input, err := e.captureUserInput(ctx)
+	turn.ListenMs = time.Since(turn.Time).Milliseconds()
+	if e.config.AdaptiveSilence && e.textIO == nil {
+		turn.TailMs = e.tailLatency().Milliseconds()
+	}
 	if err != nil {
 		return fmt.Errorf("failed to capture user input: %w", err)
 	}
+	userInput, words, confidence := input.text, input.words, input.confidence
 
 	if strings.TrimSpace(userInput) == "" {
 		return nil // Skip empty input
 	}
+	if e.config.NormalizeTranscripts {
+		userInput = stt.NormalizeTechnical(userInput)
+	}
+	turn.Answer, turn.Confidence = userInput, confidence
+	e.publishState(feed.StateThinking)
+	e.playEarcon(ctx, earcon.ListenStop)
 
 	log.Printf("User said: %s", userInput)
+	if command := e.matchCommand(userInput); command != CommandNone {
+		e.emit(session.EventCommand, command.String())
+		turn.Kind, turn.Stage = turnlog.KindCommand, turnlog.StageSpeak
+		if err := e.handleCommand(ctx, command); err != nil {
+			return err
+		}
+		turn.Stage = turnlog.StageDone
+		return nil
+	}
+	answeredAt := time.Now()
 
-	// Generate AI response
-	aiResponse, err := e.generateResponse(userInput)
-	if err != nil {
-		return fmt.Errorf("failed to generate AI response: %w", err)
+	question := e.lastQuestion()
+	turn.Question = question
+	if e.unclear(confidence) {
+		turn.Kind, turn.Stage = turnlog.KindUnclear, turnlog.StageSpeak
+		if err := e.askToRepeat(ctx, session.Answer{
+			Question:   question,
+			Text:       userInput,
+			AnsweredAt: answeredAt,
+			Confidence: confidence,
+			Languages:  input.languages,
+			Unclear:    true,
+		}); err != nil {
+			return err
+		}
+		turn.Stage = turnlog.StageDone
+		return nil
+	}
+	e.unclearTurns = 0
+
+	if topic := e.deferredTopic(userInput); topic != "" {
+		turn.Kind, turn.Stage = turnlog.KindDeferred, turnlog.StageSpeak
+		if err := e.deferQuestion(ctx, topic, userInput); err != nil {
+			return err
+		}
+		turn.Stage = turnlog.StageDone
+		return nil
+	}
+
+	// A reaction synthesized ahead covers the time the response takes. It is
+	// spoken with the rest of the response as one stream
+	speech := e.newSpeech(ctx, e.currentConfig().Role)
+	defer speech.finish()
+	e.react(speech)
+	sentiment := e.analyzeAnswer(question, userInput)
+
+	// Score the answer and adapt difficulty before asking the next question
+	e.awaitFollowUp()
+	asked := e.askedQuestion()
+	assessment := e.adaptDifficulty(userInput, asked)
+	e.planQuestion()
+	interviewer := e.personaName()
+
+	// Generate AI response. The fast model writes the first sentence while
+	// the main model writes the rest, the sentence is spoken as soon as it is
+	// ready. There is none when the next persona of the panel takes over and
+	// introduces itself first
+	turn.Stage = turnlog.StageGenerate
+	generating := time.Now()
+	opened := !e.handOff(speech) && e.fastGPT != nil
+	var aiResponse string
+	var generateErr error
+	generated := make(chan struct{})
+	e.goTask("response", func() {
+		defer close(generated)
+		aiResponse, generateErr = e.generateResponse(userInput, opened)
+	})
+	opener := ""
+	if opened {
+		opener = e.generateOpener(userInput)
+		e.speakOpener(speech, opener)
+	}
+	<-generated
+	turn.GenerateMs = time.Since(generating).Milliseconds()
+	if generateErr != nil {
+		return fmt.Errorf("failed to generate AI response: %w", generateErr)
+	}
+	e.introducing.Store(false)
+	if err := ctx.Err(); err != nil {
+		// The model request does not follow cancellation, a response to a
+		// turn stopped meanwhile is dropped
+		return err
 	}
+	aiResponse, spoken := e.prepareSpeech(aiResponse)
+	turn.Response = strings.TrimSpace(opener + " " + aiResponse)
+	e.emitf(session.EventResponseGenerated, "%q in %dms", aiResponse, turn.GenerateMs)
+	e.checkFollowUp(e.lastAIResponse(), turn.Response)
 
 	log.Printf("AI response: %s", aiResponse)
 
-	// Convert response to speech and play it
-	if err := e.speakResponse(ctx, aiResponse); err != nil {
+	// Convert response to speech and play it after what was queued before
+	turn.Stage = turnlog.StageSpeak
+	e.announceTime(speech)
+	speaking := time.Now()
+	err = speech.say(speechSegment{template: tts.Template{Text: spoken}, text: aiResponse})
+	if err == nil {
+		err = speech.finish()
+	}
+	turn.SpeakMs = time.Since(speaking).Milliseconds()
+	if err != nil {
 		return fmt.Errorf("failed to speak response: %w", err)
 	}
+	turn.Stage = turnlog.StageDone
 
 	// Add to conversation history
 	e.addToHistory(ConversationEntry{
 		UserInput:  userInput,
-		AIResponse: aiResponse,
+		AIResponse: turn.Response,
 		Timestamp:  time.Now(),
 	})
 
+	e.recordAnswer(plannedAnswer(session.Answer{
+		Question:    question,
+		Text:        userInput,
+		AnsweredAt:  answeredAt,
+		Score:       scoreOf(assessment),
+		Assessment:  rubricOf(assessment),
+		Sentiment:   <-sentiment,
+		Fluency:     analysis.AnalyzeFluency(userInput, words),
+		Confidence:  confidence,
+		Languages:   input.languages,
+		Interviewer: interviewer,
+	}, asked))
+
 	return nil
 }
 
+// capturedInput is the transcribed answer of the candidate
+type capturedInput struct {
+	text  string
+	words []stt.Word
+
+	// confidence is the lowest one reported for the recognized utterances,
+	// zero when none was
+	confidence float64
+
+	// languages are the detected languages of the utterances in the order
+	// they were first spoken, more than one when the candidate switched
+	languages []string
+}
+
 // captureUserInput captures and transcribes user audio input
-func (e *Engine) captureUserInput(ctx context.Context) (string, error) {
-	audioData := make(chan []byte, 100)
-	sttResults := make(chan string, 10)
+func (e *Engine) captureUserInput(ctx context.Context) (capturedInput, error) {
+	cued := e.publishState(feed.StateListening)
+	listening := time.Now()
+	if e.textIO != nil {
+		e.watchdog.waiting.Store(true)
+		defer func() {
+			e.watchdog.waiting.Store(false)
+			e.progress()
+		}()
+		text, err := e.textIO.ReadAnswer(ctx)
+		if err == nil && strings.TrimSpace(text) != "" {
+			e.publishAnswer(feed.KindFinal, text)
+			e.caption(e.candidateSpeaker(), text, listening, time.Now())
+		}
+		return capturedInput{text: text}, err
+	}
 
-	// Start audio capture
+	// The cue plays before capture starts, so it is not recognized. Turns
+	// that heard nothing listen again without it
+	if cued {
+		e.playEarcon(ctx, earcon.ListenStart)
+	}
+
+	sttResults := make(chan stt.Utterance, 10)
+
+	// Start audio capture. The next turn must not open the device while this
+	// capture is still stopping, so the turn waits for it
 	captureCtx, captureCancel := context.WithCancel(ctx)
-	defer captureCancel()
+	captureDone := make(chan struct{})
+	defer func() {
+		captureCancel()
+		select {
+		case <-captureDone:
+		case <-time.After(captureStopTimeout):
+			log.Printf("Audio capture did not stop within %s", captureStopTimeout)
+		}
+	}()
 
-	go func() {
-		if err := e.audioStreamer.StartCapture(captureCtx, audioData); err != nil {
-			log.Printf("Audio capture error: %v", err)
+	// Live audio keeps the most recent speech when STT falls behind
+	pool := e.capturePool()
+	audioData := stream.NewPipe(captureCtx, e.config.StreamBufferBytes, stream.DropOldest, pool)
+	defer func() {
+		if dropped := audioData.Dropped(); dropped > 0 {
+			log.Printf("Dropped %d captured audio chunks, speech recognition fell behind", dropped)
 		}
-		close(audioData)
 	}()
 
+	deviceLost := make(chan error, 1)
+	e.goTask("capture", func() {
+		defer close(captureDone)
+		if err := e.audioStreamer.StartCapture(captureCtx, audioData.In()); err != nil {
+			if errors.Is(err, audio.ErrDeviceLost) {
+				deviceLost <- err
+			} else {
+				log.Printf("Audio capture error: %v", err)
+			}
+		}
+		close(audioData.In())
+	})
+
 	// Start STT processing
 	sttCtx, sttCancel := context.WithCancel(ctx)
 	defer sttCancel()
+	// Partial results tell that the candidate is saying a phrase that is not final yet
+	partials := make(chan time.Time, 1)
+	sttCtx = stt.WithPartials(sttCtx, func(text string) {
+		e.publishAnswer(feed.KindPartial, text)
+		select {
+		case partials <- time.Now():
+		default:
+		}
+	})
 
-	go func() {
-		if err := e.sttClient.StreamRecognize(sttCtx, audioData, sttResults, e.config.SampleRate); err != nil {
+	// With adaptive silence the turn follows when the recognizer is given speech
+	adaptive := e.config.AdaptiveSilence
+	recognized := audioData.Out()
+	var voiced chan time.Time
+	if adaptive {
+		voiced = make(chan time.Time, 1)
+		recognized = e.trackVoice(sttCtx, recognized, voiced)
+	}
+
+	recognitionFailed := make(chan error, 1)
+	e.goTask("recognition", func() {
+		if err := e.safely("recognition", func() error {
+			return e.recognize(sttCtx, e.conditionAudio(sttCtx, recognized, pool), sttResults)
+		}); err != nil {
 			log.Printf("STT error: %v", err)
+			if sttCtx.Err() == nil {
+				recognitionFailed <- err
+			}
 		}
-		close(sttResults)
-	}()
+	})
 
 	// Collect STT results with silence timeout
-	var transcription strings.Builder
-	silenceTimer := time.NewTimer(e.config.SilenceTimeout)
+	silenceTimeout := e.currentConfig().SilenceTimeout
+	var answer stt.Assembler
+	silenceTimer := time.NewTimer(silenceTimeout)
 	defer silenceTimer.Stop()
+	resetSilence := func(timeout time.Duration) {
+		if !silenceTimer.Stop() {
+			<-silenceTimer.C
+		}
+		silenceTimer.Reset(timeout)
+	}
+
+	// lastVoice is when the recognizer was last given speech, pending tells
+	// that no final arrived since, waiting that the turn waits for one
+	var lastVoice time.Time
+	pending, waiting := false, false
+
+	// lastPartial after lastFinal means the phrase being said is not final yet
+	var lastPartial, lastFinal time.Time
 
 	for {
 		select {
 		case <-ctx.Done():
-			return "", ctx.Err()
+			return capturedInput{}, ctx.Err()
+		case err := <-deviceLost:
+			return capturedInput{}, err
+		case err := <-recognitionFailed:
+			// Capture stops at once instead of running until the silence
+			// timeout, what was recognized before the failure is kept
+			if !answer.Empty() {
+				return assembledInput(&answer), nil
+			}
+			captureCancel()
+			select {
+			case <-time.After(recognitionRetryInterval):
+			case <-ctx.Done():
+			}
+			return capturedInput{}, fmt.Errorf("speech recognition failed: %w", err)
 		case result, ok := <-sttResults:
+			e.progress()
 			if !ok {
-				return transcription.String(), nil
+				// Recognition also ends when the device is lost
+				select {
+				case err := <-deviceLost:
+					return capturedInput{}, err
+				default:
+				}
+				return assembledInput(&answer), nil
 			}
-			if result != "" {
-				transcription.WriteString(result)
-				transcription.WriteString(" ")
-				// Reset silence timer on new input
-				if !silenceTimer.Stop() {
-					<-silenceTimer.C
+			if result.Text != "" {
+				e.debugf("STT result: %s", result.Text)
+				e.speculateReactions(ctx)
+				e.emit(session.EventSpeechRecognized, recognizedDetail(result))
+				e.publishAnswer(feed.KindFinal, result.Text)
+				e.captionAnswer(result, listening)
+				answer.Add(result)
+				lastFinal = time.Now()
+				// Reset silence timer on new input, adaptive silence counts it
+				// from the end of the speech
+				if adaptive && !lastVoice.IsZero() {
+					if pending {
+						e.measureTail(time.Since(lastVoice))
+						pending = false
+					}
+					resetSilence(max(silenceTimeout-time.Since(lastVoice), 0))
+				} else {
+					resetSilence(silenceTimeout)
 				}
-				silenceTimer.Reset(e.config.SilenceTimeout)
 			}
+		case at := <-voiced:
+			lastVoice, pending, waiting = at, true, false
+			resetSilence(silenceTimeout)
+		case lastPartial = <-partials:
 		case <-silenceTimer.C:
+			// The final of a phrase still being said or recognized is merged
+			// into the answer, the turn does not end between its fragments
+			if since := time.Since(lastPartial); lastPartial.After(lastFinal) && since < e.config.MergeWindow {
+				silenceTimer.Reset(e.config.MergeWindow - since)
+				continue
+			}
+			// Speech given to the recognizer after its last final is still
+			// being recognized, the turn waits for it as long as the tail
+			// latency suggests
+			if pending && !waiting {
+				waiting = true
+				silenceTimer.Reset(e.tailWait())
+				continue
+			}
 			// Silence timeout reached, stop capturing
+			if adaptive {
+				e.emitf(session.EventTurnEnded, "%s of silence, recognition tail %s", silenceTimeout, e.tailLatency().Round(time.Millisecond))
+			} else {
+				e.emitf(session.EventTurnEnded, "%s of silence", silenceTimeout)
+			}
 			captureCancel()
 			sttCancel()
-			return transcription.String(), nil
+			return assembledInput(&answer), nil
 		}
 	}
 }
 
-// generateResponse creates an AI response using the GPT client
-func (e *Engine) generateResponse(userInput string) (string, error) {
-	systemMessage := e.buildSystemMessage()
-	return e.gptClient.Complete(systemMessage, userInput)
+// assembledInput returns the answer merged from the recognized finals
+func assembledInput(answer *stt.Assembler) capturedInput {
+	return capturedInput{answer.Text(), answer.Words(), answer.Confidence(), answer.Languages()}
 }
 
-// speakResponse converts text to speech and plays it
-func (e *Engine) speakResponse(ctx context.Context, text string) error {
-	audioData := make(chan []byte, 100)
+// recognizedDetail describes a recognition result in the event log
+func recognizedDetail(result stt.Utterance) string {
+	detail := strconv.Quote(result.Text)
+	if result.Confidence > 0 {
+		detail += fmt.Sprintf(" confidence %.2f", result.Confidence)
+	}
+	if result.Language != "" {
+		detail += " language " + result.Language
+	}
+	return detail
+}
 
-	// Start TTS synthesis
-	ttsCtx, ttsCancel := context.WithCancel(ctx)
-	defer ttsCancel()
+// recognize streams audio to the STT client, failing over to the fallback
+// recognizer when one is set and the stream cannot be established
+func (e *Engine) recognize(ctx context.Context, audioData <-chan []byte, results chan<- stt.Utterance) error {
+	if e.fallbackSTT != nil {
+		return e.recognizeWithFallback(ctx, audioData, results)
+	}
+	return recognizeWith(ctx, e.sttClient, audioData, results, e.config.SampleRate)
+}
 
-	synthesisOptions := tts.SynthesisOptions{
-		Voice:  "jane", // Default voice
-		Speed:  1.0,
-		Volume: 1.0,
-		Model:  "tts-1", // Default model
+// recognizeWith streams audio to sttClient. Word timings are reported when
+// the client supports them, otherwise utterances carry only the text
+func recognizeWith(ctx context.Context, sttClient stt.STTClient, audioData <-chan []byte, results chan<- stt.Utterance, sampleRate int64) error {
+	if recognizer, ok := sttClient.(stt.WordRecognizer); ok {
+		return recognizer.StreamRecognizeWords(ctx, audioData, results, sampleRate)
 	}
 
+	texts := make(chan string, cap(results))
 	go func() {
-		if err := e.ttsClient.SynthesizeToStreamWithContext(ttsCtx, text, synthesisOptions, audioData); err != nil {
-			log.Printf("TTS synthesis error: %v", err)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case text, ok := <-texts:
+				if !ok {
+					close(results)
+					return
+				}
+				results <- stt.Utterance{Text: text}
+			}
 		}
-		close(audioData)
 	}()
 
-	// Play the audio
-	return e.soundPlayer.PlayStream(ctx, audioData)
+	return sttClient.StreamRecognize(ctx, audioData, texts, sampleRate)
+}
+
+// generateResponse creates an AI response using the GPT client. When the
+// fast model writes the opener at the same time the response follows it
+func (e *Engine) generateResponse(userInput string, opened bool) (string, error) {
+	systemMessage := e.buildSystemMessage()
+	if opened {
+		systemMessage += continuationInstruction
+	}
+	response, err := e.complete(observe.StageQuestion, systemMessage, userInput)
+	if err != nil {
+		return "", err
+	}
+	return e.moderateResponse(systemMessage, userInput, response)
 }
 
 // buildSystemMessage constructs the system message with conversation history
@@ -273,10 +1065,56 @@ func (e *Engine) buildSystemMessage() string {
 		systemMessage.WriteString("\n")
 	}
 
+	systemMessage.WriteString(e.systemInstructions())
+	return systemMessage.String()
+}
+
+// systemInstructions returns the system prompt with the difficulty and prohibited topics
+func (e *Engine) systemInstructions() string {
+	var instructions strings.Builder
+
 	// Add the main system prompt
-	systemMessage.WriteString(e.config.SystemPrompt)
+	instructions.WriteString(e.currentConfig().SystemPrompt)
 
-	return systemMessage.String()
+	// Add the persona of the panel leading the interview
+	instructions.WriteString(e.personaInstruction())
+
+	// Add difficulty instructions when adaptation is enabled
+	if e.config.DifficultyStrategy != nil {
+		instructions.WriteString(fmt.Sprintf(
+			"\n\nAsk the next question at %s difficulty level.", e.GetDifficulty(),
+		))
+	}
+
+	// Add the planned question of the bank
+	instructions.WriteString(e.planInstruction())
+
+	// Add topics that must not be raised in the interview
+	if topics := e.config.ProhibitedTopics; len(topics) > 0 {
+		instructions.WriteString(fmt.Sprintf(
+			"\n\nNever ask the candidate about: %s.", strings.Join(topics, ", "),
+		))
+	}
+
+	// Keep the model from improvising on questions left to the recruiter
+	if e.config.Deferral != nil {
+		instructions.WriteString(deferralInstruction)
+	}
+
+	// Add the instructions given during the session
+	if added := e.addedInstructions(); len(added) > 0 {
+		instructions.WriteString("\n\nAdditional instructions from the recruiter:")
+		for _, instruction := range added {
+			instructions.WriteString("\n- " + instruction)
+		}
+	}
+
+	// Describe the prosody markup the answers may use
+	if e.currentConfig().ProsodyMarkup {
+		instructions.WriteString(prosodyInstruction)
+	}
+
+	return instructions.String()
 }
 
 // addToHistory adds a conversation entry to the history
@@ -292,6 +1130,96 @@ func (e *Engine) addToHistory(entry ConversationEntry) {
 	}
 }
 
+// adaptDifficulty scores the answer to the last AI question and updates the difficulty level.
+// It returns the assessment, or nil when the answer was not scored. Evaluators
+// that do not score on a rubric only set its overall score. An answer to a
+// planned question with a rubric of its own is scored on it when the
+// evaluator supports that
+func (e *Engine) adaptDifficulty(userInput string, asked *questions.Question) *eval.Assessment {
+	if e.evaluator == nil {
+		return nil
+	}
+
+	question := e.lastAIResponse()
+	if question == "" {
+		return nil // Nothing was asked yet
+	}
+
+	var assessment eval.Assessment
+	var err error
+	questionEvaluator, scoresOnQuestion := e.evaluator.(eval.QuestionRubricEvaluator)
+	if evaluator, ok := e.evaluator.(eval.RubricEvaluator); ok {
+		if asked != nil && len(asked.Rubric) > 0 && scoresOnQuestion {
+			assessment, err = questionEvaluator.AssessAnswerOn(asked.Rubric, question, userInput)
+		} else {
+			assessment, err = evaluator.AssessAnswer(question, userInput)
+		}
+	} else {
+		assessment.Score, err = e.evaluator.ScoreAnswer(question, userInput)
+	}
+	if err != nil {
+		log.Printf("Failed to score answer: %v", err)
+		return nil
+	}
+	score := assessment.Score
+
+	e.difficultyMutex.Lock()
+	defer e.difficultyMutex.Unlock()
+
+	e.scores = append(e.scores, score)
+	next := e.config.DifficultyStrategy.Next(e.difficulty, e.scores).clamp()
+	if next != e.difficulty {
+		log.Printf("Answer score %.1f, difficulty changed from %s to %s", score, e.difficulty, next)
+		e.emitf(session.EventDifficulty, "%s to %s after score %.1f", e.difficulty, next, score)
+	}
+	e.difficulty = next
+	return &assessment
+}
+
+// scoreOf returns the overall score of the assessment, nil when the answer was not scored
+func scoreOf(assessment *eval.Assessment) *float64 {
+	if assessment == nil {
+		return nil
+	}
+	return &assessment.Score
+}
+
+// rubricOf returns the assessment when the answer was scored on a rubric
+func rubricOf(assessment *eval.Assessment) *eval.Assessment {
+	if assessment == nil || len(assessment.Dimensions) == 0 {
+		return nil
+	}
+	return assessment
+}
+
+// lastAIResponse returns the most recent AI response from the history
+func (e *Engine) lastAIResponse() string {
+	e.historyMutex.RLock()
+	defer e.historyMutex.RUnlock()
+
+	if len(e.history) == 0 {
+		return ""
+	}
+	return e.history[len(e.history)-1].AIResponse
+}
+
+// GetDifficulty returns the current question difficulty
+func (e *Engine) GetDifficulty() Difficulty {
+	e.difficultyMutex.RLock()
+	defer e.difficultyMutex.RUnlock()
+	return e.difficulty
+}
+
+// GetScores returns a copy of the answer scores collected in this session
+func (e *Engine) GetScores() []float64 {
+	e.difficultyMutex.RLock()
+	defer e.difficultyMutex.RUnlock()
+
+	scores := make([]float64, len(e.scores))
+	copy(scores, e.scores)
+	return scores
+}
+
 // GetHistory returns a copy of the conversation history
 func (e *Engine) GetHistory() []ConversationEntry {
 	e.historyMutex.RLock()
@@ -310,6 +1238,74 @@ func (e *Engine) ClearHistory() {
 	e.history = e.history[:0]
 }
 
+// UpdateConfig applies settings that can change on a running engine:
+// system prompt, greeting, farewell and their variables, phrases, voice and roles, speed, silence timeout, log level and closing details.
+// Structural settings like the sample rate and history size are kept as is
+func (e *Engine) UpdateConfig(update EngineConfig) {
+	e.configMutex.Lock()
+	defer e.configMutex.Unlock()
+
+	if update.SystemPrompt != "" {
+		e.config.SystemPrompt = update.SystemPrompt
+	}
+	if update.Greeting != "" {
+		e.config.Greeting = update.Greeting
+	}
+	if update.Farewell != "" {
+		e.config.Farewell = update.Farewell
+	}
+	if update.Messages != nil {
+		e.config.Messages = update.Messages
+	}
+	if update.GreetingVariables != nil {
+		e.config.GreetingVariables = update.GreetingVariables
+	}
+	if update.Voice != "" {
+		e.config.Voice = update.Voice
+	}
+	if update.Role != "" {
+		e.config.Role = update.Role
+	}
+	if update.GreetingRole != "" {
+		e.config.GreetingRole = update.GreetingRole
+	}
+	if update.ClosingRole != "" {
+		e.config.ClosingRole = update.ClosingRole
+	}
+	if update.Speed != 0 {
+		e.config.Speed = update.Speed
+	}
+	if update.SilenceTimeout != 0 {
+		e.config.SilenceTimeout = update.SilenceTimeout
+	}
+	if update.LogLevel != "" {
+		e.config.LogLevel = update.LogLevel
+	}
+	if update.NextSteps != "" {
+		e.config.NextSteps = update.NextSteps
+	}
+	if update.CandidateName != "" {
+		e.config.CandidateName = update.CandidateName
+	}
+
+	log.Printf("Engine configuration updated (voice: %s, speed: %.2f, silence timeout: %s, log level: %s)",
+		e.config.Voice, e.config.Speed, e.config.SilenceTimeout, e.config.LogLevel)
+}
+
+// currentConfig returns a snapshot of the engine configuration
+func (e *Engine) currentConfig() EngineConfig {
+	e.configMutex.RLock()
+	defer e.configMutex.RUnlock()
+	return e.config
+}
+
+// debugf logs the message only when the debug log level is enabled
+func (e *Engine) debugf(format string, args ...interface{}) {
+	if e.currentConfig().LogLevel == "debug" {
+		log.Printf(format, args...)
+	}
+}
+
 // IsRunning returns whether the engine is currently running
 func (e *Engine) IsRunning() bool {
 	e.runningMutex.RLock()
@@ -322,12 +1318,28 @@ func (e *Engine) Stop() error {
 	// Close all clients
 	var errors []error
 
-	if err := e.sttClient.Close(); err != nil {
-		errors = append(errors, fmt.Errorf("failed to close STT client: %w", err))
+	if e.sttClient != nil {
+		if err := e.sttClient.Close(); err != nil {
+			errors = append(errors, fmt.Errorf("failed to close STT client: %w", err))
+		}
+	}
+
+	if e.ttsClient != nil {
+		if err := e.ttsClient.Close(); err != nil {
+			errors = append(errors, fmt.Errorf("failed to close TTS client: %w", err))
+		}
 	}
 
-	if err := e.ttsClient.Close(); err != nil {
-		errors = append(errors, fmt.Errorf("failed to close TTS client: %w", err))
+	if e.fallbackSTT != nil {
+		if err := e.fallbackSTT.Close(); err != nil {
+			errors = append(errors, fmt.Errorf("failed to close fallback STT client: %w", err))
+		}
+	}
+
+	if e.fallbackTTS != nil {
+		if err := e.fallbackTTS.Close(); err != nil {
+			errors = append(errors, fmt.Errorf("failed to close fallback TTS client: %w", err))
+		}
 	}
 
 	if len(errors) > 0 {
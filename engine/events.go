@@ -0,0 +1,41 @@
+package engine
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/d1nch8g/aihr/session"
+)
+
+// emit appends an event of the current session to the event log when one is
+// set, so the sequence of decisions can be replayed later
+func (e *Engine) emit(eventType, detail string) {
+	eventLog := e.currentConfig().EventLog
+	if eventLog == nil {
+		return
+	}
+
+	e.recordMutex.Lock()
+	e.eventSeq++
+	event := session.Event{
+		Time:    time.Now(),
+		Session: e.record.ID,
+		Seq:     e.eventSeq,
+		Type:    eventType,
+		Detail:  detail,
+	}
+	e.recordMutex.Unlock()
+
+	if err := eventLog.AppendEvent(event); err != nil {
+		log.Printf("Failed to record %s event: %v", eventType, err)
+	}
+}
+
+// emitf appends an event with a formatted detail
+func (e *Engine) emitf(eventType, format string, args ...interface{}) {
+	if e.currentConfig().EventLog == nil {
+		return
+	}
+	e.emit(eventType, fmt.Sprintf(format, args...))
+}
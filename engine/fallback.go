@@ -0,0 +1,173 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/d1nch8g/aihr/session"
+	"github.com/d1nch8g/aihr/stt"
+	"github.com/d1nch8g/aihr/tts"
+)
+
+// ttsRetryDelay is the pause before retrying a failed synthesis request
+const ttsRetryDelay = 300 * time.Millisecond
+
+// Components recorded in session.Record.Degraded when they fell back to a local backend
+const (
+	DegradedTTS = "tts"
+	DegradedSTT = "stt"
+)
+
+// synthesizeVoice synthesizes a segment with the TTS client. When a fallback
+// voice is set, requests that fail before producing audio are retried
+// TTSRetries times and then the plain text is spoken by the fallback voice, so
+// the turn is not lost. A failure in the middle of a phrase is returned as is.
+// It reports whether the fallback voice, or the stock voice replacing a
+// cloned one, spoke
+func (e *Engine) synthesizeVoice(ctx context.Context, segment speechSegment, options tts.SynthesisOptions, audioData chan<- []byte) (bool, error) {
+	// A cloned voice that fails before producing audio is replaced by the
+	// stock voice of the persona, its audio is not cached for the cloned one
+	if stock, cloned := e.stockVoice(options.Voice); cloned {
+		started, err := e.attemptSynthesis(ctx, segment.template, options, audioData)
+		if err == nil || started || ctx.Err() != nil {
+			close(audioData)
+			return false, err
+		}
+		e.replaceVoice(options.Voice, stock, err)
+		options.Voice = stock
+		_, err = e.synthesizeVoice(ctx, segment, options, audioData)
+		return true, err
+	}
+
+	if e.fallbackTTS == nil {
+		return false, tts.SynthesizeTemplate(ctx, e.ttsClient, segment.template, options, audioData)
+	}
+
+	var err error
+	for attempt := 0; attempt <= e.currentConfig().TTSRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(ttsRetryDelay):
+			case <-ctx.Done():
+				close(audioData)
+				return false, ctx.Err()
+			}
+		}
+
+		var started bool
+		started, err = e.attemptSynthesis(ctx, segment.template, options, audioData)
+		if err == nil || started || ctx.Err() != nil {
+			close(audioData)
+			return false, err
+		}
+		log.Printf("TTS attempt %d failed: %v", attempt+1, err)
+		e.emitf(session.EventTTSRetry, "attempt %d failed: %v", attempt+1, err)
+	}
+
+	log.Printf("TTS provider is unavailable, speaking with the fallback voice: %v", err)
+	e.markDegraded(DegradedTTS)
+	e.emit(session.EventTTSFallback, err.Error())
+	if err := e.fallbackTTS.SynthesizeToStreamWithContext(ctx, segment.text, options, audioData); err != nil {
+		return true, fmt.Errorf("fallback voice failed: %w", err)
+	}
+	return true, nil
+}
+
+// attemptSynthesis runs one synthesis request and forwards its audio without
+// closing audioData. It reports whether any audio was forwarded
+func (e *Engine) attemptSynthesis(ctx context.Context, template tts.Template, options tts.SynthesisOptions, audioData chan<- []byte) (bool, error) {
+	attemptData := make(chan []byte)
+	result := make(chan error, 1)
+	go func() {
+		result <- tts.SynthesizeTemplate(ctx, e.ttsClient, template, options, attemptData)
+	}()
+
+	started := false
+	for chunk := range attemptData {
+		select {
+		case audioData <- chunk:
+			started = true
+		case <-ctx.Done():
+			// Let the request finish without blocking on its channel
+			go func() {
+				for range attemptData {
+				}
+			}()
+			return started, ctx.Err()
+		}
+	}
+	return started, <-result
+}
+
+// recognizeWithFallback streams audio to the STT client. When the client
+// fails before reading any audio, e.g. because the stream cannot be
+// established, the audio is recognized by the fallback recognizer instead
+func (e *Engine) recognizeWithFallback(ctx context.Context, audioData <-chan []byte, results chan<- stt.Utterance) error {
+	// The client reads the audio through a relay, so it is known whether it
+	// consumed any, and the fallback can take over the rest
+	relay := make(chan []byte)
+	stopRelay := make(chan struct{})
+	relayDone := make(chan struct{})
+	var consumed atomic.Bool
+	go func() {
+		defer close(relayDone)
+		defer close(relay)
+		for chunk := range audioData {
+			select {
+			case relay <- chunk:
+				consumed.Store(true)
+			case <-stopRelay:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	// Results of the client are forwarded until its channel closes, then
+	// those of the fallback when it takes over
+	primaryResults := make(chan stt.Utterance, cap(results))
+	next := make(chan chan stt.Utterance, 1)
+	go func() {
+		defer close(results)
+		source := primaryResults
+		for source != nil {
+			select {
+			case utterance, ok := <-source:
+				if !ok {
+					source = <-next
+					continue
+				}
+				select {
+				case results <- utterance:
+				case <-ctx.Done():
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	err := recognizeWith(ctx, e.sttClient, relay, primaryResults, e.config.SampleRate)
+	close(stopRelay)
+	<-relayDone
+	if err == nil || consumed.Load() || ctx.Err() != nil {
+		close(next)
+		return err
+	}
+
+	log.Printf("STT provider is unavailable, recognizing with the fallback recognizer: %v", err)
+	e.markDegraded(DegradedSTT)
+	e.emit(session.EventSTTFallback, err.Error())
+	fallbackResults := make(chan stt.Utterance, cap(results))
+	next <- fallbackResults
+	close(next)
+	if err := recognizeWith(ctx, e.fallbackSTT, audioData, fallbackResults, e.config.SampleRate); err != nil {
+		return fmt.Errorf("fallback recognizer failed: %w", err)
+	}
+	return nil
+}
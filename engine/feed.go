@@ -0,0 +1,49 @@
+package engine
+
+import (
+	"log"
+	"time"
+
+	"github.com/d1nch8g/aihr/feed"
+)
+
+// publish sends an event of the current session to the live feed when one is set
+func (e *Engine) publish(event feed.Event) {
+	if e.feed == nil {
+		return
+	}
+	e.recordMutex.RLock()
+	event.Session = e.record.ID
+	e.recordMutex.RUnlock()
+	event.Time = time.Now()
+	e.feed.Publish(event)
+}
+
+// publishState tells the live feed and the indicator what the engine does
+// now, unless they were told already. It reports whether the state changed
+func (e *Engine) publishState(state string) bool {
+	if previous, _ := e.feedState.Swap(state).(string); previous == state {
+		return false
+	}
+	if e.indicator != nil {
+		if err := e.indicator.Show(state); err != nil {
+			log.Printf("Failed to show the %s state: %v", state, err)
+		}
+	}
+	e.publish(feed.Event{Kind: feed.KindState, State: state})
+	return true
+}
+
+// publishSpeech sends the text the interviewer starts to speak to the live feed
+func (e *Engine) publishSpeech(text string) {
+	if e.feed == nil && e.indicator == nil {
+		return
+	}
+	e.publishState(feed.StateSpeaking)
+	e.publish(feed.Event{Kind: feed.KindResponse, Speaker: feed.SpeakerInterviewer, Text: text})
+}
+
+// publishAnswer sends a recognized phrase of the candidate to the live feed
+func (e *Engine) publishAnswer(kind, text string) {
+	e.publish(feed.Event{Kind: kind, Speaker: feed.SpeakerCandidate, Text: text})
+}
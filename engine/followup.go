@@ -0,0 +1,42 @@
+package engine
+
+import "log"
+
+// checkFollowUp classifies the response to the previous question in the
+// background, the result is needed when the next response is generated.
+// Without MaxFollowUps every response moves on. A response that fails to be
+// classified counts as moving on, so the limit never holds back a question
+func (e *Engine) checkFollowUp(previous, response string) {
+	e.planMutex.Lock()
+	defer e.planMutex.Unlock()
+
+	if e.followUpDetector == nil || e.config.MaxFollowUps <= 0 || previous == "" {
+		e.moveOn(false)
+		return
+	}
+
+	done := make(chan struct{})
+	e.followUpCheck = done
+	e.goTask("follow-up", func() {
+		defer close(done)
+		followUp, err := e.followUpDetector.IsFollowUp(previous, response)
+		if err != nil {
+			log.Printf("Failed to classify question: %v", err)
+		}
+
+		e.planMutex.Lock()
+		defer e.planMutex.Unlock()
+		e.moveOn(followUp)
+	})
+}
+
+// awaitFollowUp waits until the last response was classified
+func (e *Engine) awaitFollowUp() {
+	e.planMutex.Lock()
+	check := e.followUpCheck
+	e.planMutex.Unlock()
+
+	if check != nil {
+		<-check
+	}
+}
@@ -0,0 +1,47 @@
+package engine
+
+import (
+	"errors"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/d1nch8g/aihr/session"
+)
+
+// Instruct changes the interviewer instructions of a running session, e.g. a
+// recruiter asking to focus on Kubernetes. With replace the text becomes the
+// system prompt and earlier added instructions are dropped, otherwise it is
+// added to them. The change applies from the next turn on
+func (e *Engine) Instruct(text string, replace bool) error {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return errors.New("instruction is empty")
+	}
+
+	e.configMutex.Lock()
+	if replace {
+		e.config.SystemPrompt = text
+		e.instructions = nil
+	} else {
+		e.instructions = append(e.instructions, text)
+	}
+	e.configMutex.Unlock()
+
+	e.instructed.Store(true)
+	if replace {
+		log.Println("System prompt replaced, applies from the next turn")
+		e.emit(session.EventInstruction, "replace: "+text)
+	} else {
+		log.Println("Instruction added, applies from the next turn")
+		e.emit(session.EventInstruction, "add: "+text)
+	}
+	return nil
+}
+
+// addedInstructions returns a copy of the instructions given with Instruct
+func (e *Engine) addedInstructions() []string {
+	e.configMutex.RLock()
+	defer e.configMutex.RUnlock()
+	return slices.Clone(e.instructions)
+}
@@ -0,0 +1,83 @@
+package engine
+
+import (
+	"context"
+	"encoding/binary"
+	"time"
+
+	"github.com/d1nch8g/aihr/pcm"
+	"github.com/d1nch8g/aihr/sound"
+)
+
+const (
+	// defaultTailLatency is the recognition tail latency assumed before the
+	// first phrase was measured
+	defaultTailLatency = 500 * time.Millisecond
+
+	// minTailSample and maxTailSample bound a plausible tail latency. Shorter
+	// delays mean the candidate kept talking after the phrase, longer ones
+	// come from noise taken for speech rather than from the recognizer
+	minTailSample = 50 * time.Millisecond
+	maxTailSample = 5 * time.Second
+
+	// tailSmoothing is the weight of a new sample in the tail latency estimate
+	tailSmoothing = 0.3
+
+	// minTailWait and maxTailWait bound how long the end of a turn waits for
+	// the recognition of speech in flight
+	minTailWait = 300 * time.Millisecond
+	maxTailWait = 3 * time.Second
+)
+
+// trackVoice forwards captured audio to the recognizer and signals voiced
+// when a chunk above the silence threshold passes, i.e. when the recognizer
+// is given speech
+func (e *Engine) trackVoice(ctx context.Context, audioData <-chan []byte, voiced chan<- time.Time) <-chan []byte {
+	tracked := make(chan []byte)
+	e.goTask("voice tracking", func() {
+		defer close(tracked)
+
+		aligner := pcm.NewAligner(binary.LittleEndian)
+		var samples []int16
+		for chunk := range audioData {
+			samples = aligner.Append(samples[:0], chunk)
+			if len(samples) > 0 && sound.Level(samples) >= e.config.SilenceThreshold {
+				select {
+				case voiced <- time.Now():
+				default:
+				}
+			}
+			select {
+			case tracked <- chunk:
+			case <-ctx.Done():
+				return
+			}
+		}
+	})
+	return tracked
+}
+
+// measureTail adds the delay of a final after the end of the speech it
+// recognizes to the tail latency estimate
+func (e *Engine) measureTail(sample time.Duration) {
+	if sample < minTailSample || sample > maxTailSample {
+		return
+	}
+	estimate := e.tailLatency()
+	e.tailNanos.Store(int64(float64(estimate) + (float64(sample)-float64(estimate))*tailSmoothing))
+}
+
+// tailLatency returns the smoothed delay of recognition finals after the end
+// of speech
+func (e *Engine) tailLatency() time.Duration {
+	if nanos := e.tailNanos.Load(); nanos > 0 {
+		return time.Duration(nanos)
+	}
+	return defaultTailLatency
+}
+
+// tailWait returns how long the end of a turn waits for the final of speech
+// the recognizer was given, twice the tail latency so jitter does not cut it off
+func (e *Engine) tailWait() time.Duration {
+	return min(max(2*e.tailLatency(), minTailWait), maxTailWait)
+}
@@ -0,0 +1,97 @@
+package engine
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/d1nch8g/aihr/analysis"
+	"github.com/d1nch8g/aihr/session"
+)
+
+// takeNotes takes the notes on the answers of a stage in the background once
+// NotesInterval answers were given since the last stage. With final set the
+// remaining answers are noted as the last stage however many there are.
+// Unclear answers are counted in the stage but not noted
+func (e *Engine) takeNotes(final bool) {
+	interval := e.currentConfig().NotesInterval
+	if e.noteTaker == nil || interval <= 0 {
+		return
+	}
+
+	e.recordMutex.Lock()
+	first, last := e.notedAnswer, len(e.record.Answers)
+	if last == first || (last-first < interval && !final) {
+		e.recordMutex.Unlock()
+		return
+	}
+	e.notedAnswer = last
+	e.notedStages++
+	stage := e.notedStages
+	var exchanges []analysis.Exchange
+	for _, answer := range e.record.Answers[first:last] {
+		if !answer.Unclear {
+			exchanges = append(exchanges, analysis.Exchange{Question: answer.Question, Answer: answer.Text})
+		}
+	}
+	e.recordMutex.Unlock()
+
+	if len(exchanges) == 0 {
+		return
+	}
+
+	e.notes.Add(1)
+	e.goTask("notes", func() {
+		defer e.notes.Done()
+
+		notes, err := e.noteTaker.TakeNotes(exchanges)
+		if err != nil {
+			log.Printf("Failed to take notes on stage %d: %v", stage, err)
+			return
+		}
+		e.recordNotes(session.StageNotes{
+			Stage:       stage,
+			FirstAnswer: first,
+			LastAnswer:  last - 1,
+			TakenAt:     time.Now(),
+			Notes:       notes,
+		})
+		e.emitf(session.EventNotes, "stage %d, answers %d-%d: %s", stage, first+1, last, summarizeNotes(notes))
+		e.debugf("Took notes on stage %d: %s", stage, summarizeNotes(notes))
+	})
+}
+
+// recordNotes adds the notes on a stage to the session record, keeping the
+// stages in order when the notes of a later one were taken first
+func (e *Engine) recordNotes(notes session.StageNotes) {
+	e.recordMutex.Lock()
+	defer e.recordMutex.Unlock()
+
+	e.record.Notes = append(e.record.Notes, notes)
+	sort.SliceStable(e.record.Notes, func(i, j int) bool {
+		return e.record.Notes[i].Stage < e.record.Notes[j].Stage
+	})
+}
+
+// summarizeNotes returns the notes on one line for the event log
+func summarizeNotes(notes analysis.Notes) string {
+	if notes.Empty() {
+		return "nothing noted"
+	}
+	var parts []string
+	for _, kind := range []struct {
+		name  string
+		notes []string
+	}{
+		{"claims", notes.Claims},
+		{"skills", notes.Skills},
+		{"red flags", notes.RedFlags},
+	} {
+		if len(kind.notes) > 0 {
+			parts = append(parts, fmt.Sprintf("%s: %s", kind.name, strings.Join(kind.notes, "; ")))
+		}
+	}
+	return strings.Join(parts, " | ")
+}
@@ -0,0 +1,67 @@
+package engine
+
+import (
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/d1nch8g/aihr/observe"
+	"github.com/d1nch8g/aihr/session"
+	"github.com/d1nch8g/aihr/tts"
+)
+
+// openerInstruction asks the fast model for the first sentence of a response
+const openerInstruction = "You are an interviewer. Write only the first sentence of your reply to the " +
+	"candidate's answer: a brief, natural reaction to what they said, in the language of the answer. " +
+	"Do not ask a question, do not evaluate the answer and do not start a new topic."
+
+// firstSentence matches the first sentence of a text
+var firstSentence = regexp.MustCompile(`^[^.!?…]*[.!?…]+`)
+
+// generateOpener asks the fast model for the first sentence of the response
+// to the answer while the main model writes the rest. It returns an empty
+// opener when there is no fast model or its sentence cannot be used
+func (e *Engine) generateOpener(userInput string) string {
+	if e.fastGPT == nil {
+		return ""
+	}
+	text, err := e.completeWith(e.fastGPT, observe.StageOpener, openerInstruction, userInput)
+	if err != nil {
+		log.Printf("Failed to generate the first sentence: %v", err)
+		return ""
+	}
+
+	text = strings.TrimSpace(text)
+	if sentence := firstSentence.FindString(text); sentence != "" {
+		text = sentence
+	}
+	if text == "" || strings.Contains(text, "?") {
+		return ""
+	}
+	if filter := e.currentConfig().SafetyFilter; filter != nil {
+		if verdict, err := filter.Check(text); err != nil || !verdict.Allowed {
+			e.debugf("Dropped the first sentence of the fast model: %s", text)
+			return ""
+		}
+	}
+	return text
+}
+
+// speakOpener queues the opener after the reaction, it is played while the
+// main model writes the rest of the response
+func (e *Engine) speakOpener(speech *speech, opener string) {
+	if opener == "" {
+		return
+	}
+
+	e.emitf(session.EventResponseGenerated, "%q first sentence", opener)
+	log.Printf("AI response: %s", opener)
+	if err := speech.say(speechSegment{template: tts.Template{Text: opener}, text: opener}); err != nil {
+		log.Printf("Failed to speak the first sentence: %v", err)
+	}
+}
+
+// continuationInstruction tells the main model the fast model writes the
+// reaction its reply is spoken after
+const continuationInstruction = "\n\nYour reply is spoken right after a brief reaction to the answer, such as " +
+	"\"Thanks, that makes sense.\" Do not react to the answer yourself, start right with the substance."
@@ -0,0 +1,275 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/d1nch8g/aihr/analysis"
+	"github.com/d1nch8g/aihr/audio"
+	"github.com/d1nch8g/aihr/eval"
+	"github.com/d1nch8g/aihr/feed"
+	"github.com/d1nch8g/aihr/gpt"
+	"github.com/d1nch8g/aihr/indicator"
+	"github.com/d1nch8g/aihr/realtime"
+	"github.com/d1nch8g/aihr/session"
+	"github.com/d1nch8g/aihr/sound"
+	"github.com/d1nch8g/aihr/stt"
+	"github.com/d1nch8g/aihr/tts"
+)
+
+// Interviewer is the stable interface of the interview engine for programs
+// that embed it as a library
+type Interviewer interface {
+	// Start runs the interview until the context is cancelled
+	Start(ctx context.Context) error
+
+	// Stop releases the provider clients
+	Stop() error
+
+	// IsRunning returns whether the interview is in progress
+	IsRunning() bool
+
+	// GetHistory returns a copy of the conversation so far
+	GetHistory() []ConversationEntry
+
+	// ClearHistory forgets the conversation so far
+	ClearHistory()
+
+	// UpdateConfig applies runtime settings to a running interview
+	UpdateConfig(update EngineConfig)
+}
+
+// Instructor is implemented by interviewers that take instructions during the interview
+type Instructor interface {
+	// Instruct replaces the system prompt or adds an instruction to it, from the next turn on
+	Instruct(text string, replace bool) error
+}
+
+// Recorder is implemented by interviewers that keep a record of the interview
+type Recorder interface {
+	// GetRecord returns the full record of the current or last interview
+	GetRecord() session.Record
+}
+
+// Closer is implemented by interviewers that can end the interview with a
+// closing message
+type Closer interface {
+	// RequestClosing ends the interview with the closing message instead of stopping abruptly
+	RequestClosing()
+}
+
+// Ensure Engine implements Interviewer interface
+var (
+	_ Interviewer = (*Engine)(nil)
+	_ Instructor  = (*Engine)(nil)
+	_ Recorder    = (*Engine)(nil)
+	_ Closer      = (*Engine)(nil)
+)
+
+// Option configures the engine created by New
+type Option func(*Engine)
+
+// WithConfig sets the engine configuration
+func WithConfig(config EngineConfig) Option {
+	return func(e *Engine) {
+		e.config = config
+	}
+}
+
+// WithAudioStreamer sets the microphone capture component
+func WithAudioStreamer(audioStreamer audio.AudioStreamer) Option {
+	return func(e *Engine) {
+		e.audioStreamer = audioStreamer
+	}
+}
+
+// WithSTT sets the speech recognition client
+func WithSTT(sttClient stt.STTClient) Option {
+	return func(e *Engine) {
+		e.sttClient = sttClient
+	}
+}
+
+// WithFallbackSTT sets a local recognizer used for a turn when the recognition
+// stream of the STT client cannot be established, e.g. during a network blip.
+// Sessions that used it are marked as degraded in the record
+func WithFallbackSTT(fallback stt.STTClient) Option {
+	return func(e *Engine) {
+		e.fallbackSTT = fallback
+	}
+}
+
+// WithGPT sets the language model client
+func WithGPT(gptClient gpt.GPTClient) Option {
+	return func(e *Engine) {
+		e.gptClient = gptClient
+	}
+}
+
+// WithFastGPT sets a faster model that writes the first sentence of every
+// response, which is spoken while the GPT client writes the rest
+func WithFastGPT(fastGPT gpt.GPTClient) Option {
+	return func(e *Engine) {
+		e.fastGPT = fastGPT
+	}
+}
+
+// WithTTS sets the speech synthesis client
+func WithTTS(ttsClient tts.Synthesizer) Option {
+	return func(e *Engine) {
+		e.ttsClient = ttsClient
+	}
+}
+
+// WithFallbackTTS sets a local synthesizer that speaks the responses when the
+// TTS client keeps failing, e.g. an offline Piper or espeak-ng voice. Sessions
+// that used it are marked as degraded in the record
+func WithFallbackTTS(fallback tts.Synthesizer) Option {
+	return func(e *Engine) {
+		e.fallbackTTS = fallback
+	}
+}
+
+// WithCaptions writes the text of every response to w word by word, following
+// the playback of its audio, so an observer can read along
+func WithCaptions(w io.Writer) Option {
+	return func(e *Engine) {
+		e.captions = w
+	}
+}
+
+// WithFeed publishes the live transcript and the state of the engine, e.g.
+// to the Server-Sent Events of a feed.Hub
+func WithFeed(publisher feed.Publisher) Option {
+	return func(e *Engine) {
+		e.feed = publisher
+	}
+}
+
+// WithIndicator shows the state of the engine on a kiosk lamp, so the
+// candidate knows when to talk without a screen
+func WithIndicator(lamp indicator.Indicator) Option {
+	return func(e *Engine) {
+		e.indicator = lamp
+	}
+}
+
+// WithPlayer sets the audio playback component
+func WithPlayer(soundPlayer sound.Player) Option {
+	return func(e *Engine) {
+		e.soundPlayer = soundPlayer
+	}
+}
+
+// WithEvaluator sets the answer evaluator used for difficulty adaptation,
+// instead of the default GPT based one
+func WithEvaluator(evaluator eval.Evaluator) Option {
+	return func(e *Engine) {
+		e.evaluator = evaluator
+	}
+}
+
+// WithAnalyzer sets the sentiment analyzer used for the session report,
+// overriding the GPT analyzer created when SentimentAnalysis is enabled
+func WithAnalyzer(analyzer analysis.Analyzer) Option {
+	return func(e *Engine) {
+		e.analyzer = analyzer
+	}
+}
+
+// WithNoteTaker sets the note taker of the interview stages, overriding the
+// GPT note taker created when NotesInterval is set
+func WithNoteTaker(noteTaker analysis.NoteTaker) Option {
+	return func(e *Engine) {
+		e.noteTaker = noteTaker
+	}
+}
+
+// WithFollowUpDetector sets the classifier of follow-up questions,
+// overriding the GPT detector created when MaxFollowUps is set
+func WithFollowUpDetector(detector analysis.FollowUpDetector) Option {
+	return func(e *Engine) {
+		e.followUpDetector = detector
+	}
+}
+
+// WithTextIO runs the interview in text mode: answers are read line by line
+// from input and responses are printed to output instead of using audio,
+// STT and TTS
+func WithTextIO(input io.Reader, output io.Writer) Option {
+	return WithTextExchange(&lineIO{input: input, output: output})
+}
+
+// WithTextExchange runs the interview in text mode with a custom exchange,
+// e.g. a simulated candidate
+func WithTextExchange(textIO TextIO) Option {
+	return func(e *Engine) {
+		e.textIO = textIO
+	}
+}
+
+// WithTypedIO runs the interview for candidates who cannot speak: answers
+// are read line by line from input, each line ends the turn, and responses
+// are printed to output and spoken. The audio streamer and STT are not used
+func WithTypedIO(input io.Reader, output io.Writer) Option {
+	return WithTypedAnswers(&lineIO{input: input, output: output})
+}
+
+// WithTypedAnswers runs the interview with answers typed into a custom
+// exchange, e.g. a web form, while responses are written to it and spoken.
+// A turn ends when ReadAnswer returns, i.e. when the candidate submits
+func WithTypedAnswers(textIO TextIO) Option {
+	return func(e *Engine) {
+		e.textIO = textIO
+		e.typedAnswers = true
+	}
+}
+
+// WithRealtime runs the interview in realtime mode: the client recognizes the
+// candidate, responds and synthesizes speech in one session, so STT and TTS
+// are not used. The GPT client still scores answers and analyzes sentiment.
+// Text mode takes precedence when both are set
+func WithRealtime(client realtime.Client) Option {
+	return func(e *Engine) {
+		e.realtimeClient = client
+	}
+}
+
+// New creates an interview engine from options. The GPT client is always
+// required; the audio streamer, STT, TTS and player components are required
+// unless the engine runs in text mode, STT and TTS also in realtime mode. TTS
+// and the player are required with typed answers
+func New(opts ...Option) (Interviewer, error) {
+	engine := &Engine{}
+	for _, opt := range opts {
+		opt(engine)
+	}
+
+	var missing []error
+	if engine.gptClient == nil {
+		missing = append(missing, errors.New("GPT client is required"))
+	}
+	if engine.textIO == nil {
+		if engine.audioStreamer == nil {
+			missing = append(missing, errors.New("audio streamer is required"))
+		}
+		if engine.sttClient == nil && engine.realtimeClient == nil {
+			missing = append(missing, errors.New("STT client is required"))
+		}
+	}
+	if engine.textIO == nil || engine.typedAnswers {
+		if engine.ttsClient == nil && (engine.typedAnswers || engine.realtimeClient == nil) {
+			missing = append(missing, errors.New("TTS client is required"))
+		}
+		if engine.soundPlayer == nil {
+			missing = append(missing, errors.New("sound player is required"))
+		}
+	}
+	if len(missing) > 0 {
+		return nil, errors.Join(missing...)
+	}
+
+	engine.init()
+	return engine, nil
+}
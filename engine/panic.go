@@ -0,0 +1,53 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"time"
+
+	"github.com/d1nch8g/aihr/i18n"
+	"github.com/d1nch8g/aihr/session"
+	"github.com/d1nch8g/aihr/turnlog"
+)
+
+// recoverTurn isolates a turn that panicked, it must be deferred by the turn.
+// The stack is logged, the turn is recorded as failed and returns an error,
+// and the candidate hears the i18n.TurnFailed apology before the interview continues
+func (e *Engine) recoverTurn(ctx context.Context, turn *turnlog.Turn, err *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	log.Printf("Turn panicked in the %s stage: %v\n%s", turn.Stage, r, debug.Stack())
+	*err = fmt.Errorf("turn panicked: %v", r)
+
+	e.emitf(session.EventTurnFailed, "%s stage: %v", turn.Stage, r)
+	e.recordFailedTurn(session.FailedTurn{
+		Time:     time.Now(),
+		Stage:    turn.Stage,
+		Question: turn.Question,
+		Answer:   turn.Answer,
+		Error:    fmt.Sprint(r),
+	})
+	if ctx.Err() != nil {
+		return
+	}
+	if err := e.safely("apology", func() error {
+		return e.speakResponse(ctx, e.phrase(i18n.TurnFailed))
+	}); err != nil {
+		log.Printf("Failed to speak apology: %v", err)
+	}
+}
+
+// safely calls fn and returns a panic in it as an error, with the stack logged
+func (e *Engine) safely(name string, fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Panic in %s: %v\n%s", name, r, debug.Stack())
+			err = fmt.Errorf("%s panicked: %v", name, r)
+		}
+	}()
+	return fn()
+}
@@ -0,0 +1,171 @@
+package engine
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/d1nch8g/aihr/i18n"
+	"github.com/d1nch8g/aihr/persona"
+	"github.com/d1nch8g/aihr/session"
+	"github.com/d1nch8g/aihr/tts"
+)
+
+// startPanel hands a new session to the first persona of the panel and
+// records the cloned voices the personas speak with
+func (e *Engine) startPanel() {
+	e.persona.Store(0)
+	e.personaAnswers = 0
+	e.introducing.Store(false)
+
+	e.voiceMutex.Lock()
+	e.replacedVoices = nil
+	e.voiceMutex.Unlock()
+
+	cloner, ok := e.ttsClient.(tts.VoiceCloner)
+	clones := ok && cloner.SupportsClonedVoices()
+	for _, p := range e.currentConfig().Personas {
+		if p.ClonedVoice == "" {
+			continue
+		}
+		if !clones {
+			log.Printf("The TTS provider does not support cloned voices, %s speaks with the stock voice", p.Name)
+			continue
+		}
+		e.recordClonedVoice(session.ClonedVoice{
+			Persona:   p.Name,
+			Voice:     p.ClonedVoice,
+			GivenBy:   p.Consent.GivenBy,
+			Date:      p.Consent.Date,
+			Reference: p.Consent.Reference,
+		})
+	}
+}
+
+// currentPersona returns the persona leading the interview, nil when no
+// panel is configured
+func (e *Engine) currentPersona() *persona.Persona {
+	panel := e.currentConfig().Personas
+	index := int(e.persona.Load())
+	if index >= len(panel) {
+		return nil
+	}
+	return &panel[index]
+}
+
+// personaName returns the name of the persona leading the interview, empty
+// when no panel is configured
+func (e *Engine) personaName() string {
+	if current := e.currentPersona(); current != nil {
+		return current.Name
+	}
+	return ""
+}
+
+// personaVoice returns the voice the persona speaks with: its cloned voice
+// when the provider supports it and it did not fail in this session, its
+// stock voice otherwise, empty for the configured one
+func (e *Engine) personaVoice(p *persona.Persona) string {
+	if p.ClonedVoice == "" {
+		return p.Voice
+	}
+	if cloner, ok := e.ttsClient.(tts.VoiceCloner); !ok || !cloner.SupportsClonedVoices() {
+		return p.Voice
+	}
+
+	e.voiceMutex.Lock()
+	defer e.voiceMutex.Unlock()
+
+	if e.replacedVoices[p.ClonedVoice] {
+		return p.Voice
+	}
+	return p.ClonedVoice
+}
+
+// stockVoice returns the voice that replaces a cloned voice of the panel,
+// false when the voice is not a cloned one
+func (e *Engine) stockVoice(voice string) (string, bool) {
+	config := e.currentConfig()
+	for _, p := range config.Personas {
+		if p.ClonedVoice == "" || p.ClonedVoice != voice {
+			continue
+		}
+		if p.Voice != "" {
+			return p.Voice, true
+		}
+		return config.Voice, true
+	}
+	return "", false
+}
+
+// replaceVoice makes the persona of a cloned voice that failed speak with
+// its stock voice for the rest of the session
+func (e *Engine) replaceVoice(voice, stock string, err error) {
+	e.voiceMutex.Lock()
+	if e.replacedVoices == nil {
+		e.replacedVoices = make(map[string]bool)
+	}
+	e.replacedVoices[voice] = true
+	e.voiceMutex.Unlock()
+
+	log.Printf("Cloned voice %s is unavailable, speaking with %s: %v", voice, stock, err)
+	e.markVoiceReplaced(voice)
+	e.emitf(session.EventVoiceReplaced, "%s by %s: %v", voice, stock, err)
+}
+
+// handOff passes the interview to the next persona of the panel once the
+// planned question or the number of answers calls for it. The persona
+// leaving says who takes over after the reaction, in its own voice, and the
+// next response introduces the new one. It reports whether the interview was
+// handed off
+func (e *Engine) handOff(speech *speech) bool {
+	panel := e.currentConfig().Personas
+	if len(panel) < 2 {
+		return false
+	}
+	e.personaAnswers++
+
+	topic := ""
+	e.planMutex.Lock()
+	if e.offered != nil {
+		topic = e.offered.Topic
+	}
+	e.planMutex.Unlock()
+
+	current := int(e.persona.Load())
+	next, ok := panel.Lead(topic, e.personaAnswers)
+	if !ok || next == current {
+		return false
+	}
+	leaving, taking := panel[current], panel[next]
+	template := tts.Template{
+		Text:      e.phrase(i18n.Handoff),
+		Variables: map[string]string{"name": taking.Name, "title": taking.Title},
+	}
+
+	log.Printf("AI response: %s", template.Render())
+	if err := speech.say(speechSegment{template: template, text: template.Render(), aside: true}); err != nil {
+		log.Printf("Failed to announce the next interviewer: %v", err)
+	}
+	e.persona.Store(int32(next))
+	e.introducing.Store(true)
+	e.emitf(session.EventPersonaHandoff, "%s to %s after %d answers", leaving.Name, taking.Name, e.personaAnswers)
+	return true
+}
+
+// personaInstruction tells the model who it is while a panel interviews the
+// candidate, and to introduce itself in the first response after a hand-off
+func (e *Engine) personaInstruction() string {
+	current := e.currentPersona()
+	if current == nil {
+		return ""
+	}
+	instruction := fmt.Sprintf("\n\nYou are %s, the %s.", current.Name, current.Title)
+	if current.Prompt != "" {
+		instruction += " " + current.Prompt
+	}
+	if e.introducing.Load() {
+		instruction += " You are taking over the interview from your colleague. Briefly introduce " +
+			"yourself by name and role, then continue with your first question."
+	}
+	return instruction
+}
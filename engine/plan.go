@@ -0,0 +1,104 @@
+package engine
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/d1nch8g/aihr/questions"
+	"github.com/d1nch8g/aihr/session"
+)
+
+// startPlan creates the question planner of a new session when a question
+// bank is configured
+func (e *Engine) startPlan() {
+	e.planMutex.Lock()
+	defer e.planMutex.Unlock()
+
+	e.planner, e.question, e.offered = nil, nil, nil
+	e.followUps, e.followUpCheck = 0, nil
+	if len(e.config.QuestionBank) > 0 {
+		e.planner = questions.NewPlanner(e.config.QuestionBank, e.config.QuestionPlan)
+	}
+}
+
+// planQuestion offers the next question of the bank at the current
+// difficulty to the model, unless one is offered already. Once the plan is
+// complete the model asks its own questions
+func (e *Engine) planQuestion() {
+	e.planMutex.Lock()
+	defer e.planMutex.Unlock()
+
+	if e.planner == nil || e.offered != nil {
+		return
+	}
+
+	difficulty := ""
+	if e.config.DifficultyStrategy != nil {
+		difficulty = e.GetDifficulty().String()
+	}
+	question, ok := e.planner.Next(difficulty)
+	if !ok {
+		log.Printf("All planned questions were asked")
+		e.planner = nil
+		return
+	}
+	e.offered = &question
+	e.emitf(session.EventQuestionPlanned, "%s on %s at %s difficulty", question.ID, question.Topic, question.Difficulty)
+}
+
+// moveOn notes that the last response left the current topic, so it asked
+// the offered question when there is one. A follow-up keeps the question
+// and the offer. It must be called with planMutex held
+func (e *Engine) moveOn(followUp bool) {
+	if followUp {
+		e.followUps++
+		if limit := e.config.MaxFollowUps; limit > 0 && e.followUps == limit {
+			log.Printf("Follow-up limit of %d reached", limit)
+			e.emitf(session.EventFollowUpLimit, "%d follow-ups in a row", e.followUps)
+		}
+		return
+	}
+	e.followUps = 0
+	e.question, e.offered = e.offered, nil
+}
+
+// askedQuestion returns the planned question the candidate is answering, nil
+// when it was not planned
+func (e *Engine) askedQuestion() *questions.Question {
+	e.planMutex.Lock()
+	defer e.planMutex.Unlock()
+
+	return e.question
+}
+
+// planInstruction tells the model to ask the offered question and whether it
+// may follow up on the current one first, empty when it asks freely
+func (e *Engine) planInstruction() string {
+	e.planMutex.Lock()
+	defer e.planMutex.Unlock()
+
+	limit := e.config.MaxFollowUps
+	exhausted := limit > 0 && e.followUps >= limit
+	switch {
+	case e.offered == nil && exhausted:
+		return fmt.Sprintf("\n\nYou asked %d follow-up questions in a row on the current topic. "+
+			"Do not ask more about it, move on to a different topic now.", e.followUps)
+	case e.offered == nil:
+		return ""
+	case limit > 0 && !exhausted && e.question != nil:
+		return fmt.Sprintf("\n\nIf the last answer calls for it, ask a follow-up question on the current topic. "+
+			"Otherwise move on to the next question, on %s, in your own words: %s", e.offered.Topic, e.offered.Text)
+	default:
+		return fmt.Sprintf("\n\nYour next question is on %s. Ask it in your own words, "+
+			"keeping to the conversation: %s", e.offered.Topic, e.offered.Text)
+	}
+}
+
+// plannedAnswer tags the answer with the planned question it was given to
+func plannedAnswer(answer session.Answer, question *questions.Question) session.Answer {
+	if question != nil {
+		answer.QuestionID = question.ID
+		answer.Topic = question.Topic
+	}
+	return answer
+}
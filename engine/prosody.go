@@ -0,0 +1,27 @@
+package engine
+
+import (
+	"log"
+
+	"github.com/d1nch8g/aihr/tts"
+)
+
+// prosodyInstruction describes the prosody markup to the model
+const prosodyInstruction = "\n\nYour answers are spoken aloud. You may mark a short pause with [pause] " +
+	"or a pause of a given length with [pause 800ms], and emphasize a word or a short phrase " +
+	"by wrapping it in single asterisks, like *this*. Use the markup sparingly and no other formatting."
+
+// prepareSpeech returns the plain text of a response and the text to
+// synthesize when prosody markup is enabled. Invalid markup is logged and the
+// response is spoken as plain text
+func (e *Engine) prepareSpeech(response string) (string, string) {
+	if !e.currentConfig().ProsodyMarkup {
+		return response, response
+	}
+
+	plain, spoken, err := tts.PrepareSpeech(e.ttsClient, response)
+	if err != nil {
+		log.Printf("Invalid prosody markup, speaking plain text: %v", err)
+	}
+	return plain, spoken
+}
@@ -0,0 +1,144 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/d1nch8g/aihr/i18n"
+	"github.com/d1nch8g/aihr/tts"
+)
+
+// speculativeReactions is the number of reactions synthesized ahead in a turn
+const speculativeReactions = 2
+
+// speculation holds the audio of the reactions synthesized while the
+// candidate speaks. Reactions that were not played are discarded with it
+type speculation struct {
+	cancel context.CancelFunc
+	audio  map[string][]byte // Synthesized audio by text, set once complete
+	mutex  sync.Mutex
+}
+
+// reactions returns the reactions of the locale
+func (e *Engine) reactions() []string {
+	var reactions []string
+	for _, line := range strings.Split(e.phrase(i18n.Reactions), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			reactions = append(reactions, line)
+		}
+	}
+	return reactions
+}
+
+// speculateReactions synthesizes the next reactions in the background once
+// the candidate started to answer, so one can be played as soon as the answer
+// ends while the response is generated. It does nothing when a speculation
+// of the turn is running
+func (e *Engine) speculateReactions(ctx context.Context) {
+	if !e.currentConfig().Reactions || e.textIO != nil {
+		return
+	}
+	reactions := e.reactions()
+	if len(reactions) == 0 {
+		return
+	}
+
+	e.speculationMutex.Lock()
+	defer e.speculationMutex.Unlock()
+	if e.speculation != nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	s := &speculation{cancel: cancel, audio: make(map[string][]byte)}
+	e.speculation = s
+
+	options := e.synthesisOptions(e.currentConfig().Role)
+	for i := range min(speculativeReactions, len(reactions)) {
+		text := reactions[(e.reactionIndex+i)%len(reactions)]
+		e.goTask("speculative synthesis", func() {
+			audio, err := e.synthesizeAll(ctx, speechSegment{template: tts.Template{Text: text}, text: text, cache: true}, options)
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Printf("Failed to synthesize reaction: %v", err)
+				}
+				return
+			}
+			s.mutex.Lock()
+			s.audio[text] = audio
+			s.mutex.Unlock()
+		})
+	}
+}
+
+// takeReaction returns a reaction whose audio is ready and removes it from
+// the speculation, false when none is ready
+func (e *Engine) takeReaction() (string, []byte, bool) {
+	e.speculationMutex.Lock()
+	defer e.speculationMutex.Unlock()
+	s := e.speculation
+	if s == nil {
+		return "", nil, false
+	}
+
+	reactions := e.reactions()
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for i := range reactions {
+		text := reactions[(e.reactionIndex+i)%len(reactions)]
+		if audio, ok := s.audio[text]; ok {
+			delete(s.audio, text)
+			e.reactionIndex = (e.reactionIndex + i + 1) % len(reactions)
+			return text, audio, true
+		}
+	}
+	return "", nil, false
+}
+
+// discardSpeculation stops the synthesis of the turn's reactions and drops
+// the audio that was not played
+func (e *Engine) discardSpeculation() {
+	e.speculationMutex.Lock()
+	defer e.speculationMutex.Unlock()
+	if e.speculation != nil {
+		e.speculation.cancel()
+		e.speculation = nil
+	}
+}
+
+// react starts the response with a reaction synthesized ahead, which covers
+// the time the rest of the response takes. A reaction is never waited for,
+// nothing is queued when none is ready
+func (e *Engine) react(speech *speech) {
+	text, audio, ok := e.takeReaction()
+	if !ok {
+		return
+	}
+
+	e.debugf("Reacting to the answer: %s", text)
+	segment := speechSegment{template: tts.Template{Text: text}, text: text, audio: audio, aside: true}
+	if err := speech.say(segment); err != nil {
+		log.Printf("Failed to play reaction: %v", err)
+	}
+}
+
+// synthesizeAll synthesizes a segment to memory
+func (e *Engine) synthesizeAll(ctx context.Context, segment speechSegment, options tts.SynthesisOptions) ([]byte, error) {
+	audioData := make(chan []byte)
+	var audio bytes.Buffer
+	collected := make(chan struct{})
+	go func() {
+		defer close(collected)
+		for chunk := range audioData {
+			audio.Write(chunk)
+		}
+	}()
+	err := e.synthesize(ctx, segment, options, audioData)
+	<-collected
+	if err == nil {
+		err = ctx.Err()
+	}
+	return audio.Bytes(), err
+}
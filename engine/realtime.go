@@ -0,0 +1,422 @@
+package engine
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/d1nch8g/aihr/analysis"
+	"github.com/d1nch8g/aihr/pcm"
+	"github.com/d1nch8g/aihr/realtime"
+	"github.com/d1nch8g/aihr/session"
+	"github.com/d1nch8g/aihr/sound"
+	"github.com/d1nch8g/aihr/stream"
+	"github.com/d1nch8g/aihr/turnlog"
+)
+
+// greetingInstruction makes the realtime model speak the configured greeting
+const greetingInstruction = "Greet the candidate by saying exactly: %q"
+
+// farewellInstruction makes the realtime model speak the short farewell
+const farewellInstruction = "Say exactly: %q"
+
+// errRealtimeClosed is returned when the provider ends the session during the interview
+var errRealtimeClosed = errors.New("realtime session closed")
+
+// realtimeTurn is one answer of the candidate and the response to it
+type realtimeTurn struct {
+	question   string
+	answer     string
+	response   string
+	answeredAt time.Time
+}
+
+// runRealtime conducts the interview over a realtime session. Microphone audio
+// is streamed to the session while no response is playing, the provider
+// detects the end of each answer and responds with speech. Voice commands and
+// the safety filter are not applied, the response is spoken as it is generated
+func (e *Engine) runRealtime(ctx context.Context) error {
+	config := e.currentConfig()
+	conn, err := e.realtimeClient.Connect(ctx, realtime.SessionConfig{
+		Instructions:    e.systemInstructions(),
+		SilenceDuration: config.SilenceTimeout,
+	})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if configurer, ok := e.soundPlayer.(sound.FormatConfigurer); ok {
+		if err := configurer.SetInputFormat(float64(conn.SampleRate()), 1); err != nil {
+			return fmt.Errorf("failed to configure playback format: %w", err)
+		}
+	}
+
+	// Answers are scored and recorded one at a time in the background, so
+	// slow evaluation does not hold up the conversation
+	turns := make(chan realtimeTurn, 8)
+	var recorder sync.WaitGroup
+	recorder.Add(1)
+	go func() {
+		defer recorder.Done()
+		for turn := range turns {
+			if err := e.safely("turn recording", func() error {
+				e.recordRealtimeTurn(conn, turn)
+				return nil
+			}); err != nil {
+				e.emit(session.EventTurnFailed, err.Error())
+				e.recordFailedTurn(session.FailedTurn{
+					Time:     time.Now(),
+					Stage:    turnlog.StageDone,
+					Question: turn.question,
+					Answer:   turn.answer,
+					Error:    err.Error(),
+				})
+			}
+		}
+	}()
+	defer func() {
+		close(turns)
+		recorder.Wait()
+	}()
+
+	var listening atomic.Bool
+	captureCtx, captureCancel := context.WithCancel(ctx)
+	captured := make(chan struct{})
+	e.goTask("realtime capture", func() {
+		defer close(captured)
+		e.streamToRealtime(captureCtx, conn, &listening)
+	})
+	defer func() {
+		captureCancel()
+		<-captured
+	}()
+
+	greeting := e.greeting().Render()
+	if greeting != "" {
+		if err := conn.Respond(fmt.Sprintf(greetingInstruction, greeting)); err != nil {
+			return err
+		}
+	} else {
+		listening.Store(true)
+	}
+
+	log.Println("AI-HR Engine started in realtime mode. Listening for user input...")
+
+	var (
+		turn       realtimeTurn
+		question   = greeting // What the candidate is answering
+		answered   bool       // The candidate spoke since the last response
+		responding bool       // A response is being generated
+		playback   *realtimePlayback
+	)
+	complete := func() {
+		turns <- turn
+		question = turn.response
+		turn, answered = realtimeTurn{}, false
+	}
+
+	for {
+		var playbackDone <-chan error
+		if playback != nil {
+			playbackDone = playback.done
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Println("Engine stopping due to context cancellation")
+			return ctx.Err()
+
+		case <-e.closeRequested:
+			log.Println("Closing the interview")
+			listening.Store(false)
+			if playback != nil {
+				playback.stop()
+			}
+			e.concludeRealtime(ctx, conn, responding)
+			return nil
+
+		case err := <-playbackDone:
+			if err != nil && !errors.Is(err, context.Canceled) {
+				log.Printf("Playback error: %v", err)
+			}
+			playback = nil
+			listening.Store(true)
+
+		case event, ok := <-conn.Events():
+			if !ok {
+				return errRealtimeClosed
+			}
+
+			switch event.Type {
+			case realtime.EventSpeechStarted:
+				e.emit(session.EventSpeechStarted, "")
+				if !answered {
+					answered = true
+					turn.question = question
+					turn.answeredAt = time.Now()
+				}
+
+			case realtime.EventTranscript:
+				if event.Text == "" {
+					continue
+				}
+				log.Printf("User said: %s", event.Text)
+				e.emitf(session.EventSpeechRecognized, "%q", event.Text)
+				turn.answer = strings.TrimSpace(turn.answer + " " + event.Text)
+				if turn.response != "" {
+					complete()
+				}
+
+			case realtime.EventResponseStarted:
+				responding = true
+
+			case realtime.EventAudio:
+				if playback == nil {
+					listening.Store(false)
+					playback = e.startRealtimePlayback(ctx)
+				}
+				playback.write(ctx, event.Audio)
+
+			case realtime.EventResponseText:
+				log.Printf("AI response: %s", event.Text)
+				if !answered {
+					question = event.Text
+					continue
+				}
+				turn.response = event.Text
+				if turn.answer != "" {
+					complete()
+				}
+
+			case realtime.EventResponseDone:
+				responding = false
+				if playback != nil {
+					playback.finish()
+				} else {
+					listening.Store(true)
+				}
+
+			case realtime.EventError:
+				log.Printf("Realtime session error: %v", event.Err)
+			}
+		}
+	}
+}
+
+// streamToRealtime sends microphone audio to the session while listening is
+// set, resampled to the rate of the session
+func (e *Engine) streamToRealtime(ctx context.Context, conn realtime.Session, listening *atomic.Bool) {
+	pool := e.capturePool()
+	audioData := stream.NewPipe(ctx, e.config.StreamBufferBytes, stream.DropOldest, pool)
+	e.goTask("capture", func() {
+		if err := e.audioStreamer.StartCapture(ctx, audioData.In()); err != nil && !errors.Is(err, context.Canceled) {
+			log.Printf("Audio capture error: %v", err)
+		}
+		close(audioData.In())
+	})
+
+	filter := e.newHighPassFilter()
+	aligner := pcm.NewAligner(binary.LittleEndian)
+	resampler := sound.NewResampler(float64(e.config.SampleRate), float64(conn.SampleRate()), 1)
+	var samples, resampled []int16
+	var data []byte
+	for chunk := range audioData.Out() {
+		if !listening.Load() {
+			// Drop the audio so the interviewer does not hear itself
+			aligner.Reset()
+			pool.Put(chunk)
+			continue
+		}
+		samples = aligner.Append(samples[:0], chunk)
+		pool.Put(chunk)
+		if filter != nil {
+			filter.Apply(samples)
+		}
+		resampled = resampler.Append(resampled[:0], samples)
+		data = pcm.AppendInt16(binary.LittleEndian, data[:0], resampled)
+		if err := conn.SendAudio(data); err != nil {
+			log.Printf("Failed to send audio: %v", err)
+			return
+		}
+	}
+}
+
+// recordRealtimeTurn scores the answer, adds the turn to the history and the
+// session record, and updates the session instructions when the difficulty
+// changed or the recruiter gave new instructions
+func (e *Engine) recordRealtimeTurn(conn realtime.Session, turn realtimeTurn) {
+	sentiment := e.analyzeAnswer(turn.question, turn.answer)
+	previous := e.GetDifficulty()
+	assessment := e.adaptDifficulty(turn.answer, nil)
+
+	e.addToHistory(ConversationEntry{
+		UserInput:  turn.answer,
+		AIResponse: turn.response,
+		Timestamp:  time.Now(),
+	})
+	e.recordAnswer(session.Answer{
+		Question:   turn.question,
+		Text:       turn.answer,
+		AnsweredAt: turn.answeredAt,
+		Score:      scoreOf(assessment),
+		Assessment: rubricOf(assessment),
+		Sentiment:  <-sentiment,
+		Fluency:    analysis.AnalyzeFluency(turn.answer, nil),
+	})
+	e.logTurn(&turnlog.Turn{
+		Time:     turn.answeredAt,
+		Kind:     turnlog.KindAnswer,
+		Stage:    turnlog.StageDone,
+		Question: turn.question,
+		Answer:   turn.answer,
+		Response: turn.response,
+	}, nil)
+
+	if e.instructed.Swap(false) || e.GetDifficulty() != previous {
+		if err := conn.UpdateInstructions(e.systemInstructions()); err != nil {
+			log.Printf("Failed to update realtime instructions: %v", err)
+		}
+	}
+}
+
+// concludeRealtime asks the session for the closing message and plays it. A
+// response in progress is cancelled first so its audio is not mixed in
+func (e *Engine) concludeRealtime(ctx context.Context, conn realtime.Session, responding bool) {
+	config := e.currentConfig()
+	if config.ClosingTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, config.ClosingTimeout)
+		defer cancel()
+	}
+
+	if responding {
+		if err := conn.Cancel(); err != nil {
+			log.Printf("Failed to cancel response: %v", err)
+		}
+		if !waitRealtimeResponse(ctx, conn) {
+			return
+		}
+	}
+
+	instructions := fmt.Sprintf(farewellInstruction, e.farewell().Render())
+	if config.Closing {
+		instructions = config.SystemPrompt + closingInstruction
+		if config.CandidateName != "" {
+			instructions += fmt.Sprintf("\n\nThe candidate's name is %s.", config.CandidateName)
+		}
+		if config.NextSteps != "" {
+			instructions += "\n\nNext steps: " + config.NextSteps
+		}
+	}
+	if err := conn.Respond(instructions); err != nil {
+		log.Printf("Failed to request closing message: %v", err)
+		return
+	}
+
+	playback := e.startRealtimePlayback(ctx)
+	events := conn.Events()
+	for {
+		select {
+		case <-ctx.Done():
+			playback.stop()
+			log.Printf("Failed to speak closing message: %v", ctx.Err())
+			return
+		case err := <-playback.done:
+			if err != nil {
+				log.Printf("Failed to speak closing message: %v", err)
+			}
+			return
+		case event, ok := <-events:
+			if !ok {
+				// The session closed, play what arrived and stop reading
+				events = nil
+				playback.finish()
+				continue
+			}
+			switch event.Type {
+			case realtime.EventAudio:
+				playback.write(ctx, event.Audio)
+			case realtime.EventResponseText:
+				log.Printf("AI response: %s", event.Text)
+			case realtime.EventResponseDone:
+				playback.finish()
+			}
+		}
+	}
+}
+
+// waitRealtimeResponse discards events until the response in progress is done.
+// It returns false when the context ends or the session closes first
+func waitRealtimeResponse(ctx context.Context, conn realtime.Session) bool {
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case event, ok := <-conn.Events():
+			if !ok {
+				return false
+			}
+			if event.Type == realtime.EventResponseDone {
+				return true
+			}
+		}
+	}
+}
+
+// realtimePlayback plays the audio of one realtime response as it arrives
+type realtimePlayback struct {
+	ctx      context.Context
+	pipe     *stream.Pipe
+	done     chan error
+	cancel   context.CancelFunc
+	finished bool
+}
+
+// startRealtimePlayback starts playing a new response
+func (e *Engine) startRealtimePlayback(ctx context.Context) *realtimePlayback {
+	playCtx, cancel := context.WithCancel(ctx)
+	playback := &realtimePlayback{
+		ctx:    playCtx,
+		pipe:   stream.NewPipe(playCtx, e.config.StreamBufferBytes, stream.Block, nil),
+		done:   make(chan error, 1),
+		cancel: cancel,
+	}
+	e.goTask("playback", func() {
+		defer cancel()
+		playback.done <- e.soundPlayer.PlayStream(playCtx, playback.pipe.Out())
+	})
+	return playback
+}
+
+// write queues a chunk of audio, waiting while playback is behind
+func (p *realtimePlayback) write(ctx context.Context, chunk []byte) {
+	if p.finished {
+		return
+	}
+	select {
+	case p.pipe.In() <- chunk:
+	case <-p.ctx.Done():
+	case <-ctx.Done():
+	}
+}
+
+// finish lets the queued audio play out, done receives once it has been played
+func (p *realtimePlayback) finish() {
+	if !p.finished {
+		p.finished = true
+		close(p.pipe.In())
+	}
+}
+
+// stop cuts the playback immediately
+func (p *realtimePlayback) stop() {
+	p.finish()
+	p.cancel()
+}
@@ -0,0 +1,147 @@
+package engine
+
+import (
+	"log"
+	"slices"
+	"time"
+
+	"github.com/d1nch8g/aihr/analysis"
+	"github.com/d1nch8g/aihr/session"
+)
+
+// startRecord begins a new session record
+func (e *Engine) startRecord(id string) {
+	e.recordMutex.Lock()
+	defer e.recordMutex.Unlock()
+
+	e.record = session.Record{
+		ID:        id,
+		StartedAt: time.Now(),
+	}
+	e.eventSeq = 0
+	e.notedAnswer, e.notedStages = 0, 0
+}
+
+// finishRecord marks the session record as ended
+func (e *Engine) finishRecord() {
+	e.recordMutex.Lock()
+	defer e.recordMutex.Unlock()
+
+	e.record.EndedAt = time.Now()
+}
+
+// recordAnswer appends an answer to the session record. Unlike the
+// conversation history the record is never trimmed. A completed stage of
+// the interview is noted
+func (e *Engine) recordAnswer(answer session.Answer) {
+	e.recordMutex.Lock()
+	e.record.Answers = append(e.record.Answers, answer)
+	e.recordMutex.Unlock()
+
+	e.takeNotes(false)
+}
+
+// markDegraded notes in the session record that a component fell back to a local backend
+func (e *Engine) markDegraded(component string) {
+	e.recordMutex.Lock()
+	defer e.recordMutex.Unlock()
+
+	if !slices.Contains(e.record.Degraded, component) {
+		e.record.Degraded = append(e.record.Degraded, component)
+	}
+}
+
+// recordFailedTurn notes in the session record that a turn ended by an internal error
+func (e *Engine) recordFailedTurn(turn session.FailedTurn) {
+	e.recordMutex.Lock()
+	defer e.recordMutex.Unlock()
+
+	e.record.FailedTurns = append(e.record.FailedTurns, turn)
+}
+
+// recordDeferred notes in the session record a question left to the recruiter
+func (e *Engine) recordDeferred(question session.DeferredQuestion) {
+	e.recordMutex.Lock()
+	defer e.recordMutex.Unlock()
+
+	e.record.Deferred = append(e.record.Deferred, question)
+}
+
+// recordClonedVoice notes in the session record that a persona speaks with
+// a cloned voice, with its consent
+func (e *Engine) recordClonedVoice(voice session.ClonedVoice) {
+	e.recordMutex.Lock()
+	defer e.recordMutex.Unlock()
+
+	e.record.ClonedVoices = append(e.record.ClonedVoices, voice)
+}
+
+// markVoiceReplaced notes in the session record that a cloned voice failed
+// and the stock voice spoke instead
+func (e *Engine) markVoiceReplaced(voice string) {
+	e.recordMutex.Lock()
+	defer e.recordMutex.Unlock()
+
+	for i := range e.record.ClonedVoices {
+		if e.record.ClonedVoices[i].Voice == voice {
+			e.record.ClonedVoices[i].Replaced = true
+		}
+	}
+}
+
+// markAborted notes in the session record that the candidate stopped the
+// interview before the end
+func (e *Engine) markAborted() {
+	e.recordMutex.Lock()
+	defer e.recordMutex.Unlock()
+
+	e.record.Aborted = true
+}
+
+// recordStall counts a turn canceled by the watchdog in the session record
+func (e *Engine) recordStall() {
+	e.recordMutex.Lock()
+	defer e.recordMutex.Unlock()
+
+	e.record.Stalls++
+}
+
+// GetRecord returns a copy of the full session record
+func (e *Engine) GetRecord() session.Record {
+	e.recordMutex.RLock()
+	defer e.recordMutex.RUnlock()
+
+	record := e.record
+	record.Answers = make([]session.Answer, len(e.record.Answers))
+	copy(record.Answers, e.record.Answers)
+	record.Degraded = slices.Clone(e.record.Degraded)
+	record.FailedTurns = slices.Clone(e.record.FailedTurns)
+	record.Notes = slices.Clone(e.record.Notes)
+	record.Deferred = slices.Clone(e.record.Deferred)
+	record.ClonedVoices = slices.Clone(e.record.ClonedVoices)
+	return record
+}
+
+// analyzeAnswer runs sentiment analysis in the background so it does not
+// delay the response. The channel yields nil when analysis is disabled or fails
+func (e *Engine) analyzeAnswer(question, answer string) <-chan *analysis.Sentiment {
+	result := make(chan *analysis.Sentiment, 1)
+	if e.analyzer == nil {
+		result <- nil
+		return result
+	}
+
+	e.goTask("analysis", func() {
+		sentiment, err := e.analyzer.AnalyzeAnswer(question, answer)
+		if err != nil {
+			log.Printf("Failed to analyze answer: %v", err)
+			result <- nil
+			return
+		}
+		if sentiment.Stressed {
+			log.Printf("Evident stress detected: %s", sentiment.Note)
+		}
+		result <- &sentiment
+	})
+	return result
+}
@@ -0,0 +1,80 @@
+package engine
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/d1nch8g/aihr/i18n"
+	"github.com/d1nch8g/aihr/observe"
+	"github.com/d1nch8g/aihr/safety"
+	"github.com/d1nch8g/aihr/session"
+)
+
+// moderateResponse checks a generated response with the safety filter and
+// regenerates it with corrective instructions while it is blocked
+func (e *Engine) moderateResponse(systemMessage, userInput, response string) (string, error) {
+	config := e.currentConfig()
+	if config.SafetyFilter == nil {
+		return response, nil
+	}
+
+	for attempt := 0; ; attempt++ {
+		verdict, err := config.SafetyFilter.Check(response)
+		if err != nil {
+			return "", fmt.Errorf("failed to check response safety: %w", err)
+		}
+		if verdict.Allowed {
+			return response, nil
+		}
+
+		log.Printf("Blocked AI response on %s (matched %q)", verdict.Category, verdict.Match)
+		e.emitf(session.EventResponseBlocked, "%s (matched %q), attempt %d", verdict.Category, verdict.Match, attempt)
+		e.debugf("Blocked response: %s", response)
+		e.auditBlocked(verdict, userInput, response, attempt)
+
+		if attempt >= config.SafetyRetries {
+			if config.SafetyFallback == "" {
+				return config.Messages.Get(i18n.SafetyFallback), nil
+			}
+			return config.SafetyFallback, nil
+		}
+
+		topics := "age, religion, family plans, marital status, nationality, health or sexual orientation"
+		if len(config.ProhibitedTopics) > 0 {
+			topics = strings.Join(config.ProhibitedTopics, ", ")
+		}
+		correction := fmt.Sprintf(
+			"\n\nYour previous reply touched on %s, which must not be discussed in a job interview. "+
+				"Do not ask about %s. "+
+				"Ask a question about the candidate's professional skills and experience instead.",
+			verdict.Category, topics,
+		)
+		response, err = e.complete(observe.StageRegenerate, systemMessage+correction, userInput)
+		if err != nil {
+			return "", err
+		}
+	}
+}
+
+// auditBlocked records a blocked generation for legal review
+func (e *Engine) auditBlocked(verdict safety.Verdict, userInput, response string, attempt int) {
+	auditor := e.currentConfig().SafetyAuditor
+	if auditor == nil {
+		return
+	}
+
+	err := auditor.Record(safety.AuditEntry{
+		Time:          time.Now(),
+		Category:      verdict.Category,
+		Jurisdictions: verdict.Jurisdictions,
+		Match:         verdict.Match,
+		Response:      response,
+		UserInput:     userInput,
+		Attempt:       attempt,
+	})
+	if err != nil {
+		log.Printf("Failed to audit blocked response: %v", err)
+	}
+}
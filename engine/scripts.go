@@ -0,0 +1,102 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"slices"
+
+	"github.com/d1nch8g/aihr/i18n"
+	"github.com/d1nch8g/aihr/tts"
+)
+
+// cachedChunkSize is the size of the chunks cached speech is played in
+const cachedChunkSize = 8192
+
+// phrase returns the phrase spoken outside of model responses in the language of the interview
+func (e *Engine) phrase(key string) string {
+	return e.currentConfig().Messages.Get(key)
+}
+
+// farewell returns the farewell template with the variables of the greeting
+func (e *Engine) farewell() tts.Template {
+	config := e.currentConfig()
+	text := config.Farewell
+	if text == "" {
+		text = config.Messages.Get(i18n.Farewell)
+	}
+	return tts.Template{Text: text, Variables: config.GreetingVariables}
+}
+
+// speakScript speaks a scripted phrase, the greeting or the farewell. Its
+// audio is kept in the speech cache unless the phrase has the candidate's name
+func (e *Engine) speakScript(ctx context.Context, template tts.Template, role string) error {
+	if speak, err := e.writeResponse(template.Render()); !speak || err != nil {
+		return err
+	}
+	personal := template.Variables["candidate"] != "" && slices.Contains(template.Slots(), "candidate")
+	segment := speechSegment{template: template, text: template.Render(), cache: !personal}
+	return e.speakSegments(ctx, []speechSegment{segment}, role)
+}
+
+// synthesize synthesizes a segment. Segments synthesized ahead are played
+// from memory, cached segments are played from the speech cache when they
+// were synthesized before, and stored in it otherwise
+func (e *Engine) synthesize(ctx context.Context, segment speechSegment, options tts.SynthesisOptions, audioData chan<- []byte) error {
+	if segment.audio != nil {
+		defer close(audioData)
+		return sendAudio(ctx, segment.audio, audioData)
+	}
+	cache := e.config.SpeechCache
+	if cache == nil || !segment.cache {
+		_, err := e.synthesizeVoice(ctx, segment, options, audioData)
+		return err
+	}
+
+	defer close(audioData)
+	if audio, ok := cache.Load(segment.text, options); ok {
+		e.debugf("Playing cached speech: %s", segment.text)
+		return sendAudio(ctx, audio, audioData)
+	}
+
+	live := make(chan []byte)
+	var fallback bool
+	var err error
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fallback, err = e.synthesizeVoice(ctx, segment, options, live)
+	}()
+
+	var audio bytes.Buffer
+	for chunk := range live {
+		audio.Write(chunk)
+		select {
+		case audioData <- chunk:
+		case <-ctx.Done():
+		}
+	}
+	<-done
+
+	// Speech of the fallback voice and interrupted speech are not cached
+	if err == nil && !fallback && ctx.Err() == nil && audio.Len() > 0 {
+		if err := cache.Store(segment.text, options, audio.Bytes()); err != nil {
+			log.Printf("Failed to cache speech: %v", err)
+		}
+	}
+	return err
+}
+
+// sendAudio sends audio held in memory in chunks
+func sendAudio(ctx context.Context, audio []byte, audioData chan<- []byte) error {
+	for len(audio) > 0 {
+		chunk := audio[:min(cachedChunkSize, len(audio))]
+		audio = audio[len(chunk):]
+		select {
+		case audioData <- chunk:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
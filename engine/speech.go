@@ -0,0 +1,459 @@
+package engine
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/d1nch8g/aihr/pcm"
+	"github.com/d1nch8g/aihr/sound"
+	"github.com/d1nch8g/aihr/stream"
+	"github.com/d1nch8g/aihr/tts"
+)
+
+// speakResponse converts text to speech with the question role and plays it
+func (e *Engine) speakResponse(ctx context.Context, text string) error {
+	return e.speakTemplate(ctx, tts.Template{Text: text}, e.currentConfig().Role)
+}
+
+// speakMarkup plays spoken, the text in the markup of the synthesizer, with the
+// question role. Text output and the repeat cache use the plain text
+func (e *Engine) speakMarkup(ctx context.Context, text, spoken string) error {
+	if speak, err := e.writeResponse(text); !speak || err != nil {
+		return err
+	}
+	return e.speakSegments(ctx, []speechSegment{{template: tts.Template{Text: spoken}, text: text}}, e.currentConfig().Role)
+}
+
+// speakTemplate synthesizes a phrase, possibly with variable slots, in the given role and plays it
+func (e *Engine) speakTemplate(ctx context.Context, template tts.Template, role string) error {
+	if speak, err := e.writeResponse(template.Render()); !speak || err != nil {
+		return err
+	}
+	return e.speakSegments(ctx, []speechSegment{{template: template, text: template.Render()}}, role)
+}
+
+// writeResponse delivers a response to the text exchange. It reports whether
+// the response should also be spoken: always without a text exchange, and
+// with typed answers, where the candidate reads and hears the interviewer
+func (e *Engine) writeResponse(text string) (bool, error) {
+	if e.textIO == nil {
+		return true, nil
+	}
+	if !e.typedAnswers {
+		e.publishSpeech(text)
+		e.caption(e.interviewerSpeaker(), text, time.Now(), time.Now())
+	}
+	if err := e.textIO.WriteResponse(text); err != nil {
+		return false, err
+	}
+	return e.typedAnswers, nil
+}
+
+// roleOrDefault returns role, or fallback when it is not set
+func roleOrDefault(role, fallback string) string {
+	if role != "" {
+		return role
+	}
+	return fallback
+}
+
+// speechSegment is a phrase to synthesize and its plain text, which is spoken
+// by the fallback voice and identifies the cached audio
+type speechSegment struct {
+	template tts.Template
+	text     string
+	cache    bool   // The audio is kept in the speech cache, see speakScript
+	audio    []byte // Audio synthesized ahead, played instead of synthesizing the segment
+	aside    bool   // Not part of the response, e.g. the reaction, left out of the text it is repeated for
+}
+
+// speakSegments synthesizes the segments one after another and plays them as a
+// single stream, see speakQueue
+func (e *Engine) speakSegments(ctx context.Context, segments []speechSegment, role string) error {
+	queue := make(chan speechSegment, len(segments))
+	for _, segment := range segments {
+		queue <- segment
+	}
+	close(queue)
+	return e.speakQueue(ctx, queue, role)
+}
+
+// speakQueue synthesizes the segments in the order they are queued and plays
+// them as a single stream, crossfading the segment boundaries to avoid clicks.
+// Playback starts with the first segment while later ones are still being
+// generated, it ends once the queue is closed and its audio was played
+func (e *Engine) speakQueue(ctx context.Context, queue <-chan speechSegment, role string) error {
+	var segment speechSegment
+	select {
+	case next, ok := <-queue:
+		if !ok {
+			return nil
+		}
+		segment = next
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	ttsCtx, ttsCancel := context.WithCancel(ctx)
+	defer ttsCancel()
+
+	// The first segment defines the playback format
+	first, format, ok, err := e.synthesizeSegment(ttsCtx, segment, role)
+	if err != nil {
+		return err
+	}
+	e.publishSpeech(segment.text)
+
+	crossfader := sound.NewCrossfader(0, 0, 1)
+	if ok {
+		e.debugf("TTS audio format: %d Hz, %d channel(s), %d bit", format.SampleRate, format.Channels, format.BitsPerSample)
+		if configurer, isConfigurer := e.soundPlayer.(sound.FormatConfigurer); isConfigurer {
+			if err := configurer.SetInputFormat(float64(format.SampleRate), format.Channels); err != nil {
+				return fmt.Errorf("failed to configure playback format: %w", err)
+			}
+		}
+		crossfader = sound.NewCrossfader(e.config.CrossfadeDuration, float64(format.SampleRate), format.Channels)
+	}
+
+	// Keep the audio so the question can be repeated without synthesizing it again
+	cache := &speechCache{format: format, hasFormat: ok}
+	cache.addText(segment)
+	var captions *captioner
+	if e.captions != nil {
+		captions = newCaptioner(segment.text, format, ok, e.currentConfig().Speed)
+	}
+
+	audioSegments := make(chan (<-chan []byte), 1)
+	audioSegments <- first
+	e.goTask("synthesis", func() {
+		defer close(audioSegments)
+		for {
+			var segment speechSegment
+			select {
+			case next, ok := <-queue:
+				if !ok {
+					return
+				}
+				segment = next
+			case <-ttsCtx.Done():
+				return
+			}
+			audio, _, _, err := e.synthesizeSegment(ttsCtx, segment, role)
+			if err != nil {
+				log.Printf("Failed to synthesize segment: %v", err)
+				continue
+			}
+			e.publishSpeech(segment.text)
+			cache.addText(segment)
+			if captions != nil {
+				captions.extend(segment.text)
+			}
+			select {
+			case audioSegments <- audio:
+			case <-ttsCtx.Done():
+				return
+			}
+		}
+	})
+
+	// Playback pulls audio at its own pace, synthesis waits when it runs ahead
+	pcmData := stream.NewPipe(ttsCtx, e.config.StreamBufferBytes, stream.Block, nil)
+	joined := make(chan []byte)
+	e.goTask("crossfade", func() {
+		if err := sound.CrossfadeSegments(ttsCtx, audioSegments, joined, crossfader); err != nil && err != context.Canceled {
+			log.Printf("Failed to join speech segments: %v", err)
+		}
+	})
+
+	e.goTask("speech buffer", func() {
+		defer close(pcmData.In())
+		for chunk := range joined {
+			cache.add(chunk)
+			select {
+			case pcmData.In() <- chunk:
+				e.progress()
+				if captions != nil {
+					captions.add(len(chunk))
+				}
+			case <-ttsCtx.Done():
+				return
+			}
+		}
+		if captions != nil {
+			captions.finish()
+		}
+	})
+
+	// Play the audio while the captions follow it
+	captionsDone := make(chan bool)
+	captionsWritten := make(chan struct{})
+	if captions != nil {
+		go func() {
+			defer close(captionsWritten)
+			captions.run(e.captions, captionsDone)
+		}()
+	}
+	playing := time.Now()
+	err = e.soundPlayer.PlayStream(ctx, pcmData.Out())
+	e.caption(e.interviewerSpeaker(), cache.heardText(), playing, time.Now())
+	if captions != nil {
+		captionsDone <- err == nil
+		<-captionsWritten
+	}
+	if err != nil {
+		return err
+	}
+	if cache.complete() {
+		e.speechMutex.Lock()
+		e.lastSpeech = cache
+		e.speechMutex.Unlock()
+	}
+	return nil
+}
+
+// speech is a response spoken while its parts are still being generated. The
+// parts are queued once they are ready and played as one stream, so the
+// boundaries between them are crossfaded
+type speech struct {
+	engine *Engine
+	ctx    context.Context
+	role   string
+
+	queue   chan speechSegment
+	played  chan struct{} // Closed once playback ended
+	err     error         // Playback error, set before played is closed
+	started bool
+	closed  bool
+}
+
+// newSpeech prepares a response spoken in the given role, playback starts with its first part
+func (e *Engine) newSpeech(ctx context.Context, role string) *speech {
+	return &speech{
+		engine: e,
+		ctx:    ctx,
+		role:   role,
+		queue:  make(chan speechSegment, 1),
+		played: make(chan struct{}),
+	}
+}
+
+// say queues a part of the response after delivering it to the text exchange
+func (s *speech) say(segment speechSegment) error {
+	if speak, err := s.engine.writeResponse(segment.text); !speak || err != nil {
+		return err
+	}
+	if !s.started {
+		s.started = true
+		s.engine.goTask("speech", func() {
+			defer close(s.played)
+			s.err = s.engine.speakQueue(s.ctx, s.queue, s.role)
+		})
+	}
+	select {
+	case s.queue <- segment:
+	case <-s.played:
+	}
+	return nil
+}
+
+// finish closes the queue and waits until the response was played. It can be
+// called more than once
+func (s *speech) finish() error {
+	if !s.closed {
+		s.closed = true
+		close(s.queue)
+	}
+	if !s.started {
+		return nil
+	}
+	<-s.played
+	return s.err
+}
+
+// replaySpeech plays the cached audio of text again. It returns false when
+// the audio of text is not cached
+func (e *Engine) replaySpeech(ctx context.Context, text string) (bool, error) {
+	e.speechMutex.Lock()
+	cache := e.lastSpeech
+	e.speechMutex.Unlock()
+
+	if e.textIO != nil || cache == nil || cache.spokenText() != text {
+		return false, nil
+	}
+
+	if configurer, ok := e.soundPlayer.(sound.FormatConfigurer); ok && cache.hasFormat {
+		if err := configurer.SetInputFormat(float64(cache.format.SampleRate), cache.format.Channels); err != nil {
+			return false, fmt.Errorf("failed to configure playback format: %w", err)
+		}
+	}
+
+	// Chunks are handed over as they are played, which the watchdog sees as progress
+	chunks := make(chan []byte)
+	e.goTask("replay", func() {
+		defer close(chunks)
+		for _, chunk := range cache.chunks {
+			select {
+			case chunks <- chunk:
+				e.progress()
+			case <-ctx.Done():
+				return
+			}
+		}
+	})
+	return true, e.soundPlayer.PlayStream(ctx, chunks)
+}
+
+// maxSpeechCacheBytes bounds the audio kept for repeating the last question,
+// about three minutes of 22.05 kHz mono speech
+const maxSpeechCacheBytes = 8 << 20
+
+// speechCache holds the played PCM audio of the last response. The audio
+// includes the asides spoken with the response, the text leaves them out
+type speechCache struct {
+	text      string // Set while the segments are queued, see addText
+	heard     string // Text with the asides
+	format    tts.AudioFormat
+	hasFormat bool
+	chunks    [][]byte
+	size      int
+	truncated bool
+	mutex     sync.Mutex
+}
+
+// add keeps a played chunk. The chunks are not modified by players, so they are not copied
+func (c *speechCache) add(chunk []byte) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.size+len(chunk) > maxSpeechCacheBytes {
+		c.truncated = true
+		return
+	}
+	c.chunks = append(c.chunks, chunk)
+	c.size += len(chunk)
+}
+
+// addText adds the text of a queued segment
+func (c *speechCache) addText(segment speechSegment) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.heard = strings.TrimSpace(c.heard + " " + segment.text)
+	if !segment.aside {
+		c.text = strings.TrimSpace(c.text + " " + segment.text)
+	}
+}
+
+// spokenText returns the text of the queued segments that belong to the response
+func (c *speechCache) spokenText() string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.text
+}
+
+// heardText returns the text of all queued segments
+func (c *speechCache) heardText() string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.heard
+}
+
+// complete returns whether the whole response is cached
+func (c *speechCache) complete() bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return !c.truncated && c.size > 0
+}
+
+// synthesizeSegment starts synthesis of one segment and returns its PCM stream
+// once the WAV header has been parsed
+func (e *Engine) synthesizeSegment(ctx context.Context, segment speechSegment, role string) (<-chan []byte, tts.AudioFormat, bool, error) {
+	synthesisOptions := e.synthesisOptions(role)
+	audioData := stream.NewPipe(ctx, e.config.StreamBufferBytes, stream.Block, nil)
+	e.goTask("synthesis", func() {
+		if err := e.synthesize(ctx, segment, synthesisOptions, audioData.In()); err != nil {
+			log.Printf("TTS synthesis error: %v", err)
+		}
+	})
+
+	// Strip the WAV header to get raw PCM
+	pcmData := make(chan []byte)
+	format, ok, err := tts.DecodeWAVStream(ctx, audioData.Out(), pcmData)
+	if err != nil {
+		return nil, tts.AudioFormat{}, false, fmt.Errorf("failed to decode synthesized audio: %w", err)
+	}
+
+	return pcmData, format, ok, nil
+}
+
+// synthesisOptions returns the options of speech in the given role
+func (e *Engine) synthesisOptions(role string) tts.SynthesisOptions {
+	config := e.currentConfig()
+	options := tts.GetDefaultSynthesisOptions()
+	options.Voice = config.Voice
+	if current := e.currentPersona(); current != nil {
+		if voice := e.personaVoice(current); voice != "" {
+			options.Voice = voice
+		}
+	}
+	options.Role = role
+	options.Speed = config.Speed
+	return options
+}
+
+// conditionAudio filters, trims silence from and normalizes the captured
+// audio before recognition when enabled, otherwise it returns the audio as is.
+// Captured chunks are returned to the pool once they are decoded
+func (e *Engine) conditionAudio(ctx context.Context, audioData <-chan []byte, pool *stream.Pool) <-chan []byte {
+	if !e.config.TrimSilence && !e.config.NormalizeLoudness && e.config.HighPassCutoff <= 0 {
+		return audioData
+	}
+
+	conditioned := make(chan []byte)
+	e.goTask("conditioning", func() {
+		defer close(conditioned)
+
+		conditioner := sound.NewSpeechConditioner(float64(e.config.SampleRate),
+			e.config.TrimSilence, e.config.NormalizeLoudness, e.config.SilenceThreshold)
+		defer func() {
+			if trimmed := conditioner.Trimmed(); trimmed > 0 {
+				e.debugf("Trimmed %s of silence before recognition", trimmed.Round(time.Millisecond))
+			}
+		}()
+
+		filter := e.newHighPassFilter()
+		aligner := pcm.NewAligner(binary.LittleEndian)
+		var samples, output []int16
+		for chunk := range audioData {
+			samples = aligner.Append(samples[:0], chunk)
+			pool.Put(chunk)
+			if filter != nil {
+				// Filtered first, so thumps and pops do not count as speech
+				filter.Apply(samples)
+			}
+			output = conditioner.Append(output[:0], samples)
+			if len(output) == 0 {
+				continue
+			}
+			// The chunk is handed over to the recognizer, so it gets its own buffer
+			select {
+			case conditioned <- pcm.Encode(output):
+			case <-ctx.Done():
+				return
+			}
+		}
+	})
+	return conditioned
+}
+
+// newHighPassFilter returns the capture high-pass filter, or nil when it is disabled
+func (e *Engine) newHighPassFilter() *sound.HighPassFilter {
+	if e.config.HighPassCutoff <= 0 {
+		return nil
+	}
+	return sound.NewHighPassFilter(e.config.HighPassCutoff, float64(e.config.SampleRate))
+}
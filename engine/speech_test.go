@@ -0,0 +1,121 @@
+package engine
+
+import (
+	"context"
+	"encoding/binary"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/d1nch8g/aihr/pcm"
+	"github.com/d1nch8g/aihr/tts"
+)
+
+// toneTTS synthesizes every text as a constant tone of the given length
+type toneTTS struct {
+	samples int
+	level   int16
+}
+
+func (s toneTTS) SynthesizeToStreamWithContext(ctx context.Context, text string, options tts.SynthesisOptions, audioData chan<- []byte) error {
+	defer close(audioData)
+
+	header := make([]byte, 44)
+	copy(header[0:4], "RIFF")
+	binary.LittleEndian.PutUint32(header[4:8], uint32(36+s.samples*pcm.SampleSize))
+	copy(header[8:12], "WAVE")
+	copy(header[12:16], "fmt ")
+	binary.LittleEndian.PutUint32(header[16:20], 16)
+	binary.LittleEndian.PutUint16(header[20:22], 1)
+	binary.LittleEndian.PutUint16(header[22:24], 1)
+	binary.LittleEndian.PutUint32(header[24:28], 8000)
+	binary.LittleEndian.PutUint32(header[28:32], 8000*pcm.SampleSize)
+	binary.LittleEndian.PutUint16(header[32:34], pcm.SampleSize)
+	binary.LittleEndian.PutUint16(header[34:36], 16)
+	copy(header[36:40], "data")
+	binary.LittleEndian.PutUint32(header[40:44], uint32(s.samples*pcm.SampleSize))
+
+	tone := make([]int16, s.samples)
+	for i := range tone {
+		tone[i] = s.level
+	}
+	for _, chunk := range [][]byte{header, pcm.Encode(tone)} {
+		select {
+		case audioData <- chunk:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+func (toneTTS) Close() error { return nil }
+
+// recordingPlayer keeps the audio it is given
+type recordingPlayer struct {
+	discardPlayer
+	mutex sync.Mutex
+	audio []byte
+}
+
+func (p *recordingPlayer) PlayStream(ctx context.Context, audioData <-chan []byte) error {
+	for chunk := range audioData {
+		p.mutex.Lock()
+		p.audio = append(p.audio, chunk...)
+		p.mutex.Unlock()
+	}
+	return ctx.Err()
+}
+
+func TestSpeakSegmentsCrossfadesBoundary(t *testing.T) {
+	const samples, fade = 800, 80 // 100 ms segments, 10 ms fade at 8 kHz
+	player := &recordingPlayer{}
+	e := NewEngine(EngineConfig{CrossfadeDuration: 10 * time.Millisecond}, silentStreamer{}, failingSTT{},
+		echoGPT{}, toneTTS{samples: samples, level: 1000}, player)
+
+	segments := []speechSegment{
+		{template: tts.Template{Text: "First."}, text: "First."},
+		{template: tts.Template{Text: "Second."}, text: "Second."},
+	}
+	if err := e.speakSegments(context.Background(), segments, ""); err != nil {
+		t.Fatalf("speakSegments: %v", err)
+	}
+
+	played := pcm.Decode(player.audio)
+	if want := 2*samples - fade; len(played) != want {
+		t.Fatalf("played %d samples, want %d with the segments overlapping", len(played), want)
+	}
+	// The fades at both ends aside, the level holds across the boundary
+	for i, sample := range played[fade : len(played)-fade] {
+		if sample < 999 || sample > 1000 {
+			t.Fatalf("sample %d is %d, want the tone level across the boundary", fade+i, sample)
+		}
+	}
+
+	if e.lastSpeech == nil || e.lastSpeech.spokenText() != "First. Second." {
+		t.Errorf("cached speech does not hold both segments")
+	}
+}
+
+func TestSpeechCacheLeavesOutAsides(t *testing.T) {
+	e := NewEngine(EngineConfig{}, silentStreamer{}, failingSTT{}, echoGPT{}, toneTTS{samples: 800, level: 1000}, &recordingPlayer{})
+
+	speech := e.newSpeech(context.Background(), "")
+	for _, segment := range []speechSegment{
+		{template: tts.Template{Text: "Good point."}, text: "Good point.", aside: true},
+		{template: tts.Template{Text: "Thanks."}, text: "Thanks."},
+		{template: tts.Template{Text: "Next question."}, text: "Next question."},
+	} {
+		if err := speech.say(segment); err != nil {
+			t.Fatalf("say: %v", err)
+		}
+	}
+	if err := speech.finish(); err != nil {
+		t.Fatalf("finish: %v", err)
+	}
+
+	// The response is repeated by the text of the turn, which has no reaction
+	if ok, err := e.replaySpeech(context.Background(), "Thanks. Next question."); !ok || err != nil {
+		t.Errorf("replaySpeech = %v, %v, want the joined response replayed", ok, err)
+	}
+}
@@ -0,0 +1,87 @@
+package engine
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"github.com/d1nch8g/aihr/stt"
+	"github.com/d1nch8g/aihr/subtitle"
+)
+
+// startCaptionFile creates the caption file of the session when CaptionFile is set
+func (e *Engine) startCaptionFile(id string) {
+	path := e.currentConfig().CaptionFile
+	if path == "" {
+		return
+	}
+	track, err := subtitle.Create(strings.ReplaceAll(path, "{session}", id))
+	if err != nil {
+		log.Printf("Failed to start captions: %v", err)
+		return
+	}
+	e.captionTrack.Store(track)
+}
+
+// finishCaptionFile writes the final caption file of the session
+func (e *Engine) finishCaptionFile() {
+	track := e.captionTrack.Swap(nil)
+	if track == nil {
+		return
+	}
+	if err := track.Close(); err != nil {
+		log.Printf("Failed to finish captions: %v", err)
+	}
+}
+
+// caption adds a phrase spoken between start and end to the caption file,
+// timed from the start of the session
+func (e *Engine) caption(speaker, text string, start, end time.Time) {
+	track := e.captionTrack.Load()
+	if track == nil {
+		return
+	}
+	e.recordMutex.RLock()
+	started := e.record.StartedAt
+	e.recordMutex.RUnlock()
+
+	cue := subtitle.Cue{Start: start.Sub(started), End: end.Sub(started), Speaker: speaker, Text: text}
+	if err := track.Add(cue); err != nil {
+		log.Printf("Failed to add caption: %v", err)
+	}
+}
+
+// candidateSpeaker names the candidate in captions
+func (e *Engine) candidateSpeaker() string {
+	if name := e.currentConfig().CandidateName; name != "" {
+		return name
+	}
+	return "Candidate"
+}
+
+// interviewerSpeaker names the interviewer in captions, the persona of the
+// panel when one leads the interview
+func (e *Engine) interviewerSpeaker() string {
+	if name := e.personaName(); name != "" {
+		return name
+	}
+	return "Interviewer"
+}
+
+// captionAnswer adds a recognized phrase of the candidate to the caption
+// file. Word timings count from the start of the capture, without them the
+// phrase is timed from the end of the previous one to its recognition
+func (e *Engine) captionAnswer(result stt.Utterance, listening time.Time) {
+	if e.captionTrack.Load() == nil {
+		return
+	}
+	start, end := e.lastCaptioned, time.Now()
+	if words := result.Words; len(words) > 0 {
+		start, end = listening.Add(words[0].Start), listening.Add(words[len(words)-1].End)
+	}
+	if start.Before(listening) {
+		start = listening
+	}
+	e.lastCaptioned = end
+	e.caption(e.candidateSpeaker(), result.Text, start, end)
+}
@@ -0,0 +1,100 @@
+package engine
+
+import (
+	"fmt"
+	"log"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// teardownTimeout bounds the wait for the goroutines of a session when it ends
+	teardownTimeout = 5 * time.Second
+
+	// captureStopTimeout bounds the wait for audio capture to stop after a turn
+	captureStopTimeout = 2 * time.Second
+
+	// recognitionRetryInterval is the pause after a failed recognition stream
+	// before listening again, so a broken provider is not retried in a busy loop
+	recognitionRetryInterval = time.Second
+)
+
+// tasks counts the running goroutines of a session by name, so teardown can
+// verify that all of them, and the streams and audio buffers they hold, were
+// released
+type tasks struct {
+	running map[string]int
+	changed chan struct{} // Closed and replaced whenever a goroutine ends
+	mutex   sync.Mutex
+}
+
+// goTask runs fn in a goroutine tracked under the name. A panic in fn is
+// logged with its stack instead of ending the process
+func (e *Engine) goTask(name string, fn func()) {
+	e.tasks.mutex.Lock()
+	if e.tasks.running == nil {
+		e.tasks.running = make(map[string]int)
+		e.tasks.changed = make(chan struct{})
+	}
+	e.tasks.running[name]++
+	e.tasks.mutex.Unlock()
+
+	go func() {
+		defer func() {
+			e.tasks.mutex.Lock()
+			defer e.tasks.mutex.Unlock()
+			if e.tasks.running[name]--; e.tasks.running[name] == 0 {
+				delete(e.tasks.running, name)
+			}
+			close(e.tasks.changed)
+			e.tasks.changed = make(chan struct{})
+		}()
+		// The panic is logged by safely, the goroutine just ends
+		_ = e.safely(name, func() error {
+			fn()
+			return nil
+		})
+	}()
+}
+
+// runningTasks returns the names of the running goroutines with their count
+// and a channel closed when one of them ends
+func (e *Engine) runningTasks() ([]string, <-chan struct{}) {
+	e.tasks.mutex.Lock()
+	defer e.tasks.mutex.Unlock()
+
+	names := make([]string, 0, len(e.tasks.running))
+	for name, count := range e.tasks.running {
+		names = append(names, fmt.Sprintf("%s (%d)", name, count))
+	}
+	sort.Strings(names)
+	return names, e.tasks.changed
+}
+
+// verifyTeardown waits for the goroutines of the session to end and reports
+// the ones still running after teardownTimeout. At the debug log level the
+// stacks of all goroutines are logged to find where they are stuck
+func (e *Engine) verifyTeardown() {
+	deadline := time.NewTimer(teardownTimeout)
+	defer deadline.Stop()
+
+	for {
+		running, changed := e.runningTasks()
+		if len(running) == 0 {
+			return
+		}
+		select {
+		case <-changed:
+		case <-deadline.C:
+			log.Printf("Session %s leaked goroutines at teardown: %s", e.GetRecord().ID, strings.Join(running, ", "))
+			if e.currentConfig().LogLevel == "debug" {
+				stacks := make([]byte, 1<<20)
+				log.Printf("Goroutine stacks:\n%s", stacks[:runtime.Stack(stacks, true)])
+			}
+			return
+		}
+	}
+}
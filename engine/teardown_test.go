@@ -0,0 +1,188 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/d1nch8g/aihr/stream"
+	"github.com/d1nch8g/aihr/tts"
+)
+
+// failingSTT fails every recognition stream at once, like a provider that is down
+type failingSTT struct{}
+
+func (failingSTT) StreamRecognize(ctx context.Context, audioData <-chan []byte, results chan<- string, sampleRate int64) error {
+	return errors.New("recognition unavailable")
+}
+
+func (failingSTT) Close() error { return nil }
+
+// flakySTT fails the first recognition streams at once and closes failed
+// after the last of them. Later streams stay open until their audio or
+// context ends, like the gRPC stream of a provider that came back
+type flakySTT struct {
+	failures int32
+	failed   chan struct{}
+	calls    atomic.Int32
+	open     atomic.Int32
+}
+
+func (s *flakySTT) StreamRecognize(ctx context.Context, audioData <-chan []byte, results chan<- string, sampleRate int64) error {
+	call := s.calls.Add(1)
+	if call <= s.failures {
+		if call == s.failures {
+			close(s.failed)
+		}
+		return errors.New("recognition unavailable")
+	}
+
+	s.open.Add(1)
+	defer s.open.Add(-1)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case _, ok := <-audioData:
+			if !ok {
+				return nil
+			}
+		}
+	}
+}
+
+func (s *flakySTT) Close() error { return nil }
+
+// silentStreamer captures nothing until the context ends
+type silentStreamer struct{}
+
+func (silentStreamer) Initialize() error { return nil }
+func (silentStreamer) Terminate()        {}
+func (silentStreamer) Open() error       { return nil }
+func (silentStreamer) Close() error      { return nil }
+
+func (silentStreamer) StartCapture(ctx context.Context, audioData chan<- []byte) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+// pooledStreamer captures silence into pooled buffers until the context ends
+type pooledStreamer struct {
+	silentStreamer
+	pool      *stream.Pool
+	capturing atomic.Int32
+}
+
+func (s *pooledStreamer) BufferPool() *stream.Pool { return s.pool }
+
+func (s *pooledStreamer) StartCapture(ctx context.Context, audioData chan<- []byte) error {
+	s.capturing.Add(1)
+	defer s.capturing.Add(-1)
+
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		chunk := s.pool.Get()
+		chunk = append(chunk, make([]byte, 320)...)
+		select {
+		case audioData <- chunk:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// discardPlayer consumes the audio it is given
+type discardPlayer struct{}
+
+func (discardPlayer) Initialize() error { return nil }
+func (discardPlayer) Terminate()        {}
+func (discardPlayer) Open() error       { return nil }
+func (discardPlayer) Close() error      { return nil }
+
+func (discardPlayer) PlayStream(ctx context.Context, audioData <-chan []byte) error {
+	for range audioData {
+	}
+	return ctx.Err()
+}
+
+func (discardPlayer) Drain(ctx context.Context) error { return nil }
+
+// silentTTS synthesizes no audio
+type silentTTS struct{}
+
+func (silentTTS) SynthesizeToStreamWithContext(ctx context.Context, text string, options tts.SynthesisOptions, audioData chan<- []byte) error {
+	close(audioData)
+	return nil
+}
+
+func (silentTTS) Close() error { return nil }
+
+// echoGPT answers every completion with the same text
+type echoGPT struct{}
+
+func (echoGPT) Complete(systemMessage, userMessage string) (string, error) {
+	return "Tell me more.", nil
+}
+
+func TestTeardownReleasesGoroutines(t *testing.T) {
+	recognizer := &flakySTT{failures: 3, failed: make(chan struct{})}
+	streamer := &pooledStreamer{pool: stream.NewPool(320)}
+	interviewer, err := New(
+		WithGPT(echoGPT{}),
+		WithSTT(recognizer),
+		WithTTS(silentTTS{}),
+		WithAudioStreamer(streamer),
+		WithPlayer(discardPlayer{}),
+		// Conditioning returns the captured chunks to the pool
+		WithConfig(EngineConfig{TrimSilence: true}),
+	)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	e := interviewer.(*Engine)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	started := make(chan error, 1)
+	go func() {
+		started <- e.Start(ctx)
+	}()
+
+	// Let a few recognition streams fail before the session ends
+	select {
+	case <-recognizer.failed:
+	case <-time.After(10 * time.Second):
+		t.Fatal("recognition was not retried after it failed")
+	}
+	cancel()
+
+	select {
+	case err := <-started:
+		if err != nil && !errors.Is(err, context.Canceled) {
+			t.Fatalf("Start: %v", err)
+		}
+	case <-time.After(teardownTimeout + time.Second):
+		t.Fatal("Start did not return after the context was cancelled")
+	}
+	if err := e.Stop(); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+
+	if running, _ := e.runningTasks(); len(running) > 0 {
+		t.Errorf("tasks still running after Stop: %v", running)
+	}
+	if open := recognizer.open.Load(); open > 0 {
+		t.Errorf("%d recognition streams still open after Stop", open)
+	}
+	if capturing := streamer.capturing.Load(); capturing > 0 {
+		t.Errorf("%d captures still running after Stop", capturing)
+	}
+	// Goroutines still holding pooled chunks are reported by goleak in TestMain
+}
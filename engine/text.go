@@ -0,0 +1,67 @@
+package engine
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// TextIO exchanges the conversation as text instead of speech
+type TextIO interface {
+	// ReadAnswer returns the next candidate answer, io.EOF ends the interview
+	ReadAnswer(ctx context.Context) (string, error)
+
+	// WriteResponse delivers an interviewer response
+	WriteResponse(text string) error
+}
+
+// textLine is a line read from the text input
+type textLine struct {
+	text string
+	err  error
+}
+
+// lineIO reads answers line by line and prints responses
+type lineIO struct {
+	input  io.Reader
+	output io.Writer
+	lines  chan textLine
+}
+
+// ReadAnswer prompts for and reads the next line
+func (l *lineIO) ReadAnswer(ctx context.Context) (string, error) {
+	fmt.Fprint(l.output, "You: ")
+
+	if l.lines == nil {
+		l.lines = make(chan textLine)
+		go func() {
+			defer close(l.lines)
+			scanner := bufio.NewScanner(l.input)
+			for scanner.Scan() {
+				l.lines <- textLine{text: scanner.Text()}
+			}
+			if err := scanner.Err(); err != nil {
+				l.lines <- textLine{err: err}
+			}
+		}()
+	}
+
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case line, ok := <-l.lines:
+		if !ok {
+			fmt.Fprintln(l.output)
+			return "", io.EOF
+		}
+		return strings.TrimSpace(line.text), line.err
+	}
+}
+
+// WriteResponse prints the response
+func (l *lineIO) WriteResponse(text string) error {
+	_, err := fmt.Fprintf(l.output, "AI: %s\n", text)
+	return err
+}
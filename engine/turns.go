@@ -0,0 +1,119 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"time"
+
+	"github.com/d1nch8g/aihr/correlation"
+	"github.com/d1nch8g/aihr/gpt"
+	"github.com/d1nch8g/aihr/observe"
+	"github.com/d1nch8g/aihr/turnlog"
+)
+
+// complete sends a completion request to the GPT client, counts the tokens
+// it consumed towards the current turn when the client reports them and
+// exports the generation made in the given stage. Clients taking a context
+// send the session ID with the request
+func (e *Engine) complete(stage, systemMessage, userMessage string) (string, error) {
+	return e.completeWith(e.gptClient, stage, systemMessage, userMessage)
+}
+
+// completeWith works like complete with the given client
+func (e *Engine) completeWith(gptClient gpt.GPTClient, stage, systemMessage, userMessage string) (string, error) {
+	start := time.Now()
+	defer e.progress()
+
+	var text string
+	var usage gpt.Usage
+	var err error
+	switch client := gptClient.(type) {
+	case gpt.ContextCompleter:
+		ctx := correlation.WithSession(context.Background(), e.GetRecord().ID)
+		text, usage, err = client.CompleteContext(ctx, systemMessage, userMessage)
+	case gpt.UsageReporter:
+		text, usage, err = client.CompleteWithUsage(systemMessage, userMessage)
+	default:
+		text, err = gptClient.Complete(systemMessage, userMessage)
+		e.export(stage, systemMessage, userMessage, text, start, nil, err)
+		return text, err
+	}
+
+	e.usageMutex.Lock()
+	e.usage = e.usage.Add(usage)
+	e.usageReported = true
+	e.usageMutex.Unlock()
+	e.export(stage, systemMessage, userMessage, text, start, &usage, err)
+	return text, err
+}
+
+// export sends the generation to the observability exporter when one is set.
+// Exports run in the background, Start waits for them before returning
+func (e *Engine) export(stage, systemMessage, userMessage, text string, start time.Time, usage *gpt.Usage, err error) {
+	exporter := e.currentConfig().Exporter
+	if exporter == nil {
+		return
+	}
+
+	generation := observe.Generation{
+		ID:        observe.NewID(),
+		Session:   e.GetRecord().ID,
+		Stage:     stage,
+		System:    systemMessage,
+		Input:     userMessage,
+		Output:    text,
+		Time:      start,
+		LatencyMs: time.Since(start).Milliseconds(),
+		Tokens:    usage,
+	}
+	if err != nil {
+		generation.Error = err.Error()
+	}
+
+	e.exports.Add(1)
+	go func() {
+		defer e.exports.Done()
+		if err := exporter.Export(generation); err != nil {
+			log.Printf("Failed to export %s generation: %v", stage, err)
+		}
+	}()
+}
+
+// resetUsage starts counting tokens for a new turn
+func (e *Engine) resetUsage() {
+	e.usageMutex.Lock()
+	defer e.usageMutex.Unlock()
+	e.usage, e.usageReported = gpt.Usage{}, false
+}
+
+// logTurn writes the turn record when a turn logger is set. Turns without an
+// answer, e.g. silence, and turns ended by stopping or by the end of the text
+// input are not logged
+func (e *Engine) logTurn(turn *turnlog.Turn, err error) {
+	logger := e.currentConfig().TurnLogger
+	if logger == nil || errors.Is(err, context.Canceled) || errors.Is(err, io.EOF) {
+		return
+	}
+	if turn.Answer == "" && err == nil {
+		return
+	}
+
+	e.usageMutex.Lock()
+	if e.usageReported {
+		usage := e.usage
+		turn.Tokens = &usage
+	}
+	e.usageMutex.Unlock()
+
+	if err != nil {
+		turn.Error = err.Error()
+	}
+	turn.Session = e.GetRecord().ID
+	e.turns++
+	turn.Turn = e.turns
+	if err := logger.Log(*turn); err != nil {
+		log.Printf("Failed to log turn: %v", err)
+	}
+}
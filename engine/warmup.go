@@ -0,0 +1,57 @@
+package engine
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/d1nch8g/aihr/gpt"
+	"github.com/d1nch8g/aihr/stt"
+	"github.com/d1nch8g/aihr/tts"
+)
+
+// warmUpTimeout bounds the warm-up of one provider
+const warmUpTimeout = 10 * time.Second
+
+// warmer is the Warmer interface shared by the stt, tts and gpt packages
+type warmer interface {
+	Warm(ctx context.Context) error
+}
+
+// warmUp connects the providers in the background while the audio devices
+// open and the greeting is spoken, so the first answer does not pay the TLS
+// handshakes and cold models. A failed warm-up is only logged, the first
+// request connects again
+func (e *Engine) warmUp(ctx context.Context) {
+	warmers := make(map[string]warmer)
+	if e.textIO == nil {
+		if w, ok := e.sttClient.(stt.Warmer); ok {
+			warmers["stt"] = w
+		}
+	}
+	if e.textIO == nil || e.typedAnswers {
+		if w, ok := e.ttsClient.(tts.Warmer); ok {
+			warmers["tts"] = w
+		}
+	}
+	if w, ok := e.gptClient.(gpt.Warmer); ok {
+		warmers["gpt"] = w
+	}
+	if w, ok := e.fastGPT.(gpt.Warmer); ok {
+		warmers["fast gpt"] = w
+	}
+
+	for name, w := range warmers {
+		e.goTask(name+" warm-up", func() {
+			ctx, cancel := context.WithTimeout(ctx, warmUpTimeout)
+			defer cancel()
+
+			start := time.Now()
+			if err := w.Warm(ctx); err != nil {
+				log.Printf("Failed to warm up %s: %v", name, err)
+				return
+			}
+			e.debugf("Warmed up %s in %dms", name, time.Since(start).Milliseconds())
+		})
+	}
+}
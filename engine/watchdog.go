@@ -0,0 +1,79 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/d1nch8g/aihr/session"
+)
+
+// stallGracePeriod is how long a stalled turn may take to end after it was
+// canceled. A stage that ignores cancellation, e.g. a hung model request, is
+// abandoned after it and the interview listens again
+const stallGracePeriod = 2 * time.Second
+
+// errTurnStalled ends a turn canceled by the watchdog
+var errTurnStalled = errors.New("turn made no progress")
+
+// watchdog holds the time of the last progress of the current turn: a
+// recognition result, a model response or played audio
+type watchdog struct {
+	last    atomic.Int64 // Unix nanoseconds
+	waiting atomic.Bool  // The turn waits for typed input, which may take any time
+}
+
+// progress notes that the current turn moved forward
+func (e *Engine) progress() {
+	e.watchdog.last.Store(time.Now().UnixNano())
+}
+
+// stalledFor returns how long the current turn has made no progress
+func (e *Engine) stalledFor() time.Duration {
+	if e.watchdog.waiting.Load() {
+		return 0
+	}
+	return time.Since(time.Unix(0, e.watchdog.last.Load()))
+}
+
+// watchTurn runs the turn and cancels it with errTurnStalled when it makes no
+// progress for StallTimeout. A stalled turn is counted in the session record
+func (e *Engine) watchTurn(ctx context.Context, cancel context.CancelCauseFunc, turn func(context.Context) error) error {
+	timeout := e.currentConfig().StallTimeout
+	if timeout <= 0 {
+		return turn(ctx)
+	}
+
+	e.progress()
+	done := make(chan error, 1)
+	e.goTask("turn", func() {
+		done <- turn(ctx)
+	})
+
+	ticker := time.NewTicker(timeout / 4)
+	defer ticker.Stop()
+	for {
+		select {
+		case err := <-done:
+			return err
+		case <-ticker.C:
+			stalled := e.stalledFor()
+			if stalled < timeout {
+				continue
+			}
+			log.Printf("Turn made no progress for %s, restarting it", stalled.Round(time.Second))
+			e.emitf(session.EventTurnStalled, "no progress for %s", stalled.Round(time.Second))
+			e.recordStall()
+			cancel(errTurnStalled)
+
+			select {
+			case <-done:
+			case <-time.After(stallGracePeriod):
+				log.Printf("Stalled turn did not end within %s, abandoning it", stallGracePeriod)
+			}
+			return errTurnStalled
+		}
+	}
+}
@@ -0,0 +1,13 @@
+package eval
+
+// Evaluator defines the interface for scoring candidate answers
+type Evaluator interface {
+	// ScoreAnswer rates how well the answer addresses the question.
+	// The returned score is in the range [MinScore, MaxScore]
+	ScoreAnswer(question, answer string) (float64, error)
+}
+
+const (
+	MinScore = 0.0
+	MaxScore = 10.0
+)
@@ -0,0 +1,93 @@
+package eval
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/d1nch8g/aihr/gpt"
+)
+
+// maxRepairs is the number of times an invalid assessment is sent back to
+// the model with the validation error before the answer is left unscored
+const maxRepairs = 2
+
+const scoringPrompt = `You are an interview evaluator. Score the candidate's answer to the interviewer's question
+on every dimension of the rubric on a scale from 0 to 10, where 0 is no relevant answer and 10 is excellent.
+Rubric:
+%s
+Reply with JSON only, matching this schema:
+%s
+"evidence" holds exact quotes from the answer that support the score, empty when there is nothing to quote.
+"confidence" is how sure you are of the scores, from 0 to 1.`
+
+// GPTEvaluator scores answers by asking the GPT model for a structured assessment
+type GPTEvaluator struct {
+	client gpt.GPTClient
+	rubric Rubric
+}
+
+// Ensure GPTEvaluator implements Evaluator interface
+var _ Evaluator = (*GPTEvaluator)(nil)
+
+// Ensure GPTEvaluator implements RubricEvaluator interface
+var _ RubricEvaluator = (*GPTEvaluator)(nil)
+
+// Ensure GPTEvaluator implements QuestionRubricEvaluator interface
+var _ QuestionRubricEvaluator = (*GPTEvaluator)(nil)
+
+// NewGPTEvaluator creates a new evaluator backed by a GPT client. Answers
+// are scored on the DefaultRubric when the rubric is empty
+func NewGPTEvaluator(client gpt.GPTClient, rubric Rubric) *GPTEvaluator {
+	if len(rubric) == 0 {
+		rubric = DefaultRubric
+	}
+	return &GPTEvaluator{client: client, rubric: rubric}
+}
+
+// ScoreAnswer assesses the answer and returns its overall score
+func (e *GPTEvaluator) ScoreAnswer(question, answer string) (float64, error) {
+	assessment, err := e.AssessAnswer(question, answer)
+	return assessment.Score, err
+}
+
+// AssessAnswer asks the model for an assessment on the rubric of the
+// evaluator, see AssessAnswerOn
+func (e *GPTEvaluator) AssessAnswer(question, answer string) (Assessment, error) {
+	return e.AssessAnswerOn(e.rubric, question, answer)
+}
+
+// AssessAnswerOn asks the model for an assessment on the rubric and validates
+// it. An invalid reply is sent back with the error to be repaired
+func (e *GPTEvaluator) AssessAnswerOn(rubric Rubric, question, answer string) (Assessment, error) {
+	schema := rubric.Schema()
+	var dimensions strings.Builder
+	for _, dimension := range rubric {
+		fmt.Fprintf(&dimensions, "- %s: %s\n", dimension.Name, dimension.Description)
+	}
+	systemMessage := fmt.Sprintf(scoringPrompt, dimensions.String(), schema)
+	userMessage := fmt.Sprintf("Question: %s\nAnswer: %s", question, answer)
+
+	request := userMessage
+	for attempt := 0; ; attempt++ {
+		var reply string
+		var err error
+		if completer, ok := e.client.(gpt.SchemaCompleter); ok {
+			reply, err = completer.CompleteJSON(systemMessage, request, schema)
+		} else {
+			reply, err = e.client.Complete(systemMessage, request)
+		}
+		if err != nil {
+			return Assessment{}, fmt.Errorf("failed to request assessment: %w", err)
+		}
+
+		assessment, err := parseAssessment(reply, rubric, answer)
+		if err == nil {
+			return assessment, nil
+		}
+		if attempt == maxRepairs {
+			return Assessment{}, fmt.Errorf("invalid assessment after %d repairs: %w", maxRepairs, err)
+		}
+		request = fmt.Sprintf("%s\n\nYour previous reply was invalid: %v\nPrevious reply: %s\n"+
+			"Reply again with the corrected JSON only.", userMessage, err, reply)
+	}
+}
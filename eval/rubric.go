@@ -0,0 +1,195 @@
+package eval
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// Dimension is one criterion of the rubric answers are scored on
+type Dimension struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Weight      float64 `json:"weight,omitempty"` // Weight in the overall score, 1 when zero
+}
+
+// Rubric is the set of criteria answers are scored on
+type Rubric []Dimension
+
+// DefaultRubric is used when no rubric is configured
+var DefaultRubric = Rubric{
+	{Name: "correctness", Description: "The answer is factually and technically correct"},
+	{Name: "depth", Description: "The answer shows understanding beyond the surface, with reasons, trade-offs or examples"},
+	{Name: "communication", Description: "The answer is clear, structured and to the point"},
+}
+
+// LoadRubric reads a rubric from a JSON file with an array of dimensions
+func LoadRubric(path string) (Rubric, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rubric: %w", err)
+	}
+	var rubric Rubric
+	if err := json.Unmarshal(content, &rubric); err != nil {
+		return nil, fmt.Errorf("failed to parse rubric %s: %w", path, err)
+	}
+	if err := rubric.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid rubric %s: %w", path, err)
+	}
+	return rubric, nil
+}
+
+// Validate checks that the rubric has dimensions with unique names and valid weights
+func (r Rubric) Validate() error {
+	if len(r) == 0 {
+		return fmt.Errorf("rubric has no dimensions")
+	}
+	seen := make(map[string]bool, len(r))
+	for _, dimension := range r {
+		if dimension.Name == "" {
+			return fmt.Errorf("rubric dimension has no name")
+		}
+		if seen[dimension.Name] {
+			return fmt.Errorf("duplicate rubric dimension %q", dimension.Name)
+		}
+		if dimension.Weight < 0 {
+			return fmt.Errorf("rubric dimension %q has a negative weight", dimension.Name)
+		}
+		seen[dimension.Name] = true
+	}
+	return nil
+}
+
+// Names returns the names of the dimensions
+func (r Rubric) Names() []string {
+	names := make([]string, len(r))
+	for i, dimension := range r {
+		names[i] = dimension.Name
+	}
+	return names
+}
+
+// Schema returns the JSON schema of an assessment on the rubric
+func (r Rubric) Schema() json.RawMessage {
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"dimensions": map[string]any{
+				"type": "array",
+				"items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"name":     map[string]any{"type": "string", "enum": r.Names()},
+						"score":    map[string]any{"type": "number", "minimum": MinScore, "maximum": MaxScore},
+						"evidence": map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+					},
+					"required": []string{"name", "score", "evidence"},
+				},
+			},
+			"confidence": map[string]any{"type": "number", "minimum": 0, "maximum": 1},
+		},
+		"required": []string{"dimensions", "confidence"},
+	}
+	content, _ := json.Marshal(schema)
+	return content
+}
+
+// DimensionScore is the score of an answer on one dimension of the rubric
+type DimensionScore struct {
+	Name     string   `json:"name"`
+	Score    float64  `json:"score"`
+	Evidence []string `json:"evidence"` // Quotes from the answer the score is based on
+}
+
+// Assessment is the structured evaluation of an answer
+type Assessment struct {
+	Score      float64          `json:"score"` // Weighted mean of the dimension scores
+	Dimensions []DimensionScore `json:"dimensions,omitempty"`
+	Confidence float64          `json:"confidence,omitempty"` // Of the evaluator, from 0 to 1
+}
+
+// RubricEvaluator is implemented by evaluators that score answers on every
+// dimension of a rubric
+type RubricEvaluator interface {
+	// AssessAnswer rates the answer on each dimension with quotes from it as evidence
+	AssessAnswer(question, answer string) (Assessment, error)
+}
+
+// QuestionRubricEvaluator is implemented by rubric evaluators that can score
+// an answer on the rubric of its question instead of their own
+type QuestionRubricEvaluator interface {
+	// AssessAnswerOn rates the answer on each dimension of the rubric
+	AssessAnswerOn(rubric Rubric, question, answer string) (Assessment, error)
+}
+
+// parseAssessment reads the JSON reply of the model and validates it against
+// the rubric. Every dimension must be scored once within the score range and
+// every evidence quote must be found in the answer
+func parseAssessment(reply string, rubric Rubric, answer string) (Assessment, error) {
+	reply = strings.TrimSpace(reply)
+	reply = strings.TrimPrefix(reply, "```json")
+	reply = strings.TrimSuffix(strings.TrimPrefix(reply, "```"), "```")
+
+	var assessment Assessment
+	if err := json.Unmarshal([]byte(reply), &assessment); err != nil {
+		return Assessment{}, fmt.Errorf("reply is not valid JSON: %w", err)
+	}
+	if assessment.Confidence < 0 || assessment.Confidence > 1 {
+		return Assessment{}, fmt.Errorf("confidence %g is not between 0 and 1", assessment.Confidence)
+	}
+
+	scores := make(map[string]DimensionScore, len(assessment.Dimensions))
+	for _, dimension := range assessment.Dimensions {
+		if _, ok := scores[dimension.Name]; ok {
+			return Assessment{}, fmt.Errorf("dimension %q is scored twice", dimension.Name)
+		}
+		if dimension.Score < MinScore || dimension.Score > MaxScore {
+			return Assessment{}, fmt.Errorf("score %g of dimension %q is not between %g and %g", dimension.Score, dimension.Name, MinScore, MaxScore)
+		}
+		for _, quote := range dimension.Evidence {
+			if !strings.Contains(normalizeQuote(answer), normalizeQuote(quote)) {
+				return Assessment{}, fmt.Errorf("evidence %q of dimension %q is not a quote from the answer", quote, dimension.Name)
+			}
+		}
+		scores[dimension.Name] = dimension
+	}
+
+	// The dimensions are kept in the order of the rubric
+	ordered := make([]DimensionScore, 0, len(rubric))
+	var total, weights float64
+	for _, dimension := range rubric {
+		score, ok := scores[dimension.Name]
+		if !ok {
+			return Assessment{}, fmt.Errorf("dimension %q is not scored", dimension.Name)
+		}
+		delete(scores, dimension.Name)
+		if score.Evidence == nil {
+			score.Evidence = []string{}
+		}
+		ordered = append(ordered, score)
+
+		weight := dimension.Weight
+		if weight == 0 {
+			weight = 1
+		}
+		total += score.Score * weight
+		weights += weight
+	}
+	for name := range scores {
+		return Assessment{}, fmt.Errorf("unknown dimension %q", name)
+	}
+
+	assessment.Dimensions = ordered
+	assessment.Score = total / weights
+	return assessment, nil
+}
+
+// normalizeQuote lowercases the text and reduces it to words, so quotes match
+// regardless of the punctuation the recognizer or the model added
+func normalizeQuote(text string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	}), " ")
+}
@@ -0,0 +1,91 @@
+// Package experiment assigns interviews to prompt, voice and model variants
+// so their outcomes can be compared
+package experiment
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"os"
+)
+
+// Variant overrides interview settings, empty fields keep the configured value
+type Variant struct {
+	Name         string  `json:"name"`
+	Weight       float64 `json:"weight"` // Relative share of sessions, 1 when omitted
+	SystemPrompt string  `json:"system_prompt,omitempty"`
+	Voice        string  `json:"voice,omitempty"`
+	Speed        float64 `json:"speed,omitempty"`
+	Model        string  `json:"model,omitempty"` // GPT model, e.g. "yandexgpt-lite/latest"
+}
+
+// Experiment is a set of variants sessions are randomly assigned to
+type Experiment struct {
+	Name     string    `json:"name"`
+	Variants []Variant `json:"variants"`
+}
+
+// Load reads an experiment definition from a JSON file
+func Load(path string) (*Experiment, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read experiment: %w", err)
+	}
+
+	var experiment Experiment
+	if err := json.Unmarshal(data, &experiment); err != nil {
+		return nil, fmt.Errorf("failed to decode experiment %s: %w", path, err)
+	}
+	if err := experiment.validate(); err != nil {
+		return nil, fmt.Errorf("invalid experiment %s: %w", path, err)
+	}
+	return &experiment, nil
+}
+
+// Assign picks a variant at random in proportion to the variant weights
+func (e *Experiment) Assign() Variant {
+	total := 0.0
+	for _, variant := range e.Variants {
+		total += variant.weight()
+	}
+
+	pick := rand.Float64() * total
+	for _, variant := range e.Variants {
+		pick -= variant.weight()
+		if pick < 0 {
+			return variant
+		}
+	}
+	return e.Variants[len(e.Variants)-1]
+}
+
+func (e *Experiment) validate() error {
+	if e.Name == "" {
+		return fmt.Errorf("experiment name is required")
+	}
+	if len(e.Variants) == 0 {
+		return fmt.Errorf("at least one variant is required")
+	}
+
+	names := make(map[string]bool)
+	for _, variant := range e.Variants {
+		if variant.Name == "" {
+			return fmt.Errorf("variant name is required")
+		}
+		if names[variant.Name] {
+			return fmt.Errorf("duplicate variant %s", variant.Name)
+		}
+		if variant.Weight < 0 {
+			return fmt.Errorf("variant %s has a negative weight", variant.Name)
+		}
+		names[variant.Name] = true
+	}
+	return nil
+}
+
+func (v Variant) weight() float64 {
+	if v.Weight == 0 {
+		return 1
+	}
+	return v.Weight
+}
@@ -0,0 +1,168 @@
+// Package export bundles the artifacts of a session into one zip archive for
+// archiving or handing to legal and compliance reviews
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/d1nch8g/aihr/session"
+)
+
+// Signature states recorded in the manifest
+const (
+	SignatureValid      = "valid"
+	SignatureTampered   = "tampered"
+	SignatureUnsigned   = "unsigned"
+	SignatureUnverified = "unverified" // Signed, but no key was given to verify it
+)
+
+// Manifest lists the files of a bundle with their SHA-256, so the receiver
+// can check that nothing was changed after the export
+type Manifest struct {
+	Session    string         `json:"session"`
+	ExportedAt time.Time      `json:"exported_at"`
+	Signature  string         `json:"signature"`
+	Files      []ManifestFile `json:"files"`
+	Missing    []string       `json:"missing,omitempty"` // Audio files of the session that no longer exist
+}
+
+// ManifestFile is a file of the bundle
+type ManifestFile struct {
+	Path   string `json:"path"`
+	Size   int    `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// Bundle holds the artifacts of a session to export
+type Bundle struct {
+	Record session.Record
+	Events []session.Event // Nil when the store keeps no events
+
+	// SigningKey verifies the signature of the session, the signature is
+	// reported as unverified when it is empty
+	SigningKey []byte
+}
+
+// Write writes the bundle as a zip archive: the record as JSON, the
+// transcript, the report as text and PDF, the event log, the audio files the
+// session was signed with and the manifest
+func Write(w io.Writer, bundle Bundle) (Manifest, error) {
+	record := bundle.Record
+	manifest := Manifest{
+		Session:    record.ID,
+		ExportedAt: time.Now().UTC(),
+		Signature:  signatureState(record, bundle.SigningKey),
+	}
+
+	archive := zip.NewWriter(w)
+	add := func(name string, data []byte) error {
+		file, err := archive.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: manifest.ExportedAt})
+		if err != nil {
+			return fmt.Errorf("failed to add %s: %w", name, err)
+		}
+		if _, err := file.Write(data); err != nil {
+			return fmt.Errorf("failed to add %s: %w", name, err)
+		}
+		sum := sha256.Sum256(data)
+		manifest.Files = append(manifest.Files, ManifestFile{Path: name, Size: len(data), SHA256: hex.EncodeToString(sum[:])})
+		return nil
+	}
+
+	recordJSON, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return manifest, fmt.Errorf("failed to encode session: %w", err)
+	}
+	var transcript, report, pdf, events bytes.Buffer
+	session.WriteTranscript(&transcript, record)
+	session.WriteReport(&report, record)
+	if err := writePDF(&pdf, report.String()); err != nil {
+		return manifest, fmt.Errorf("failed to render report: %w", err)
+	}
+	encoder := json.NewEncoder(&events)
+	for _, event := range bundle.Events {
+		if err := encoder.Encode(event); err != nil {
+			return manifest, fmt.Errorf("failed to encode events: %w", err)
+		}
+	}
+
+	files := map[string][]byte{
+		"session.json":   recordJSON,
+		"transcript.txt": transcript.Bytes(),
+		"report.txt":     report.Bytes(),
+		"report.pdf":     pdf.Bytes(),
+	}
+	if bundle.Events != nil {
+		files["events.jsonl"] = events.Bytes()
+	}
+	for _, name := range []string{"session.json", "transcript.txt", "report.txt", "report.pdf", "events.jsonl"} {
+		if data, ok := files[name]; ok {
+			if err := add(name, data); err != nil {
+				return manifest, err
+			}
+		}
+	}
+
+	if record.Signature != nil {
+		for _, file := range record.Signature.Files {
+			data, err := os.ReadFile(file.Path)
+			if errors.Is(err, os.ErrNotExist) {
+				manifest.Missing = append(manifest.Missing, file.Path)
+				continue
+			}
+			if err != nil {
+				return manifest, fmt.Errorf("failed to read audio: %w", err)
+			}
+			if err := add(path.Join("audio", filepath.Base(file.Path)), data); err != nil {
+				return manifest, err
+			}
+		}
+	}
+
+	manifestJSON, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return manifest, fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	file, err := archive.CreateHeader(&zip.FileHeader{Name: "manifest.json", Method: zip.Deflate, Modified: manifest.ExportedAt})
+	if err != nil {
+		return manifest, fmt.Errorf("failed to add manifest: %w", err)
+	}
+	if _, err := file.Write(manifestJSON); err != nil {
+		return manifest, fmt.Errorf("failed to add manifest: %w", err)
+	}
+	if err := archive.Close(); err != nil {
+		return manifest, fmt.Errorf("failed to write archive: %w", err)
+	}
+	return manifest, nil
+}
+
+// signatureState verifies the signature of the record with the key. A
+// signature that cannot be checked, e.g. because an audio file is gone, is
+// unverified
+func signatureState(record session.Record, key []byte) string {
+	if record.Signature == nil {
+		return SignatureUnsigned
+	}
+	if len(key) == 0 {
+		return SignatureUnverified
+	}
+	err := session.Verify(record, key)
+	switch {
+	case err == nil:
+		return SignatureValid
+	case errors.Is(err, session.ErrTampered):
+		return SignatureTampered
+	default:
+		return SignatureUnverified
+	}
+}
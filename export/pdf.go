@@ -0,0 +1,137 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// Page layout of the PDF report: A4 in points, Courier so the columns of the
+// text report stay aligned
+const (
+	pageWidth    = 595
+	pageHeight   = 842
+	pageMargin   = 40
+	fontSize     = 9
+	lineHeight   = 11
+	lineWidth    = (pageWidth - 2*pageMargin) * 10 / (fontSize * 6) // Courier glyphs are 0.6 em wide
+	linesPerPage = (pageHeight - 2*pageMargin) / lineHeight
+)
+
+// cyrillic transliterates the Cyrillic letters the standard PDF fonts cannot show
+var cyrillic = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo", 'ж': "zh", 'з': "z", 'и': "i",
+	'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t",
+	'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "",
+	'э': "e", 'ю': "yu", 'я': "ya",
+}
+
+// writePDF writes the plain text as a PDF document with the built-in Courier
+// font, wrapping long lines and breaking pages. The standard fonts only cover
+// Latin-1, so Cyrillic is transliterated and other characters become "?"
+func writePDF(w io.Writer, text string) error {
+	lines := wrapLines(text)
+	var pages [][]string
+	for len(lines) > linesPerPage {
+		pages = append(pages, lines[:linesPerPage])
+		lines = lines[linesPerPage:]
+	}
+	pages = append(pages, lines)
+
+	// Objects: 1 catalog, 2 page tree, 3 font, then a page and its content per page
+	var objects []string
+	objects = append(objects, "<< /Type /Catalog /Pages 2 0 R >>")
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 4+2*i)
+	}
+	objects = append(objects, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	objects = append(objects, "<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+	for i, page := range pages {
+		var content bytes.Buffer
+		// The ' operator moves to the next line before showing the text
+		fmt.Fprintf(&content, "BT /F1 %d Tf %d TL %d %d Td\n", fontSize, lineHeight, pageMargin, pageHeight-pageMargin)
+		for _, line := range page {
+			fmt.Fprintf(&content, "(%s) '\n", escapePDF(line))
+		}
+		content.WriteString("ET")
+
+		objects = append(objects, fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] "+
+			"/Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>", pageWidth, pageHeight, 5+2*i))
+		objects = append(objects, fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", content.Len(), content.String()))
+	}
+
+	var pdf bytes.Buffer
+	pdf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = pdf.Len()
+		fmt.Fprintf(&pdf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	xref := pdf.Len()
+	fmt.Fprintf(&pdf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&pdf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&pdf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	_, err := w.Write(pdf.Bytes())
+	return err
+}
+
+// wrapLines splits the text into lines of at most lineWidth characters
+func wrapLines(text string) []string {
+	var lines []string
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		runes := []rune(winAnsi(line))
+		for len(runes) > lineWidth {
+			lines = append(lines, string(runes[:lineWidth]))
+			runes = runes[lineWidth:]
+		}
+		lines = append(lines, string(runes))
+	}
+	return lines
+}
+
+// winAnsi returns the text with the characters the WinAnsi encoding cannot
+// represent transliterated or replaced
+func winAnsi(text string) string {
+	var result strings.Builder
+	for _, r := range text {
+		lower := unicode.ToLower(r)
+		switch latin, ok := cyrillic[lower]; {
+		case ok && lower != r && latin != "":
+			result.WriteString(strings.ToUpper(latin[:1]) + latin[1:])
+		case ok:
+			result.WriteString(latin)
+		case r == '\t':
+			result.WriteString("    ")
+		case r >= 0x20 && r < 0x7f || r >= 0xa0 && r <= 0xff:
+			result.WriteRune(r)
+		default:
+			result.WriteByte('?')
+		}
+	}
+	return result.String()
+}
+
+// escapePDF encodes the line as the bytes of a PDF string literal
+func escapePDF(line string) string {
+	var result strings.Builder
+	for _, r := range line {
+		switch r {
+		case '(', ')', '\\':
+			result.WriteByte('\\')
+			result.WriteRune(r)
+		default:
+			if r > 0x7e {
+				fmt.Fprintf(&result, "\\%03o", r)
+			} else {
+				result.WriteRune(r)
+			}
+		}
+	}
+	return result.String()
+}
@@ -0,0 +1,127 @@
+// Package feed streams the live transcript and the state of the engine to
+// external consumers, e.g. a captions overlay or a note-taking app, as
+// Server-Sent Events
+package feed
+
+import (
+	"sync"
+	"time"
+)
+
+// Kinds of feed events, sent as the SSE event name
+const (
+	KindPartial  = "partial"  // Hypothesis of the phrase the candidate is saying, replaced by the next one
+	KindFinal    = "final"    // Recognized phrase of the candidate
+	KindResponse = "response" // Phrase of the interviewer as it starts playing
+	KindState    = "state"    // The engine changed state
+)
+
+// States of the engine
+const (
+	StateListening = "listening"
+	StateThinking  = "thinking"
+	StateSpeaking  = "speaking"
+	StateEnded     = "ended"
+)
+
+// Speakers of transcript segments
+const (
+	SpeakerCandidate   = "candidate"
+	SpeakerInterviewer = "interviewer"
+)
+
+// Event is one update of a session
+type Event struct {
+	Kind    string    `json:"kind"`
+	Session string    `json:"session"`
+	Time    time.Time `json:"time"`
+	Speaker string    `json:"speaker,omitempty"`
+	Text    string    `json:"text,omitempty"`
+	State   string    `json:"state,omitempty"`
+}
+
+// Publisher receives the events of the sessions
+type Publisher interface {
+	Publish(event Event)
+}
+
+// subscriberBuffer is the number of events queued for a subscriber, a
+// subscriber that falls further behind misses events
+const subscriberBuffer = 64
+
+// subscriber receives the events of one session, or of every session when
+// the session is empty
+type subscriber struct {
+	session string
+	events  chan Event
+}
+
+// Hub fans the events out to the subscribers of the feed. Publishing never
+// blocks the engine, events a slow subscriber has no room for are dropped
+type Hub struct {
+	mutex       sync.Mutex
+	subscribers map[*subscriber]bool
+	states      map[string]Event // Last state event of every running session
+}
+
+// Ensure Hub implements Publisher interface
+var _ Publisher = (*Hub)(nil)
+
+// NewHub creates a hub without subscribers
+func NewHub() *Hub {
+	return &Hub{
+		subscribers: make(map[*subscriber]bool),
+		states:      make(map[string]Event),
+	}
+}
+
+// Publish sends the event to the subscribers of its session
+func (h *Hub) Publish(event Event) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if event.Kind == KindState {
+		if event.State == StateEnded {
+			delete(h.states, event.Session)
+		} else {
+			h.states[event.Session] = event
+		}
+	}
+	for s := range h.subscribers {
+		if s.session != "" && s.session != event.Session {
+			continue
+		}
+		select {
+		case s.events <- event:
+		default:
+		}
+	}
+}
+
+// subscribe registers a subscriber of the session, every session when it is
+// empty. The current state of the sessions is queued first, so a consumer
+// joining mid-session knows what the engine is doing
+func (h *Hub) subscribe(session string) *subscriber {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	s := &subscriber{session: session, events: make(chan Event, subscriberBuffer)}
+	for id, state := range h.states {
+		if session == "" || session == id {
+			select {
+			case s.events <- state:
+			default:
+			}
+		}
+	}
+	h.subscribers[s] = true
+	return s
+}
+
+// unsubscribe removes the subscriber
+func (h *Hub) unsubscribe(s *subscriber) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	delete(h.subscribers, s)
+}
@@ -0,0 +1,111 @@
+package feed
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// keepAliveInterval is how often an idle stream sends a comment, so proxies
+// do not close it
+const keepAliveInterval = 15 * time.Second
+
+// Handler serves the feed of every session at /events and the feed of one
+// session at /sessions/{id}/events. When token is set it must be sent as a
+// bearer token or, for browsers' EventSource, in the token query parameter
+func (h *Hub) Handler(token string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /events", func(w http.ResponseWriter, r *http.Request) {
+		h.stream(w, r, "")
+	})
+	mux.HandleFunc("GET /sessions/{id}/events", func(w http.ResponseWriter, r *http.Request) {
+		h.stream(w, r, r.PathValue("id"))
+	})
+	if token == "" {
+		return mux
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		given := r.URL.Query().Get("token")
+		if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+			given = bearer
+		}
+		if subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// stream writes the events of the session to the client until it disconnects
+func (h *Hub) stream(w http.ResponseWriter, r *http.Request, session string) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	s := h.subscribe(session)
+	defer h.unsubscribe(s)
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+
+	id := 0
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		case event := <-s.events:
+			data, err := json.Marshal(event)
+			if err != nil {
+				log.Printf("Failed to encode feed event: %v", err)
+				continue
+			}
+			id++
+			if _, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", id, event.Kind, data); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+// Serve runs the feed server on addr until ctx is done
+func Serve(ctx context.Context, addr string, handler http.Handler) error {
+	// Streams end with ctx, so the server shuts down without waiting for clients
+	server := &http.Server{
+		Addr:        addr,
+		Handler:     handler,
+		BaseContext: func(net.Listener) context.Context { return ctx },
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			server.Close()
+		}
+	}()
+
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("feed server failed: %w", err)
+	}
+	return nil
+}
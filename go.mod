@@ -6,11 +6,12 @@ require (
 	github.com/gordonklaus/portaudio v0.0.0-20250206071425-98a94950218b
 	github.com/joho/godotenv v1.5.1
 	github.com/yandex-cloud/go-genproto v0.5.0
+	go.uber.org/goleak v1.3.0
+	golang.org/x/net v0.35.0
 	google.golang.org/grpc v1.72.1
 )
 
 require (
-	golang.org/x/net v0.35.0 // indirect
 	golang.org/x/sys v0.30.0 // indirect
 	golang.org/x/text v0.22.0 // indirect
 	google.golang.org/genproto/googleapis/api v0.0.0-20250218202821-56aae31c358a // indirect
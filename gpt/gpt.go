@@ -1,7 +1,55 @@
 package gpt
 
+import (
+	"context"
+	"encoding/json"
+)
+
 // GPTClient defines the interface for GPT API clients
 type GPTClient interface {
 	// Complete sends a completion request and returns the response
 	Complete(systemMessage, userMessage string) (string, error)
 }
+
+// Usage is the number of tokens a completion consumed
+type Usage struct {
+	InputTokens      int `json:"input"`
+	CompletionTokens int `json:"completion"`
+	TotalTokens      int `json:"total"`
+}
+
+// Add returns the sum of both usages
+func (u Usage) Add(other Usage) Usage {
+	return Usage{
+		InputTokens:      u.InputTokens + other.InputTokens,
+		CompletionTokens: u.CompletionTokens + other.CompletionTokens,
+		TotalTokens:      u.TotalTokens + other.TotalTokens,
+	}
+}
+
+// UsageReporter is implemented by clients that report token usage
+type UsageReporter interface {
+	// CompleteWithUsage works like Complete and also returns the tokens consumed
+	CompleteWithUsage(systemMessage, userMessage string) (string, Usage, error)
+}
+
+// Warmer is implemented by clients that can connect and make a minimal
+// request before the first answer, so its response is not delayed by a cold start
+type Warmer interface {
+	// Warm connects to the service, it may send a small request
+	Warm(ctx context.Context) error
+}
+
+// ContextCompleter is implemented by clients that send the session ID of the
+// context with their requests, see the correlation package
+type ContextCompleter interface {
+	// CompleteContext works like CompleteWithUsage within the context
+	CompleteContext(ctx context.Context, systemMessage, userMessage string) (string, Usage, error)
+}
+
+// SchemaCompleter is implemented by clients whose model can be constrained to
+// reply with JSON matching a schema
+type SchemaCompleter interface {
+	// CompleteJSON works like Complete with a reply conforming to the JSON schema
+	CompleteJSON(systemMessage, userMessage string, schema json.RawMessage) (string, error)
+}
@@ -2,16 +2,55 @@ package gpt
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/d1nch8g/aihr/correlation"
 )
 
 const (
 	YandexGPTEndpoint = "https://llm.api.cloud.yandex.net/foundationModels/v1/completion"
 )
 
+// Settings of the API connections. Turns are usually less than a minute
+// apart, so idle connections are kept long enough to be reused by the next one
+const (
+	idleConnTimeout       = 5 * time.Minute
+	maxIdleConnsPerHost   = 4 // The response, the analysis and the evaluation of a turn may run at once
+	dialTimeout           = 10 * time.Second
+	tlsHandshakeTimeout   = 10 * time.Second
+	responseHeaderTimeout = 2 * time.Minute
+)
+
+// transport is shared by the clients, so the sessions of one process reuse
+// the connections as well
+var transport = newTransport()
+
+// newTransport returns a transport that keeps connections alive between
+// requests and negotiates HTTP/2, which multiplexes concurrent requests over
+// one connection
+func newTransport() *http.Transport {
+	dialer := &net.Dialer{Timeout: dialTimeout, KeepAlive: 30 * time.Second}
+	return &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          16,
+		MaxIdleConnsPerHost:   maxIdleConnsPerHost,
+		IdleConnTimeout:       idleConnTimeout,
+		TLSHandshakeTimeout:   tlsHandshakeTimeout,
+		ResponseHeaderTimeout: responseHeaderTimeout,
+		ExpectContinueTimeout: time.Second,
+	}
+}
+
 // Message represents a message in the conversation
 type Message struct {
 	Role string `json:"role"`
@@ -24,11 +63,17 @@ type CompletionOptions struct {
 	Temperature float64 `json:"temperature"`
 }
 
+// JSONSchema constrains the response to JSON conforming to the schema
+type JSONSchema struct {
+	Schema json.RawMessage `json:"schema"`
+}
+
 // Request represents the request to the Yandex GPT API
 type Request struct {
 	ModelURI          string            `json:"modelUri"`
 	CompletionOptions CompletionOptions `json:"completionOptions"`
 	Messages          []Message         `json:"messages"`
+	JSONSchema        *JSONSchema       `json:"jsonSchema,omitempty"`
 }
 
 // Alternative represents an alternative response
@@ -59,6 +104,8 @@ type YandexGPTClient struct {
 	IAMToken   string
 	HTTPClient *http.Client
 	ModelURI   string
+
+	tokenMutex sync.RWMutex
 }
 
 // NewYandexGPTClient creates a new Yandex GPT client
@@ -66,13 +113,36 @@ func NewYandexGPTClient(folderID, iamToken string) *YandexGPTClient {
 	return &YandexGPTClient{
 		FolderID:   folderID,
 		IAMToken:   iamToken,
-		HTTPClient: &http.Client{},
+		HTTPClient: &http.Client{Transport: transport},
 		ModelURI:   "gpt://" + folderID + "/yandexgpt/rc",
 	}
 }
 
+// Ensure YandexGPTClient implements UsageReporter interface
+var _ UsageReporter = (*YandexGPTClient)(nil)
+
+// Ensure YandexGPTClient implements ContextCompleter interface
+var _ ContextCompleter = (*YandexGPTClient)(nil)
+
+// Ensure YandexGPTClient implements Warmer interface
+var _ Warmer = (*YandexGPTClient)(nil)
+
+// Ensure YandexGPTClient implements SchemaCompleter interface
+var _ SchemaCompleter = (*YandexGPTClient)(nil)
+
 // Complete sends a completion request to the Yandex GPT API
 func (c *YandexGPTClient) Complete(systemMessage, userMessage string) (string, error) {
+	text, _, err := c.CompleteWithUsage(systemMessage, userMessage)
+	return text, err
+}
+
+// CompleteWithUsage sends a completion request and returns the response with its token usage
+func (c *YandexGPTClient) CompleteWithUsage(systemMessage, userMessage string) (string, Usage, error) {
+	return c.CompleteContext(context.Background(), systemMessage, userMessage)
+}
+
+// CompleteContext sends a completion request with the session ID of the context as the request ID
+func (c *YandexGPTClient) CompleteContext(ctx context.Context, systemMessage, userMessage string) (string, Usage, error) {
 	req := Request{
 		ModelURI: c.ModelURI,
 		CompletionOptions: CompletionOptions{
@@ -90,36 +160,99 @@ func (c *YandexGPTClient) Complete(systemMessage, userMessage string) (string, e
 			},
 		},
 	}
+	return c.send(ctx, req)
+}
 
+// CompleteJSON sends a completion request with structured output, the model
+// replies with JSON conforming to the schema. The low temperature keeps
+// repeated evaluations of the same answer consistent
+func (c *YandexGPTClient) CompleteJSON(systemMessage, userMessage string, schema json.RawMessage) (string, error) {
+	text, _, err := c.send(context.Background(), Request{
+		ModelURI: c.ModelURI,
+		CompletionOptions: CompletionOptions{
+			MaxTokens:   1024,
+			Temperature: 0.1,
+		},
+		Messages: []Message{
+			{Role: "system", Text: systemMessage},
+			{Role: "user", Text: userMessage},
+		},
+		JSONSchema: &JSONSchema{Schema: schema},
+	})
+	return text, err
+}
+
+// Warm sends a one token completion, which opens the connection and wakes the
+// model. The connection is kept alive for the next request
+func (c *YandexGPTClient) Warm(ctx context.Context) error {
+	_, _, err := c.send(ctx, Request{
+		ModelURI:          c.ModelURI,
+		CompletionOptions: CompletionOptions{MaxTokens: 1},
+		Messages:          []Message{{Role: "user", Text: "ping"}},
+	})
+	return err
+}
+
+// send sends a completion request and returns the response with its token usage
+func (c *YandexGPTClient) send(ctx context.Context, req Request) (string, Usage, error) {
 	reqBody, err := json.Marshal(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %w", err)
+		return "", Usage{}, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	httpReq, err := http.NewRequest("POST", YandexGPTEndpoint, bytes.NewBuffer(reqBody))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", YandexGPTEndpoint, bytes.NewBuffer(reqBody))
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return "", Usage{}, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	httpReq.Header.Set("Content-Type", "application/json")
+	c.tokenMutex.RLock()
 	httpReq.Header.Set("Authorization", "Bearer "+c.IAMToken)
+	c.tokenMutex.RUnlock()
 	httpReq.Header.Set("x-folder-id", c.FolderID)
+	if id := correlation.Session(ctx); id != "" {
+		httpReq.Header.Set(correlation.RequestIDHeader, id)
+	}
 
 	resp, err := c.HTTPClient.Do(httpReq)
 	if err != nil {
-		return "", fmt.Errorf("failed to send request: %w", err)
+		return "", Usage{}, fmt.Errorf("failed to send request: %w", err)
 	}
-	defer resp.Body.Close()
+	defer func() {
+		// The body is read to the end so the connection goes back to the pool
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return "", Usage{}, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
 	}
 
 	var response Response
 	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return "", fmt.Errorf("failed to decode response: %w", err)
+		return "", Usage{}, fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	return response.Result.Alternatives[0].Message.Text, nil
+	if len(response.Result.Alternatives) == 0 {
+		return "", Usage{}, fmt.Errorf("response has no alternatives")
+	}
+
+	// Token counts are sent as strings
+	usage := response.Result.Usage
+	input, _ := strconv.Atoi(usage.InputTextTokens)
+	completion, _ := strconv.Atoi(usage.CompletionTokens)
+	total, _ := strconv.Atoi(usage.TotalTokens)
+	return response.Result.Alternatives[0].Message.Text, Usage{
+		InputTokens:      input,
+		CompletionTokens: completion,
+		TotalTokens:      total,
+	}, nil
+}
+
+// SetIamToken replaces the IAM token used for subsequent requests
+func (c *YandexGPTClient) SetIamToken(iamToken string) {
+	c.tokenMutex.Lock()
+	defer c.tokenMutex.Unlock()
+	c.IAMToken = iamToken
 }
@@ -0,0 +1,129 @@
+// Package i18n holds the phrases the interviewer speaks on its own, outside
+// of the model responses, in the language of the interview
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Keys of the phrases
+const (
+	Greeting       = "greeting"
+	Farewell       = "farewell"
+	NextSteps      = "next_steps"      // Told in the closing message, a text/template
+	RepeatRequest  = "repeat_request"  // The answer was not recognized reliably
+	InputLost      = "input_lost"      // The microphone was disconnected
+	InputRestored  = "input_restored"  // The microphone delivers audio again
+	TurnFailed     = "turn_failed"     // Apology for an internal error
+	SafetyFallback = "safety_fallback" // Replaces a response blocked by the safety filter
+	Deferral       = "deferral"        // Answers a candidate question left to the recruiter
+	TimeRemaining  = "time_remaining"  // The time left, with the {minutes} slot
+	AbortConfirm   = "abort_confirm"   // Asks whether the candidate has to stop
+	AbortCanceled  = "abort_canceled"  // The candidate continues after all
+	AbortFarewell  = "abort_farewell"  // Ends an interview stopped early
+	Handoff        = "handoff"         // Passes the interview to the next persona, with {name} and {title}
+	Reactions      = "reactions"       // Short reactions to an answer, one per line
+)
+
+// DefaultLocale is used for languages without a locale and fills missing phrases
+const DefaultLocale = "en"
+
+//go:embed locales
+var locales embed.FS
+
+// english holds the phrases of the default locale
+var english = mustParse(DefaultLocale)
+
+// Messages are the phrases of one locale by key
+type Messages map[string]string
+
+// Get returns the phrase, the English one when the locale does not have it
+func (m Messages) Get(key string) string {
+	if text, ok := m[key]; ok {
+		return text
+	}
+	return english[key]
+}
+
+// Locale returns the locale of a language, e.g. "ru" for "ru-RU"
+func Locale(language string) string {
+	locale, _, _ := strings.Cut(strings.ToLower(language), "-")
+	return locale
+}
+
+// Load returns the phrases of the language. A <locale>.json file in dir,
+// when set, replaces the embedded phrases it has. Languages without a locale
+// are spoken in English
+func Load(language, dir string) (Messages, error) {
+	locale := Locale(language)
+	messages := make(Messages)
+	if embedded, err := parse(locales, "locales/"+locale+".json"); err == nil {
+		messages = embedded
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	if dir != "" {
+		custom, err := parse(os.DirFS(dir), locale+".json")
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		for key, text := range custom {
+			// Unknown keys are an error so typos do not go unnoticed
+			if _, ok := english[key]; !ok {
+				return nil, fmt.Errorf("unknown phrase %q in locale file %s", key, filepath.Join(dir, locale+".json"))
+			}
+			messages[key] = text
+		}
+	}
+	return messages, nil
+}
+
+// Locales returns the embedded locales and the ones in dir, sorted
+func Locales(dir string) []string {
+	matches, _ := fs.Glob(locales, "locales/*.json")
+	if dir != "" {
+		custom, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+		matches = append(matches, custom...)
+	}
+
+	seen := make(map[string]bool)
+	for _, match := range matches {
+		seen[strings.TrimSuffix(filepath.Base(match), ".json")] = true
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// parse reads a locale file
+func parse(fsys fs.FS, name string) (Messages, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	messages := make(Messages)
+	if err := json.Unmarshal(data, &messages); err != nil {
+		return nil, fmt.Errorf("failed to parse locale %s: %w", name, err)
+	}
+	return messages, nil
+}
+
+func mustParse(locale string) Messages {
+	messages, err := parse(locales, "locales/"+locale+".json")
+	if err != nil {
+		panic(err)
+	}
+	return messages
+}
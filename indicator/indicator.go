@@ -0,0 +1,177 @@
+// Package indicator shows the state of the engine on kiosk hardware, a lamp
+// driven by GPIO pins or a controller on a serial port, so candidates know
+// when to talk without a screen
+package indicator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/d1nch8g/aihr/feed"
+)
+
+// Colors of the lamp
+const (
+	ColorGreen  = "green"  // The candidate can talk
+	ColorBlue   = "blue"   // The interviewer speaks
+	ColorYellow = "yellow" // The interviewer thinks
+	ColorOff    = "off"    // The session ended
+)
+
+// colors are the colors of the lamp with a GPIO pin each, in the order of the spec
+var colors = []string{ColorGreen, ColorBlue, ColorYellow}
+
+// ColorOf returns the color showing a feed state
+func ColorOf(state string) string {
+	switch state {
+	case feed.StateListening:
+		return ColorGreen
+	case feed.StateSpeaking:
+		return ColorBlue
+	case feed.StateThinking:
+		return ColorYellow
+	default:
+		return ColorOff
+	}
+}
+
+// Indicator shows the state of the engine
+type Indicator interface {
+	// Show lights the color of a feed state
+	Show(state string) error
+
+	// Close turns the lamp off and releases the device
+	Close() error
+}
+
+// Open opens the indicator of a spec: "gpio:GREEN,BLUE,YELLOW" with the
+// sysfs numbers of the pins lighting each color, or "serial:PATH" with the
+// port of a controller that reads the color names line by line
+func Open(spec string) (Indicator, error) {
+	kind, target, ok := strings.Cut(spec, ":")
+	if !ok || target == "" {
+		return nil, fmt.Errorf("invalid indicator %q, use gpio:GREEN,BLUE,YELLOW or serial:PATH", spec)
+	}
+	switch kind {
+	case "gpio":
+		return OpenGPIO(target)
+	case "serial":
+		return OpenSerial(target)
+	default:
+		return nil, fmt.Errorf("unknown indicator %q, use gpio or serial", kind)
+	}
+}
+
+// Serial writes the color of every state as a line, e.g. "blue\n", to a
+// serial port. The port speed is set by the system, e.g. with stty
+type Serial struct {
+	file  *os.File
+	mutex sync.Mutex
+}
+
+// Ensure Serial implements Indicator interface
+var _ Indicator = (*Serial)(nil)
+
+// OpenSerial opens the serial port at path
+func OpenSerial(path string) (*Serial, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open indicator port: %w", err)
+	}
+	return &Serial{file: file}, nil
+}
+
+// Show writes the color of the state
+func (s *Serial) Show(state string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, err := fmt.Fprintln(s.file, ColorOf(state)); err != nil {
+		return fmt.Errorf("failed to write indicator: %w", err)
+	}
+	return nil
+}
+
+// Close turns the lamp off and closes the port
+func (s *Serial) Close() error {
+	err := s.Show(feed.StateEnded)
+	if closeErr := s.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// gpioRoot is the sysfs directory of the GPIO pins
+const gpioRoot = "/sys/class/gpio"
+
+// GPIO lights one sysfs GPIO pin per color
+type GPIO struct {
+	pins  map[string]int // Pin of every color
+	mutex sync.Mutex
+}
+
+// Ensure GPIO implements Indicator interface
+var _ Indicator = (*GPIO)(nil)
+
+// OpenGPIO exports the pins of the green, blue and yellow lights, given as
+// comma-separated numbers, as outputs
+func OpenGPIO(spec string) (*GPIO, error) {
+	fields := strings.Split(spec, ",")
+	if len(fields) != len(colors) {
+		return nil, fmt.Errorf("invalid indicator pins %q, use GREEN,BLUE,YELLOW", spec)
+	}
+	g := &GPIO{pins: make(map[string]int, len(colors))}
+	for i, field := range fields {
+		pin, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil || pin < 0 {
+			return nil, fmt.Errorf("invalid indicator pin %q", field)
+		}
+		if err := exportPin(pin); err != nil {
+			return nil, err
+		}
+		g.pins[colors[i]] = pin
+	}
+	return g, g.Show(feed.StateEnded)
+}
+
+// exportPin makes the pin available in sysfs, unless it is already, and sets it to output
+func exportPin(pin int) error {
+	dir := filepath.Join(gpioRoot, "gpio"+strconv.Itoa(pin))
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		if err := os.WriteFile(filepath.Join(gpioRoot, "export"), []byte(strconv.Itoa(pin)), 0); err != nil {
+			return fmt.Errorf("failed to export GPIO pin %d: %w", pin, err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "direction"), []byte("out"), 0); err != nil {
+		return fmt.Errorf("failed to set GPIO pin %d to output: %w", pin, err)
+	}
+	return nil
+}
+
+// Show lights the pin of the color of the state and turns the others off
+func (g *GPIO) Show(state string) error {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	color := ColorOf(state)
+	for _, c := range colors {
+		value := "0"
+		if c == color {
+			value = "1"
+		}
+		path := filepath.Join(gpioRoot, "gpio"+strconv.Itoa(g.pins[c]), "value")
+		if err := os.WriteFile(path, []byte(value), 0); err != nil {
+			return fmt.Errorf("failed to set GPIO pin %d: %w", g.pins[c], err)
+		}
+	}
+	return nil
+}
+
+// Close turns the lamp off, the pins stay exported
+func (g *GPIO) Close() error {
+	return g.Show(feed.StateEnded)
+}
@@ -0,0 +1,55 @@
+// Package mail delivers interview reports by email
+package mail
+
+import "fmt"
+
+// Attachment is a file sent along with a message
+type Attachment struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// Message is a plain text email with optional attachments
+type Message struct {
+	From        string
+	To          []string
+	Subject     string
+	Body        string
+	Attachments []Attachment
+}
+
+// Sender delivers email messages
+type Sender interface {
+	Send(message Message) error
+}
+
+// Config selects and configures the email provider
+type Config struct {
+	Provider string // "smtp" or "sendgrid"
+
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+
+	SendGridAPIKey string
+}
+
+// NewSender creates the sender for the configured provider
+func NewSender(config Config) (Sender, error) {
+	switch config.Provider {
+	case "smtp":
+		if config.SMTPHost == "" {
+			return nil, fmt.Errorf("SMTP host is required")
+		}
+		return NewSMTPSender(config.SMTPHost, config.SMTPPort, config.SMTPUsername, config.SMTPPassword), nil
+	case "sendgrid":
+		if config.SendGridAPIKey == "" {
+			return nil, fmt.Errorf("SendGrid API key is required")
+		}
+		return NewSendGridSender(config.SendGridAPIKey), nil
+	default:
+		return nil, fmt.Errorf("unknown mail provider %q", config.Provider)
+	}
+}
@@ -0,0 +1,106 @@
+package mail
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+const (
+	SendGridEndpoint = "https://api.sendgrid.com/v3/mail/send"
+)
+
+// sendGridRequest is the body of the SendGrid mail send API
+type sendGridRequest struct {
+	Personalizations []sendGridPersonalization `json:"personalizations"`
+	From             sendGridAddress           `json:"from"`
+	Subject          string                    `json:"subject"`
+	Content          []sendGridContent         `json:"content"`
+	Attachments      []sendGridAttachment      `json:"attachments,omitempty"`
+}
+
+type sendGridPersonalization struct {
+	To []sendGridAddress `json:"to"`
+}
+
+type sendGridAddress struct {
+	Email string `json:"email"`
+}
+
+type sendGridContent struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type sendGridAttachment struct {
+	Content     string `json:"content"`
+	Type        string `json:"type"`
+	Filename    string `json:"filename"`
+	Disposition string `json:"disposition"`
+}
+
+// SendGridSender sends messages through the SendGrid API
+type SendGridSender struct {
+	APIKey     string
+	HTTPClient *http.Client
+}
+
+// Ensure SendGridSender implements Sender interface
+var _ Sender = (*SendGridSender)(nil)
+
+// NewSendGridSender creates a SendGrid client
+func NewSendGridSender(apiKey string) *SendGridSender {
+	return &SendGridSender{
+		APIKey:     apiKey,
+		HTTPClient: &http.Client{},
+	}
+}
+
+// Send delivers the message to all recipients
+func (s *SendGridSender) Send(message Message) error {
+	request := sendGridRequest{
+		From:    sendGridAddress{Email: message.From},
+		Subject: message.Subject,
+		Content: []sendGridContent{{Type: "text/plain", Value: message.Body}},
+	}
+	var to []sendGridAddress
+	for _, address := range message.To {
+		to = append(to, sendGridAddress{Email: address})
+	}
+	request.Personalizations = []sendGridPersonalization{{To: to}}
+	for _, attachment := range message.Attachments {
+		request.Attachments = append(request.Attachments, sendGridAttachment{
+			Content:     base64.StdEncoding.EncodeToString(attachment.Data),
+			Type:        attachment.ContentType,
+			Filename:    attachment.Name,
+			Disposition: "attachment",
+		})
+	}
+
+	reqBody, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequest("POST", SendGridEndpoint, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+s.APIKey)
+
+	resp, err := s.HTTPClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
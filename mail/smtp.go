@@ -0,0 +1,110 @@
+package mail
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SMTPSender sends messages through an SMTP server. STARTTLS is used when the
+// server offers it, credentials are only sent over an encrypted connection
+type SMTPSender struct {
+	addr string
+	auth smtp.Auth
+}
+
+// Ensure SMTPSender implements Sender interface
+var _ Sender = (*SMTPSender)(nil)
+
+// NewSMTPSender creates a sender for the server at host:port. Authentication
+// is skipped when username is empty
+func NewSMTPSender(host string, port int, username, password string) *SMTPSender {
+	if port == 0 {
+		port = 587
+	}
+	sender := &SMTPSender{addr: net.JoinHostPort(host, strconv.Itoa(port))}
+	if username != "" {
+		sender.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return sender
+}
+
+// Send delivers the message to all recipients
+func (s *SMTPSender) Send(message Message) error {
+	data, err := encodeMIME(message)
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %w", err)
+	}
+	if err := smtp.SendMail(s.addr, s.auth, message.From, message.To, data); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+// encodeMIME builds a multipart message with the body and the attachments
+func encodeMIME(message Message) ([]byte, error) {
+	var buffer bytes.Buffer
+	writer := multipart.NewWriter(&buffer)
+
+	fmt.Fprintf(&buffer, "From: %s\r\n", message.From)
+	fmt.Fprintf(&buffer, "To: %s\r\n", strings.Join(message.To, ", "))
+	fmt.Fprintf(&buffer, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", message.Subject))
+	fmt.Fprintf(&buffer, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&buffer, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buffer, "Content-Type: multipart/mixed; boundary=%s\r\n\r\n", writer.Boundary())
+
+	body, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/plain; charset=utf-8"},
+		"Content-Transfer-Encoding": {"base64"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := writeBase64(body, []byte(message.Body)); err != nil {
+		return nil, err
+	}
+
+	for _, attachment := range message.Attachments {
+		part, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {attachment.ContentType},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Name})},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeBase64(part, attachment.Data); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// writeBase64 writes data base64 encoded in lines of 76 characters
+func writeBase64(w io.Writer, data []byte) error {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 0 {
+		line := encoded
+		if len(line) > 76 {
+			line = line[:76]
+		}
+		encoded = encoded[len(line):]
+		if _, err := w.Write([]byte(line + "\r\n")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
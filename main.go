@@ -2,296 +2,395 @@
 package main
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"os"
 	"os/signal"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
-	"github.com/d1nch8g/aihr/audio"
+	"github.com/d1nch8g/aihr/aihr"
+	"github.com/d1nch8g/aihr/ats"
+	"github.com/d1nch8g/aihr/audit"
 	"github.com/d1nch8g/aihr/config"
-	"github.com/d1nch8g/aihr/gpt"
-	"github.com/d1nch8g/aihr/sound"
-	"github.com/d1nch8g/aihr/stt"
-	"github.com/d1nch8g/aihr/tts"
+	"github.com/d1nch8g/aihr/correlation"
+	"github.com/d1nch8g/aihr/engine"
+	"github.com/d1nch8g/aihr/feed"
+	"github.com/d1nch8g/aihr/indicator"
+	"github.com/d1nch8g/aihr/mail"
+	"github.com/d1nch8g/aihr/secrets"
+	"github.com/d1nch8g/aihr/session"
+	"github.com/d1nch8g/aihr/upload"
 )
 
 func main() {
+	profile := flag.String("profile", "", "configuration profile to use, overrides "+config.ProfileEnv)
+	headless := flag.Bool("headless", false, "run without local audio devices, overrides HEADLESS")
+	flag.Parse()
+
+	if *profile != "" {
+		os.Setenv(config.ProfileEnv, *profile)
+	}
+	if *headless {
+		os.Setenv("HEADLESS", "true")
+	}
+
+	if args := flag.Args(); len(args) > 0 {
+		os.Exit(runCommand(args))
+	}
+
+	runInterview()
+}
+
+// runInterview conducts an interview until it is stopped by a signal
+func runInterview(opts ...aihr.Option) {
 	// Load configuration
 	cfg, err := config.LoadConfig()
 	if err != nil {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
-	fmt.Printf("Starting AI-HR interview system (Language: %s). Press Ctrl-C to stop.\n", cfg.Audio.Language)
+	if cfg.Profile != "" {
+		fmt.Fprintf(cfg.Console(), "Using configuration profile: %s\n", cfg.Profile)
+	}
+	limitResources(cfg.Resources)
+	fmt.Fprintf(cfg.Console(), "Starting AI-HR interview system (Language: %s). Press Ctrl-C to stop.\n", cfg.Audio.Language)
 
-	// Setup signal handling
+	// Setup signal handling, SIGHUP reloads the configuration
 	sig := make(chan os.Signal, 1)
-	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	// Initialize audio streamer for recording
-	audioConfig := audio.PortaudioConfig{
-		SampleRate:      cfg.Audio.SampleRate,
-		FramesPerBuffer: cfg.Audio.FramesPerBuffer,
-		InputChannels:   cfg.Audio.InputChannels,
-		OutputChannels:  cfg.Audio.OutputChannels,
+	// The session ID is generated here rather than by the engine so every log
+	// line of the process carries it
+	sessionID := session.NewID()
+	ctx = correlation.WithSession(ctx, sessionID)
+	log.SetPrefix("session " + sessionID + " ")
+	log.SetFlags(log.Flags() | log.Lmsgprefix)
+
+	if cfg.FeedAddr != "" {
+		hub := feed.NewHub()
+		go func() {
+			if err := feed.Serve(ctx, cfg.FeedAddr, hub.Handler(cfg.FeedToken)); err != nil {
+				log.Printf("Live feed stopped: %v", err)
+			}
+		}()
+		opts = append(opts, aihr.WithEngineOptions(engine.WithFeed(hub)))
+		log.Printf("Streaming the live feed on %s", cfg.FeedAddr)
 	}
 
-	audioStreamer := audio.NewPortaudioStreamer(audioConfig)
-	if err := audioStreamer.Initialize(); err != nil {
-		log.Fatalf("Failed to initialize PortAudio for recording: %v", err)
+	if cfg.Indicator != "" {
+		lamp, err := indicator.Open(cfg.Indicator)
+		if err != nil {
+			log.Fatalf("Failed to open indicator: %v", err)
+		}
+		defer func() {
+			if err := lamp.Close(); err != nil {
+				log.Printf("Failed to close indicator: %v", err)
+			}
+		}()
+		opts = append(opts, aihr.WithEngineOptions(engine.WithIndicator(lamp)))
 	}
-	defer audioStreamer.Terminate()
 
-	if err := audioStreamer.Open(); err != nil {
-		log.Fatalf("Failed to open audio stream for recording: %v", err)
+	interview, err := aihr.New(append([]aihr.Option{
+		aihr.WithConfig(cfg),
+	}, opts...)...)
+	if err != nil {
+		log.Fatalf("Failed to initialize interview: %v", err)
 	}
-	defer audioStreamer.Close()
+	defer func() {
+		if err := interview.Stop(); err != nil {
+			log.Printf("Failed to stop engine: %v", err)
+		}
+	}()
+	defer finishInterview(cfg, interview)
 
-	// Initialize audio player for TTS playback
-	playerConfig := sound.PlayerConfig{
-		SampleRate:      22050.0,
-		FramesPerBuffer: 2048,
-		InputChannels:   0,
-		OutputChannels:  1,
+	// Keep credentials from the secrets provider fresh for long sessions
+	if cfg.Secrets.Provider != "" {
+		provider, err := secrets.NewProviderFromEnv()
+		if err != nil {
+			log.Fatalf("Failed to create secrets provider: %v", err)
+		}
+		go secrets.Refresh(ctx, provider, cfg.Secrets.RefreshInterval, func(values map[string]string) {
+			if token, ok := values["IAM_TOKEN"]; ok {
+				interview.SetIamToken(token)
+				log.Println("IAM token refreshed from secrets provider")
+			}
+		})
 	}
 
-	player := sound.NewPortaudioPlayer(playerConfig)
-	if err := player.Initialize(); err != nil {
-		log.Fatalf("Failed to initialize PortAudio for playback: %v", err)
+	if cfg.RecruiterPipe != "" {
+		go readRecruiterPipe(ctx, cfg.RecruiterPipe, interview)
 	}
-	defer player.Terminate()
 
-	if err := player.Open(); err != nil {
-		log.Fatalf("Failed to open audio stream for playback: %v", err)
+	engineDone := make(chan error, 1)
+	go func() {
+		engineDone <- interview.Start(ctx)
+	}()
+
+	// Main loop - handle signals. The first interrupt lets the interviewer say
+	// goodbye when closing is enabled, the second one stops immediately
+	closing := false
+	for {
+		select {
+		case s := <-sig:
+			if s == syscall.SIGHUP {
+				reloadConfig(interview)
+				continue
+			}
+			if cfg.Engine.Closing && !closing && interview.RequestClosing() {
+				closing = true
+				fmt.Fprintln(cfg.Console(), "\nClosing the interview, press Ctrl-C again to stop immediately...")
+				continue
+			}
+			fmt.Fprintln(cfg.Console(), "\nStopping AI-HR interview system...")
+			cancel()
+			<-engineDone
+			return
+		case err := <-engineDone:
+			if err != nil && err != context.Canceled {
+				log.Printf("Engine stopped: %v", err)
+			}
+			return
+		}
 	}
-	defer player.Close()
+}
 
-	// Initialize STT client
-	sttConfig := stt.YandexConfig{
-		IamToken:   cfg.IamToken,
-		FolderID:   cfg.FolderID,
-		Language:   cfg.Audio.Language,
-		SampleRate: int32(cfg.Audio.SampleRate),
+// limitResources applies the configured CPU and memory limits to the Go runtime
+func limitResources(resources config.ResourcesConfig) {
+	if resources.MaxProcs > 0 {
+		runtime.GOMAXPROCS(resources.MaxProcs)
+	}
+	if resources.MemoryLimit > 0 {
+		debug.SetMemoryLimit(resources.MemoryLimit)
 	}
+}
 
-	sttClient, err := stt.NewYandexSTTClient(sttConfig)
-	if err != nil {
-		log.Fatalf("Failed to create STT client: %v", err)
+// readRecruiterPipe passes every line written to the named pipe at path to the
+// interviewer as a hidden instruction. The pipe is reopened after each writer
+// closes it, so a recruiter can send instructions with echo or keep cat open
+func readRecruiterPipe(ctx context.Context, path string, interview *aihr.Interview) {
+	info, err := os.Stat(path)
+	if err != nil || info.Mode()&os.ModeNamedPipe == 0 {
+		log.Printf("Recruiter pipe %s is not a named pipe, create it with mkfifo", path)
+		return
 	}
-	defer sttClient.Close()
 
-	// Initialize TTS client
-	ttsConfig := tts.YandexConfig{
-		IamToken: cfg.IamToken,
-		FolderID: cfg.FolderID,
+	for ctx.Err() == nil {
+		// Opening blocks until a writer connects
+		pipe, err := os.Open(path)
+		if err != nil {
+			log.Printf("Failed to open recruiter pipe: %v", err)
+			return
+		}
+		scanner := bufio.NewScanner(pipe)
+		for scanner.Scan() {
+			text := strings.TrimSpace(scanner.Text())
+			if text == "" {
+				continue
+			}
+			if err := interview.Instruct(text, false); err != nil {
+				log.Printf("Failed to apply recruiter instruction: %v", err)
+			}
+		}
+		pipe.Close()
 	}
+}
 
-	ttsClient, err := tts.NewYandexTTSClient(ttsConfig)
+// reloadConfig re-reads the configuration and applies runtime settings to the engine
+func reloadConfig(interview *aihr.Interview) {
+	cfg, err := config.ReloadConfig()
 	if err != nil {
-		log.Fatalf("Failed to create TTS client: %v", err)
+		log.Printf("Failed to reload config: %v", err)
+		return
 	}
-	defer ttsClient.Close()
 
-	// Initialize GPT client
-	gptClient := gpt.NewYandexGPTClient(cfg.FolderID, cfg.IamToken)
+	if err := interview.ApplyConfig(cfg); err != nil {
+		log.Printf("Failed to reload config: %v", err)
+		return
+	}
+	recordAudit(cfg.Storage.AuditLog, audit.ActionConfigReload, "", "SIGHUP")
+}
 
-	// Create channels for communication
-	audioData := make(chan []byte, 10)
-	sttResults := make(chan string, 10)
-	gptResponses := make(chan string, 10)
+// finishInterview saves the session, writes its report when a report file is
+// configured and delivers it by email and to the ATS when they are configured
+func finishInterview(cfg *config.Config, interview *aihr.Interview) {
+	record, err := interview.Finish()
+	if err != nil {
+		log.Printf("Failed to finish interview: %v", err)
+	} else if cfg.Storage.SessionDir != "" {
+		recordAudit(cfg.Storage.AuditLog, audit.ActionSessionCreate, record.ID, "")
+	}
+	deliverSession(cfg, record)
+}
 
-	// Start STT recognition
-	go func() {
-		if err := sttClient.StreamRecognize(ctx, audioData, sttResults, int64(cfg.Audio.SampleRate)); err != nil {
-			log.Printf("STT error: %v", err)
+// deliverSession sends the report of a saved session by email, to the ATS
+// and to object storage when they are configured and writes it to the report file
+func deliverSession(cfg *config.Config, record session.Record) {
+	if cfg.Mail.Provider != "" {
+		if err := mailReport(cfg, record); err != nil {
+			log.Printf("Failed to email report: %v", err)
+		} else {
+			recordAudit(cfg.Storage.AuditLog, audit.ActionReportMail, record.ID, strings.Join(cfg.Mail.To, ", "))
+			fmt.Fprintf(cfg.Console(), "Interview report emailed to %s\n", strings.Join(cfg.Mail.To, ", "))
 		}
-	}()
+	}
 
-	// Start audio capture
-	go func() {
-		defer close(audioData)
-		if err := audioStreamer.StartCapture(ctx, audioData); err != nil && err != context.Canceled {
-			log.Printf("Audio capture error: %v", err)
+	if cfg.ATS.Provider != "" {
+		if err := pushToATS(cfg, record); err != nil {
+			log.Printf("Failed to push results to %s: %v", cfg.ATS.Provider, err)
+		} else {
+			recordAudit(cfg.Storage.AuditLog, audit.ActionATSPush, record.ID, cfg.ATS.Provider)
+			fmt.Fprintf(cfg.Console(), "Interview results pushed to %s\n", cfg.ATS.Provider)
 		}
-	}()
+	}
 
-	// Process STT results with GPT
-	go func() {
-		defer close(gptResponses)
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case result, ok := <-sttResults:
-				if !ok {
-					return
-				}
-
-				fmt.Printf("User: %s\n", result)
-
-				reply, err := gptClient.Complete("Ты HR проводящий собеседование на go разработчика", result)
-				if err != nil {
-					log.Printf("GPT error: %v", err)
-					continue
-				}
-
-				fmt.Printf("GPT: %s\n", reply)
-
-				select {
-				case gptResponses <- reply:
-				case <-ctx.Done():
-					return
-				}
+	uploaded := false
+	if cfg.Upload.Bucket != "" {
+		artifacts, err := uploadSession(cfg, record)
+		if err != nil {
+			log.Printf("Failed to upload session: %v", err)
+		} else {
+			uploaded = true
+			recordAudit(cfg.Storage.AuditLog, audit.ActionSessionUpload, record.ID, cfg.Upload.Bucket)
+			for _, artifact := range artifacts {
+				fmt.Fprintf(cfg.Console(), "Uploaded %s to %s\n", artifact.Name, artifact.URL)
 			}
 		}
-	}()
+	}
 
-	// Process GPT responses with TTS and play them
-	go func() {
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case response, ok := <-gptResponses:
-				if !ok {
-					return
-				}
-
-				// Play the GPT response using TTS
-				if err := playTTSResponse(ctx, ttsClient, player, response, playerConfig); err != nil {
-					log.Printf("TTS playback error: %v", err)
-				}
-			}
+	// Uploaded sessions are not kept on the machine when local deletion is enabled
+	if uploaded && cfg.Upload.DeleteLocal && cfg.Storage.SessionDir != "" {
+		if err := deleteSession(cfg.Storage.SessionDir, record.ID); err != nil {
+			log.Printf("Failed to delete local session: %v", err)
+		} else {
+			recordAudit(cfg.Storage.AuditLog, audit.ActionSessionDelete, record.ID, "uploaded")
 		}
-	}()
-
-	// Play welcome message
-	welcomeMsg := "Hello! Welcome to the AI-HR interview system. I will be conducting your interview today. Please introduce yourself and tell me about your experience with Go development."
-	fmt.Printf("AI-HR: %s\n", welcomeMsg)
-	if err := playTTSResponse(ctx, ttsClient, player, welcomeMsg, playerConfig); err != nil {
-		log.Printf("Welcome message TTS error: %v", err)
 	}
 
-	// Main loop - handle signals
-	for {
-		select {
-		case <-sig:
-			fmt.Println("\nStopping AI-HR interview system...")
-			cancel()
-			// Give some time for graceful shutdown
-			time.Sleep(1 * time.Second)
-			return
-		case <-ctx.Done():
-			return
-		case <-time.After(100 * time.Millisecond):
-			// Keep the main loop alive
-		}
+	path := strings.ReplaceAll(cfg.Report.Path, "{session}", record.ID)
+	if path == "" {
+		return
+	}
+	if uploaded && cfg.Upload.DeleteLocal && path != "-" {
+		return
 	}
-}
 
-// playTTSResponse synthesizes text to speech and plays it back
-func playTTSResponse(ctx context.Context, ttsClient *tts.YandexTTSClient, player *sound.PortaudioPlayer, text string, playerConfig sound.PlayerConfig) error {
-	// Get default synthesis options
-	options := tts.GetDefaultSynthesisOptions()
-	options.Voice = "marina"
-	options.Speed = 1.0
-	options.Volume = 0.0
+	if path == "-" {
+		session.WriteReport(os.Stdout, record)
+		return
+	}
 
-	// Create context with timeout for TTS
-	ttsCtx, ttsCancel := context.WithTimeout(ctx, 30*time.Second)
-	defer ttsCancel()
+	file, err := os.Create(path)
+	if err != nil {
+		log.Printf("Failed to write report: %v", err)
+		return
+	}
+	defer file.Close()
 
-	// Create context for playback control
-	playCtx, playCancel := context.WithCancel(ctx)
-	defer playCancel()
+	session.WriteReport(file, record)
+	fmt.Fprintf(cfg.Console(), "Interview report written to %s\n", path)
+}
 
-	// Create channels for audio data flow
-	ttsAudioData := make(chan []byte, 100)
-	playbackAudioData := make(chan []byte, 10)
+// deleteSession removes a stored session record, missing records are ignored
+func deleteSession(dir, id string) error {
+	store, err := session.NewFileStore(dir)
+	if err != nil {
+		return err
+	}
+	if err := store.Delete(id); err != nil && !errors.Is(err, session.ErrNotFound) {
+		return err
+	}
+	return nil
+}
 
-	// Start TTS synthesis
-	synthesisComplete := make(chan error, 1)
-	go func() {
-		synthesisComplete <- ttsClient.SynthesizeToStreamWithContext(ttsCtx, text, options, ttsAudioData)
-	}()
+// uploadSession uploads the session record, report and transcript to object storage
+func uploadSession(cfg *config.Config, record session.Record) ([]upload.Artifact, error) {
+	uploader, err := upload.NewS3Uploader(upload.S3Config{
+		Endpoint:        cfg.Upload.Endpoint,
+		Region:          cfg.Upload.Region,
+		Bucket:          cfg.Upload.Bucket,
+		AccessKeyID:     cfg.Upload.AccessKeyID,
+		SecretAccessKey: cfg.Upload.SecretAccessKey,
+		Encryption:      cfg.Upload.Encryption,
+		KMSKeyID:        cfg.Upload.KMSKeyID,
+		Retries:         cfg.Upload.Retries,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return upload.UploadSession(context.Background(), uploader, cfg.Upload.Prefix, record)
+}
 
-	// Start audio playback
-	playbackComplete := make(chan error, 1)
-	go func() {
-		playbackComplete <- player.PlayStream(playCtx, playbackAudioData)
-	}()
+// mailReport emails the report and the transcript of the session. The subject
+// and body templates can use the template variables and the session details
+func mailReport(cfg *config.Config, record session.Record) error {
+	sender, err := mail.NewSender(mail.Config{
+		Provider:       cfg.Mail.Provider,
+		SMTPHost:       cfg.Mail.SMTPHost,
+		SMTPPort:       cfg.Mail.SMTPPort,
+		SMTPUsername:   cfg.Mail.SMTPUsername,
+		SMTPPassword:   cfg.Mail.SMTPPassword,
+		SendGridAPIKey: cfg.Mail.SendGridAPIKey,
+	})
+	if err != nil {
+		return err
+	}
 
-	// Process and stream audio data from TTS to playback
-	go func() {
-		defer close(playbackAudioData)
-
-		var audioBuffer []byte
-		chunkSize := playerConfig.FramesPerBuffer * 2 * playerConfig.OutputChannels
-
-		for {
-			select {
-			case chunk, ok := <-ttsAudioData:
-				if !ok {
-					// TTS finished, flush remaining buffer
-					if len(audioBuffer) > 0 {
-						if len(audioBuffer) < chunkSize {
-							padded := make([]byte, chunkSize)
-							copy(padded, audioBuffer)
-							audioBuffer = padded
-						}
-
-						select {
-						case playbackAudioData <- audioBuffer:
-						case <-playCtx.Done():
-							return
-						}
-					}
-					return
-				}
-
-				// Add chunk to buffer
-				audioBuffer = append(audioBuffer, chunk...)
-
-				// Send complete chunks to playback
-				for len(audioBuffer) >= chunkSize {
-					select {
-					case playbackAudioData <- audioBuffer[:chunkSize]:
-						audioBuffer = audioBuffer[chunkSize:]
-					case <-playCtx.Done():
-						return
-					}
-				}
-
-			case <-playCtx.Done():
-				return
-			}
-		}
-	}()
+	vars := aihr.TemplateVars(cfg)
+	vars["session"] = record.ID
+	vars["started"] = record.StartedAt.Format(time.RFC1123)
+	vars["answers"] = strconv.Itoa(len(record.Answers))
 
-	// Wait for synthesis to complete
-	select {
-	case err := <-synthesisComplete:
-		if err != nil && err != context.Canceled {
-			return fmt.Errorf("synthesis error: %v", err)
-		}
-	case <-ttsCtx.Done():
-		return fmt.Errorf("synthesis timed out or cancelled")
+	subject, err := aihr.RenderTemplate(cfg.Mail.Subject, vars)
+	if err != nil {
+		return fmt.Errorf("invalid MAIL_SUBJECT: %w", err)
+	}
+	body, err := aihr.RenderTemplate(cfg.Mail.Body, vars)
+	if err != nil {
+		return fmt.Errorf("invalid MAIL_BODY: %w", err)
 	}
 
-	// Wait for playback to complete
-	select {
-	case err := <-playbackComplete:
-		if err != nil && err != context.Canceled {
-			return fmt.Errorf("playback error: %v", err)
-		}
-	case <-playCtx.Done():
-		// Context cancelled
+	var report, transcript bytes.Buffer
+	session.WriteReport(&report, record)
+	session.WriteTranscript(&transcript, record)
+
+	return sender.Send(mail.Message{
+		From:    cfg.Mail.From,
+		To:      cfg.Mail.To,
+		Subject: subject,
+		Body:    body,
+		Attachments: []mail.Attachment{
+			{Name: "report-" + record.ID + ".txt", ContentType: "text/plain; charset=utf-8", Data: report.Bytes()},
+			{Name: "transcript-" + record.ID + ".txt", ContentType: "text/plain; charset=utf-8", Data: transcript.Bytes()},
+		},
+	})
+}
+
+// pushToATS sends the session results to the candidate profile in the ATS
+func pushToATS(cfg *config.Config, record session.Record) error {
+	if cfg.ATS.CandidateID == "" {
+		return fmt.Errorf("ATS_CANDIDATE_ID is not set")
 	}
 
-	return nil
+	connector, err := ats.NewConnector(ats.Config{
+		Provider: cfg.ATS.Provider,
+		APIKey:   cfg.ATS.APIKey,
+		UserID:   cfg.ATS.UserID,
+		Mapping:  cfg.ATS.FieldMapping,
+	})
+	if err != nil {
+		return err
+	}
+	return connector.Push(cfg.ATS.CandidateID, ats.NewResult(record))
 }
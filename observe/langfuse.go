@@ -0,0 +1,174 @@
+package observe
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	LangfuseEndpoint = "https://cloud.langfuse.com"
+)
+
+// langfuseBatch is the body of the Langfuse ingestion API
+type langfuseBatch struct {
+	Batch []langfuseEvent `json:"batch"`
+}
+
+type langfuseEvent struct {
+	ID        string      `json:"id"`
+	Timestamp time.Time   `json:"timestamp"`
+	Type      string      `json:"type"`
+	Body      interface{} `json:"body"`
+}
+
+// langfuseTrace groups the generations of one interview
+type langfuseTrace struct {
+	ID        string            `json:"id"`
+	Name      string            `json:"name"`
+	SessionID string            `json:"sessionId"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+}
+
+type langfuseGeneration struct {
+	ID            string            `json:"id"`
+	TraceID       string            `json:"traceId"`
+	Name          string            `json:"name"`
+	StartTime     time.Time         `json:"startTime"`
+	EndTime       time.Time         `json:"endTime"`
+	Model         string            `json:"model,omitempty"`
+	Input         []langfuseMessage `json:"input"`
+	Output        string            `json:"output,omitempty"`
+	Usage         *langfuseUsage    `json:"usage,omitempty"`
+	Metadata      map[string]string `json:"metadata,omitempty"`
+	Level         string            `json:"level,omitempty"`
+	StatusMessage string            `json:"statusMessage,omitempty"`
+}
+
+type langfuseMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type langfuseUsage struct {
+	Input  int    `json:"input"`
+	Output int    `json:"output"`
+	Total  int    `json:"total"`
+	Unit   string `json:"unit"`
+}
+
+// langfuseResponse lists the events the ingestion API rejected
+type langfuseResponse struct {
+	Errors []struct {
+		ID      string `json:"id"`
+		Status  int    `json:"status"`
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// LangfuseExporter sends generations through the Langfuse ingestion API. The
+// generations of a session are grouped in one trace named after the session
+type LangfuseExporter struct {
+	PublicKey  string
+	SecretKey  string
+	Endpoint   string
+	HTTPClient *http.Client
+}
+
+// Ensure LangfuseExporter implements Exporter interface
+var _ Exporter = (*LangfuseExporter)(nil)
+
+// NewLangfuseExporter creates a Langfuse Cloud client, set Endpoint for a self-hosted instance
+func NewLangfuseExporter(publicKey, secretKey string) *LangfuseExporter {
+	return &LangfuseExporter{
+		PublicKey:  publicKey,
+		SecretKey:  secretKey,
+		Endpoint:   LangfuseEndpoint,
+		HTTPClient: &http.Client{Timeout: exportTimeout},
+	}
+}
+
+// Export creates or updates the trace of the session and adds the generation to it
+func (l *LangfuseExporter) Export(generation Generation) error {
+	var traceMetadata map[string]string
+	metadata := map[string]string{"stage": generation.Stage}
+	if generation.Variant != "" {
+		traceMetadata = map[string]string{"variant": generation.Variant}
+		metadata["variant"] = generation.Variant
+	}
+
+	body := langfuseGeneration{
+		ID:        generation.ID,
+		TraceID:   generation.Session,
+		Name:      generation.Stage,
+		StartTime: generation.Time,
+		EndTime:   generation.Time.Add(time.Duration(generation.LatencyMs) * time.Millisecond),
+		Model:     generation.Model,
+		Input: []langfuseMessage{
+			{Role: "system", Content: generation.System},
+			{Role: "user", Content: generation.Input},
+		},
+		Output:   generation.Output,
+		Metadata: metadata,
+	}
+	if generation.Tokens != nil {
+		body.Usage = &langfuseUsage{
+			Input:  generation.Tokens.InputTokens,
+			Output: generation.Tokens.CompletionTokens,
+			Total:  generation.Tokens.TotalTokens,
+			Unit:   "TOKENS",
+		}
+	}
+	if generation.Error != "" {
+		body.Level, body.StatusMessage = "ERROR", generation.Error
+	}
+
+	now := time.Now()
+	batch := langfuseBatch{Batch: []langfuseEvent{
+		{ID: NewID(), Timestamp: now, Type: "trace-create", Body: langfuseTrace{
+			ID:        generation.Session,
+			Name:      "interview",
+			SessionID: generation.Session,
+			Metadata:  traceMetadata,
+		}},
+		{ID: NewID(), Timestamp: now, Type: "generation-create", Body: body},
+	}}
+	return l.send(batch)
+}
+
+// send posts a batch of events, the API answers 207 when some of them were rejected
+func (l *LangfuseExporter) send(batch langfuseBatch) error {
+	reqBody, err := json.Marshal(batch)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequest("POST", strings.TrimRight(l.Endpoint, "/")+"/api/public/ingestion", bytes.NewBuffer(reqBody))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.SetBasicAuth(l.PublicKey, l.SecretKey)
+
+	resp, err := l.HTTPClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var result langfuseResponse
+	if err := json.Unmarshal(respBody, &result); err == nil && len(result.Errors) > 0 {
+		first := result.Errors[0]
+		return fmt.Errorf("Langfuse rejected %d events, e.g. status %d: %s", len(result.Errors), first.Status, first.Message)
+	}
+	return nil
+}
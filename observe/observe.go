@@ -0,0 +1,108 @@
+// Package observe exports model generations to LLM observability tools, so
+// prompt engineers can inspect and grade the prompts and responses of
+// production interviews
+package observe
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/d1nch8g/aihr/correlation"
+	"github.com/d1nch8g/aihr/gpt"
+)
+
+// exportTimeout bounds one export request
+const exportTimeout = 10 * time.Second
+
+// Stages a generation is made in
+const (
+	StageQuestion   = "question"   // The response to an answer
+	StageOpener     = "opener"     // The first sentence of a response, written by the fast model
+	StageRegenerate = "regenerate" // A response regenerated after the safety filter blocked one
+	StageRephrase   = "rephrase"   // The last question in other words
+	StageClosing    = "closing"    // The summary and goodbye at the end of the interview
+)
+
+// Generation is one prompt and response pair of the model
+type Generation struct {
+	ID        string     `json:"id"`
+	Session   string     `json:"session"`
+	Stage     string     `json:"stage"`
+	Model     string     `json:"model,omitempty"`
+	Variant   string     `json:"variant,omitempty"`
+	System    string     `json:"system"`
+	Input     string     `json:"input"`
+	Output    string     `json:"output,omitempty"`
+	Time      time.Time  `json:"time"`
+	LatencyMs int64      `json:"latency_ms"`
+	Tokens    *gpt.Usage `json:"tokens,omitempty"`
+	Error     string     `json:"error,omitempty"`
+}
+
+// Exporter defines the interface for observability backends
+type Exporter interface {
+	// Export sends a generation to the backend
+	Export(generation Generation) error
+}
+
+// Config selects and configures the exporter
+type Config struct {
+	Provider  string // "langfuse" or "webhook"
+	Endpoint  string // Langfuse host or the webhook URL
+	PublicKey string // Langfuse public key
+	SecretKey string // Langfuse secret key, or the bearer token of the webhook
+
+	// Model and Variant are attached to every generation
+	Model   string
+	Variant string
+}
+
+// NewExporter creates the exporter for the configured provider
+func NewExporter(config Config) (Exporter, error) {
+	var exporter Exporter
+	switch config.Provider {
+	case "langfuse":
+		if config.PublicKey == "" || config.SecretKey == "" {
+			return nil, fmt.Errorf("Langfuse requires a public and a secret key")
+		}
+		langfuse := NewLangfuseExporter(config.PublicKey, config.SecretKey)
+		if config.Endpoint != "" {
+			langfuse.Endpoint = config.Endpoint
+		}
+		exporter = langfuse
+	case "webhook":
+		if config.Endpoint == "" {
+			return nil, fmt.Errorf("webhook exporter requires an endpoint")
+		}
+		exporter = NewWebhookExporter(config.Endpoint, config.SecretKey)
+	default:
+		return nil, fmt.Errorf("unknown observability provider %q", config.Provider)
+	}
+	return &Tagged{Exporter: exporter, Model: config.Model, Variant: config.Variant}, nil
+}
+
+// Tagged fills the model and variant of generations before exporting them
+type Tagged struct {
+	Exporter Exporter
+	Model    string
+	Variant  string
+}
+
+// Ensure Tagged implements Exporter interface
+var _ Exporter = (*Tagged)(nil)
+
+// Export tags the generation and passes it on
+func (t *Tagged) Export(generation Generation) error {
+	if generation.Model == "" {
+		generation.Model = t.Model
+	}
+	if generation.Variant == "" {
+		generation.Variant = t.Variant
+	}
+	return t.Exporter.Export(generation)
+}
+
+// NewID returns a random identifier in the UUID format
+func NewID() string {
+	return correlation.NewID()
+}
@@ -0,0 +1,57 @@
+package observe
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// WebhookExporter posts every generation as JSON to a generic endpoint
+type WebhookExporter struct {
+	Endpoint   string
+	Token      string // Sent as a bearer token when set
+	HTTPClient *http.Client
+}
+
+// Ensure WebhookExporter implements Exporter interface
+var _ Exporter = (*WebhookExporter)(nil)
+
+// NewWebhookExporter creates an exporter posting to the endpoint
+func NewWebhookExporter(endpoint, token string) *WebhookExporter {
+	return &WebhookExporter{
+		Endpoint:   endpoint,
+		Token:      token,
+		HTTPClient: &http.Client{Timeout: exportTimeout},
+	}
+}
+
+// Export posts the generation
+func (w *WebhookExporter) Export(generation Generation) error {
+	reqBody, err := json.Marshal(generation)
+	if err != nil {
+		return fmt.Errorf("failed to marshal generation: %w", err)
+	}
+
+	httpReq, err := http.NewRequest("POST", w.Endpoint, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if w.Token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+w.Token)
+	}
+
+	resp, err := w.HTTPClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package pcm
+
+import "encoding/binary"
+
+// Aligner decodes a stream of byte chunks whose boundaries may split a
+// sample, carrying the odd byte over to the next chunk
+type Aligner struct {
+	order   binary.ByteOrder
+	odd     byte
+	hasOdd  bool
+	scratch [SampleSize]byte
+}
+
+// NewAligner creates an aligner for 16-bit samples in the given byte order
+func NewAligner(order binary.ByteOrder) *Aligner {
+	return &Aligner{order: order}
+}
+
+// Append appends the complete samples of chunk to dst
+func (a *Aligner) Append(dst []int16, chunk []byte) []int16 {
+	if len(chunk) == 0 {
+		return dst
+	}
+
+	if a.hasOdd {
+		a.scratch[0], a.scratch[1] = a.odd, chunk[0]
+		dst = append(dst, int16(a.order.Uint16(a.scratch[:])))
+		chunk = chunk[1:]
+		a.hasOdd = false
+	}
+
+	if len(chunk)%SampleSize != 0 {
+		a.odd, a.hasOdd = chunk[len(chunk)-1], true
+		chunk = chunk[:len(chunk)-1]
+	}
+
+	return AppendSamples(a.order, dst, chunk)
+}
+
+// Pending returns whether an odd byte is waiting for the next chunk
+func (a *Aligner) Pending() bool {
+	return a.hasOdd
+}
+
+// Reset drops a pending odd byte, e.g. at the start of a new stream
+func (a *Aligner) Reset() {
+	a.hasOdd = false
+}
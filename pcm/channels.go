@@ -0,0 +1,69 @@
+package pcm
+
+// Interleave merges per-channel samples into frames. Channels shorter than
+// the first one are padded with silence
+func Interleave(channels [][]int16) []int16 {
+	if len(channels) == 0 {
+		return nil
+	}
+
+	frames := len(channels[0])
+	out := make([]int16, frames*len(channels))
+	for c, samples := range channels {
+		for f := 0; f < frames && f < len(samples); f++ {
+			out[f*len(channels)+c] = samples[f]
+		}
+	}
+	return out
+}
+
+// Deinterleave splits frames into per-channel samples. An incomplete
+// trailing frame is dropped
+func Deinterleave(samples []int16, channels int) [][]int16 {
+	if channels <= 0 {
+		return nil
+	}
+
+	frames := len(samples) / channels
+	out := make([][]int16, channels)
+	for c := range out {
+		out[c] = make([]int16, frames)
+		for f := 0; f < frames; f++ {
+			out[c][f] = samples[f*channels+c]
+		}
+	}
+	return out
+}
+
+// ToMono averages the channels of every frame. An incomplete trailing frame is dropped
+func ToMono(samples []int16, channels int) []int16 {
+	if channels <= 1 {
+		return samples
+	}
+
+	frames := len(samples) / channels
+	out := make([]int16, frames)
+	for f := 0; f < frames; f++ {
+		sum := 0
+		for c := 0; c < channels; c++ {
+			sum += int(samples[f*channels+c])
+		}
+		out[f] = int16(sum / channels)
+	}
+	return out
+}
+
+// FromMono duplicates every sample into the given number of channels
+func FromMono(samples []int16, channels int) []int16 {
+	if channels <= 1 {
+		return samples
+	}
+
+	out := make([]int16, len(samples)*channels)
+	for f, sample := range samples {
+		for c := 0; c < channels; c++ {
+			out[f*channels+c] = sample
+		}
+	}
+	return out
+}
@@ -0,0 +1,74 @@
+// Package pcm converts between raw PCM bytes and samples. All functions are
+// safe for chunks of any length: a trailing odd byte is never read as a sample
+package pcm
+
+import (
+	"encoding/binary"
+	"slices"
+)
+
+// SampleSize is the size of a 16-bit sample in bytes
+const SampleSize = 2
+
+// AppendInt16 appends samples to dst as 16-bit PCM in the given byte order
+func AppendInt16(order binary.ByteOrder, dst []byte, samples []int16) []byte {
+	offset := len(dst)
+	dst = grow(dst, len(samples)*SampleSize)
+	for i, sample := range samples {
+		order.PutUint16(dst[offset+i*SampleSize:], uint16(sample))
+	}
+	return dst
+}
+
+// AppendInt32As16 appends 32-bit samples to dst as 16-bit PCM, keeping the
+// most significant bits
+func AppendInt32As16(order binary.ByteOrder, dst []byte, samples []int32) []byte {
+	offset := len(dst)
+	dst = grow(dst, len(samples)*SampleSize)
+	for i, sample := range samples {
+		order.PutUint16(dst[offset+i*SampleSize:], uint16(int16(sample>>16)))
+	}
+	return dst
+}
+
+// AppendSamples appends the 16-bit samples in data to dst. A trailing odd
+// byte is ignored, use an Aligner to keep it for the next chunk
+func AppendSamples(order binary.ByteOrder, dst []int16, data []byte) []int16 {
+	count := len(data) / SampleSize
+	dst = slices.Grow(dst, count)
+	for i := 0; i < count; i++ {
+		dst = append(dst, int16(order.Uint16(data[i*SampleSize:])))
+	}
+	return dst
+}
+
+// Encode returns samples as little-endian 16-bit PCM
+func Encode(samples []int16) []byte {
+	return AppendInt16(binary.LittleEndian, make([]byte, 0, len(samples)*SampleSize), samples)
+}
+
+// Decode returns the little-endian 16-bit samples in data
+func Decode(data []byte) []int16 {
+	return AppendSamples(binary.LittleEndian, make([]int16, 0, len(data)/SampleSize), data)
+}
+
+// Clamp16 converts a sample value to int16, saturating instead of wrapping around
+func Clamp16(value float64) int16 {
+	if value > 32767 {
+		return 32767
+	}
+	if value < -32768 {
+		return -32768
+	}
+	return int16(value)
+}
+
+// grow extends dst by n bytes, reusing its capacity when possible
+func grow(dst []byte, n int) []byte {
+	if cap(dst)-len(dst) >= n {
+		return dst[:len(dst)+n]
+	}
+	grown := make([]byte, len(dst)+n, 2*cap(dst)+n)
+	copy(grown, dst)
+	return grown
+}
@@ -0,0 +1,101 @@
+package pcm
+
+import (
+	"bytes"
+	"encoding/binary"
+	"slices"
+	"testing"
+)
+
+// orderOf returns the byte order selected by a fuzzed flag
+func orderOf(bigEndian bool) binary.ByteOrder {
+	if bigEndian {
+		return binary.BigEndian
+	}
+	return binary.LittleEndian
+}
+
+func FuzzAppendSamples(f *testing.F) {
+	f.Add([]byte{}, false)
+	f.Add([]byte{0x01}, false)
+	f.Add([]byte{0x01, 0x02, 0x03}, true)
+	f.Add([]byte{0xff, 0x7f, 0x00, 0x80}, false)
+
+	f.Fuzz(func(t *testing.T, data []byte, bigEndian bool) {
+		order := orderOf(bigEndian)
+		samples := AppendSamples(order, nil, data)
+		if len(samples) != len(data)/SampleSize {
+			t.Fatalf("decoded %d samples from %d bytes", len(samples), len(data))
+		}
+		even := data[:len(data)/SampleSize*SampleSize]
+		if encoded := AppendInt16(order, nil, samples); !bytes.Equal(encoded, even) {
+			t.Fatalf("round trip gives %x, want %x", encoded, even)
+		}
+	})
+}
+
+func FuzzAligner(f *testing.F) {
+	f.Add([]byte{0x01, 0x02, 0x03, 0x04, 0x05}, []byte{1, 1, 1})
+	f.Add([]byte{0x01, 0x02, 0x03}, []byte{3})
+	f.Add([]byte{}, []byte{})
+
+	f.Fuzz(func(t *testing.T, data []byte, cuts []byte) {
+		aligner := NewAligner(binary.LittleEndian)
+		var samples []int16
+		rest := data
+		for _, cut := range cuts {
+			n := min(int(cut), len(rest))
+			samples = aligner.Append(samples, rest[:n])
+			rest = rest[n:]
+		}
+		samples = aligner.Append(samples, rest)
+
+		if want := Decode(data); !slices.Equal(samples, want) {
+			t.Fatalf("split decode gives %v, want %v", samples, want)
+		}
+		if aligner.Pending() != (len(data)%SampleSize != 0) {
+			t.Fatalf("pending is %v after %d bytes", aligner.Pending(), len(data))
+		}
+	})
+}
+
+func FuzzInterleave(f *testing.F) {
+	f.Add([]byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06}, uint8(2))
+	f.Add([]byte{0x01, 0x02, 0x03}, uint8(3))
+
+	f.Fuzz(func(t *testing.T, data []byte, count uint8) {
+		channels := int(count%8) + 1
+		samples := Decode(data)
+		frames := len(samples) / channels
+
+		split := Deinterleave(samples, channels)
+		if len(split) != channels {
+			t.Fatalf("deinterleaved %d channels, want %d", len(split), channels)
+		}
+		for c, channel := range split {
+			if len(channel) != frames {
+				t.Fatalf("channel %d has %d samples, want %d", c, len(channel), frames)
+			}
+		}
+		if merged := Interleave(split); !slices.Equal(merged, samples[:frames*channels]) {
+			t.Fatalf("round trip gives %v, want %v", merged, samples[:frames*channels])
+		}
+	})
+}
+
+func FuzzToMono(f *testing.F) {
+	f.Add([]byte{0x01, 0x02, 0x03, 0x04}, uint8(2))
+	f.Add([]byte{0xff, 0x7f, 0xff, 0x7f, 0x00}, uint8(1))
+
+	f.Fuzz(func(t *testing.T, data []byte, count uint8) {
+		channels := int(count%8) + 1
+		samples := Decode(data)
+
+		if mono := ToMono(samples, channels); len(mono) != len(samples)/channels {
+			t.Fatalf("mono has %d samples from %d in %d channels", len(mono), len(samples), channels)
+		}
+		if mono := ToMono(FromMono(samples, channels), channels); !slices.Equal(mono, samples) {
+			t.Fatalf("round trip gives %v, want %v", mono, samples)
+		}
+	})
+}
@@ -0,0 +1,130 @@
+// Package persona describes the panel of interviewers who take turns in a
+// session, each with its own voice and instructions
+package persona
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+)
+
+// Persona is one interviewer of the panel
+type Persona struct {
+	Name   string `json:"name"`
+	Title  string `json:"title"`           // e.g. "technical interviewer", "hiring manager"
+	Voice  string `json:"voice,omitempty"` // TTS voice, the configured voice when empty
+	Prompt string `json:"prompt"`          // Instructions added to the system prompt while the persona leads
+
+	// ClonedVoice is the ID of a cloned or company-branded voice, spoken
+	// instead of Voice by providers that support it. Consent records the
+	// permission of the person whose voice was cloned and is required with it
+	ClonedVoice string   `json:"cloned_voice,omitempty"`
+	Consent     *Consent `json:"consent,omitempty"`
+
+	// Topics are the topics of the question plan the persona leads. Without
+	// topics the persona leads for Answers answers, to the end of the
+	// interview when zero
+	Topics  []string `json:"topics,omitempty"`
+	Answers int      `json:"answers,omitempty"`
+}
+
+// Consent records the permission to synthesize a cloned voice
+type Consent struct {
+	GivenBy   string `json:"given_by"`            // Person whose voice was cloned
+	Date      string `json:"date"`                // Day the consent was given, YYYY-MM-DD
+	Reference string `json:"reference,omitempty"` // Signed release or ticket kept on file
+}
+
+// Panel is the interviewers of a session in the order they take over
+type Panel []Persona
+
+// Load reads a panel from a JSON file with an array of personas
+func Load(path string) (Panel, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read personas: %w", err)
+	}
+	var panel Panel
+	if err := json.Unmarshal(content, &panel); err != nil {
+		return nil, fmt.Errorf("failed to parse personas %s: %w", path, err)
+	}
+	if err := panel.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid personas %s: %w", path, err)
+	}
+	return panel, nil
+}
+
+// Validate checks that the personas have unique names, titles, consent to
+// their cloned voices and topics led by one persona only
+func (p Panel) Validate() error {
+	if len(p) == 0 {
+		return fmt.Errorf("panel has no personas")
+	}
+	names := make(map[string]bool, len(p))
+	topics := make(map[string]string)
+	for _, persona := range p {
+		switch {
+		case persona.Name == "":
+			return fmt.Errorf("persona has no name")
+		case names[persona.Name]:
+			return fmt.Errorf("duplicate persona %q", persona.Name)
+		case persona.Title == "":
+			return fmt.Errorf("persona %q has no title", persona.Name)
+		case persona.Answers < 0:
+			return fmt.Errorf("persona %q has a negative number of answers", persona.Name)
+		case persona.ClonedVoice != "" && persona.Consent == nil:
+			return fmt.Errorf("persona %q has a cloned voice without consent", persona.Name)
+		}
+		if consent := persona.Consent; consent != nil {
+			if consent.GivenBy == "" {
+				return fmt.Errorf("consent of persona %q does not say who gave it", persona.Name)
+			}
+			if _, err := time.Parse(time.DateOnly, consent.Date); err != nil {
+				return fmt.Errorf("consent of persona %q has an invalid date %q", persona.Name, consent.Date)
+			}
+		}
+		names[persona.Name] = true
+		for _, topic := range persona.Topics {
+			if other, ok := topics[topic]; ok {
+				return fmt.Errorf("topic %q is led by both %q and %q", topic, other, persona.Name)
+			}
+			topics[topic] = persona.Name
+		}
+	}
+	return nil
+}
+
+// Names returns the names of the personas
+func (p Panel) Names() []string {
+	names := make([]string, len(p))
+	for i, persona := range p {
+		names[i] = persona.Name
+	}
+	return names
+}
+
+// Lead returns the index of the persona leading the next question, on the
+// topic of the question plan or after the given number of answers. It
+// returns false when no persona leads the topic, the current one then stays
+func (p Panel) Lead(topic string, answers int) (int, bool) {
+	hasTopics := false
+	for i, persona := range p {
+		if topic != "" && slices.Contains(persona.Topics, topic) {
+			return i, true
+		}
+		hasTopics = hasTopics || len(persona.Topics) > 0
+	}
+	if hasTopics {
+		return 0, false
+	}
+
+	for i, persona := range p {
+		if persona.Answers == 0 || answers < persona.Answers {
+			return i, true
+		}
+		answers -= persona.Answers
+	}
+	return len(p) - 1, true
+}
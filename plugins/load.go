@@ -0,0 +1,79 @@
+//go:build cgo && (linux || darwin || freebsd)
+
+package plugins
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"plugin"
+	"strings"
+)
+
+// LoadDir opens every .so file in dir and registers the plugin it exports.
+// A missing directory is not an error
+func LoadDir(dir string) ([]string, error) {
+	paths, err := pluginFiles(dir)
+	if err != nil || len(paths) == 0 {
+		return nil, err
+	}
+
+	var loaded []string
+	for _, path := range paths {
+		name, err := load(path)
+		if err != nil {
+			return loaded, err
+		}
+		loaded = append(loaded, name)
+	}
+
+	return loaded, nil
+}
+
+// load opens a single plugin file and registers its plugin
+func load(path string) (string, error) {
+	handle, err := plugin.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open plugin %s: %w", path, err)
+	}
+
+	symbol, err := handle.Lookup(Symbol)
+	if err != nil {
+		return "", fmt.Errorf("plugin %s does not export %s: %w", path, Symbol, err)
+	}
+
+	p, ok := symbol.(**Plugin)
+	if !ok || *p == nil {
+		return "", fmt.Errorf("plugin %s: %s must be of type *plugins.Plugin", path, Symbol)
+	}
+
+	if err := Register(*p); err != nil {
+		return "", fmt.Errorf("failed to register plugin %s: %w", path, err)
+	}
+
+	return (*p).Name, nil
+}
+
+// pluginFiles lists the plugin files in dir in a stable order
+func pluginFiles(dir string) ([]string, error) {
+	if dir == "" {
+		return nil, nil
+	}
+
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plugin directory: %w", err)
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".so") {
+			continue
+		}
+		paths = append(paths, filepath.Join(dir, entry.Name()))
+	}
+	return paths, nil
+}
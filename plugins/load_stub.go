@@ -0,0 +1,33 @@
+//go:build !cgo || !(linux || darwin || freebsd)
+
+package plugins
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LoadDir reports an error when plugin files are present, because this
+// build cannot load Go plugins (it requires cgo on Linux, macOS or FreeBSD)
+func LoadDir(dir string) ([]string, error) {
+	if dir == "" {
+		return nil, nil
+	}
+
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plugin directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".so") {
+			return nil, fmt.Errorf("cannot load plugin %s: plugins are not supported by this build", filepath.Join(dir, entry.Name()))
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,118 @@
+// Package plugins lets third parties ship STT, TTS and GPT providers as
+// separately built Go plugins that are discovered at runtime
+package plugins
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/d1nch8g/aihr/config"
+	"github.com/d1nch8g/aihr/gpt"
+	"github.com/d1nch8g/aihr/stt"
+	"github.com/d1nch8g/aihr/tts"
+)
+
+// APIVersion is the plugin interface version supported by this build.
+// It is increased whenever Plugin or the provider interfaces change incompatibly
+const APIVersion = 1
+
+// Symbol is the name of the exported variable every plugin must define.
+// Its type must be *plugins.Plugin, e.g.
+//
+//	var AIHRPlugin = &plugins.Plugin{APIVersion: plugins.APIVersion, Name: "acme", NewSTT: newSTT}
+const Symbol = "AIHRPlugin"
+
+// Plugin describes the providers offered by a plugin. Factories that are nil
+// mean the plugin does not provide that component
+type Plugin struct {
+	APIVersion int
+	Name       string
+
+	NewSTT func(cfg *config.Config) (stt.STTClient, error)
+	NewTTS func(cfg *config.Config) (tts.Synthesizer, error)
+	NewGPT func(cfg *config.Config) (gpt.GPTClient, error)
+}
+
+var (
+	registry      = make(map[string]*Plugin)
+	registryMutex sync.RWMutex
+)
+
+// Register makes a plugin available by name. Plugins compiled into the
+// binary may call it from init, plugins loaded from disk are registered by LoadDir
+func Register(p *Plugin) error {
+	if p == nil || p.Name == "" {
+		return fmt.Errorf("plugin name is required")
+	}
+	if p.APIVersion != APIVersion {
+		return fmt.Errorf("plugin %s uses API version %d, expected %d", p.Name, p.APIVersion, APIVersion)
+	}
+
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+
+	if _, exists := registry[p.Name]; exists {
+		return fmt.Errorf("plugin %s is already registered", p.Name)
+	}
+	registry[p.Name] = p
+	return nil
+}
+
+// Lookup returns a registered plugin by name
+func Lookup(name string) (*Plugin, bool) {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	p, ok := registry[name]
+	return p, ok
+}
+
+// Names returns the names of all registered plugins
+func Names() []string {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewSTT creates a speech recognition client from the named plugin
+func NewSTT(name string, cfg *config.Config) (stt.STTClient, error) {
+	p, ok := Lookup(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown STT provider %q", name)
+	}
+	if p.NewSTT == nil {
+		return nil, fmt.Errorf("plugin %s does not provide STT", name)
+	}
+	return p.NewSTT(cfg)
+}
+
+// NewTTS creates a speech synthesis client from the named plugin
+func NewTTS(name string, cfg *config.Config) (tts.Synthesizer, error) {
+	p, ok := Lookup(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown TTS provider %q", name)
+	}
+	if p.NewTTS == nil {
+		return nil, fmt.Errorf("plugin %s does not provide TTS", name)
+	}
+	return p.NewTTS(cfg)
+}
+
+// NewGPT creates a language model client from the named plugin
+func NewGPT(name string, cfg *config.Config) (gpt.GPTClient, error) {
+	p, ok := Lookup(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown GPT provider %q", name)
+	}
+	if p.NewGPT == nil {
+		return nil, fmt.Errorf("plugin %s does not provide GPT", name)
+	}
+	return p.NewGPT(cfg)
+}
@@ -0,0 +1,46 @@
+package prompts
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteDiff prints the line differences between two versions, removed lines
+// prefixed with "-" and added ones with "+"
+func WriteDiff(w io.Writer, from, to Version) {
+	fmt.Fprintf(w, "--- %s (%s)\n+++ %s (%s)\n", from.Ref(), from.Hash, to.Ref(), to.Hash)
+	a := strings.Split(from.Text, "\n")
+	b := strings.Split(to.Text, "\n")
+
+	// common[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	common := make([][]int, len(a)+1)
+	for i := range common {
+		common[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			fmt.Fprintf(w, " %s\n", a[i])
+			i++
+			j++
+		case j < len(b) && (i == len(a) || common[i][j+1] >= common[i+1][j]):
+			fmt.Fprintf(w, "+%s\n", b[j])
+			j++
+		default:
+			fmt.Fprintf(w, "-%s\n", a[i])
+			i++
+		}
+	}
+}
@@ -0,0 +1,204 @@
+// Package prompts keeps versioned interview templates (system prompts), so
+// every session can be traced to the exact prompt it was run with and
+// evaluations of prompt changes can be reproduced
+package prompts
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DefaultName is the template used when no prompt is configured
+const DefaultName = "go-developer"
+
+// pinsFile stores the pinned versions in the registry directory
+const pinsFile = "pins.json"
+
+//go:embed builtin
+var builtin embed.FS
+
+// Version is one version of a template
+type Version struct {
+	Name    string
+	Version string // Semantic version, e.g. "1.2.0"
+	Hash    string // Content hash, see Hash
+	Text    string
+	Builtin bool // Embedded in the binary rather than read from the registry directory
+}
+
+// Ref returns the reference of the version, e.g. "go-developer@1.2.0"
+func (v Version) Ref() string {
+	return v.Name + "@" + v.Version
+}
+
+// Hash returns the short SHA-256 of a prompt. Surrounding whitespace is
+// ignored, so a trailing newline of a file does not change it
+func Hash(text string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(text)))
+	return hex.EncodeToString(sum[:6])
+}
+
+// Registry holds the embedded templates and the ones in a directory laid out
+// as <name>/<version>.txt, and the versions pinned in it
+type Registry struct {
+	dir      string
+	versions map[string][]Version // Sorted by version
+	pins     map[string]string
+}
+
+// Load reads the embedded templates and, when dir is set, the templates and
+// pins in it. A template in dir replaces an embedded one of the same version
+func Load(dir string) (*Registry, error) {
+	r := &Registry{dir: dir, versions: make(map[string][]Version), pins: make(map[string]string)}
+	if err := r.load(builtin, "builtin", true); err != nil {
+		return nil, err
+	}
+	if dir == "" {
+		return r, nil
+	}
+
+	if err := r.load(os.DirFS(dir), ".", false); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, pinsFile))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read prompt pins: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &r.pins); err != nil {
+			return nil, fmt.Errorf("failed to decode prompt pins: %w", err)
+		}
+	}
+	return r, nil
+}
+
+// load adds the templates under root of fsys
+func (r *Registry) load(fsys fs.FS, root string, embedded bool) error {
+	files, err := fs.Glob(fsys, path.Join(root, "*", "*.txt"))
+	if err != nil {
+		return fmt.Errorf("failed to list prompts: %w", err)
+	}
+	for _, file := range files {
+		name := path.Base(path.Dir(file))
+		version := strings.TrimSuffix(path.Base(file), ".txt")
+		if _, err := parseVersion(version); err != nil {
+			return fmt.Errorf("invalid prompt version %s: %w", file, err)
+		}
+		content, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return fmt.Errorf("failed to read prompt: %w", err)
+		}
+
+		text := strings.TrimSpace(string(content))
+		r.add(Version{Name: name, Version: version, Hash: Hash(text), Text: text, Builtin: embedded})
+	}
+	return nil
+}
+
+// add inserts a version, replacing an existing one with the same number
+func (r *Registry) add(version Version) {
+	versions := r.versions[version.Name]
+	for i, existing := range versions {
+		if existing.Version == version.Version {
+			versions[i] = version
+			return
+		}
+	}
+	versions = append(versions, version)
+	sort.Slice(versions, func(i, j int) bool {
+		return compareVersions(versions[i].Version, versions[j].Version) < 0
+	})
+	r.versions[version.Name] = versions
+}
+
+// Names returns the template names in alphabetical order
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.versions))
+	for name := range r.versions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Versions returns the versions of a template from the oldest to the newest
+func (r *Registry) Versions(name string) []Version {
+	return r.versions[name]
+}
+
+// Pinned returns the pinned version of a template, empty when it is not pinned
+func (r *Registry) Pinned(name string) string {
+	return r.pins[name]
+}
+
+// Resolve returns the version a reference points to. "name@1.2.0" selects
+// that version, a bare name the pinned version or else the newest one
+func (r *Registry) Resolve(ref string) (Version, error) {
+	name, version, _ := strings.Cut(ref, "@")
+	versions := r.versions[name]
+	if len(versions) == 0 {
+		return Version{}, fmt.Errorf("unknown prompt %q", name)
+	}
+	if version == "" {
+		version = r.pins[name]
+	}
+	if version == "" {
+		return versions[len(versions)-1], nil
+	}
+
+	for _, v := range versions {
+		if compareVersions(v.Version, version) == 0 {
+			return v, nil
+		}
+	}
+	return Version{}, fmt.Errorf("prompt %s has no version %s", name, version)
+}
+
+// Identify returns the template version with the same content as the text
+func (r *Registry) Identify(text string) (Version, bool) {
+	hash := Hash(text)
+	for _, name := range r.Names() {
+		for _, v := range r.versions[name] {
+			if v.Hash == hash {
+				return v, true
+			}
+		}
+	}
+	return Version{}, false
+}
+
+// Pin makes a bare reference to the template resolve to the version, an
+// empty version removes the pin. Pins are saved in the registry directory
+func (r *Registry) Pin(name, version string) error {
+	if r.dir == "" {
+		return fmt.Errorf("prompt directory is not set")
+	}
+	if version == "" {
+		delete(r.pins, name)
+	} else {
+		resolved, err := r.Resolve(name + "@" + version)
+		if err != nil {
+			return err
+		}
+		r.pins[name] = resolved.Version
+	}
+
+	data, err := json.MarshalIndent(r.pins, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode prompt pins: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(r.dir, pinsFile), append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to save prompt pins: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package prompts
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseVersion parses a semantic version of the form MAJOR.MINOR.PATCH with
+// an optional "v" prefix. Pre-release and build suffixes are not supported
+func parseVersion(version string) ([3]int, error) {
+	var parsed [3]int
+	parts := strings.Split(strings.TrimPrefix(version, "v"), ".")
+	if len(parts) != 3 {
+		return parsed, fmt.Errorf("%q is not of the form MAJOR.MINOR.PATCH", version)
+	}
+	for i, part := range parts {
+		number, err := strconv.Atoi(part)
+		if err != nil || number < 0 {
+			return parsed, fmt.Errorf("%q is not of the form MAJOR.MINOR.PATCH", version)
+		}
+		parsed[i] = number
+	}
+	return parsed, nil
+}
+
+// compareVersions returns -1, 0 or 1 as a is older, equal to or newer than b.
+// Invalid versions sort before valid ones
+func compareVersions(a, b string) int {
+	parsedA, errA := parseVersion(a)
+	parsedB, errB := parseVersion(b)
+	switch {
+	case errA != nil && errB != nil:
+		return strings.Compare(a, b)
+	case errA != nil:
+		return -1
+	case errB != nil:
+		return 1
+	}
+	for i := range parsedA {
+		if parsedA[i] != parsedB[i] {
+			if parsedA[i] < parsedB[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
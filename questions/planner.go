@@ -0,0 +1,109 @@
+package questions
+
+import "time"
+
+// Plan describes the questions a session covers
+type Plan struct {
+	// Topics are the stages of the interview in order, every topic of the
+	// questions with the tags in the order of the bank when empty
+	Topics []string
+
+	// Tags select the questions of the bank that can be asked, e.g. the role
+	Tags []string
+
+	// PerTopic is the number of questions asked on a topic before moving to
+	// the next one, one when zero
+	PerTopic int
+
+	// Budget is the time the questions may take by their estimates, zero
+	// means no limit
+	Budget time.Duration
+}
+
+// Planner picks the next question of a session. The choice only depends on
+// the plan and the difficulty levels asked for, so candidates who answer
+// alike get the same questions. A planner is not safe for concurrent use
+type Planner struct {
+	bank    Bank
+	plan    Plan
+	topics  []string
+	topic   int // Index of the current topic
+	onTopic int // Questions asked on the current topic
+	asked   map[string]bool
+	spent   time.Duration
+}
+
+// NewPlanner creates a planner of a session over the questions of the bank
+func NewPlanner(bank Bank, plan Plan) *Planner {
+	if plan.PerTopic <= 0 {
+		plan.PerTopic = 1
+	}
+	topics := plan.Topics
+	if len(topics) == 0 {
+		topics = bank.Topics(plan.Tags)
+	}
+	return &Planner{
+		bank:   bank,
+		plan:   plan,
+		topics: topics,
+		asked:  make(map[string]bool),
+	}
+}
+
+// Next returns the next question closest to the difficulty, preferring the
+// harder one of two equally close levels. Any level fits when the difficulty
+// is empty. The planner moves to the next topic once PerTopic questions were
+// asked or the topic has no questions left within the budget. It returns
+// false when the plan is complete
+func (p *Planner) Next(difficulty string) (Question, bool) {
+	for p.topic < len(p.topics) {
+		if p.onTopic < p.plan.PerTopic {
+			if question, ok := p.pick(p.topics[p.topic], difficulty); ok {
+				p.asked[question.ID] = true
+				p.spent += time.Duration(question.EstimatedTime)
+				p.onTopic++
+				return question, true
+			}
+		}
+		p.topic++
+		p.onTopic = 0
+	}
+	return Question{}, false
+}
+
+// Topic returns the topic of the current stage, empty when the plan is complete
+func (p *Planner) Topic() string {
+	if p.topic >= len(p.topics) {
+		return ""
+	}
+	return p.topics[p.topic]
+}
+
+// pick returns the first question of the topic in the bank order that was
+// not asked, fits the budget and is closest to the difficulty
+func (p *Planner) pick(topic, difficulty string) (Question, bool) {
+	target := level(difficulty)
+	var best Question
+	bestDistance := -1
+	for _, question := range p.bank {
+		if question.Topic != topic || p.asked[question.ID] || !question.HasTags(p.plan.Tags) {
+			continue
+		}
+		if p.plan.Budget > 0 && p.spent+time.Duration(question.EstimatedTime) > p.plan.Budget {
+			continue
+		}
+
+		distance := 0
+		if target >= 0 {
+			// Doubled so a harder question wins a tie with an easier one
+			distance = 2 * (level(question.Difficulty) - target)
+			if distance < 0 {
+				distance = -distance + 1
+			}
+		}
+		if bestDistance < 0 || distance < bestDistance {
+			best, bestDistance = question, distance
+		}
+	}
+	return best, bestDistance >= 0
+}
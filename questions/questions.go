@@ -0,0 +1,135 @@
+// Package questions keeps a bank of interview questions tagged by topic,
+// difficulty and expected time, and plans which of them a session asks so
+// candidates for the same role cover the same ground
+package questions
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/d1nch8g/aihr/eval"
+)
+
+// Difficulties lists the difficulty levels of questions from the easiest,
+// named like the levels of the engine
+var Difficulties = []string{"easy", "medium", "hard", "expert"}
+
+// Question is a question of the bank with its taxonomy
+type Question struct {
+	ID         string   `json:"id"`
+	Text       string   `json:"text"`
+	Topic      string   `json:"topic"`
+	Difficulty string   `json:"difficulty"`     // One of Difficulties
+	Tags       []string `json:"tags,omitempty"` // Free labels like the roles or seniority the question fits
+
+	// Rubric holds the criteria of a good answer to this question, e.g. the
+	// points it should cover, the rubric of the evaluator when empty
+	Rubric eval.Rubric `json:"rubric,omitempty"`
+
+	// EstimatedTime is how long asking and answering the question takes
+	EstimatedTime Duration `json:"estimated_time,omitempty"`
+}
+
+// HasTags reports whether the question is tagged with all the tags
+func (q Question) HasTags(tags []string) bool {
+	for _, tag := range tags {
+		if !slices.Contains(q.Tags, tag) {
+			return false
+		}
+	}
+	return true
+}
+
+// level returns the position of the difficulty in Difficulties
+func level(difficulty string) int {
+	return slices.Index(Difficulties, strings.ToLower(difficulty))
+}
+
+// Bank is the list of questions in the order they are preferred
+type Bank []Question
+
+// LoadBank reads a question bank from a JSON file with an array of questions
+func LoadBank(path string) (Bank, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read question bank: %w", err)
+	}
+	var bank Bank
+	if err := json.Unmarshal(content, &bank); err != nil {
+		return nil, fmt.Errorf("failed to parse question bank %s: %w", path, err)
+	}
+	if err := bank.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid question bank %s: %w", path, err)
+	}
+	return bank, nil
+}
+
+// Validate checks that every question has a unique ID, a text, a topic and
+// a known difficulty
+func (b Bank) Validate() error {
+	if len(b) == 0 {
+		return fmt.Errorf("question bank is empty")
+	}
+	seen := make(map[string]bool, len(b))
+	for _, question := range b {
+		switch {
+		case question.ID == "":
+			return fmt.Errorf("question has no id")
+		case seen[question.ID]:
+			return fmt.Errorf("duplicate question %q", question.ID)
+		case strings.TrimSpace(question.Text) == "":
+			return fmt.Errorf("question %q has no text", question.ID)
+		case question.Topic == "":
+			return fmt.Errorf("question %q has no topic", question.ID)
+		case level(question.Difficulty) < 0:
+			return fmt.Errorf("question %q has unknown difficulty %q", question.ID, question.Difficulty)
+		case question.EstimatedTime < 0:
+			return fmt.Errorf("question %q has a negative estimated time", question.ID)
+		}
+		if len(question.Rubric) > 0 {
+			if err := question.Rubric.Validate(); err != nil {
+				return fmt.Errorf("question %q: %w", question.ID, err)
+			}
+		}
+		seen[question.ID] = true
+	}
+	return nil
+}
+
+// Topics returns the topics of the questions with the tags in the order they
+// first appear in the bank
+func (b Bank) Topics(tags []string) []string {
+	var topics []string
+	for _, question := range b {
+		if question.HasTags(tags) && !slices.Contains(topics, question.Topic) {
+			topics = append(topics, question.Topic)
+		}
+	}
+	return topics
+}
+
+// Duration is a time.Duration encoded as a string like "2m" in JSON
+type Duration time.Duration
+
+// UnmarshalJSON parses durations like "2m30s"
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// MarshalJSON formats the duration like "2m30s"
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
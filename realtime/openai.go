@@ -0,0 +1,292 @@
+package realtime
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/d1nch8g/aihr/correlation"
+
+	"golang.org/x/net/websocket"
+)
+
+const (
+	OpenAIRealtimeEndpoint = "wss://api.openai.com/v1/realtime"
+	OpenAIRealtimeModel    = "gpt-4o-realtime-preview"
+	OpenAIRealtimeVoice    = "alloy"
+
+	// OpenAISampleRate is the rate of the pcm16 audio used by the Realtime API
+	OpenAISampleRate = 24000
+)
+
+// OpenAIClient opens sessions with the OpenAI Realtime API
+type OpenAIClient struct {
+	APIKey   string
+	Model    string
+	Voice    string
+	Language string // Transcription language as an ISO-639-1 code, empty to detect it
+	Endpoint string
+}
+
+// Ensure OpenAIClient implements Client interface
+var _ Client = (*OpenAIClient)(nil)
+
+// NewOpenAIClient creates a Realtime API client with the default endpoint, model and voice
+func NewOpenAIClient(apiKey string) *OpenAIClient {
+	return &OpenAIClient{
+		APIKey:   apiKey,
+		Model:    OpenAIRealtimeModel,
+		Voice:    OpenAIRealtimeVoice,
+		Endpoint: OpenAIRealtimeEndpoint,
+	}
+}
+
+// openAIEvent is the subset of client and server events used by the session
+type openAIEvent struct {
+	Type       string          `json:"type"`
+	Session    *openAISession  `json:"session,omitempty"`
+	Response   *openAIResponse `json:"response,omitempty"`
+	Audio      string          `json:"audio,omitempty"`
+	Delta      string          `json:"delta,omitempty"`
+	Transcript string          `json:"transcript,omitempty"`
+	Error      *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+type openAISession struct {
+	Modalities              []string             `json:"modalities,omitempty"`
+	Instructions            string               `json:"instructions,omitempty"`
+	Voice                   string               `json:"voice,omitempty"`
+	InputAudioFormat        string               `json:"input_audio_format,omitempty"`
+	OutputAudioFormat       string               `json:"output_audio_format,omitempty"`
+	InputAudioTranscription *openAITranscription `json:"input_audio_transcription,omitempty"`
+	TurnDetection           *openAITurnDetection `json:"turn_detection,omitempty"`
+}
+
+type openAITranscription struct {
+	Model    string `json:"model"`
+	Language string `json:"language,omitempty"`
+}
+
+type openAITurnDetection struct {
+	Type              string `json:"type"`
+	SilenceDurationMs int64  `json:"silence_duration_ms,omitempty"`
+}
+
+type openAIResponse struct {
+	Instructions string `json:"instructions,omitempty"`
+}
+
+// Connect opens a WebSocket session and configures audio, transcription and turn detection
+func (c *OpenAIClient) Connect(ctx context.Context, config SessionConfig) (Session, error) {
+	endpoint, err := url.Parse(c.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid realtime endpoint: %w", err)
+	}
+	query := endpoint.Query()
+	query.Set("model", c.Model)
+	endpoint.RawQuery = query.Encode()
+
+	origin := "http://" + endpoint.Host
+	if endpoint.Scheme == "wss" {
+		origin = "https://" + endpoint.Host
+	}
+
+	wsConfig, err := websocket.NewConfig(endpoint.String(), origin)
+	if err != nil {
+		return nil, fmt.Errorf("invalid realtime endpoint: %w", err)
+	}
+	wsConfig.Header.Set("Authorization", "Bearer "+c.APIKey)
+	wsConfig.Header.Set("OpenAI-Beta", "realtime=v1")
+	if id := correlation.Session(ctx); id != "" {
+		wsConfig.Header.Set(correlation.RequestIDHeader, id)
+	}
+
+	conn, err := wsConfig.DialContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to realtime API: %w", err)
+	}
+
+	session := &openAIRealtimeSession{
+		conn:   conn,
+		events: make(chan Event, 64),
+		done:   make(chan struct{}),
+	}
+
+	err = session.send(openAIEvent{
+		Type: "session.update",
+		Session: &openAISession{
+			Modalities:              []string{"audio", "text"},
+			Instructions:            config.Instructions,
+			Voice:                   c.Voice,
+			InputAudioFormat:        "pcm16",
+			OutputAudioFormat:       "pcm16",
+			InputAudioTranscription: &openAITranscription{Model: "whisper-1", Language: c.Language},
+			TurnDetection: &openAITurnDetection{
+				Type:              "server_vad",
+				SilenceDurationMs: config.SilenceDuration.Milliseconds(),
+			},
+		},
+	})
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	go session.read()
+	return session, nil
+}
+
+// openAIRealtimeSession is a session with the OpenAI Realtime API
+type openAIRealtimeSession struct {
+	conn       *websocket.Conn
+	writeMutex sync.Mutex
+	events     chan Event
+
+	// done is closed by Close, so the reader stops even when nobody
+	// consumes the events anymore
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// Ensure openAIRealtimeSession implements Session interface
+var _ Session = (*openAIRealtimeSession)(nil)
+
+// SampleRate returns the rate of the pcm16 audio format
+func (s *openAIRealtimeSession) SampleRate() int {
+	return OpenAISampleRate
+}
+
+// SendAudio appends the audio to the input buffer of the session
+func (s *openAIRealtimeSession) SendAudio(data []byte) error {
+	return s.send(openAIEvent{
+		Type:  "input_audio_buffer.append",
+		Audio: base64.StdEncoding.EncodeToString(data),
+	})
+}
+
+// Respond creates a response with instructions for this response only
+func (s *openAIRealtimeSession) Respond(instructions string) error {
+	return s.send(openAIEvent{
+		Type:     "response.create",
+		Response: &openAIResponse{Instructions: instructions},
+	})
+}
+
+// Cancel cancels the response in progress
+func (s *openAIRealtimeSession) Cancel() error {
+	return s.send(openAIEvent{Type: "response.cancel"})
+}
+
+// UpdateInstructions changes the session instructions
+func (s *openAIRealtimeSession) UpdateInstructions(instructions string) error {
+	return s.send(openAIEvent{
+		Type:    "session.update",
+		Session: &openAISession{Instructions: instructions},
+	})
+}
+
+// Events returns the channel of session events
+func (s *openAIRealtimeSession) Events() <-chan Event {
+	return s.events
+}
+
+// Close closes the WebSocket, the events channel is closed once reading stops
+func (s *openAIRealtimeSession) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		close(s.done)
+		err = s.conn.Close()
+	})
+	return err
+}
+
+// send writes one client event
+func (s *openAIRealtimeSession) send(event openAIEvent) error {
+	s.writeMutex.Lock()
+	defer s.writeMutex.Unlock()
+
+	if err := websocket.JSON.Send(s.conn, event); err != nil {
+		return fmt.Errorf("failed to send %s: %w", event.Type, err)
+	}
+	return nil
+}
+
+// read converts server events until the connection closes
+func (s *openAIRealtimeSession) read() {
+	defer close(s.events)
+
+	for {
+		var event openAIEvent
+		if err := websocket.JSON.Receive(s.conn, &event); err != nil {
+			if !errors.Is(err, io.EOF) {
+				s.emit(Event{Type: EventError, Err: fmt.Errorf("failed to read realtime event: %w", err)})
+			}
+			return
+		}
+
+		switch event.Type {
+		case "input_audio_buffer.speech_started":
+			if !s.emit(Event{Type: EventSpeechStarted}) {
+				return
+			}
+		case "conversation.item.input_audio_transcription.completed":
+			if !s.emit(Event{Type: EventTranscript, Text: strings.TrimSpace(event.Transcript)}) {
+				return
+			}
+		case "response.created":
+			if !s.emit(Event{Type: EventResponseStarted}) {
+				return
+			}
+		case "response.audio.delta":
+			audio, err := base64.StdEncoding.DecodeString(event.Delta)
+			if err != nil {
+				if !s.emit(Event{Type: EventError, Err: fmt.Errorf("failed to decode response audio: %w", err)}) {
+					return
+				}
+				continue
+			}
+			if !s.emit(Event{Type: EventAudio, Audio: audio}) {
+				return
+			}
+		case "response.audio_transcript.done":
+			if !s.emit(Event{Type: EventResponseText, Text: strings.TrimSpace(event.Transcript)}) {
+				return
+			}
+		case "response.done":
+			if !s.emit(Event{Type: EventResponseDone}) {
+				return
+			}
+		case "error":
+			message := "unknown error"
+			if event.Error != nil {
+				message = event.Error.Message
+			}
+			if !s.emit(Event{Type: EventError, Err: fmt.Errorf("realtime API error: %s", message)}) {
+				return
+			}
+		}
+	}
+}
+
+// emit hands an event to the consumer. It returns false once the session is
+// closed, the event is dropped then
+func (s *openAIRealtimeSession) emit(event Event) bool {
+	select {
+	case <-s.done:
+		return false
+	default:
+	}
+	select {
+	case s.events <- event:
+		return true
+	case <-s.done:
+		return false
+	}
+}
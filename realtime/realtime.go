@@ -0,0 +1,76 @@
+// Package realtime defines speech-to-speech sessions that recognize the
+// candidate, generate the response and synthesize it over one connection,
+// replacing the separate STT, GPT and TTS round trips
+package realtime
+
+import (
+	"context"
+	"time"
+)
+
+// EventType identifies what a session reported
+type EventType int
+
+const (
+	// EventSpeechStarted is sent when the candidate starts speaking
+	EventSpeechStarted EventType = iota
+	// EventTranscript carries the transcription of the candidate's turn in Text
+	EventTranscript
+	// EventResponseStarted is sent when the provider starts a response
+	EventResponseStarted
+	// EventAudio carries a chunk of the spoken response as 16-bit mono PCM in Audio
+	EventAudio
+	// EventResponseText carries the transcript of the spoken response in Text
+	EventResponseText
+	// EventResponseDone is sent when the response is complete
+	EventResponseDone
+	// EventError carries an error reported by the provider in Err, the session stays open
+	EventError
+)
+
+// Event is a message received from a session
+type Event struct {
+	Type  EventType
+	Text  string
+	Audio []byte
+	Err   error
+}
+
+// SessionConfig holds the conversation settings of a session
+type SessionConfig struct {
+	Instructions string
+
+	// SilenceDuration is the pause that ends the candidate's turn
+	SilenceDuration time.Duration
+}
+
+// Client opens realtime sessions
+type Client interface {
+	Connect(ctx context.Context, config SessionConfig) (Session, error)
+}
+
+// Session is one realtime conversation. The provider detects the end of the
+// candidate's turn and responds on its own
+type Session interface {
+	// SampleRate returns the rate of the mono PCM audio sent and received
+	SampleRate() int
+
+	// SendAudio streams 16-bit mono PCM from the microphone
+	SendAudio(data []byte) error
+
+	// Respond asks for a response without waiting for the candidate, following
+	// the given instructions for this response only
+	Respond(instructions string) error
+
+	// Cancel stops the response in progress, EventResponseDone still follows
+	Cancel() error
+
+	// UpdateInstructions replaces the instructions for the following responses
+	UpdateInstructions(instructions string) error
+
+	// Events returns the channel of session events, closed when the session ends
+	Events() <-chan Event
+
+	// Close ends the session
+	Close() error
+}
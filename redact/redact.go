@@ -0,0 +1,116 @@
+// Package redact removes names, contacts and other identifying details from
+// interview texts so transcripts can be shared outside of the hiring team
+package redact
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/d1nch8g/aihr/gpt"
+)
+
+// Redactor defines the interface for removing identifying details from text
+type Redactor interface {
+	Redact(text string) (string, error)
+}
+
+// Chain applies the redactors in order, each to the output of the previous one
+type Chain []Redactor
+
+// Ensure Chain implements Redactor interface
+var _ Redactor = Chain(nil)
+
+// Redact runs the text through every redactor of the chain
+func (c Chain) Redact(text string) (string, error) {
+	for _, redactor := range c {
+		var err error
+		if text, err = redactor.Redact(text); err != nil {
+			return "", err
+		}
+	}
+	return text, nil
+}
+
+// rules are the patterns of contact details replaced by RuleRedactor, in the
+// order they are applied so URLs are replaced before the emails inside them
+var rules = []struct {
+	pattern     *regexp.Regexp
+	placeholder string
+	minDigits   int // Matches with fewer digits are kept, e.g. "2015-2019" is not a phone number
+}{
+	{pattern: regexp.MustCompile(`(?i)\b(?:https?://|www\.)\S+`), placeholder: "[URL]"},
+	{pattern: regexp.MustCompile(`(?i)\b[a-z0-9._%+-]+@[a-z0-9.-]+\.[a-z]{2,}\b`), placeholder: "[EMAIL]"},
+	{pattern: regexp.MustCompile(`(?i)@[a-z0-9_]{3,}`), placeholder: "[HANDLE]"},
+	{pattern: regexp.MustCompile(`\+?\d[\d\s().-]{7,}\d`), placeholder: "[PHONE]", minDigits: 10},
+}
+
+// RuleRedactor replaces contact details matched by patterns: links, emails,
+// social handles and phone numbers. It is fast and deterministic but misses
+// names and employers, which GPTRedactor finds
+type RuleRedactor struct{}
+
+// Ensure RuleRedactor implements Redactor interface
+var _ Redactor = RuleRedactor{}
+
+// Redact replaces the contact details of the text with placeholders
+func (RuleRedactor) Redact(text string) (string, error) {
+	for _, rule := range rules {
+		text = rule.pattern.ReplaceAllStringFunc(text, func(match string) string {
+			if digits(match) < rule.minDigits {
+				return match
+			}
+			return rule.placeholder
+		})
+	}
+	return text, nil
+}
+
+// digits returns the number of digits in the text
+func digits(text string) int {
+	count := 0
+	for _, r := range text {
+		if r >= '0' && r <= '9' {
+			count++
+		}
+	}
+	return count
+}
+
+const redactionPrompt = `You anonymize interview transcripts for interviewer training and research.
+Rewrite the text replacing every detail that could identify the candidate or another person:
+names with [NAME], employers and clients with [COMPANY], schools and universities with [SCHOOL],
+cities, countries and addresses with [LOCATION], and other identifying details, like project or product
+names, dates of birth or ages, with [REDACTED]. Keep technologies, programming languages and everything
+else word for word. Reply with the rewritten text only.`
+
+// GPTRedactor replaces names, employers, schools and other identifying
+// details by asking the GPT model to rewrite the text
+type GPTRedactor struct {
+	client gpt.GPTClient
+}
+
+// Ensure GPTRedactor implements Redactor interface
+var _ Redactor = (*GPTRedactor)(nil)
+
+// NewGPTRedactor creates a new redactor backed by a GPT client
+func NewGPTRedactor(client gpt.GPTClient) *GPTRedactor {
+	return &GPTRedactor{client: client}
+}
+
+// Redact asks the model to rewrite the text without identifying details.
+// Empty text is returned as is without a request
+func (r *GPTRedactor) Redact(text string) (string, error) {
+	if strings.TrimSpace(text) == "" {
+		return text, nil
+	}
+	reply, err := r.client.Complete(redactionPrompt, text)
+	if err != nil {
+		return "", fmt.Errorf("failed to request redaction: %w", err)
+	}
+	reply = strings.TrimSpace(reply)
+	if reply == "" {
+		return "", fmt.Errorf("model returned an empty redaction")
+	}
+	return reply, nil
+}
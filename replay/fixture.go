@@ -0,0 +1,129 @@
+// Package replay runs recorded interview fixtures through the engine and
+// compares the resulting conversation with golden transcripts
+package replay
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FixtureFile and GoldenFile are the file names inside a fixture directory
+const (
+	FixtureFile = "fixture.json"
+	GoldenFile  = "golden.json"
+)
+
+// Utterance is a mock STT result emitted once the audio reaches At
+type Utterance struct {
+	At   Duration `json:"at"`
+	Text string   `json:"text"`
+}
+
+// Fixture describes a recorded interview. Audio is raw 16-bit mono PCM.
+// When Utterances are given they replace the real STT, and when Responses
+// are given they replace the real GPT
+type Fixture struct {
+	Audio          string      `json:"audio"`
+	SampleRate     float64     `json:"sample_rate"`
+	SilenceTimeout Duration    `json:"silence_timeout"`
+	Greeting       string      `json:"greeting,omitempty"`
+	Utterances     []Utterance `json:"utterances,omitempty"`
+	Responses      []string    `json:"responses,omitempty"`
+
+	dir string
+}
+
+// Turn is one candidate answer and the interviewer response to it
+type Turn struct {
+	Answer   string `json:"answer"`
+	Response string `json:"response"`
+}
+
+// Golden is the expected conversation of a fixture
+type Golden struct {
+	Turns []Turn `json:"turns"`
+}
+
+// Duration is a time.Duration encoded as a string like "1.5s" in JSON
+type Duration time.Duration
+
+// UnmarshalJSON parses durations like "1.5s"
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// MarshalJSON formats the duration like "1.5s"
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// LoadFixture reads the fixture description from a directory
+func LoadFixture(dir string) (*Fixture, error) {
+	var fixture Fixture
+	if err := readJSON(filepath.Join(dir, FixtureFile), &fixture); err != nil {
+		return nil, err
+	}
+	if fixture.Audio == "" {
+		return nil, fmt.Errorf("fixture %s has no audio", dir)
+	}
+	if fixture.SampleRate == 0 {
+		fixture.SampleRate = 16000
+	}
+	if fixture.SilenceTimeout == 0 {
+		fixture.SilenceTimeout = Duration(3 * time.Second)
+	}
+	fixture.dir = dir
+	return &fixture, nil
+}
+
+// AudioPath returns the path of the fixture audio
+func (f *Fixture) AudioPath() string {
+	if filepath.IsAbs(f.Audio) {
+		return f.Audio
+	}
+	return filepath.Join(f.dir, f.Audio)
+}
+
+// LoadGolden reads the golden transcript of a fixture directory
+func LoadGolden(dir string) (*Golden, error) {
+	var golden Golden
+	if err := readJSON(filepath.Join(dir, GoldenFile), &golden); err != nil {
+		return nil, err
+	}
+	return &golden, nil
+}
+
+// WriteGolden replaces the golden transcript of a fixture directory
+func WriteGolden(dir string, golden Golden) error {
+	data, err := json.MarshalIndent(golden, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode golden transcript: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, GoldenFile), append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write golden transcript: %w", err)
+	}
+	return nil
+}
+
+func readJSON(path string, value interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, value); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	return nil
+}
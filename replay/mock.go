@@ -0,0 +1,103 @@
+package replay
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/d1nch8g/aihr/gpt"
+	"github.com/d1nch8g/aihr/stt"
+	"github.com/d1nch8g/aihr/tts"
+)
+
+// MockSTT emits scripted utterances when the consumed audio reaches their
+// time, so turn taking behaves as with a live recognizer
+type MockSTT struct {
+	utterances []Utterance
+	sampleRate float64
+
+	consumed int // Bytes of audio consumed across all recognition streams
+	next     int
+	mutex    sync.Mutex
+}
+
+// Ensure MockSTT implements STTClient interface
+var _ stt.STTClient = (*MockSTT)(nil)
+
+// NewMockSTT creates a recognizer for 16-bit mono audio at sampleRate
+func NewMockSTT(utterances []Utterance, sampleRate float64) *MockSTT {
+	return &MockSTT{utterances: utterances, sampleRate: sampleRate}
+}
+
+// StreamRecognize consumes audio and sends the utterances that are due
+func (m *MockSTT) StreamRecognize(ctx context.Context, audioData <-chan []byte, results chan<- string, sampleRate int64) error {
+	defer close(results)
+
+	for chunk := range audioData {
+		for _, text := range m.consume(len(chunk)) {
+			select {
+			case results <- text:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+	return nil
+}
+
+// consume advances the audio position and returns the utterances that became due
+func (m *MockSTT) consume(bytes int) []string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.consumed += bytes
+	position := time.Duration(float64(m.consumed) / (m.sampleRate * 2) * float64(time.Second))
+
+	var due []string
+	for m.next < len(m.utterances) && time.Duration(m.utterances[m.next].At) <= position {
+		due = append(due, m.utterances[m.next].Text)
+		m.next++
+	}
+	return due
+}
+
+func (m *MockSTT) Close() error { return nil }
+
+// ScriptedGPT returns recorded responses in order
+type ScriptedGPT struct {
+	responses []string
+	next      int
+	mutex     sync.Mutex
+}
+
+// Ensure ScriptedGPT implements GPTClient interface
+var _ gpt.GPTClient = (*ScriptedGPT)(nil)
+
+// NewScriptedGPT creates a GPT client replaying responses
+func NewScriptedGPT(responses []string) *ScriptedGPT {
+	return &ScriptedGPT{responses: responses}
+}
+
+// Complete returns the next recorded response
+func (g *ScriptedGPT) Complete(systemMessage, userMessage string) (string, error) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if g.next >= len(g.responses) {
+		return "", fmt.Errorf("no recorded response left for %q", userMessage)
+	}
+	response := g.responses[g.next]
+	g.next++
+	return response, nil
+}
+
+// silentTTS produces no audio, responses are only recorded
+type silentTTS struct{}
+
+func (silentTTS) SynthesizeToStreamWithContext(ctx context.Context, text string, options tts.SynthesisOptions, audioData chan<- []byte) error {
+	close(audioData)
+	return nil
+}
+
+func (silentTTS) Close() error { return nil }
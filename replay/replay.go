@@ -0,0 +1,155 @@
+package replay
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/d1nch8g/aihr/audio"
+	"github.com/d1nch8g/aihr/engine"
+	"github.com/d1nch8g/aihr/gpt"
+	"github.com/d1nch8g/aihr/sound"
+	"github.com/d1nch8g/aihr/stt"
+)
+
+// Run plays the fixture audio through the engine in real time and returns
+// the resulting conversation. The mock STT and GPT of the fixture are used
+// unless sttClient or gptClient are given
+func Run(ctx context.Context, fixture *Fixture, sttClient stt.STTClient, gptClient gpt.GPTClient) ([]Turn, error) {
+	if sttClient == nil {
+		sttClient = NewMockSTT(fixture.Utterances, fixture.SampleRate)
+	}
+	if gptClient == nil {
+		gptClient = NewScriptedGPT(fixture.Responses)
+	}
+
+	file, err := os.Open(fixture.AudioPath())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open fixture audio: %w", err)
+	}
+	input := &eofReader{reader: file, done: make(chan struct{})}
+
+	streamConfig := audio.GetDefaultConfig()
+	streamConfig.SampleRate = fixture.SampleRate
+	playerConfig := sound.GetDefaultConfig()
+
+	silenceTimeout := time.Duration(fixture.SilenceTimeout)
+	interviewer, err := engine.New(
+		engine.WithConfig(engine.EngineConfig{
+			SampleRate:     int64(fixture.SampleRate),
+			SilenceTimeout: silenceTimeout,
+			Greeting:       fixture.Greeting,
+			MaxHistorySize: 1000,
+		}),
+		engine.WithAudioStreamer(audio.NewReaderStreamer(input, streamConfig, true)),
+		engine.WithSTT(sttClient),
+		engine.WithGPT(gptClient),
+		engine.WithTTS(silentTTS{}),
+		engine.WithPlayer(sound.NewWriterPlayer(io.Discard, playerConfig)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- interviewer.Start(runCtx)
+	}()
+
+	// Once the audio is exhausted, stop when no turn completed for a while
+	idle := 2*silenceTimeout + time.Second
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	turns, changedAt := 0, time.Now()
+	for {
+		select {
+		case err := <-done:
+			if err != nil && err != context.Canceled {
+				return nil, err
+			}
+			return collectTurns(interviewer), nil
+		case <-ctx.Done():
+			cancel()
+			<-done
+			return nil, ctx.Err()
+		case <-ticker.C:
+			if count := len(interviewer.GetHistory()); count != turns {
+				turns, changedAt = count, time.Now()
+			}
+			if input.finished() && time.Since(changedAt) > idle {
+				cancel()
+				<-done
+				return collectTurns(interviewer), nil
+			}
+		}
+	}
+}
+
+// Compare returns the differences between the golden and the actual turns.
+// Responses are only compared when they are scripted, because real model
+// output varies between runs
+func Compare(golden *Golden, turns []Turn, compareResponses bool) []string {
+	var diffs []string
+	if len(golden.Turns) != len(turns) {
+		diffs = append(diffs, fmt.Sprintf("expected %d turns, got %d", len(golden.Turns), len(turns)))
+	}
+
+	for i := 0; i < len(golden.Turns) && i < len(turns); i++ {
+		expected, actual := golden.Turns[i], turns[i]
+		if normalize(expected.Answer) != normalize(actual.Answer) {
+			diffs = append(diffs, fmt.Sprintf("turn %d answer: expected %q, got %q", i+1, expected.Answer, actual.Answer))
+		}
+		if compareResponses && normalize(expected.Response) != normalize(actual.Response) {
+			diffs = append(diffs, fmt.Sprintf("turn %d response: expected %q, got %q", i+1, expected.Response, actual.Response))
+		}
+	}
+	return diffs
+}
+
+func collectTurns(interviewer engine.Interviewer) []Turn {
+	var turns []Turn
+	for _, entry := range interviewer.GetHistory() {
+		turns = append(turns, Turn{Answer: normalize(entry.UserInput), Response: entry.AIResponse})
+	}
+	return turns
+}
+
+func normalize(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// eofReader reports when the underlying reader is exhausted
+type eofReader struct {
+	reader io.ReadCloser
+	done   chan struct{}
+	once   sync.Once
+}
+
+func (r *eofReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if err == io.EOF {
+		r.once.Do(func() { close(r.done) })
+	}
+	return n, err
+}
+
+func (r *eofReader) Close() error {
+	return r.reader.Close()
+}
+
+func (r *eofReader) finished() bool {
+	select {
+	case <-r.done:
+		return true
+	default:
+		return false
+	}
+}
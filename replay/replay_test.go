@@ -0,0 +1,34 @@
+package replay
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRunFixture(t *testing.T) {
+	if testing.Short() {
+		t.Skip("the fixture audio is replayed in real time")
+	}
+
+	dir := filepath.Join("testdata", "interview")
+	fixture, err := LoadFixture(dir)
+	if err != nil {
+		t.Fatalf("LoadFixture: %v", err)
+	}
+	golden, err := LoadGolden(dir)
+	if err != nil {
+		t.Fatalf("LoadGolden: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	turns, err := Run(ctx, fixture, nil, nil)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	for _, diff := range Compare(golden, turns, true) {
+		t.Error(diff)
+	}
+}
@@ -0,0 +1,56 @@
+package safety
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// AuditEntry records a blocked generation for legal review
+type AuditEntry struct {
+	Time          time.Time `json:"time"`
+	Category      string    `json:"category"`
+	Jurisdictions []string  `json:"jurisdictions,omitempty"`
+	Match         string    `json:"match"`
+	Response      string    `json:"response"`
+	UserInput     string    `json:"user_input"`
+	Attempt       int       `json:"attempt"`
+}
+
+// AuditLog appends blocked generations to a file as JSON lines
+type AuditLog struct {
+	file  *os.File
+	mutex sync.Mutex
+}
+
+// NewAuditLog opens the audit log for appending, creating it if needed
+func NewAuditLog(path string) (*AuditLog, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	return &AuditLog{file: file}, nil
+}
+
+// Record writes an entry to the audit log
+func (a *AuditLog) Record(entry AuditEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit entry: %w", err)
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if _, err := a.file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit entry: %w", err)
+	}
+	return nil
+}
+
+// Close closes the audit log file
+func (a *AuditLog) Close() error {
+	return a.file.Close()
+}
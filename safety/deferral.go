@@ -0,0 +1,39 @@
+package safety
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// deferred lists the subjects of candidate questions the interviewer must
+// not answer, only the recruiter can make commitments on them. The patterns
+// match questions, so an answer that mentions a salary is not deferred
+var deferred = []topic{
+	{"compensation", regexp.MustCompile(`(?i)\bwhat (is|s|'s|would be|will be) the (salary|pay|compensation|bonus|salary range|pay range)\b|\bhow much (does|will|would) (it|this|the (role|position|job)|you) pay\b|\b(salary|pay|compensation) (range|band)\b.*\?|\b(is there|are there|do you (offer|give|have)) (a |any )?(bonus|bonuses|equity|stock options|signing bonus)\b|какая (будет )?(зарплата|заработная плата|зарплатная вилка|вилка)|сколько (вы )?(платите|будете платить|получает)|какой (оклад|бонус)|есть ли (бонусы|опционы|премии)`)},
+	{"visa sponsorship", regexp.MustCompile(`(?i)\b(do|will|can|could) you (sponsor|help (me )?with|provide|offer) (a |the |my )?(visa|work permit|h-?1b|relocation)\b|\b(is there|do you offer|do you provide) (any )?(visa|work permit) (sponsorship|support)\b|\bsponsor (a |my |the )?(visa|work permit|h-?1b)\b|(делаете|оформляете|помогаете с|поможете с) (визу|визой|разрешени\S* на работу|релокаци\S*)|есть ли визов\S* поддержк|спонсир\S* (ли )?(визу|визы)`)},
+	{"legal terms", regexp.MustCompile(`(?i)\b(is there|do you have|what about|what is|what's) (a |the )?(non-?compete|non-?disclosure|nda|notice period|probation(ary)? period|severance)\b|\bwhat (are|is) the (terms of (the )?(contract|employment)|contract terms)\b|\bwho owns the (intellectual property|ip|code)\b|\bcan i see the contract\b|(какие|каковы) условия (трудового )?договора|есть ли (соглашение о неразглашении|nda|испытательный срок|неконкуренци\S*)|какой (испытательный срок|срок уведомления)`)},
+}
+
+// DeferralRules returns the rules matching candidate questions about
+// compensation, visa sponsorship and legal terms
+func DeferralRules() []Rule {
+	rules := make([]Rule, len(deferred))
+	for i, t := range deferred {
+		rules[i] = Rule{Category: t.category, Pattern: t.pattern}
+	}
+	return rules
+}
+
+// NewDeferralFilter creates a filter of the candidate's questions the
+// recruiter answers by name: "rules" for DeferralRules, "off" or an empty
+// name disables deferral and returns nil
+func NewDeferralFilter(name string) (Filter, error) {
+	switch name {
+	case "", "off":
+		return nil, nil
+	case "rules":
+		return NewRuleFilter(DeferralRules()), nil
+	default:
+		return nil, fmt.Errorf("unknown deferral filter %q", name)
+	}
+}
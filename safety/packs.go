@@ -0,0 +1,87 @@
+package safety
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// topic is a protected subject with patterns in English and Russian
+type topic struct {
+	category string
+	pattern  *regexp.Regexp
+}
+
+// topics lists every protected subject in the order rules are checked
+var topics = []topic{
+	{"age", regexp.MustCompile(`(?i)\bhow old are you\b|\byour age\b|\bwhen were you born\b|\byear of birth\b|сколько вам лет|ваш возраст|год рождения|в каком году вы родились`)},
+	{"religion", regexp.MustCompile(`(?i)\bwhat (is your )?religion\b|\byour (religion|religious|faith)\b|\bdo you (pray|go to church|attend (church|a mosque|mosque|synagogue|temple))\b|вероисповедани|ваша религия|вы верующ|ходите ли вы в (церковь|мечеть|синагогу)`)},
+	{"family plans", regexp.MustCompile(`(?i)\b(plan(ning)?|want|intend) to have (children|kids|a baby|a family)\b|\bare you (pregnant|married)\b|\bmarital status\b|\bdo you have (any )?(children|kids)\b|планируете (ли )?(вы )?(детей|ребенка|ребёнка)|вы замужем|вы женаты|есть ли у вас дети|беременн|семейное положение`)},
+	{"national origin", regexp.MustCompile(`(?i)\bwhere are you (originally )?from\b|\byour (nationality|ethnicity|race)\b|ваша национальность|какой вы национальности`)},
+	{"gender", regexp.MustCompile(`(?i)\bare you (a )?(man|woman|male|female)\b|\byour (gender|sex)\b|ваш пол(?:[^\p{L}]|$)`)},
+	{"health", regexp.MustCompile(`(?i)\b(do you have|any) (disabilit|health (condition|problem|issue)|chronic)|\bhave you ever been (sick|ill|hospitali[sz]ed)\b|инвалидност|состояние здоровья|хронические заболевания`)},
+	{"genetic information", regexp.MustCompile(`(?i)\bgenetic (test|information|condition)|\bfamily (medical|health) history\b|наследственн\S* заболевани`)},
+	{"sexual orientation", regexp.MustCompile(`(?i)\bsexual orientation\b|\bare you (gay|straight|lesbian)\b|сексуальн\S* ориентаци`)},
+	{"political views", regexp.MustCompile(`(?i)\byour political (views|opinions|affiliation|party)\b|\bwho did you vote for\b|политические взгляды|за кого вы голосовали|в какой партии`)},
+	{"trade union membership", regexp.MustCompile(`(?i)\b(trade|labou?r) union\b|\bunion member|профсоюз`)},
+	{"place of residence", regexp.MustCompile(`(?i)где вы прописаны|ваша прописка|место регистрации|есть ли у вас регистрация`)},
+	{"property", regexp.MustCompile(`(?i)\bdo you own (a|your) (home|house|flat|apartment|car)\b|есть ли у вас (своя )?(квартира|машина|недвижимость)`)},
+}
+
+// packs maps jurisdiction names to the categories they prohibit
+var packs = map[string][]string{
+	// Title VII, ADEA, ADA, GINA and the Pregnancy Discrimination Act
+	"us": {"age", "religion", "family plans", "national origin", "gender", "health", "genetic information", "sexual orientation"},
+	// Employment Equality Directive and GDPR special categories of personal data
+	"eu": {"age", "religion", "family plans", "national origin", "gender", "health", "genetic information", "sexual orientation", "political views", "trade union membership"},
+	// Labour Code of the Russian Federation, articles 3 and 64
+	"ru": {"age", "religion", "family plans", "national origin", "gender", "political views", "trade union membership", "place of residence", "property"},
+}
+
+// Packs returns the names of the available jurisdiction packs
+func Packs() []string {
+	names := make([]string, 0, len(packs))
+	for name := range packs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RulesForPacks returns the rules enforced by the union of the given
+// jurisdiction packs, or by every pack when no names are given
+func RulesForPacks(names []string) ([]Rule, error) {
+	if len(names) == 0 {
+		names = Packs()
+	}
+
+	jurisdictions := make(map[string][]string)
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		categories, ok := packs[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown jurisdiction pack %q", name)
+		}
+		for _, category := range categories {
+			jurisdictions[category] = append(jurisdictions[category], name)
+		}
+	}
+
+	var rules []Rule
+	for _, t := range topics {
+		if packNames, ok := jurisdictions[t.category]; ok {
+			rules = append(rules, Rule{Category: t.category, Jurisdictions: packNames, Pattern: t.pattern})
+		}
+	}
+	return rules, nil
+}
+
+// Categories returns the prohibited categories of the given rules
+func Categories(rules []Rule) []string {
+	categories := make([]string, len(rules))
+	for i, rule := range rules {
+		categories[i] = rule.Category
+	}
+	return categories
+}
@@ -0,0 +1,85 @@
+package safety
+
+import "testing"
+
+func TestRulesForPacks(t *testing.T) {
+	tests := []struct {
+		pack     string
+		text     string
+		category string // Empty when the text is allowed
+	}{
+		{"us", "How old are you?", "age"},
+		{"us", "Сколько вам лет?", "age"},
+		{"us", "Do you go to church on Sundays?", "religion"},
+		{"us", "Are you planning to have children soon?", "family plans"},
+		{"us", "Where are you originally from?", "national origin"},
+		{"us", "Are you a woman?", "gender"},
+		{"us", "Какой ваш пол?", "gender"},
+		{"us", "ваш пол", "gender"},
+		{"us", "Do you have any disability we should know about?", "health"},
+		{"us", "Did you take a genetic test?", "genetic information"},
+		{"us", "Are you gay?", "sexual orientation"},
+		{"us", "Who did you vote for?", ""},
+		{"us", "Are you a member of a trade union?", ""},
+		{"us", "Where did you work before?", ""},
+		{"us", "How old is the codebase you maintained?", ""},
+		{"us", "Ваш полный опыт работы с Go?", ""},
+
+		{"eu", "What is your year of birth?", "age"},
+		{"eu", "Ваша религия?", "religion"},
+		{"eu", "What is your marital status?", "family plans"},
+		{"eu", "Какой вы национальности?", "national origin"},
+		{"eu", "What is your gender?", "gender"},
+		{"eu", "Укажите ваш пол, пожалуйста.", "gender"},
+		{"eu", "Have you ever been hospitalized?", "health"},
+		{"eu", "Tell me about your family medical history.", "genetic information"},
+		{"eu", "What is your sexual orientation?", "sexual orientation"},
+		{"eu", "What are your political views?", "political views"},
+		{"eu", "Are you a union member?", "trade union membership"},
+		{"eu", "Где вы прописаны?", ""},
+		{"eu", "Do you own a car?", ""},
+		{"eu", "Which union types does Go support?", ""},
+		{"eu", "Ваш полис ОМС оформит отдел кадров.", ""},
+
+		{"ru", "В каком году вы родились?", "age"},
+		{"ru", "Вы верующий человек?", "religion"},
+		{"ru", "Есть ли у вас дети?", "family plans"},
+		{"ru", "Ваша национальность?", "national origin"},
+		{"ru", "Какой ваш пол?", "gender"},
+		{"ru", "Ваш пол — мужской?", "gender"},
+		{"ru", "За кого вы голосовали?", "political views"},
+		{"ru", "Вы состоите в профсоюзе?", "trade union membership"},
+		{"ru", "Есть ли у вас регистрация в Москве?", "place of residence"},
+		{"ru", "Есть ли у вас своя квартира?", "property"},
+		{"ru", "Есть ли у вас инвалидность?", ""},
+		{"ru", "Are you gay?", ""},
+		{"ru", "Расскажите про ваш полный рабочий день.", ""},
+		{"ru", "Какой у вас опыт с Kubernetes?", ""},
+	}
+
+	for _, test := range tests {
+		rules, err := RulesForPacks([]string{test.pack})
+		if err != nil {
+			t.Fatalf("RulesForPacks(%q): %v", test.pack, err)
+		}
+		verdict, err := NewRuleFilter(rules).Check(test.text)
+		if err != nil {
+			t.Fatalf("Check(%q): %v", test.text, err)
+		}
+		if test.category == "" {
+			if !verdict.Allowed {
+				t.Errorf("%s: %q blocked as %s by %q, want allowed", test.pack, test.text, verdict.Category, verdict.Match)
+			}
+			continue
+		}
+		if verdict.Allowed || verdict.Category != test.category {
+			t.Errorf("%s: %q gives category %q, want %q", test.pack, test.text, verdict.Category, test.category)
+		}
+	}
+}
+
+func TestRulesForPacksUnknown(t *testing.T) {
+	if _, err := RulesForPacks([]string{"mars"}); err == nil {
+		t.Error("RulesForPacks accepted an unknown pack")
+	}
+}
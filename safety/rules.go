@@ -0,0 +1,36 @@
+package safety
+
+import "regexp"
+
+// Rule blocks responses matching a pattern
+type Rule struct {
+	Category      string
+	Jurisdictions []string // Packs that prohibit the category
+	Pattern       *regexp.Regexp
+}
+
+// RuleFilter blocks responses that match any of its rules
+type RuleFilter struct {
+	rules []Rule
+}
+
+// NewRuleFilter creates a filter from the given rules
+func NewRuleFilter(rules []Rule) *RuleFilter {
+	return &RuleFilter{rules: rules}
+}
+
+// Check returns the first rule matched by the text
+func (f *RuleFilter) Check(text string) (Verdict, error) {
+	for _, rule := range f.rules {
+		if match := rule.Pattern.FindString(text); match != "" {
+			return Verdict{Category: rule.Category, Jurisdictions: rule.Jurisdictions, Match: match}, nil
+		}
+	}
+	return Verdict{Allowed: true}, nil
+}
+
+// DefaultRules returns the rules of every jurisdiction pack
+func DefaultRules() []Rule {
+	rules, _ := RulesForPacks(Packs())
+	return rules
+}
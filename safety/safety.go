@@ -0,0 +1,41 @@
+// Package safety checks AI responses for interview questions that are
+// discriminatory or legally risky before they are spoken
+package safety
+
+import "fmt"
+
+// Verdict is the outcome of checking a response
+type Verdict struct {
+	Allowed       bool
+	Category      string   // Topic of the violated rule, e.g. "age" or "religion"
+	Jurisdictions []string // Jurisdiction packs that prohibit the topic
+	Match         string   // Text that triggered the rule
+}
+
+// Filter defines the interface for moderating generated responses
+type Filter interface {
+	Check(text string) (Verdict, error)
+}
+
+// Auditor records blocked generations
+type Auditor interface {
+	Record(entry AuditEntry) error
+}
+
+// NewFilter creates a filter by name: "rules" for the rules of the given
+// jurisdiction packs (all packs when none are given), "off" or an empty name
+// disables filtering and returns nil
+func NewFilter(name string, jurisdictions []string) (Filter, error) {
+	switch name {
+	case "", "off":
+		return nil, nil
+	case "rules":
+		rules, err := RulesForPacks(jurisdictions)
+		if err != nil {
+			return nil, err
+		}
+		return NewRuleFilter(rules), nil
+	default:
+		return nil, fmt.Errorf("unknown safety filter %q", name)
+	}
+}
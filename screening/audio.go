@@ -0,0 +1,54 @@
+package screening
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/d1nch8g/aihr/pcm"
+	"github.com/d1nch8g/aihr/tts"
+)
+
+// readAudio returns the recording as raw 16-bit mono PCM at the sample rate.
+// WAV files in another format and other containers, e.g. video, are
+// converted with ffmpeg
+func readAudio(ctx context.Context, path string, sampleRate int64) ([]byte, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".pcm", ".raw":
+		return os.ReadFile(path)
+	case ".wav":
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		format, offset, err := tts.ParseWAVHeader(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		if format.BitsPerSample == 16 && int64(format.SampleRate) == sampleRate {
+			return pcm.Encode(pcm.ToMono(pcm.Decode(data[offset:]), format.Channels)), nil
+		}
+	}
+	return convertAudio(ctx, path, sampleRate)
+}
+
+// convertAudio decodes any audio or video file ffmpeg reads
+func convertAudio(ctx context.Context, path string, sampleRate int64) ([]byte, error) {
+	if _, err := exec.LookPath("ffmpeg"); err != nil {
+		return nil, fmt.Errorf("ffmpeg is required to read %s: %w", path, err)
+	}
+	cmd := exec.CommandContext(ctx, "ffmpeg", "-nostdin", "-loglevel", "error", "-i", path,
+		"-vn", "-f", "s16le", "-ac", "1", "-ar", strconv.FormatInt(sampleRate, 10), "-")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	data, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert %s: %w: %s", path, err, strings.TrimSpace(stderr.String()))
+	}
+	return data, nil
+}
@@ -0,0 +1,187 @@
+package screening
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/d1nch8g/aihr/analysis"
+	"github.com/d1nch8g/aihr/eval"
+	"github.com/d1nch8g/aihr/questions"
+	"github.com/d1nch8g/aihr/session"
+	"github.com/d1nch8g/aihr/stt"
+)
+
+// chunkSize is the size of the audio chunks streamed to the recognizer
+const chunkSize = 8192
+
+// Screener transcribes and scores the recorded answers of submissions
+type Screener struct {
+	STT        stt.STTClient
+	SampleRate int64
+
+	// Evaluator scores every answer, an answer to a question of the bank
+	// with a rubric of its own is scored on it when the evaluator supports that
+	Evaluator eval.Evaluator
+
+	// Analyzer scores the sentiment and confidence of every answer, optional
+	Analyzer analysis.Analyzer
+
+	// Bank resolves the question IDs of the recordings
+	Bank questions.Bank
+
+	// NormalizeTranscripts rewrites technical content of answers in written
+	// form, see stt.NormalizeTechnical
+	NormalizeTranscripts bool
+}
+
+// Evaluate transcribes and scores every answer of the submission and returns
+// the session record. It starts when the answers were submitted and has no
+// end, a screening has no duration. An answer that cannot be scored is kept
+// without a score
+func (s *Screener) Evaluate(ctx context.Context, submission *Submission) (session.Record, error) {
+	record := session.Record{
+		ID:        session.NewID(),
+		StartedAt: submission.SubmittedAt,
+		Mode:      session.ModeScreening,
+	}
+	if record.StartedAt.IsZero() {
+		record.StartedAt = time.Now()
+	}
+
+	for i, recording := range submission.Answers {
+		question, asked, err := s.question(recording)
+		if err != nil {
+			return record, fmt.Errorf("answer %d: %w", i+1, err)
+		}
+		text, words, err := s.transcribe(ctx, submission.AudioPath(recording))
+		if err != nil {
+			return record, fmt.Errorf("answer %d: %w", i+1, err)
+		}
+		if s.NormalizeTranscripts {
+			text = stt.NormalizeTechnical(text)
+		}
+		log.Printf("Answer %d: %s", i+1, text)
+
+		answer := session.Answer{
+			Question:   question,
+			Text:       text,
+			AnsweredAt: record.StartedAt,
+			Fluency:    analysis.AnalyzeFluency(text, words),
+		}
+		if asked != nil {
+			answer.QuestionID, answer.Topic = asked.ID, asked.Topic
+		}
+		if strings.TrimSpace(text) != "" {
+			s.score(&answer, asked)
+		}
+		record.Answers = append(record.Answers, answer)
+	}
+
+	return record, nil
+}
+
+// question returns the text of the question of a recording and its question
+// of the bank, nil when the recording has the text only
+func (s *Screener) question(recording Recording) (string, *questions.Question, error) {
+	if recording.QuestionID == "" {
+		return recording.Question, nil, nil
+	}
+	for _, question := range s.Bank {
+		if question.ID == recording.QuestionID {
+			text := recording.Question
+			if text == "" {
+				text = question.Text
+			}
+			return text, &question, nil
+		}
+	}
+	return "", nil, fmt.Errorf("question %q is not in the question bank", recording.QuestionID)
+}
+
+// transcribe recognizes the recording. Word timings are returned when the
+// recognizer reports them
+func (s *Screener) transcribe(ctx context.Context, path string) (string, []stt.Word, error) {
+	audio, err := readAudio(ctx, path, s.SampleRate)
+	if err != nil {
+		return "", nil, err
+	}
+
+	audioData := make(chan []byte, len(audio)/chunkSize+1)
+	for len(audio) > 0 {
+		chunk := audio[:min(chunkSize, len(audio))]
+		audio = audio[len(chunk):]
+		audioData <- chunk
+	}
+	close(audioData)
+
+	results := make(chan stt.Utterance, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- s.recognize(ctx, audioData, results)
+	}()
+
+	var answer stt.Assembler
+	for utterance := range results {
+		answer.Add(utterance)
+	}
+	if err := <-done; err != nil {
+		return "", nil, fmt.Errorf("failed to transcribe %s: %w", path, err)
+	}
+	return answer.Text(), answer.Words(), nil
+}
+
+// recognize streams the audio to the recognizer, the results channel is
+// closed when recognition ends
+func (s *Screener) recognize(ctx context.Context, audioData <-chan []byte, results chan<- stt.Utterance) error {
+	if recognizer, ok := s.STT.(stt.WordRecognizer); ok {
+		return recognizer.StreamRecognizeWords(ctx, audioData, results, s.SampleRate)
+	}
+
+	texts := make(chan string, cap(results))
+	go func() {
+		defer close(results)
+		for text := range texts {
+			results <- stt.Utterance{Text: text}
+		}
+	}()
+	return s.STT.StreamRecognize(ctx, audioData, texts, s.SampleRate)
+}
+
+// score assesses the answer on the rubric of the evaluator, or on the rubric
+// of its question of the bank when it has one
+func (s *Screener) score(answer *session.Answer, asked *questions.Question) {
+	if s.Evaluator != nil {
+		var assessment eval.Assessment
+		var err error
+		questionEvaluator, scoresOnQuestion := s.Evaluator.(eval.QuestionRubricEvaluator)
+		if evaluator, ok := s.Evaluator.(eval.RubricEvaluator); ok {
+			if asked != nil && len(asked.Rubric) > 0 && scoresOnQuestion {
+				assessment, err = questionEvaluator.AssessAnswerOn(asked.Rubric, answer.Question, answer.Text)
+			} else {
+				assessment, err = evaluator.AssessAnswer(answer.Question, answer.Text)
+			}
+		} else {
+			assessment.Score, err = s.Evaluator.ScoreAnswer(answer.Question, answer.Text)
+		}
+		if err != nil {
+			log.Printf("Failed to score answer: %v", err)
+		} else {
+			answer.Score = &assessment.Score
+			if len(assessment.Dimensions) > 0 {
+				answer.Assessment = &assessment
+			}
+		}
+	}
+
+	if s.Analyzer != nil {
+		sentiment, err := s.Analyzer.AnalyzeAnswer(answer.Question, answer.Text)
+		if err != nil {
+			log.Printf("Failed to analyze answer: %v", err)
+		} else {
+			answer.Sentiment = &sentiment
+		}
+	}
+}
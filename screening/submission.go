@@ -0,0 +1,70 @@
+// Package screening evaluates recorded answers to fixed questions, for
+// asynchronous screenings without a live dialogue. The answers are
+// transcribed and scored like the answers of an interview and kept in the
+// same session record, so they get the same report
+package screening
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// SubmissionFile is the file name of the submission inside its directory
+const SubmissionFile = "screening.json"
+
+// Recording is the recorded answer to one question. The question is its
+// text or the ID of a question of the bank
+type Recording struct {
+	Question   string `json:"question,omitempty"`
+	QuestionID string `json:"question_id,omitempty"`
+
+	// Audio is a WAV file, raw 16-bit mono PCM at the sample rate of the
+	// screener with the .pcm or .raw extension, or any audio or video file
+	// ffmpeg reads
+	Audio string `json:"audio"`
+}
+
+// Submission is the set of recorded answers a candidate uploaded
+type Submission struct {
+	SubmittedAt time.Time   `json:"submitted_at,omitempty"`
+	Answers     []Recording `json:"answers"`
+
+	dir string
+}
+
+// LoadSubmission reads the submission description from a directory
+func LoadSubmission(dir string) (*Submission, error) {
+	path := filepath.Join(dir, SubmissionFile)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	var submission Submission
+	if err := json.Unmarshal(data, &submission); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	if len(submission.Answers) == 0 {
+		return nil, fmt.Errorf("submission %s has no answers", dir)
+	}
+	for i, answer := range submission.Answers {
+		if answer.Audio == "" {
+			return nil, fmt.Errorf("answer %d of submission %s has no audio", i+1, dir)
+		}
+		if answer.Question == "" && answer.QuestionID == "" {
+			return nil, fmt.Errorf("answer %d of submission %s has no question", i+1, dir)
+		}
+	}
+	submission.dir = dir
+	return &submission, nil
+}
+
+// AudioPath returns the path of the audio of a recording
+func (s *Submission) AudioPath(recording Recording) string {
+	if filepath.IsAbs(recording.Audio) {
+		return recording.Audio
+	}
+	return filepath.Join(s.dir, recording.Audio)
+}
@@ -0,0 +1,96 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/d1nch8g/aihr/sigv4"
+)
+
+// AWSConfig holds the settings for reading an AWS Secrets Manager secret
+type AWSConfig struct {
+	Region          string
+	SecretID        string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// AWSProvider reads a JSON key/value secret from AWS Secrets Manager
+type AWSProvider struct {
+	config     AWSConfig
+	httpClient *http.Client
+}
+
+// Ensure AWSProvider implements Provider interface
+var _ Provider = (*AWSProvider)(nil)
+
+// NewAWSProvider creates a new AWS Secrets Manager provider
+func NewAWSProvider(config AWSConfig) (*AWSProvider, error) {
+	if config.Region == "" || config.SecretID == "" {
+		return nil, fmt.Errorf("AWS_REGION and AWS_SECRET_ID must be set")
+	}
+	if config.AccessKeyID == "" || config.SecretAccessKey == "" {
+		return nil, fmt.Errorf("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY must be set")
+	}
+
+	return &AWSProvider{
+		config:     config,
+		httpClient: &http.Client{Timeout: requestTimeout},
+	}, nil
+}
+
+func (p *AWSProvider) Name() string {
+	return "aws"
+}
+
+func (p *AWSProvider) Fetch(ctx context.Context) (map[string]string, error) {
+	body, err := json.Marshal(map[string]string{"SecretId": p.config.SecretID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("https://secretsmanager.%s.amazonaws.com/", p.config.Region)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+
+	sigv4.Sign(req, sigv4.HashHex(body), sigv4.Credentials{
+		AccessKeyID:     p.config.AccessKeyID,
+		SecretAccessKey: p.config.SecretAccessKey,
+		SessionToken:    p.config.SessionToken,
+	}, p.config.Region, "secretsmanager", time.Now())
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("secrets manager request failed with status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var response struct {
+		SecretString string `json:"SecretString"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	values := make(map[string]string)
+	if err := json.Unmarshal([]byte(response.SecretString), &values); err != nil {
+		return nil, fmt.Errorf("secret must be a JSON object of string values: %w", err)
+	}
+
+	return values, nil
+}
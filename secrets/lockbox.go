@@ -0,0 +1,118 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+const (
+	LockboxPayloadEndpoint = "https://payload.lockbox.api.cloud.yandex.net/lockbox/v1/secrets/"
+
+	// Token endpoint of the Yandex Cloud VM metadata service, used when no IAM token is configured
+	MetadataTokenEndpoint = "http://169.254.169.254/computeMetadata/v1/instance/service-accounts/default/token"
+)
+
+// LockboxConfig holds the settings for reading a Yandex Lockbox secret
+type LockboxConfig struct {
+	SecretID string
+	IamToken string // Optional, the VM service account token is used when empty
+}
+
+// LockboxProvider reads secret entries from Yandex Lockbox
+type LockboxProvider struct {
+	config     LockboxConfig
+	httpClient *http.Client
+}
+
+// Ensure LockboxProvider implements Provider interface
+var _ Provider = (*LockboxProvider)(nil)
+
+// NewLockboxProvider creates a new Yandex Lockbox secrets provider
+func NewLockboxProvider(config LockboxConfig) (*LockboxProvider, error) {
+	if config.SecretID == "" {
+		return nil, fmt.Errorf("LOCKBOX_SECRET_ID must be set")
+	}
+
+	return &LockboxProvider{
+		config:     config,
+		httpClient: &http.Client{Timeout: requestTimeout},
+	}, nil
+}
+
+func (p *LockboxProvider) Name() string {
+	return "lockbox"
+}
+
+func (p *LockboxProvider) Fetch(ctx context.Context) (map[string]string, error) {
+	token := p.config.IamToken
+	if token == "" {
+		metadataToken, err := p.metadataToken(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get service account token: %w", err)
+		}
+		token = metadataToken
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, LockboxPayloadEndpoint+p.config.SecretID+"/payload", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	var response struct {
+		Entries []struct {
+			Key       string `json:"key"`
+			TextValue string `json:"textValue"`
+		} `json:"entries"`
+	}
+	if err := p.doJSON(req, &response); err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string, len(response.Entries))
+	for _, entry := range response.Entries {
+		values[entry.Key] = entry.TextValue
+	}
+
+	return values, nil
+}
+
+// metadataToken requests an IAM token for the service account attached to the VM
+func (p *LockboxProvider) metadataToken(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, MetadataTokenEndpoint, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+
+	var response struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := p.doJSON(req, &response); err != nil {
+		return "", err
+	}
+
+	return response.AccessToken, nil
+}
+
+func (p *LockboxProvider) doJSON(req *http.Request, out interface{}) error {
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+)
+
+// requestTimeout bounds every request to a secrets provider, so a stalled
+// connection does not hold up the next refresh
+const requestTimeout = 30 * time.Second
+
+// Provider defines the interface for secret storage backends
+type Provider interface {
+	// Fetch returns the secret values keyed by variable name, e.g. IAM_TOKEN
+	Fetch(ctx context.Context) (map[string]string, error)
+
+	// Name returns the provider name for logging
+	Name() string
+}
+
+// NewProviderFromEnv creates the provider selected by SECRETS_PROVIDER.
+// It returns nil when no provider is configured
+func NewProviderFromEnv() (Provider, error) {
+	switch name := strings.ToLower(os.Getenv("SECRETS_PROVIDER")); name {
+	case "":
+		return nil, nil
+	case "vault":
+		return NewVaultProvider(VaultConfig{
+			Address: os.Getenv("VAULT_ADDR"),
+			Token:   os.Getenv("VAULT_TOKEN"),
+			Path:    os.Getenv("VAULT_SECRET_PATH"),
+		})
+	case "aws":
+		return NewAWSProvider(AWSConfig{
+			Region:          os.Getenv("AWS_REGION"),
+			SecretID:        os.Getenv("AWS_SECRET_ID"),
+			AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		})
+	case "lockbox":
+		return NewLockboxProvider(LockboxConfig{
+			SecretID: os.Getenv("LOCKBOX_SECRET_ID"),
+			IamToken: os.Getenv("LOCKBOX_IAM_TOKEN"),
+		})
+	default:
+		return nil, fmt.Errorf("unknown secrets provider: %s", name)
+	}
+}
+
+// Apply fetches secrets from the provider and exports them as environment variables
+func Apply(ctx context.Context, provider Provider) (map[string]string, error) {
+	values, err := provider.Fetch(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch secrets from %s: %w", provider.Name(), err)
+	}
+
+	for key, value := range values {
+		if err := os.Setenv(key, value); err != nil {
+			return nil, fmt.Errorf("failed to set %s: %w", key, err)
+		}
+	}
+
+	return values, nil
+}
+
+// Refresh periodically re-applies secrets from the provider and calls onUpdate
+// after every successful refresh. It blocks until the context is cancelled
+func Refresh(ctx context.Context, provider Provider, interval time.Duration, onUpdate func(map[string]string)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			applyCtx, cancel := context.WithTimeout(ctx, requestTimeout)
+			values, err := Apply(applyCtx, provider)
+			cancel()
+			if err != nil {
+				log.Printf("Secrets refresh error: %v", err)
+				continue
+			}
+			if onUpdate != nil {
+				onUpdate(values)
+			}
+		}
+	}
+}
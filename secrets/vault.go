@@ -0,0 +1,89 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// VaultConfig holds the settings for reading a HashiCorp Vault KV secret
+type VaultConfig struct {
+	Address string // e.g. https://vault.example.com:8200
+	Token   string
+	Path    string // e.g. secret/data/aihr for KV v2
+}
+
+// VaultProvider reads secrets from a Vault KV v1 or v2 engine
+type VaultProvider struct {
+	config     VaultConfig
+	httpClient *http.Client
+}
+
+// Ensure VaultProvider implements Provider interface
+var _ Provider = (*VaultProvider)(nil)
+
+// NewVaultProvider creates a new Vault secrets provider
+func NewVaultProvider(config VaultConfig) (*VaultProvider, error) {
+	if config.Address == "" || config.Token == "" || config.Path == "" {
+		return nil, fmt.Errorf("VAULT_ADDR, VAULT_TOKEN and VAULT_SECRET_PATH must be set")
+	}
+
+	return &VaultProvider{
+		config:     config,
+		httpClient: &http.Client{Timeout: requestTimeout},
+	}, nil
+}
+
+func (p *VaultProvider) Name() string {
+	return "vault"
+}
+
+func (p *VaultProvider) Fetch(ctx context.Context) (map[string]string, error) {
+	url := strings.TrimRight(p.config.Address, "/") + "/v1/" + strings.TrimLeft(p.config.Path, "/")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", p.config.Token)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("vault request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var response struct {
+		Data map[string]json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	// KV v2 nests the values under data.data, KV v1 keeps them under data
+	data := response.Data
+	if nested, ok := response.Data["data"]; ok {
+		if err := json.Unmarshal(nested, &data); err != nil {
+			return nil, fmt.Errorf("failed to decode KV v2 data: %w", err)
+		}
+	}
+
+	values := make(map[string]string, len(data))
+	for key, raw := range data {
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil {
+			continue // Skip non-string values like metadata
+		}
+		values[key] = value
+	}
+
+	return values, nil
+}
@@ -0,0 +1,80 @@
+package session
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/d1nch8g/aihr/eval"
+	"github.com/d1nch8g/aihr/redact"
+)
+
+// Anonymize returns a copy of the record that can be shared outside of the
+// hiring team. The questions, answers and notes are redacted, the session ID
+// is replaced with a pseudonym and the times are moved to the midnight of the
+// interview day, keeping the offsets of the answers. Data that links to
+// people or other sessions, like human scores, duplicates, embeddings and
+// the signature, is left out
+func Anonymize(record Record, redactor redact.Redactor) (Record, error) {
+	sum := sha256.Sum256([]byte(record.ID))
+	day := time.Date(record.StartedAt.Year(), record.StartedAt.Month(), record.StartedAt.Day(), 0, 0, 0, 0, time.UTC)
+	shift := record.StartedAt.Sub(day)
+
+	anonymized := Record{
+		ID:         "anon-" + hex.EncodeToString(sum[:6]),
+		StartedAt:  day,
+		Outcome:    record.Outcome,
+		Experiment: record.Experiment,
+		Variant:    record.Variant,
+		Prompts:    record.Prompts,
+		Stalls:     record.Stalls,
+	}
+	if !record.EndedAt.IsZero() {
+		anonymized.EndedAt = record.EndedAt.Add(-shift)
+	}
+
+	for i, answer := range record.Answers {
+		question, err := redactor.Redact(answer.Question)
+		if err != nil {
+			return Record{}, fmt.Errorf("failed to anonymize question %d: %w", i+1, err)
+		}
+		text, err := redactor.Redact(answer.Text)
+		if err != nil {
+			return Record{}, fmt.Errorf("failed to anonymize answer %d: %w", i+1, err)
+		}
+
+		answer.Question = question
+		answer.Text = text
+		answer.AnsweredAt = answer.AnsweredAt.Add(-shift)
+		answer.Embedding = nil
+		answer.Duplicate = nil
+		if answer.Assessment != nil {
+			// The evidence quotes the original answer
+			assessment := *answer.Assessment
+			assessment.Dimensions = make([]eval.DimensionScore, len(answer.Assessment.Dimensions))
+			for j, dimension := range answer.Assessment.Dimensions {
+				dimension.Evidence = []string{}
+				assessment.Dimensions[j] = dimension
+			}
+			answer.Assessment = &assessment
+		}
+		anonymized.Answers = append(anonymized.Answers, answer)
+	}
+
+	for _, stage := range record.Notes {
+		for _, notes := range []*[]string{&stage.Claims, &stage.Skills, &stage.RedFlags} {
+			redacted := make([]string, len(*notes))
+			for i, note := range *notes {
+				var err error
+				if redacted[i], err = redactor.Redact(note); err != nil {
+					return Record{}, fmt.Errorf("failed to anonymize notes of stage %d: %w", stage.Stage, err)
+				}
+			}
+			*notes = redacted
+		}
+		stage.TakenAt = stage.TakenAt.Add(-shift)
+		anonymized.Notes = append(anonymized.Notes, stage)
+	}
+	return anonymized, nil
+}
@@ -0,0 +1,66 @@
+package session
+
+import (
+	"strings"
+
+	"github.com/d1nch8g/aihr/embed"
+)
+
+// MinDuplicateWords is the shortest answer checked for duplicates,
+// short answers like "yes" are naturally similar across candidates
+const MinDuplicateWords = 8
+
+// Duplicate points to a nearly identical answer of another candidate
+type Duplicate struct {
+	SessionID   string  `json:"session_id"`
+	AnswerIndex int     `json:"answer_index"`
+	Similarity  float64 `json:"similarity"`
+}
+
+// Embed computes the embeddings of answers long enough for duplicate detection
+func Embed(record *Record, embedder embed.Embedder) error {
+	for i := range record.Answers {
+		answer := &record.Answers[i]
+		if answer.Embedding != nil || answer.Unclear || len(strings.Fields(answer.Text)) < MinDuplicateWords {
+			continue
+		}
+		embedding, err := embedder.Embed(answer.Text)
+		if err != nil {
+			return err
+		}
+		answer.Embedding = embedding
+	}
+	return nil
+}
+
+// FindDuplicates marks answers whose similarity to an answer of another
+// session is at least threshold, keeping the most similar match
+func FindDuplicates(record *Record, others []Record, threshold float64) int {
+	found := 0
+	for i := range record.Answers {
+		answer := &record.Answers[i]
+		if answer.Embedding == nil {
+			continue
+		}
+
+		answer.Duplicate = nil
+		for _, other := range others {
+			if other.ID == record.ID {
+				continue
+			}
+			for j, otherAnswer := range other.Answers {
+				similarity := embed.CosineSimilarity(answer.Embedding, otherAnswer.Embedding)
+				if similarity < threshold {
+					continue
+				}
+				if answer.Duplicate == nil || similarity > answer.Duplicate.Similarity {
+					answer.Duplicate = &Duplicate{SessionID: other.ID, AnswerIndex: j, Similarity: similarity}
+				}
+			}
+		}
+		if answer.Duplicate != nil {
+			found++
+		}
+	}
+	return found
+}
@@ -0,0 +1,144 @@
+package session
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Types of engine events
+const (
+	EventSessionStarted    = "session.started"
+	EventSessionEnded      = "session.ended"
+	EventSpeechStarted     = "speech.started"    // Voice activity detected
+	EventSpeechRecognized  = "speech.recognized" // A final recognition result arrived
+	EventTurnEnded         = "turn.ended"        // The silence timeout ended the answer
+	EventTurnFailed        = "turn.failed"       // The turn panicked and was abandoned
+	EventTurnStalled       = "turn.stalled"      // The watchdog canceled a turn without progress
+	EventCommand           = "command"
+	EventAnswerUnclear     = "answer.unclear"
+	EventResponseGenerated = "response.generated"
+	EventResponseBlocked   = "response.blocked"
+	EventDifficulty        = "difficulty.changed"
+	EventTTSRetry          = "tts.retry"
+	EventTTSFallback       = "tts.fallback"
+	EventSTTFallback       = "stt.fallback"
+	EventInputLost         = "input.lost"
+	EventInputRestored     = "input.restored"
+	EventInstruction       = "instruction"
+	EventClosing           = "closing.requested"
+	EventNotes             = "notes.taken"       // Interim notes on a stage of the interview
+	EventQuestionPlanned   = "question.planned"  // The next question was picked from the bank
+	EventFollowUpLimit     = "followup.limit"    // The model is told to leave the topic
+	EventQuestionDeferred  = "question.deferred" // A candidate question was left to the recruiter
+	EventTimeAnnounced     = "time.announced"    // The candidate was told the time left
+	EventAborted           = "session.aborted"   // The candidate confirmed they have to stop
+	EventAbortCanceled     = "abort.canceled"    // The candidate chose to continue after all
+	EventPersonaHandoff    = "persona.handoff"   // The next interviewer of the panel took over
+	EventVoiceReplaced     = "voice.replaced"    // A cloned voice failed, the stock voice speaks instead
+)
+
+// Event is one decision or observation of the engine during a session
+type Event struct {
+	Time    time.Time `json:"time"`
+	Session string    `json:"session"`
+	Seq     int       `json:"seq"`
+	Type    string    `json:"type"`
+	Detail  string    `json:"detail,omitempty"`
+}
+
+// EventLog defines the interface for the append-only event log of sessions
+type EventLog interface {
+	// AppendEvent adds an event to the log of its session
+	AppendEvent(event Event) error
+
+	// Events returns the events of a session in the order they were recorded
+	Events(id string) ([]Event, error)
+}
+
+// Ensure FileStore implements EventLog interface
+var _ EventLog = (*FileStore)(nil)
+
+// eventsMutex serializes appends, events of one session come from several goroutines
+var eventsMutex sync.Mutex
+
+// AppendEvent appends the event to the session's events file as a JSON line
+func (s *FileStore) AppendEvent(event Event) error {
+	if event.Session == "" {
+		return fmt.Errorf("session ID is required")
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	eventsMutex.Lock()
+	defer eventsMutex.Unlock()
+
+	file, err := os.OpenFile(s.eventsPath(event.Session), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open events: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write event: %w", err)
+	}
+	return nil
+}
+
+// Events reads the events file of a session
+func (s *FileStore) Events(id string) ([]Event, error) {
+	file, err := os.Open(s.eventsPath(id))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read events: %w", err)
+	}
+	defer file.Close()
+
+	var events []Event
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		var event Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return nil, fmt.Errorf("failed to decode event %d of session %s: %w", len(events)+1, id, err)
+		}
+		events = append(events, event)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read events: %w", err)
+	}
+	return events, nil
+}
+
+// eventsPath returns the events file of a session
+func (s *FileStore) eventsPath(id string) string {
+	return filepath.Join(s.dir, filepath.Base(id)+".events.jsonl")
+}
+
+// WriteEvents prints the events as a timeline relative to the first one
+func WriteEvents(w io.Writer, events []Event) {
+	if len(events) == 0 {
+		return
+	}
+	start := events[0].Time
+	for _, event := range events {
+		offset := event.Time.Sub(start).Round(time.Millisecond)
+		if event.Detail != "" {
+			fmt.Fprintf(w, "%4d %10s  %-19s %s\n", event.Seq, offset, event.Type, event.Detail)
+		} else {
+			fmt.Fprintf(w, "%4d %10s  %s\n", event.Seq, offset, event.Type)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package session
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/d1nch8g/aihr/eval"
+)
+
+// OverallDimension is the dimension of human scores rating the interview as a
+// whole, it is compared with the average AI score of the answers
+const OverallDimension = "overall"
+
+// HumanScore is the rating of a candidate by a human interviewer, used to
+// calibrate the AI evaluation
+type HumanScore struct {
+	Interviewer string             `json:"interviewer"`
+	Scores      map[string]float64 `json:"scores"` // By rubric dimension or OverallDimension
+	ScoredAt    time.Time          `json:"scored_at"`
+}
+
+// SetHumanScore adds the scores of an interviewer to the record, replacing
+// the ones they gave before
+func (r *Record) SetHumanScore(score HumanScore) {
+	for i := range r.HumanScores {
+		if r.HumanScores[i].Interviewer == score.Interviewer {
+			r.HumanScores[i] = score
+			return
+		}
+	}
+	r.HumanScores = append(r.HumanScores, score)
+}
+
+// RubricScores returns the average AI score of every rubric dimension over
+// the assessed answers, empty when no answer was assessed
+func (r Record) RubricScores() map[string]float64 {
+	totals := make(map[string]float64)
+	counts := make(map[string]int)
+	for _, answer := range r.Answers {
+		if answer.Assessment == nil {
+			continue
+		}
+		for _, dimension := range answer.Assessment.Dimensions {
+			totals[dimension.Name] += dimension.Score
+			counts[dimension.Name]++
+		}
+	}
+	for name := range totals {
+		totals[name] /= float64(counts[name])
+	}
+	return totals
+}
+
+// ReadHumanScores reads human scores from CSV with the columns session,
+// interviewer, dimension and score, one row per dimension. It returns the
+// scores by session ID
+func ReadHumanScores(r io.Reader, scoredAt time.Time) (map[string][]HumanScore, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 4
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("human scores are empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read human scores: %w", err)
+	}
+	if strings.ToLower(strings.Join(header, ",")) != "session,interviewer,dimension,score" {
+		return nil, fmt.Errorf("human scores must have the columns session, interviewer, dimension and score")
+	}
+
+	type key struct{ session, interviewer string }
+	scores := make(map[key]*HumanScore)
+	var order []key
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read human scores: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		id, interviewer, dimension := row[0], row[1], strings.ToLower(row[2])
+		if id == "" || interviewer == "" || dimension == "" {
+			return nil, fmt.Errorf("line %d: session, interviewer and dimension are required", line)
+		}
+		value, err := strconv.ParseFloat(row[3], 64)
+		if err != nil || value < eval.MinScore || value > eval.MaxScore {
+			return nil, fmt.Errorf("line %d: score must be a number from %g to %g", line, eval.MinScore, eval.MaxScore)
+		}
+
+		k := key{id, interviewer}
+		score, ok := scores[k]
+		if !ok {
+			score = &HumanScore{Interviewer: interviewer, Scores: make(map[string]float64), ScoredAt: scoredAt}
+			scores[k] = score
+			order = append(order, k)
+		}
+		score.Scores[dimension] = value
+	}
+
+	bySession := make(map[string][]HumanScore)
+	for _, k := range order {
+		bySession[k.session] = append(bySession[k.session], *scores[k])
+	}
+	return bySession, nil
+}
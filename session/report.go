@@ -0,0 +1,182 @@
+package session
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/d1nch8g/aihr/analysis"
+)
+
+// WriteReport prints a human readable summary of the interview with a
+// timeline of the candidate's answers
+func WriteReport(w io.Writer, record Record) {
+	fmt.Fprintf(w, "Interview %s\n", record.ID)
+	if record.Mode == ModeScreening {
+		fmt.Fprintf(w, "Mode:     recorded screening, answers to fixed questions without a live dialogue\n")
+	}
+	fmt.Fprintf(w, "Started:  %s\n", record.StartedAt.Format(time.RFC3339))
+	if !record.EndedAt.IsZero() {
+		fmt.Fprintf(w, "Duration: %s\n", record.EndedAt.Sub(record.StartedAt).Round(time.Second))
+	}
+	fmt.Fprintf(w, "Answers:  %d\n", len(record.Answers))
+	if record.Aborted {
+		fmt.Fprintf(w, "Status:   PARTIAL, the candidate stopped the interview early; reschedule the rest\n")
+	}
+	for _, prompt := range record.Prompts {
+		fmt.Fprintf(w, "Prompt:   %s\n", prompt)
+	}
+	if len(record.Degraded) > 0 {
+		fmt.Fprintf(w, "Degraded: %s fell back to a local backend\n", strings.Join(record.Degraded, ", "))
+	}
+	if record.Stalls > 0 {
+		fmt.Fprintf(w, "Stalls:   %d turns made no progress and were restarted\n", record.Stalls)
+	}
+	if record.Signature != nil {
+		fmt.Fprintf(w, "Signed:   %s, transcript and %d audio files\n", record.Signature.SignedAt.Format(time.RFC3339), len(record.Signature.Files))
+	}
+	if record.Unsigned != "" {
+		fmt.Fprintf(w, "Signed:   no, %s\n", record.Unsigned)
+	}
+	for _, voice := range record.ClonedVoices {
+		consent := fmt.Sprintf("consent by %s on %s", voice.GivenBy, voice.Date)
+		if voice.Reference != "" {
+			consent += " (" + voice.Reference + ")"
+		}
+		if voice.Replaced {
+			consent += ", replaced by the stock voice after it failed"
+		}
+		fmt.Fprintf(w, "Voice:    %s spoke with cloned voice %s, %s\n", voice.Persona, voice.Voice, consent)
+	}
+	for _, question := range record.Deferred {
+		offset := question.Time.Sub(record.StartedAt).Round(time.Second)
+		fmt.Fprintf(w, "Deferred: %s question at %s, for the recruiter to follow up: %s\n", question.Topic, offset, truncate(question.Question, 80))
+	}
+	for _, turn := range record.FailedTurns {
+		offset := turn.Time.Sub(record.StartedAt).Round(time.Second)
+		fmt.Fprintf(w, "Failed:   turn at %s in the %s stage: %s\n", offset, turn.Stage, turn.Error)
+	}
+
+	if len(record.Answers) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "\nTimeline:\n")
+	var stressed []int
+	duplicates := 0
+	for i, answer := range record.Answers {
+		offset := answer.AnsweredAt.Sub(record.StartedAt).Round(time.Second)
+		fmt.Fprintf(w, "%3d. [%s] %s\n", i+1, offset, truncate(answer.Question, 80))
+
+		var details []string
+		if answer.QuestionID != "" {
+			details = append(details, fmt.Sprintf("question %s on %s", answer.QuestionID, answer.Topic))
+		}
+		if answer.Interviewer != "" {
+			details = append(details, "asked by "+answer.Interviewer)
+		}
+		if answer.Unclear {
+			details = append(details, fmt.Sprintf("UNCLEAR (recognition confidence %.2f), asked to repeat", answer.Confidence))
+		}
+		if answer.Score != nil {
+			details = append(details, fmt.Sprintf("score %.1f", *answer.Score))
+		}
+		if a := answer.Assessment; a != nil {
+			for _, dimension := range a.Dimensions {
+				details = append(details, fmt.Sprintf("%s %.1f", dimension.Name, dimension.Score))
+			}
+			details = append(details, fmt.Sprintf("evaluator confidence %.2f", a.Confidence))
+		}
+		if s := answer.Sentiment; s != nil {
+			details = append(details, fmt.Sprintf("sentiment %+.2f", s.Sentiment), fmt.Sprintf("confidence %.2f", s.Confidence))
+			if s.Stressed {
+				details = append(details, "STRESS")
+				stressed = append(stressed, i+1)
+			}
+			if s.Note != "" {
+				details = append(details, s.Note)
+			}
+		}
+		if len(answer.Languages) > 1 {
+			details = append(details, "languages "+strings.Join(answer.Languages, ", "))
+		}
+		if d := answer.Duplicate; d != nil {
+			details = append(details, fmt.Sprintf("DUPLICATE of session %s answer %d (similarity %.2f)", d.SessionID, d.AnswerIndex+1, d.Similarity))
+			duplicates++
+		}
+		if len(details) > 0 {
+			fmt.Fprintf(w, "     %s\n", strings.Join(details, ", "))
+		}
+	}
+
+	writeNotes(w, record.Notes)
+	writeFluency(w, record.Fluency())
+
+	if duplicates > 0 {
+		fmt.Fprintf(w, "\n%d answers are nearly identical to answers of other candidates,\n", duplicates)
+		fmt.Fprintf(w, "which may indicate a leaked question bank or a shared cheat sheet.\n")
+	}
+
+	if len(stressed) > 0 {
+		fmt.Fprintf(w, "\nEvident stress at answers %s. These moments are context for the human reviewer,\n", joinInts(stressed))
+		fmt.Fprintf(w, "not a rating of the candidate.\n")
+	}
+}
+
+// writeNotes prints the interim notes taken after every stage
+func writeNotes(w io.Writer, stages []StageNotes) {
+	if len(stages) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\nNotes:\n")
+	for _, stage := range stages {
+		fmt.Fprintf(w, "  Stage %d, answers %d-%d:\n", stage.Stage, stage.FirstAnswer+1, stage.LastAnswer+1)
+		for _, claim := range stage.Claims {
+			fmt.Fprintf(w, "    Claim:    %s\n", claim)
+		}
+		for _, skill := range stage.Skills {
+			fmt.Fprintf(w, "    Skill:    %s\n", skill)
+		}
+		for _, flag := range stage.RedFlags {
+			fmt.Fprintf(w, "    Red flag: %s\n", flag)
+		}
+	}
+}
+
+// writeFluency prints the communication statistics of the candidate
+func writeFluency(w io.Writer, fluency analysis.Fluency) {
+	fmt.Fprintf(w, "\nCommunication:\n")
+	fmt.Fprintf(w, "  Words:          %d\n", fluency.Words)
+	fmt.Fprintf(w, "  Filler words:   %d (%.1f%%)", fluency.FillerWords, fluency.FillerRatio()*100)
+	if len(fluency.Fillers) > 0 {
+		fillers := make([]string, 0, len(fluency.Fillers))
+		for filler, count := range fluency.Fillers {
+			fillers = append(fillers, fmt.Sprintf("%q x%d", filler, count))
+		}
+		sort.Strings(fillers)
+		fmt.Fprintf(w, " %s", strings.Join(fillers, ", "))
+	}
+	fmt.Fprintln(w)
+	if fluency.SpeakingTime > 0 {
+		fmt.Fprintf(w, "  Speech rate:    %.0f words per minute\n", fluency.WordsPerMinute)
+		fmt.Fprintf(w, "  Average pause:  %s (%d pauses)\n", fluency.AveragePause.Round(10*time.Millisecond), fluency.Pauses)
+	}
+}
+
+func truncate(text string, limit int) string {
+	runes := []rune(strings.Join(strings.Fields(text), " "))
+	if len(runes) <= limit {
+		return string(runes)
+	}
+	return string(runes[:limit-3]) + "..."
+}
+
+func joinInts(values []int) string {
+	parts := make([]string, len(values))
+	for i, value := range values {
+		parts[i] = fmt.Sprint(value)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,177 @@
+// Package session keeps the full record of an interview for reports and storage
+package session
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/d1nch8g/aihr/analysis"
+	"github.com/d1nch8g/aihr/correlation"
+	"github.com/d1nch8g/aihr/eval"
+)
+
+// Answer is a single question and the candidate's reply
+type Answer struct {
+	Question   string              `json:"question"`
+	Text       string              `json:"text"`
+	AnsweredAt time.Time           `json:"answered_at"`
+	Score      *float64            `json:"score,omitempty"`
+	Assessment *eval.Assessment    `json:"assessment,omitempty"` // Scores on the rubric, set by rubric evaluators
+	Sentiment  *analysis.Sentiment `json:"sentiment,omitempty"`
+	Fluency    analysis.Fluency    `json:"fluency"`
+	Embedding  []float64           `json:"embedding,omitempty"`
+	Duplicate  *Duplicate          `json:"duplicate,omitempty"`
+
+	// Confidence is the recognition confidence of the answer, zero when the
+	// recognizer does not report it. Unclear answers were below the threshold,
+	// the candidate was asked to repeat them and they were not evaluated
+	Confidence float64 `json:"confidence,omitempty"`
+	Unclear    bool    `json:"unclear,omitempty"`
+
+	// Languages are the languages detected in the answer when several are
+	// recognized, more than one when the candidate switched between them
+	Languages []string `json:"languages,omitempty"`
+
+	// QuestionID and Topic identify the question of the bank the interviewer
+	// was told to ask, empty when the question was not planned
+	QuestionID string `json:"question_id,omitempty"`
+	Topic      string `json:"topic,omitempty"`
+
+	// Interviewer is the persona of the panel who asked the question, empty
+	// when the session had one interviewer
+	Interviewer string `json:"interviewer,omitempty"`
+}
+
+// Hiring decisions recorded for a session after the interview
+const (
+	OutcomeHired    = "hired"
+	OutcomeRejected = "rejected"
+)
+
+// ModeScreening marks the record of recorded answers to fixed questions,
+// evaluated without a live dialogue
+const ModeScreening = "screening"
+
+// Record is the complete history of one interview
+type Record struct {
+	ID        string    `json:"id"`
+	Mode      string    `json:"mode,omitempty"` // ModeScreening, empty for a live interview
+	StartedAt time.Time `json:"started_at"`
+	EndedAt   time.Time `json:"ended_at,omitempty"`
+	Answers   []Answer  `json:"answers"`
+	Outcome   string    `json:"outcome,omitempty"` // Hiring decision, set by the hiring team
+
+	// Aborted marks a partial interview the candidate stopped before the
+	// end, e.g. to reschedule it. Its answers cover only part of the rubric
+	Aborted bool `json:"aborted,omitempty"`
+
+	// HumanScores are the ratings of human interviewers of the same
+	// candidate, imported to calibrate the AI evaluation
+	HumanScores []HumanScore `json:"human_scores,omitempty"`
+
+	// Experiment and Variant tag sessions that took part in an A/B test
+	Experiment string `json:"experiment,omitempty"`
+	Variant    string `json:"variant,omitempty"`
+
+	// Prompts are the system prompts the session ran with, more than one when
+	// the prompt was changed by a configuration reload
+	Prompts []PromptVersion `json:"prompts,omitempty"`
+
+	// Degraded lists the components that fell back to a local backend during
+	// the session, e.g. "tts" when some speech used the fallback voice
+	Degraded []string `json:"degraded,omitempty"`
+
+	// FailedTurns are the turns that ended by an internal error, the
+	// interview continued after them
+	FailedTurns []FailedTurn `json:"failed_turns,omitempty"`
+
+	// Stalls is the number of turns canceled because they made no progress
+	Stalls int `json:"stalls,omitempty"`
+
+	// Notes are the interim notes taken after every stage of the interview
+	Notes []StageNotes `json:"notes,omitempty"`
+
+	// Deferred are the candidate's questions the interviewer did not answer,
+	// e.g. on compensation, for the recruiter to follow up on
+	Deferred []DeferredQuestion `json:"deferred,omitempty"`
+
+	// ClonedVoices are the cloned voices the interviewers spoke with and the
+	// consent on file for each of them
+	ClonedVoices []ClonedVoice `json:"cloned_voices,omitempty"`
+
+	// Signature shows the transcript and the audio were not modified after
+	// the interview, nil when no signing key is configured
+	Signature *Signature `json:"signature,omitempty"`
+
+	// Unsigned is why the session has no signature although a signing key is configured
+	Unsigned string `json:"unsigned,omitempty"`
+}
+
+// StageNotes are the notes on the answers of one stage of the interview
+type StageNotes struct {
+	Stage       int       `json:"stage"`        // From 1
+	FirstAnswer int       `json:"first_answer"` // Index of the first answer of the stage
+	LastAnswer  int       `json:"last_answer"`  // Index of the last answer of the stage
+	TakenAt     time.Time `json:"taken_at"`
+	analysis.Notes
+}
+
+// DeferredQuestion is a question of the candidate left to the recruiter
+type DeferredQuestion struct {
+	Time     time.Time `json:"time"`
+	Topic    string    `json:"topic"`    // Subject of the question, e.g. "compensation"
+	Question string    `json:"question"` // What the candidate asked
+}
+
+// ClonedVoice is a cloned voice an interviewer of the panel spoke with
+type ClonedVoice struct {
+	Persona   string `json:"persona"`
+	Voice     string `json:"voice"`            // Voice ID at the provider
+	GivenBy   string `json:"consent_given_by"` // Person whose voice was cloned
+	Date      string `json:"consent_date"`     // Day the consent was given
+	Reference string `json:"consent_reference,omitempty"`
+
+	// Replaced is set when the voice failed and the stock voice spoke instead
+	Replaced bool `json:"replaced,omitempty"`
+}
+
+// FailedTurn is a turn that ended by an internal error, e.g. a panic in a provider
+type FailedTurn struct {
+	Time     time.Time `json:"time"`
+	Stage    string    `json:"stage"` // Stage the turn reached, see turnlog
+	Question string    `json:"question,omitempty"`
+	Answer   string    `json:"answer,omitempty"`
+	Error    string    `json:"error"`
+}
+
+// PromptVersion identifies a system prompt. Name and Version are set when the
+// prompt is a version of a registered template, the hash is always set
+type PromptVersion struct {
+	Name    string `json:"name,omitempty"`
+	Version string `json:"version,omitempty"`
+	Hash    string `json:"hash"`
+}
+
+// String returns the version as "name@version (hash)", or the hash alone
+func (p PromptVersion) String() string {
+	if p.Name == "" {
+		return p.Hash
+	}
+	return fmt.Sprintf("%s@%s (%s)", p.Name, p.Version, p.Hash)
+}
+
+// NewID returns a random session identifier in the UUID format
+func NewID() string {
+	return correlation.NewID()
+}
+
+// Fluency returns the speech statistics over all answers of the session
+func (r Record) Fluency() analysis.Fluency {
+	items := make([]analysis.Fluency, 0, len(r.Answers))
+	for _, answer := range r.Answers {
+		if !answer.Unclear {
+			items = append(items, answer.Fluency)
+		}
+	}
+	return analysis.MergeFluency(items...)
+}
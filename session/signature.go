@@ -0,0 +1,136 @@
+package session
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// SignatureAlgorithm is the algorithm of session signatures
+const SignatureAlgorithm = "hmac-sha256"
+
+var (
+	// ErrNotSigned is returned when verifying a session that has no signature
+	ErrNotSigned = errors.New("session is not signed")
+
+	// ErrTampered is returned when the session or its audio changed after signing
+	ErrTampered = errors.New("session was modified after signing")
+)
+
+// Signature shows that the transcript and the audio of a session are the ones
+// recorded at the end of the interview. The MAC covers the digests, so a
+// changed answer or audio file, or a changed digest, fails verification.
+// Live sessions keep no audio files, their signature covers the transcript only
+type Signature struct {
+	Algorithm  string       `json:"algorithm"`
+	SignedAt   time.Time    `json:"signed_at"`
+	Transcript string       `json:"transcript"` // SHA-256 of the transcript
+	Files      []SignedFile `json:"files,omitempty"`
+	MAC        string       `json:"mac"`
+}
+
+// SignedFile is an audio file of the session and its SHA-256
+type SignedFile struct {
+	Path   string `json:"path"` // Absolute, so the session verifies from any directory
+	SHA256 string `json:"sha256"`
+}
+
+// Sign signs the transcript of the record and the audio files with the key
+func Sign(record *Record, key []byte, files []string) error {
+	signature := &Signature{
+		Algorithm:  SignatureAlgorithm,
+		SignedAt:   time.Now().UTC(),
+		Transcript: transcriptHash(*record),
+	}
+	for _, path := range files {
+		path, err := filepath.Abs(path)
+		if err != nil {
+			return fmt.Errorf("failed to sign session: %w", err)
+		}
+		sum, err := fileHash(path)
+		if err != nil {
+			return fmt.Errorf("failed to sign session: %w", err)
+		}
+		signature.Files = append(signature.Files, SignedFile{Path: path, SHA256: sum})
+	}
+	signature.MAC = signature.mac(record.ID, key)
+	record.Signature = signature
+	return nil
+}
+
+// Verify checks the signature of the record, rereading its audio files. It
+// returns ErrTampered with the parts that changed
+func Verify(record Record, key []byte) error {
+	signature := record.Signature
+	if signature == nil && record.Unsigned != "" {
+		return fmt.Errorf("%w: %s", ErrNotSigned, record.Unsigned)
+	}
+	if signature == nil {
+		return ErrNotSigned
+	}
+	if signature.Algorithm != SignatureAlgorithm {
+		return fmt.Errorf("unsupported signature algorithm %q", signature.Algorithm)
+	}
+
+	var changed []string
+	if !hmac.Equal([]byte(signature.MAC), []byte(signature.mac(record.ID, key))) {
+		changed = append(changed, "signature")
+	}
+	if signature.Transcript != transcriptHash(record) {
+		changed = append(changed, "transcript")
+	}
+	for _, file := range signature.Files {
+		sum, err := fileHash(file.Path)
+		if err != nil {
+			return fmt.Errorf("failed to verify session: %w", err)
+		}
+		if sum != file.SHA256 {
+			changed = append(changed, file.Path)
+		}
+	}
+	if len(changed) > 0 {
+		return fmt.Errorf("%w: %s", ErrTampered, strings.Join(changed, ", "))
+	}
+	return nil
+}
+
+// mac returns the HMAC of the session ID and the digests
+func (s *Signature) mac(id string, key []byte) string {
+	mac := hmac.New(sha256.New, key)
+	fmt.Fprintf(mac, "%s\n%s\n%s\ntranscript %s\n", s.Algorithm, id, s.SignedAt.Format(time.RFC3339Nano), s.Transcript)
+	for _, file := range s.Files {
+		fmt.Fprintf(mac, "file %s %s\n", file.SHA256, file.Path)
+	}
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// transcriptHash returns the SHA-256 of the transcript of the record
+func transcriptHash(record Record) string {
+	var transcript bytes.Buffer
+	WriteTranscript(&transcript, record)
+	sum := sha256.Sum256(transcript.Bytes())
+	return hex.EncodeToString(sum[:])
+}
+
+// fileHash returns the SHA-256 of a file
+func fileHash(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	sum := sha256.New()
+	if _, err := io.Copy(sum, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(sum.Sum(nil)), nil
+}
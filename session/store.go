@@ -0,0 +1,21 @@
+package session
+
+import "errors"
+
+// ErrNotFound is returned when a session is not in the store
+var ErrNotFound = errors.New("session not found")
+
+// Store defines the interface for persisting session records
+type Store interface {
+	// Save creates or replaces the record with the same ID
+	Save(record Record) error
+
+	// Load returns the record with the given ID
+	Load(id string) (Record, error)
+
+	// List returns all stored records ordered by start time
+	List() ([]Record, error)
+
+	// Delete removes the record with the given ID
+	Delete(id string) error
+}
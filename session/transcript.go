@@ -0,0 +1,20 @@
+package session
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// WriteTranscript prints the full questions and answers of the interview
+func WriteTranscript(w io.Writer, record Record) {
+	fmt.Fprintf(w, "Interview %s, %s\n", record.ID, record.StartedAt.Format(time.RFC3339))
+	for _, answer := range record.Answers {
+		offset := answer.AnsweredAt.Sub(record.StartedAt).Round(time.Second)
+		fmt.Fprintf(w, "\nAI: %s\n", answer.Question)
+		fmt.Fprintf(w, "Candidate [%s]: %s\n", offset, answer.Text)
+		if answer.Unclear {
+			fmt.Fprintf(w, "(unclear, confidence %.2f, asked to repeat)\n", answer.Confidence)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package simulator
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/d1nch8g/aihr/gpt"
+)
+
+// ScriptedCandidate replies with predefined answers in order
+type ScriptedCandidate struct {
+	answers []string
+	next    int
+}
+
+// NewScriptedCandidate creates a candidate that gives the answers in order
+func NewScriptedCandidate(answers []string) *ScriptedCandidate {
+	return &ScriptedCandidate{answers: answers}
+}
+
+// Answer returns the next scripted answer regardless of the question
+func (c *ScriptedCandidate) Answer(question string) (string, error) {
+	if c.next >= len(c.answers) {
+		return "", io.EOF
+	}
+	answer := c.answers[c.next]
+	c.next++
+	return answer, nil
+}
+
+const candidatePrompt = `You are a job candidate in a spoken interview for a Go developer position.
+Stay in character and answer the interviewer's last question in two to four spoken sentences, without markdown.
+Your persona: %s`
+
+// GPTCandidate answers questions with the GPT model playing a persona
+type GPTCandidate struct {
+	client  gpt.GPTClient
+	persona string
+	history []Turn
+}
+
+// NewGPTCandidate creates a candidate played by the GPT model
+func NewGPTCandidate(client gpt.GPTClient, persona string) *GPTCandidate {
+	return &GPTCandidate{client: client, persona: persona}
+}
+
+// Answer asks the model to reply to the question in character
+func (c *GPTCandidate) Answer(question string) (string, error) {
+	var conversation strings.Builder
+	for _, turn := range c.history {
+		conversation.WriteString(fmt.Sprintf("Interviewer: %s\nYou: %s\n", turn.Question, turn.Answer))
+	}
+	conversation.WriteString(fmt.Sprintf("Interviewer: %s\nYou:", question))
+
+	answer, err := c.client.Complete(fmt.Sprintf(candidatePrompt, c.persona), conversation.String())
+	if err != nil {
+		return "", fmt.Errorf("failed to generate candidate answer: %w", err)
+	}
+
+	answer = strings.TrimSpace(answer)
+	c.history = append(c.history, Turn{Question: question, Answer: answer})
+	return answer, nil
+}
@@ -0,0 +1,82 @@
+package simulator
+
+import (
+	"runtime"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// ResourceStats is the resource usage of the process during a load test
+type ResourceStats struct {
+	PeakGoroutines int
+	PeakHeap       uint64        // Bytes of allocated heap objects
+	CPUTime        time.Duration // User and system CPU time
+	Elapsed        time.Duration
+}
+
+// Monitor samples the goroutine count and heap size of the process until stopped
+type Monitor struct {
+	stats   ResourceStats
+	started time.Time
+	cpu     time.Duration // CPU time when the monitor started
+	done    chan struct{}
+	wg      sync.WaitGroup
+	mutex   sync.Mutex
+}
+
+// StartMonitor starts sampling every interval
+func StartMonitor(interval time.Duration) *Monitor {
+	m := &Monitor{started: time.Now(), cpu: cpuTime(), done: make(chan struct{})}
+	m.sample()
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				m.sample()
+			case <-m.done:
+				return
+			}
+		}
+	}()
+	return m
+}
+
+// Stop ends sampling and returns the peaks and the CPU time used since the start
+func (m *Monitor) Stop() ResourceStats {
+	close(m.done)
+	m.wg.Wait()
+	m.sample()
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.stats.CPUTime = cpuTime() - m.cpu
+	m.stats.Elapsed = time.Since(m.started)
+	return m.stats
+}
+
+// sample records the current goroutine count and heap size when they are new peaks
+func (m *Monitor) sample() {
+	var memory runtime.MemStats
+	runtime.ReadMemStats(&memory)
+	goroutines := runtime.NumGoroutine()
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.stats.PeakGoroutines = max(m.stats.PeakGoroutines, goroutines)
+	m.stats.PeakHeap = max(m.stats.PeakHeap, memory.HeapAlloc)
+}
+
+// cpuTime returns the user and system CPU time of the process so far
+func cpuTime() time.Duration {
+	var usage syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &usage); err != nil {
+		return 0
+	}
+	return time.Duration(usage.Utime.Nano() + usage.Stime.Nano())
+}
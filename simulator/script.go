@@ -0,0 +1,97 @@
+package simulator
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/d1nch8g/aihr/gpt"
+)
+
+// Script describes a simulated interview. Scripted answers are used when
+// given, otherwise the GPT model plays the persona for MaxTurns answers
+type Script struct {
+	Persona  string   `json:"persona,omitempty"`
+	Answers  []string `json:"answers,omitempty"`
+	MaxTurns int      `json:"max_turns,omitempty"`
+	Checks   []Check  `json:"checks,omitempty"`
+}
+
+// Check asserts that an interviewer response matches a pattern
+type Check struct {
+	Turn    int    `json:"turn"`    // 1-based turn, 0 checks every turn
+	Pattern string `json:"pattern"` // Regular expression
+	Absent  bool   `json:"absent"`  // The response must not match
+
+	pattern *regexp.Regexp
+}
+
+// CheckResult is the outcome of a check on one turn
+type CheckResult struct {
+	Check  Check
+	Turn   int
+	Passed bool
+}
+
+// LoadScript reads a simulation script from a JSON file
+func LoadScript(path string) (*Script, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read script: %w", err)
+	}
+
+	var script Script
+	if err := json.Unmarshal(data, &script); err != nil {
+		return nil, fmt.Errorf("failed to decode script %s: %w", path, err)
+	}
+
+	if len(script.Answers) == 0 && script.Persona == "" {
+		return nil, fmt.Errorf("script %s needs answers or a persona", path)
+	}
+	if len(script.Answers) == 0 && script.MaxTurns == 0 {
+		script.MaxTurns = 5
+	}
+	for i := range script.Checks {
+		pattern, err := regexp.Compile(script.Checks[i].Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid check pattern %q: %w", script.Checks[i].Pattern, err)
+		}
+		script.Checks[i].pattern = pattern
+	}
+
+	return &script, nil
+}
+
+// Candidate returns the candidate described by the script
+func (s *Script) Candidate(client gpt.GPTClient) Candidate {
+	if len(s.Answers) > 0 {
+		return NewScriptedCandidate(s.Answers)
+	}
+	return NewGPTCandidate(client, s.Persona)
+}
+
+// Evaluate runs the checks against the completed turns. A check for a turn
+// that was not reached fails
+func (s *Script) Evaluate(turns []Turn) []CheckResult {
+	var results []CheckResult
+	for _, check := range s.Checks {
+		if check.Turn == 0 {
+			for i, turn := range turns {
+				results = append(results, CheckResult{Check: check, Turn: i + 1, Passed: check.matches(turn.Response)})
+			}
+			continue
+		}
+
+		passed := false
+		if check.Turn <= len(turns) {
+			passed = check.matches(turns[check.Turn-1].Response)
+		}
+		results = append(results, CheckResult{Check: check, Turn: check.Turn, Passed: passed})
+	}
+	return results
+}
+
+func (c Check) matches(response string) bool {
+	return c.pattern.MatchString(response) != c.Absent
+}
@@ -0,0 +1,131 @@
+// Package simulator plays a scripted or LLM generated candidate against the
+// interview engine for automated end-to-end tests and latency benchmarks
+package simulator
+
+import (
+	"context"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/d1nch8g/aihr/engine"
+)
+
+// Candidate produces answers to interviewer questions
+type Candidate interface {
+	// Answer returns the reply to the question, io.EOF ends the interview
+	Answer(question string) (string, error)
+}
+
+// Turn is one question, the simulated answer and the engine response time
+type Turn struct {
+	Question string
+	Answer   string
+	Response string
+	Latency  time.Duration // From submitting the answer to receiving the response
+}
+
+// Simulator connects a candidate to the engine in text mode
+type Simulator struct {
+	candidate Candidate
+	maxTurns  int
+
+	turns      []Turn
+	pending    *Turn
+	question   string
+	answeredAt time.Time
+	mutex      sync.Mutex
+}
+
+// Ensure Simulator implements engine.TextIO interface
+var _ engine.TextIO = (*Simulator)(nil)
+
+// New creates a simulator that ends the interview after maxTurns answers,
+// or when the candidate has nothing more to say if maxTurns is 0
+func New(candidate Candidate, maxTurns int) *Simulator {
+	return &Simulator{candidate: candidate, maxTurns: maxTurns}
+}
+
+// ReadAnswer asks the candidate to answer the last response of the engine
+func (s *Simulator) ReadAnswer(ctx context.Context) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	s.mutex.Lock()
+	question := s.question
+	if s.maxTurns > 0 && len(s.turns) >= s.maxTurns {
+		s.mutex.Unlock()
+		return "", io.EOF
+	}
+	s.mutex.Unlock()
+
+	answer, err := s.candidate.Answer(question)
+	if err != nil {
+		return "", err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.pending = &Turn{Question: question, Answer: answer}
+	s.answeredAt = time.Now()
+	return answer, nil
+}
+
+// WriteResponse records the engine response to the pending answer
+func (s *Simulator) WriteResponse(text string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.pending != nil {
+		s.pending.Response = text
+		s.pending.Latency = time.Since(s.answeredAt)
+		s.turns = append(s.turns, *s.pending)
+		s.pending = nil
+	}
+	s.question = text
+	return nil
+}
+
+// Turns returns the completed turns
+func (s *Simulator) Turns() []Turn {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	turns := make([]Turn, len(s.turns))
+	copy(turns, s.turns)
+	return turns
+}
+
+// LatencyStats summarizes engine response times
+type LatencyStats struct {
+	Count   int
+	Average time.Duration
+	Median  time.Duration
+	P95     time.Duration
+	Max     time.Duration
+}
+
+// Latencies computes response time statistics of the turns
+func Latencies(turns []Turn) LatencyStats {
+	if len(turns) == 0 {
+		return LatencyStats{}
+	}
+
+	latencies := make([]time.Duration, len(turns))
+	var total time.Duration
+	for i, turn := range turns {
+		latencies[i] = turn.Latency
+		total += turn.Latency
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	return LatencyStats{
+		Count:   len(latencies),
+		Average: total / time.Duration(len(latencies)),
+		Median:  latencies[len(latencies)/2],
+		P95:     latencies[(len(latencies)*95-1)/100],
+		Max:     latencies[len(latencies)-1],
+	}
+}
@@ -0,0 +1,70 @@
+package sound
+
+import (
+	"fmt"
+	"sort"
+)
+
+// BackendFactory creates a Player for one audio system
+type BackendFactory func(config PlayerConfig) (Player, error)
+
+type backend struct {
+	name     string
+	priority int
+	factory  BackendFactory
+}
+
+var backends = map[string]backend{}
+
+// RegisterBackend makes a playback backend available for selection by name.
+// With "auto" selection the registered backend with the highest priority is used
+func RegisterBackend(name string, priority int, factory BackendFactory) {
+	backends[name] = backend{name: name, priority: priority, factory: factory}
+}
+
+// Backends returns the names of the registered backends, preferred first
+func Backends() []string {
+	sorted := sortedBackends()
+	names := make([]string, len(sorted))
+	for i, b := range sorted {
+		names[i] = b.name
+	}
+	return names
+}
+
+// NewPlayer creates a player using the named backend, or the preferred
+// registered backend when name is "auto" or empty
+func NewPlayer(name string, config PlayerConfig) (Player, string, error) {
+	if name == "" || name == "auto" {
+		sorted := sortedBackends()
+		if len(sorted) == 0 {
+			return nil, "", fmt.Errorf("no audio playback backend available in this build")
+		}
+		name = sorted[0].name
+	}
+
+	b, ok := backends[name]
+	if !ok {
+		return nil, "", fmt.Errorf("unknown audio playback backend %q, available: %v", name, Backends())
+	}
+
+	player, err := b.factory(config)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create %s playback: %w", name, err)
+	}
+	return player, name, nil
+}
+
+func sortedBackends() []backend {
+	sorted := make([]backend, 0, len(backends))
+	for _, b := range backends {
+		sorted = append(sorted, b)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].priority != sorted[j].priority {
+			return sorted[i].priority > sorted[j].priority
+		}
+		return sorted[i].name < sorted[j].name
+	})
+	return sorted
+}
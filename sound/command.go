@@ -0,0 +1,208 @@
+//go:build linux
+
+package sound
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"sync"
+)
+
+// commandBackend describes a player program that reads raw 16-bit PCM from stdin
+type commandBackend struct {
+	name     string
+	program  string
+	args     func(sampleRate, channels string) []string
+	priority int
+}
+
+// Linux audio systems driven through their command line tools run as
+// subprocesses, a fallback rather than native access to the audio system. No
+// cgo is required, but the tools must be installed, e.g. from the
+// pipewire-bin, pulseaudio-utils or alsa-utils packages
+var commandBackends = []commandBackend{
+	{
+		name:    "pipewire",
+		program: "pw-play",
+		args: func(rate, channels string) []string {
+			return []string{"--format", "s16", "--rate", rate, "--channels", channels, "-"}
+		},
+		priority: -1,
+	},
+	{
+		name:    "pulse",
+		program: "pacat",
+		args: func(rate, channels string) []string {
+			return []string{"--raw", "--format=s16le", "--rate=" + rate, "--channels=" + channels}
+		},
+		priority: -2,
+	},
+	{
+		name:    "alsa",
+		program: "aplay",
+		args: func(rate, channels string) []string {
+			return []string{"-q", "-t", "raw", "-f", "S16_LE", "-r", rate, "-c", channels}
+		},
+		priority: -3,
+	},
+}
+
+func init() {
+	for _, backend := range commandBackends {
+		if _, err := exec.LookPath(backend.program); err != nil {
+			continue
+		}
+		backend := backend
+		RegisterBackend(backend.name, backend.priority, func(config PlayerConfig) (Player, error) {
+			return NewCommandPlayer(backend, config), nil
+		})
+	}
+}
+
+// CommandPlayer plays audio by piping it into a player program, one process per stream
+type CommandPlayer struct {
+	backend commandBackend
+	config  PlayerConfig
+
+	done      chan error // Receives the exit status of the running player process
+	doneMutex sync.Mutex
+}
+
+// Ensure CommandPlayer implements the player interfaces
+var (
+	_ Player           = (*CommandPlayer)(nil)
+	_ FormatConfigurer = (*CommandPlayer)(nil)
+)
+
+// NewCommandPlayer creates a player for the given program
+func NewCommandPlayer(backend commandBackend, config PlayerConfig) *CommandPlayer {
+	return &CommandPlayer{
+		backend: backend,
+		config:  config,
+	}
+}
+
+func (c *CommandPlayer) Initialize() error {
+	_, err := exec.LookPath(c.backend.program)
+	return err
+}
+
+func (c *CommandPlayer) Terminate() {}
+
+func (c *CommandPlayer) Open() error {
+	return nil
+}
+
+func (c *CommandPlayer) Close() error {
+	return nil
+}
+
+// SetInputFormat changes the format used for the next player process
+func (c *CommandPlayer) SetInputFormat(sampleRate float64, channels int) error {
+	c.config.SampleRate = sampleRate
+	c.config.OutputChannels = channels
+	return nil
+}
+
+func (c *CommandPlayer) PlayStream(ctx context.Context, audioData <-chan []byte) error {
+	prebufferBytes := int(c.config.PrebufferDuration.Seconds()*c.config.SampleRate) * c.config.OutputChannels * 2
+
+	var stdin io.WriteCloser
+	var pending []byte
+	cmdCtx, cmdCancel := context.WithCancel(ctx)
+	defer cmdCancel()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case audioBytes, ok := <-audioData:
+			if !ok {
+				if stdin == nil && len(pending) == 0 {
+					return nil
+				}
+				if stdin == nil {
+					var err error
+					if stdin, err = c.start(cmdCtx); err != nil {
+						return err
+					}
+				}
+				if _, err := stdin.Write(pending); err != nil {
+					return fmt.Errorf("failed to write audio: %w", err)
+				}
+				// Closing stdin lets the player finish the buffered audio and exit
+				stdin.Close()
+				return c.Drain(ctx)
+			}
+
+			pending = append(pending, audioBytes...)
+			if stdin == nil {
+				if len(pending) < prebufferBytes {
+					continue
+				}
+				var err error
+				if stdin, err = c.start(cmdCtx); err != nil {
+					return err
+				}
+			}
+			if _, err := stdin.Write(pending); err != nil {
+				return fmt.Errorf("failed to write audio: %w", err)
+			}
+			pending = pending[:0]
+		}
+	}
+}
+
+// start launches the player process and returns its stdin
+func (c *CommandPlayer) start(ctx context.Context) (io.WriteCloser, error) {
+	args := c.backend.args(
+		strconv.Itoa(int(c.config.SampleRate)),
+		strconv.Itoa(c.config.OutputChannels),
+	)
+	cmd := exec.CommandContext(ctx, c.backend.program, args...)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start %s: %w", c.backend.program, err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	c.doneMutex.Lock()
+	c.done = done
+	c.doneMutex.Unlock()
+
+	return stdin, nil
+}
+
+// Drain waits for the player process to finish playing and exit
+func (c *CommandPlayer) Drain(ctx context.Context) error {
+	c.doneMutex.Lock()
+	done := c.done
+	c.doneMutex.Unlock()
+
+	if done == nil {
+		return nil
+	}
+
+	select {
+	case err := <-done:
+		c.doneMutex.Lock()
+		c.done = nil
+		c.doneMutex.Unlock()
+		if err != nil {
+			return fmt.Errorf("%s failed: %w", c.backend.program, err)
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
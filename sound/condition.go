@@ -0,0 +1,133 @@
+package sound
+
+import (
+	"math"
+	"time"
+
+	"github.com/d1nch8g/aihr/pcm"
+)
+
+const (
+	// conditionPreroll is the audio kept before the onset of speech, so the
+	// first syllable is not cut off
+	conditionPreroll = 300 * time.Millisecond
+
+	// conditionHangover is the silence still sent after speech, long enough
+	// for the recognizer to detect the end of the utterance
+	conditionHangover = time.Second
+
+	// Normalization raises the level of speech towards -20 dBFS, boosting it
+	// at most conditionMaxGain times and never attenuating it
+	conditionTargetLevel = 3277
+	conditionMaxGain     = 8
+	conditionGainRate    = 0.2
+)
+
+// SpeechConditioner prepares captured mono 16-bit audio for speech recognition.
+// Silence longer than the hangover is held back, only its last part is sent
+// when speech starts again, and quiet speech is amplified with a smoothly
+// changing gain. It keeps state between calls, one conditioner serves one stream
+type SpeechConditioner struct {
+	trim      bool
+	normalize bool
+	threshold float64 // RMS level below which a chunk is silence
+	preroll   int     // Samples of silence kept before speech
+	hangover  int     // Samples of silence sent after speech
+
+	gain    float64
+	pending []int16 // Silence held back, at most preroll samples
+	silent  int     // Samples of silence since the last speech
+	trimmed int     // Samples dropped so far
+	rate    float64
+}
+
+// NewSpeechConditioner creates a conditioner for the sample rate. trim drops
+// silence, normalize amplifies quiet speech; threshold is the level in dBFS
+// below which audio counts as silence, e.g. -45
+func NewSpeechConditioner(sampleRate float64, trim, normalize bool, threshold float64) *SpeechConditioner {
+	return &SpeechConditioner{
+		trim:      trim,
+		normalize: normalize,
+		threshold: 32768 * math.Pow(10, threshold/20),
+		preroll:   int(sampleRate * conditionPreroll.Seconds()),
+		hangover:  int(sampleRate * conditionHangover.Seconds()),
+		gain:      1,
+		silent:    math.MaxInt, // Leading silence is trimmed
+		rate:      sampleRate,
+	}
+}
+
+// Append conditions the samples of a chunk and appends the audio to send to dst
+func (c *SpeechConditioner) Append(dst, samples []int16) []int16 {
+	if len(samples) == 0 {
+		return dst
+	}
+
+	level := rms(samples)
+	speech := level >= c.threshold
+	if speech && c.normalize {
+		desired := math.Min(math.Max(conditionTargetLevel/level, 1), conditionMaxGain)
+		c.gain += (desired - c.gain) * conditionGainRate
+	}
+
+	if !c.trim {
+		return c.amplify(dst, samples)
+	}
+
+	if speech {
+		dst = c.amplify(dst, c.pending)
+		c.pending = c.pending[:0]
+		c.silent = 0
+		return c.amplify(dst, samples)
+	}
+
+	if c.silent < c.hangover {
+		c.silent += len(samples)
+		return c.amplify(dst, samples)
+	}
+
+	// Hold the silence back, keeping only the most recent preroll samples
+	c.pending = append(c.pending, samples...)
+	if excess := len(c.pending) - c.preroll; excess > 0 {
+		c.trimmed += excess
+		c.pending = c.pending[:copy(c.pending, c.pending[excess:])]
+	}
+	return dst
+}
+
+// Trimmed returns the duration of the silence dropped so far
+func (c *SpeechConditioner) Trimmed() time.Duration {
+	if c.rate <= 0 {
+		return 0
+	}
+	return time.Duration(float64(c.trimmed) / c.rate * float64(time.Second))
+}
+
+// amplify appends the samples multiplied by the current gain to dst
+func (c *SpeechConditioner) amplify(dst, samples []int16) []int16 {
+	if c.gain == 1 {
+		return append(dst, samples...)
+	}
+	for _, sample := range samples {
+		dst = append(dst, pcm.Clamp16(float64(sample)*c.gain))
+	}
+	return dst
+}
+
+// Level returns the root mean square level of the samples in dBFS, the scale
+// of the silence threshold
+func Level(samples []int16) float64 {
+	if len(samples) == 0 {
+		return math.Inf(-1)
+	}
+	return 20 * math.Log10(rms(samples)/32768)
+}
+
+// rms returns the root mean square level of the samples
+func rms(samples []int16) float64 {
+	var sum float64
+	for _, sample := range samples {
+		sum += float64(sample) * float64(sample)
+	}
+	return math.Sqrt(sum / float64(len(samples)))
+}
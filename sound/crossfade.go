@@ -0,0 +1,208 @@
+package sound
+
+import (
+	"context"
+	"encoding/binary"
+	"time"
+
+	"github.com/d1nch8g/aihr/pcm"
+)
+
+// Crossfader joins consecutive PCM segments without audible clicks. The end of
+// each segment is held back and mixed with the start of the next one, the very
+// first segment fades in and the last one fades out
+type Crossfader struct {
+	fadeSamples int
+	channels    int
+
+	tail []int16 // Held end of the previous segment, nil before the first segment
+	head []int16 // Start of the current segment, collected until the fade length is reached
+	body []int16 // Rest of the current segment, its last fade length is held back
+	open bool    // Whether the head of the current segment has been joined
+
+	out []int16 // Output buffer reused by Write
+}
+
+// NewCrossfader creates a crossfader with the given fade duration
+func NewCrossfader(fade time.Duration, sampleRate float64, channels int) *Crossfader {
+	if channels <= 0 {
+		channels = 1
+	}
+	return &Crossfader{
+		fadeSamples: int(fade.Seconds()*sampleRate) * channels,
+		channels:    channels,
+	}
+}
+
+// Write adds samples of the current segment and returns the samples ready for
+// playback. The returned slice is only valid until the next call
+func (c *Crossfader) Write(samples []int16) []int16 {
+	if c.fadeSamples == 0 {
+		return samples
+	}
+
+	if !c.open {
+		c.head = append(c.head, samples...)
+		if len(c.head) < c.fadeSamples {
+			return nil
+		}
+		c.body = append(c.body, c.join()...)
+		c.open = true
+	} else {
+		c.body = append(c.body, samples...)
+	}
+
+	// Keep the last fade length of the segment until we know how it ends
+	release := len(c.body) - c.fadeSamples
+	if release <= 0 {
+		return nil
+	}
+	c.out = append(c.out[:0], c.body[:release]...)
+	c.body = c.body[:copy(c.body, c.body[release:])]
+	return c.out
+}
+
+// EndSegment marks the boundary between segments and returns the samples
+// ready for playback. The held end of the segment is mixed into the next one
+func (c *Crossfader) EndSegment() []int16 {
+	if c.fadeSamples == 0 {
+		return nil
+	}
+
+	if !c.open {
+		// Segment was shorter than the fade length
+		c.body = append(c.body, c.join()...)
+	}
+
+	var out []int16
+	if len(c.body) > c.fadeSamples {
+		out = c.body[:len(c.body)-c.fadeSamples]
+		c.body = c.body[len(c.body)-c.fadeSamples:]
+	}
+
+	c.tail = c.body
+	c.body = nil
+	c.open = false
+	return out
+}
+
+// Flush ends the last segment and returns the remaining samples faded out to silence
+func (c *Crossfader) Flush() []int16 {
+	if c.fadeSamples == 0 {
+		return nil
+	}
+
+	out := c.EndSegment()
+	out = append(out, c.fadeOut(c.tail)...)
+	c.tail = nil
+	return out
+}
+
+// join overlaps the held tail of the previous segment with the collected head
+// of the current one. Without a previous segment the head fades in from silence
+func (c *Crossfader) join() []int16 {
+	head := c.head
+	c.head = nil
+
+	if c.tail == nil {
+		// Only the fade length is faded in, the head can hold a longer chunk
+		fade := min(len(head), c.fadeSamples)
+		return append(c.fadeIn(head[:fade]), head[fade:]...)
+	}
+
+	overlap := len(c.tail)
+	if len(head) < overlap {
+		overlap = len(head)
+	}
+	overlap -= overlap % c.channels
+
+	out := make([]int16, 0, len(c.tail)+len(head)-overlap)
+	out = append(out, c.tail[:len(c.tail)-overlap]...)
+
+	frames := overlap / c.channels
+	fadingOut := c.tail[len(c.tail)-overlap:]
+	for frame := 0; frame < frames; frame++ {
+		gain := float64(frame) / float64(frames)
+		for channel := 0; channel < c.channels; channel++ {
+			i := frame*c.channels + channel
+			out = append(out, pcm.Clamp16(float64(fadingOut[i])*(1-gain)+float64(head[i])*gain))
+		}
+	}
+
+	c.tail = nil
+	return append(out, head[overlap:]...)
+}
+
+func (c *Crossfader) fadeIn(samples []int16) []int16 {
+	return c.applyGain(samples, func(frame, frames int) float64 {
+		return float64(frame) / float64(frames)
+	})
+}
+
+func (c *Crossfader) fadeOut(samples []int16) []int16 {
+	return c.applyGain(samples, func(frame, frames int) float64 {
+		return float64(frames-frame-1) / float64(frames)
+	})
+}
+
+// applyGain returns a copy of the interleaved samples scaled by a per-frame gain
+func (c *Crossfader) applyGain(samples []int16, gain func(frame, frames int) float64) []int16 {
+	frames := len(samples) / c.channels
+	out := make([]int16, len(samples))
+	copy(out, samples)
+	for frame := 0; frame < frames; frame++ {
+		g := gain(frame, frames)
+		for channel := 0; channel < c.channels; channel++ {
+			i := frame*c.channels + channel
+			out[i] = pcm.Clamp16(float64(samples[i]) * g)
+		}
+	}
+	return out
+}
+
+// CrossfadeSegments reads PCM segments one after another and writes them to out
+// as a single continuous stream joined by the crossfader. The out channel is
+// closed when the segments channel is closed
+func CrossfadeSegments(ctx context.Context, segments <-chan (<-chan []byte), out chan<- []byte, crossfader *Crossfader) error {
+	defer close(out)
+
+	send := func(samples []int16) error {
+		if len(samples) == 0 {
+			return nil
+		}
+		select {
+		case out <- pcm.Encode(samples):
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	// Decoded samples of a chunk, reused across chunks
+	var samples []int16
+
+	for {
+		var segment <-chan []byte
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case next, ok := <-segments:
+			if !ok {
+				return send(crossfader.Flush())
+			}
+			segment = next
+		}
+
+		aligner := pcm.NewAligner(binary.LittleEndian)
+		for chunk := range segment {
+			samples = aligner.Append(samples[:0], chunk)
+			if err := send(crossfader.Write(samples)); err != nil {
+				return err
+			}
+		}
+
+		if err := send(crossfader.EndSegment()); err != nil {
+			return err
+		}
+	}
+}
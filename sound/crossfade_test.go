@@ -0,0 +1,16 @@
+package sound
+
+import (
+	"testing"
+	"time"
+)
+
+func BenchmarkCrossfaderWrite(b *testing.B) {
+	crossfader := NewCrossfader(10*time.Millisecond, 22050, 1)
+	chunk := ramp(2048)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(chunk) * 2))
+	for b.Loop() {
+		crossfader.Write(chunk)
+	}
+}
@@ -0,0 +1,42 @@
+package sound
+
+import (
+	"math"
+
+	"github.com/d1nch8g/aihr/pcm"
+)
+
+// HighPassFilter removes low frequency rumble, desk thumps and plosive pops
+// from mono 16-bit audio with a second order Butterworth filter. It keeps
+// state between calls so chunk boundaries stay continuous
+type HighPassFilter struct {
+	b0, b1, b2, a1, a2 float64
+	x1, x2, y1, y2     float64
+}
+
+// NewHighPassFilter creates a filter that attenuates frequencies below cutoff Hz
+func NewHighPassFilter(cutoff, sampleRate float64) *HighPassFilter {
+	omega := 2 * math.Pi * cutoff / sampleRate
+	alpha := math.Sin(omega) / math.Sqrt2 // Q of 1/sqrt(2)
+	cos := math.Cos(omega)
+	a0 := 1 + alpha
+
+	return &HighPassFilter{
+		b0: (1 + cos) / 2 / a0,
+		b1: -(1 + cos) / a0,
+		b2: (1 + cos) / 2 / a0,
+		a1: -2 * cos / a0,
+		a2: (1 - alpha) / a0,
+	}
+}
+
+// Apply filters the samples in place
+func (f *HighPassFilter) Apply(samples []int16) {
+	for i, sample := range samples {
+		x := float64(sample)
+		y := f.b0*x + f.b1*f.x1 + f.b2*f.x2 - f.a1*f.y1 - f.a2*f.y2
+		f.x2, f.x1 = f.x1, x
+		f.y2, f.y1 = f.y1, y
+		samples[i] = pcm.Clamp16(y)
+	}
+}
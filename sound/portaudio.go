@@ -1,26 +1,38 @@
+//go:build cgo
+
 package sound
 
 import (
 	"context"
 	"encoding/binary"
 	"errors"
+	"fmt"
 	"log"
+	"time"
 
+	"github.com/d1nch8g/aihr/pcm"
 	"github.com/gordonklaus/portaudio"
 )
 
-// PlayerConfig represents the configuration for audio playback
-type PlayerConfig struct {
-	SampleRate      float64
-	FramesPerBuffer int
-	InputChannels   int
-	OutputChannels  int
+func init() {
+	RegisterBackend("portaudio", 0, func(config PlayerConfig) (Player, error) {
+		return NewPortaudioPlayer(config), nil
+	})
 }
 
 type PortaudioPlayer struct {
 	stream      *portaudio.Stream
 	audioBuffer []int16
 	config      PlayerConfig
+	resampler   *Resampler // Set when input audio rate differs from the stream rate
+	upmix       int        // Channels mono input is duplicated into, zero when the channels match
+	resampled   []int16    // Buffer reused for resampled audio
+
+	// Playback position tracking used by Drain
+	playing       bool
+	startTime     time.Duration // Stream time when playback started
+	startWall     time.Time     // Wall clock fallback for hosts without stream time
+	framesWritten int64
 }
 
 func NewPortaudioPlayer(config PlayerConfig) *PortaudioPlayer {
@@ -32,20 +44,28 @@ func NewPortaudioPlayer(config PlayerConfig) *PortaudioPlayer {
 	}
 }
 
-func GetDefaultConfig() PlayerConfig {
-	return PlayerConfig{
-		SampleRate:      44100,
-		FramesPerBuffer: 1024,
-		InputChannels:   0,
-		OutputChannels:  2, // Default to stereo
-	}
-}
-
 func (p *PortaudioPlayer) Initialize() error {
 	return portaudio.Initialize()
 }
 
+// Open opens the default output device. A rate or channel count the device
+// does not support is replaced with the nearest supported one, and audio in
+// the requested format is converted to it
 func (p *PortaudioPlayer) Open() error {
+	p.resampler, p.upmix = nil, 0
+	requestedRate, requestedChannels := p.config.SampleRate, p.config.OutputChannels
+	rate, channels, err := ProbeFormat(false, requestedChannels, requestedRate, p.config.FramesPerBuffer, p.audioBuffer)
+	if err != nil {
+		return err
+	}
+	if rate != requestedRate || channels != requestedChannels {
+		log.Printf("Output device does not support %.0f Hz, %d channel(s), using %.0f Hz, %d channel(s)",
+			requestedRate, requestedChannels, rate, channels)
+		p.config.SampleRate = rate
+		p.config.OutputChannels = channels
+		p.audioBuffer = make([]int16, p.config.FramesPerBuffer*channels)
+	}
+
 	stream, err := portaudio.OpenDefaultStream(
 		p.config.InputChannels,
 		p.config.OutputChannels,
@@ -57,7 +77,7 @@ func (p *PortaudioPlayer) Open() error {
 		return err
 	}
 	p.stream = stream
-	return nil
+	return p.convertFrom(requestedRate, requestedChannels)
 }
 
 func (p *PortaudioPlayer) PlayStream(ctx context.Context, audioData <-chan []byte) error {
@@ -65,10 +85,42 @@ func (p *PortaudioPlayer) PlayStream(ctx context.Context, audioData <-chan []byt
 		return errors.New("Stream not opened")
 	}
 
-	if err := p.stream.Start(); err != nil {
-		return err
+	// The stream is started once enough audio is buffered
+	started := false
+	start := func() error {
+		if started {
+			return nil
+		}
+		if err := p.stream.Start(); err != nil {
+			return err
+		}
+		started = true
+		p.playing = true
+		p.startTime = p.stream.Time()
+		p.startWall = time.Now()
+		p.framesWritten = 0
+		return nil
 	}
-	defer p.stream.Stop()
+	drained := false
+	defer func() {
+		if !started {
+			return
+		}
+		// Cancelled playback is cut immediately instead of playing out the buffers
+		if drained {
+			p.stream.Stop()
+		} else {
+			p.stream.Abort()
+		}
+		p.playing = false
+	}()
+	prebufferSamples := int(p.config.PrebufferDuration.Seconds()*p.config.SampleRate) * p.config.OutputChannels
+
+	// Incoming chunks may have any size, so keep the samples that do not
+	// fill a whole buffer (and a trailing odd byte) until the next chunk arrives
+	// The buffers are reused for every chunk to keep the playback loop free of allocations
+	var pending, decoded []int16
+	aligner := pcm.NewAligner(binary.LittleEndian)
 
 	for {
 		select {
@@ -76,39 +128,179 @@ func (p *PortaudioPlayer) PlayStream(ctx context.Context, audioData <-chan []byt
 			return ctx.Err()
 		case audioBytes, ok := <-audioData:
 			if !ok {
-				return nil // Channel closed, playback complete
+				// Channel closed, flush the remaining audio and finish playback
+				if len(pending) > 0 {
+					if err := start(); err != nil {
+						return err
+					}
+					for len(pending) > len(p.audioBuffer) {
+						p.writeBuffer(pending[:len(p.audioBuffer)])
+						pending = pending[len(p.audioBuffer):]
+					}
+					p.writeBuffer(pending)
+				}
+				if err := p.Drain(ctx); err != nil {
+					return err
+				}
+				drained = true
+				return nil
 			}
 
-			// Convert bytes to int16 samples
-			samples := p.convertBytesToSamples(audioBytes)
-
-			// Copy samples to buffer
-			expectedSamples := len(p.audioBuffer)
-
-			if len(samples) >= expectedSamples {
-				copy(p.audioBuffer, samples[:expectedSamples])
+			if p.resampler != nil || p.upmix > 1 {
+				decoded = aligner.Append(decoded[:0], audioBytes)
+				pending = p.convert(pending, decoded)
 			} else {
-				copy(p.audioBuffer, samples)
-				// Zero-fill remaining buffer
-				for i := len(samples); i < expectedSamples; i++ {
-					p.audioBuffer[i] = 0
-				}
+				pending = aligner.Append(pending, audioBytes)
 			}
 
-			if err := p.stream.Write(); err != nil {
-				log.Printf("Error writing audio: %v", err)
+			if !started && len(pending) < prebufferSamples {
 				continue
 			}
+			if err := start(); err != nil {
+				return err
+			}
+
+			written := 0
+			for len(pending)-written >= len(p.audioBuffer) {
+				p.writeBuffer(pending[written : written+len(p.audioBuffer)])
+				written += len(p.audioBuffer)
+			}
+			// Move the remainder to the front so the buffer does not keep growing
+			pending = pending[:copy(pending, pending[written:])]
+		}
+	}
+}
+
+// SetInputFormat prepares the player for audio with the given sample rate and
+// channel count. The output stream is reopened with the new format, or the
+// nearest one the device supports. When the device rejects it altogether the
+// previous stream is kept and the audio is converted to it
+func (p *PortaudioPlayer) SetInputFormat(sampleRate float64, channels int) error {
+	p.resampler, p.upmix = nil, 0
+	if sampleRate == p.config.SampleRate && channels == p.config.OutputChannels {
+		return nil
+	}
+
+	previous := p.config
+	err := p.reopen(sampleRate, channels)
+	if err == nil {
+		log.Printf("Playback reconfigured to %.0f Hz, %d channel(s)", p.config.SampleRate, p.config.OutputChannels)
+		return nil
+	}
+	log.Printf("Output device rejected %.0f Hz, %d channel(s): %v", sampleRate, channels, err)
+
+	// Fall back to the previous stream and convert into it
+	if err := p.reopen(previous.SampleRate, previous.OutputChannels); err != nil {
+		return fmt.Errorf("failed to restore playback stream: %w", err)
+	}
+	return p.convertFrom(sampleRate, channels)
+}
+
+// convertFrom sets up the conversion of audio in the given format to the
+// stream format. Mono audio can be played on any number of channels, other
+// channel counts must match the stream
+func (p *PortaudioPlayer) convertFrom(sampleRate float64, channels int) error {
+	p.resampler, p.upmix = nil, 0
+	if channels != p.config.OutputChannels {
+		if channels != 1 {
+			return fmt.Errorf("unsupported channel count %d, output has %d", channels, p.config.OutputChannels)
+		}
+		p.upmix = p.config.OutputChannels
+		log.Printf("Playing mono audio on %d channels", p.upmix)
+	}
+	if sampleRate != p.config.SampleRate {
+		p.resampler = NewResampler(sampleRate, p.config.SampleRate, channels)
+		log.Printf("Resampling playback from %.0f Hz to %.0f Hz", sampleRate, p.config.SampleRate)
+	}
+	return nil
+}
+
+// convert appends the samples converted to the stream format to dst
+func (p *PortaudioPlayer) convert(dst, samples []int16) []int16 {
+	if p.resampler != nil {
+		p.resampled = p.resampler.Append(p.resampled[:0], samples)
+		samples = p.resampled
+	}
+	if p.upmix <= 1 {
+		return append(dst, samples...)
+	}
+	for _, sample := range samples {
+		for c := 0; c < p.upmix; c++ {
+			dst = append(dst, sample)
+		}
+	}
+	return dst
+}
+
+// reopen closes the current stream and opens a new one with the given format
+func (p *PortaudioPlayer) reopen(sampleRate float64, channels int) error {
+	if err := p.Close(); err != nil {
+		return err
+	}
+	p.stream = nil
+
+	p.config.SampleRate = sampleRate
+	p.config.OutputChannels = channels
+	p.audioBuffer = make([]int16, p.config.FramesPerBuffer*channels)
+
+	return p.Open()
+}
+
+// writeBuffer copies samples into the stream buffer, zero-filling
+// any remainder, and writes it to the output device
+func (p *PortaudioPlayer) writeBuffer(samples []int16) {
+	// Copy samples to buffer
+	expectedSamples := len(p.audioBuffer)
+
+	if len(samples) >= expectedSamples {
+		copy(p.audioBuffer, samples[:expectedSamples])
+	} else {
+		copy(p.audioBuffer, samples)
+		// Zero-fill remaining buffer
+		clear(p.audioBuffer[len(samples):])
+	}
+
+	if err := p.stream.Write(); err != nil {
+		log.Printf("Error writing audio: %v", err)
+	}
+	p.framesWritten += int64(p.config.FramesPerBuffer)
+}
+
+// Drain blocks until all audio written to the stream has been played by the
+// device, based on the stream clock and the output latency
+func (p *PortaudioPlayer) Drain(ctx context.Context) error {
+	if p.stream == nil || !p.playing {
+		return nil
+	}
+
+	played := time.Duration(float64(p.framesWritten) / p.config.SampleRate * float64(time.Second))
+	if info := p.stream.Info(); info != nil {
+		played += info.OutputLatency
+	}
+
+	for {
+		remaining := played - p.elapsed()
+		if remaining <= 0 {
+			return nil
+		}
+		if remaining > 20*time.Millisecond {
+			remaining = 20 * time.Millisecond
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(remaining):
 		}
 	}
 }
 
-func (p *PortaudioPlayer) convertBytesToSamples(audioBytes []byte) []int16 {
-	samples := make([]int16, len(audioBytes)/2)
-	for i := 0; i < len(samples); i++ {
-		samples[i] = int16(binary.LittleEndian.Uint16(audioBytes[i*2 : i*2+2]))
+// elapsed returns the playback time since the stream was started
+func (p *PortaudioPlayer) elapsed() time.Duration {
+	if now := p.stream.Time(); now > 0 && p.startTime > 0 {
+		return now - p.startTime
 	}
-	return samples
+	return time.Since(p.startWall)
 }
 
 func (p *PortaudioPlayer) Close() error {
@@ -0,0 +1,83 @@
+//go:build cgo
+
+package sound
+
+import (
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/gordonklaus/portaudio"
+)
+
+// StandardSampleRates are tried, nearest first, when a device rejects the requested rate
+var StandardSampleRates = []float64{8000, 11025, 16000, 22050, 32000, 44100, 48000, 88200, 96000}
+
+// ProbeFormat returns the format nearest to the requested one that the default
+// input or output device supports. The requested rate is kept when possible,
+// otherwise the nearest standard rate or the device's default rate is used,
+// and a channel count above the device maximum is reduced to it. buffer is a
+// buffer of the stream's sample type, e.g. []int16
+func ProbeFormat(input bool, channels int, sampleRate float64, framesPerBuffer int, buffer interface{}) (float64, int, error) {
+	device, maxChannels, err := defaultDevice(input)
+	if err != nil {
+		return 0, 0, err
+	}
+	if maxChannels < 1 {
+		return 0, 0, fmt.Errorf("device %q has no %s channels", device.Name, direction(input))
+	}
+	channels = min(channels, maxChannels)
+
+	rates := append([]float64{sampleRate, device.DefaultSampleRate}, StandardSampleRates...)
+	slices.SortStableFunc(rates, func(a, b float64) int {
+		da, db := math.Abs(a-sampleRate), math.Abs(b-sampleRate)
+		switch {
+		case da < db:
+			return -1
+		case da > db:
+			return 1
+		}
+		return 0
+	})
+
+	for _, rate := range slices.Compact(rates) {
+		params := portaudio.HighLatencyParameters(nil, device)
+		stream := &params.Output
+		if input {
+			params = portaudio.HighLatencyParameters(device, nil)
+			stream = &params.Input
+		}
+		stream.Channels = channels
+		params.SampleRate = rate
+		params.FramesPerBuffer = framesPerBuffer
+		if portaudio.IsFormatSupported(params, buffer) == nil {
+			return rate, channels, nil
+		}
+	}
+	return 0, 0, fmt.Errorf("device %q supports none of the standard sample rates with %d %s channel(s)",
+		device.Name, channels, direction(input))
+}
+
+// defaultDevice returns the default input or output device and its channel count
+func defaultDevice(input bool) (*portaudio.DeviceInfo, int, error) {
+	if input {
+		device, err := portaudio.DefaultInputDevice()
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to find input device: %w", err)
+		}
+		return device, device.MaxInputChannels, nil
+	}
+	device, err := portaudio.DefaultOutputDevice()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to find output device: %w", err)
+	}
+	return device, device.MaxOutputChannels, nil
+}
+
+// direction names the stream direction in messages
+func direction(input bool) string {
+	if input {
+		return "input"
+	}
+	return "output"
+}
@@ -0,0 +1,78 @@
+package sound
+
+import (
+	"slices"
+
+	"github.com/d1nch8g/aihr/pcm"
+)
+
+// Resampler converts interleaved 16-bit PCM between sample rates using linear
+// interpolation. It keeps state between calls so chunk boundaries stay continuous
+type Resampler struct {
+	ratio    float64 // Input samples per output sample
+	channels int
+	position float64 // Position of the next output frame relative to the current input chunk
+	last     []int16 // Last input frame of the previous chunk
+}
+
+// NewResampler creates a resampler from one sample rate to another
+func NewResampler(fromRate, toRate float64, channels int) *Resampler {
+	if channels <= 0 {
+		channels = 1
+	}
+	return &Resampler{
+		ratio:    fromRate / toRate,
+		channels: channels,
+		position: 0,
+	}
+}
+
+// Process resamples the interleaved input samples and returns the output samples
+func (r *Resampler) Process(input []int16) []int16 {
+	return r.Append(nil, input)
+}
+
+// Append resamples the interleaved input samples and appends the output to dst,
+// so callers can reuse one buffer across chunks
+func (r *Resampler) Append(dst, input []int16) []int16 {
+	frames := len(input) / r.channels
+	if frames == 0 {
+		return dst
+	}
+
+	// frame returns the input frame at index i, where -1 is the last frame of the previous chunk
+	frame := func(i, channel int) float64 {
+		if i < 0 {
+			if r.last == nil {
+				return float64(input[channel])
+			}
+			return float64(r.last[channel])
+		}
+		return float64(input[i*r.channels+channel])
+	}
+
+	output := slices.Grow(dst, int(float64(frames)/r.ratio+1)*r.channels)
+	for r.position < float64(frames-1) {
+		index := int(r.position)
+		if r.position < 0 {
+			index = -1
+		}
+		fraction := r.position - float64(index)
+
+		for channel := 0; channel < r.channels; channel++ {
+			a := frame(index, channel)
+			b := frame(index+1, channel)
+			output = append(output, pcm.Clamp16(a+(b-a)*fraction))
+		}
+		r.position += r.ratio
+	}
+
+	// Carry the fractional position over to the next chunk
+	r.position -= float64(frames)
+	if r.last == nil {
+		r.last = make([]int16, r.channels)
+	}
+	copy(r.last, input[(frames-1)*r.channels:frames*r.channels])
+
+	return output
+}
@@ -0,0 +1,23 @@
+package sound
+
+import "testing"
+
+// ramp returns count samples of a repeating ramp
+func ramp(count int) []int16 {
+	samples := make([]int16, count)
+	for i := range samples {
+		samples[i] = int16((i%512 - 256) * 128)
+	}
+	return samples
+}
+
+func BenchmarkResamplerAppend(b *testing.B) {
+	resampler := NewResampler(48000, 16000, 1)
+	input := ramp(1024)
+	var output []int16
+	b.ReportAllocs()
+	b.SetBytes(int64(len(input) * 2))
+	for b.Loop() {
+		output = resampler.Append(output[:0], input)
+	}
+}
@@ -1,6 +1,30 @@
 package sound
 
-import "context"
+import (
+	"context"
+	"time"
+)
+
+// PlayerConfig represents the configuration for audio playback
+type PlayerConfig struct {
+	SampleRate      float64
+	FramesPerBuffer int
+	InputChannels   int
+	OutputChannels  int
+
+	// PrebufferDuration is the amount of audio collected before playback
+	// starts, so slow streaming sources do not stutter at the beginning
+	PrebufferDuration time.Duration
+}
+
+func GetDefaultConfig() PlayerConfig {
+	return PlayerConfig{
+		SampleRate:      44100,
+		FramesPerBuffer: 1024,
+		InputChannels:   0,
+		OutputChannels:  2, // Default to stereo
+	}
+}
 
 // Player defines the interface for audio playback
 type Player interface {
@@ -10,6 +34,23 @@ type Player interface {
 	// Terminate terminates the audio playback system
 	Terminate()
 
-	// PlayStream plays audio data from a channel
+	// Open opens the playback stream with configured parameters
+	Open() error
+
+	// Close closes the playback stream
+	Close() error
+
+	// PlayStream plays audio data from a channel. When the channel is closed it
+	// returns only after the buffered audio has actually been played
 	PlayStream(ctx context.Context, audioData <-chan []byte) error
+
+	// Drain blocks until all audio written so far has been played by the device
+	Drain(ctx context.Context) error
+}
+
+// FormatConfigurer is implemented by players that can adapt to the
+// sample rate and channel count of the audio they are given
+type FormatConfigurer interface {
+	// SetInputFormat prepares the player for audio in the given format
+	SetInputFormat(sampleRate float64, channels int) error
 }
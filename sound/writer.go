@@ -0,0 +1,77 @@
+package sound
+
+import (
+	"context"
+	"io"
+	"log"
+	"os"
+)
+
+// WriterPlayer writes raw 16-bit PCM to an io.Writer instead of a device,
+// e.g. a named pipe or file in headless deployments
+type WriterPlayer struct {
+	writer io.Writer
+	config PlayerConfig
+}
+
+// Ensure WriterPlayer implements the player interfaces
+var (
+	_ Player           = (*WriterPlayer)(nil)
+	_ FormatConfigurer = (*WriterPlayer)(nil)
+)
+
+// NewWriterPlayer creates a player writing PCM to writer
+func NewWriterPlayer(writer io.Writer, config PlayerConfig) *WriterPlayer {
+	return &WriterPlayer{
+		writer: writer,
+		config: config,
+	}
+}
+
+func (w *WriterPlayer) Initialize() error { return nil }
+
+func (w *WriterPlayer) Terminate() {}
+
+func (w *WriterPlayer) Open() error { return nil }
+
+// Close closes the writer, standard output is left open for the process
+func (w *WriterPlayer) Close() error {
+	if w.writer == os.Stdout {
+		return nil
+	}
+	if closer, ok := w.writer.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// SetInputFormat records the format; consumers of the output must expect it
+func (w *WriterPlayer) SetInputFormat(sampleRate float64, channels int) error {
+	if sampleRate != w.config.SampleRate || channels != w.config.OutputChannels {
+		log.Printf("Headless playback format: %.0f Hz, %d channel(s)", sampleRate, channels)
+	}
+	w.config.SampleRate = sampleRate
+	w.config.OutputChannels = channels
+	return nil
+}
+
+func (w *WriterPlayer) PlayStream(ctx context.Context, audioData <-chan []byte) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case audioBytes, ok := <-audioData:
+			if !ok {
+				return nil
+			}
+			if _, err := w.writer.Write(audioBytes); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Drain returns immediately, written audio is owned by the writer
+func (w *WriterPlayer) Drain(ctx context.Context) error {
+	return nil
+}
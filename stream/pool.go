@@ -0,0 +1,35 @@
+package stream
+
+import "sync"
+
+// Pool reuses byte buffers of a fixed capacity between chunks
+type Pool struct {
+	size int
+	pool sync.Pool
+}
+
+// NewPool creates a pool of buffers with the given capacity
+func NewPool(size int) *Pool {
+	p := &Pool{size: size}
+	p.pool.New = func() any {
+		buffer := make([]byte, 0, size)
+		return &buffer
+	}
+	return p
+}
+
+// Get returns an empty buffer with at least the pool capacity
+func (p *Pool) Get() []byte {
+	return (*p.pool.Get().(*[]byte))[:0]
+}
+
+// Put returns a buffer to the pool. Buffers of a different capacity are left
+// to the garbage collector so the pool never grows beyond its buffer size.
+// Putting to a nil pool does nothing
+func (p *Pool) Put(buffer []byte) {
+	if p == nil || cap(buffer) != p.size {
+		return
+	}
+	buffer = buffer[:0]
+	p.pool.Put(&buffer)
+}
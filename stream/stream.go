@@ -0,0 +1,147 @@
+// Package stream connects audio stages with bounded channels. Each pipe
+// holds at most a fixed number of bytes and applies an overflow policy when
+// the producer outpaces the consumer
+package stream
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+)
+
+// Policy decides what happens to a chunk that does not fit into a full pipe
+type Policy int
+
+const (
+	// Block stops reading from the producer until the consumer catches up
+	Block Policy = iota
+	// DropNewest discards the incoming chunk
+	DropNewest
+	// DropOldest discards the oldest queued chunks to make room for the incoming one
+	DropOldest
+)
+
+// String returns the policy name as accepted by ParsePolicy
+func (p Policy) String() string {
+	switch p {
+	case Block:
+		return "block"
+	case DropNewest:
+		return "drop-newest"
+	case DropOldest:
+		return "drop-oldest"
+	default:
+		return fmt.Sprintf("Policy(%d)", int(p))
+	}
+}
+
+// ParsePolicy returns the policy with the given name
+func ParsePolicy(name string) (Policy, error) {
+	switch name {
+	case "block":
+		return Block, nil
+	case "drop-newest":
+		return DropNewest, nil
+	case "drop-oldest":
+		return DropOldest, nil
+	default:
+		return 0, fmt.Errorf("unknown stream policy %q", name)
+	}
+}
+
+// Pipe moves byte chunks from In to Out, queueing at most maxBytes in between.
+// With the Block policy the last accepted chunk may overshoot the limit. Out
+// is closed once In is closed and the queue is drained, or when the context
+// is cancelled
+type Pipe struct {
+	in      chan []byte
+	out     chan []byte
+	policy  Policy
+	pool    *Pool
+	dropped atomic.Int64
+}
+
+// NewPipe starts a pipe holding up to maxBytes of queued chunks. A chunk larger
+// than maxBytes is still accepted when the queue is empty. Dropped chunks are
+// returned to the pool when one is given
+func NewPipe(ctx context.Context, maxBytes int, policy Policy, pool *Pool) *Pipe {
+	p := &Pipe{
+		in:     make(chan []byte, 1), // Lets non-blocking producers hand over a chunk while the pipe is sending
+		out:    make(chan []byte),
+		policy: policy,
+		pool:   pool,
+	}
+	go p.run(ctx, maxBytes)
+	return p
+}
+
+// In returns the channel the producer writes to and closes when done
+func (p *Pipe) In() chan<- []byte {
+	return p.in
+}
+
+// Out returns the channel the consumer reads from
+func (p *Pipe) Out() <-chan []byte {
+	return p.out
+}
+
+// Dropped returns the number of chunks discarded by the overflow policy
+func (p *Pipe) Dropped() int64 {
+	return p.dropped.Load()
+}
+
+func (p *Pipe) run(ctx context.Context, maxBytes int) {
+	defer close(p.out)
+
+	var queue [][]byte
+	queued := 0
+	in := p.in
+
+	for in != nil || len(queue) > 0 {
+		// Stop reading from a blocked producer while the queue is full
+		receive := in
+		if p.policy == Block && len(queue) > 0 && queued >= maxBytes {
+			receive = nil
+		}
+		var send chan []byte
+		var next []byte
+		if len(queue) > 0 {
+			send = p.out
+			next = queue[0]
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case send <- next:
+			queue[0] = nil
+			queue = queue[1:]
+			queued -= len(next)
+		case chunk, ok := <-receive:
+			if !ok {
+				in = nil
+				continue
+			}
+			switch {
+			case len(queue) == 0 || queued+len(chunk) <= maxBytes:
+			case p.policy == DropNewest:
+				p.drop(chunk)
+				continue
+			case p.policy == DropOldest:
+				for len(queue) > 0 && queued+len(chunk) > maxBytes {
+					queued -= len(queue[0])
+					p.drop(queue[0])
+					queue[0] = nil
+					queue = queue[1:]
+				}
+			}
+			queue = append(queue, chunk)
+			queued += len(chunk)
+		}
+	}
+}
+
+func (p *Pipe) drop(chunk []byte) {
+	p.dropped.Add(1)
+	p.pool.Put(chunk)
+}
@@ -0,0 +1,197 @@
+package stt
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Assembler merges the finals recognized during one answer into one
+// utterance. Recognizers end a final at every pause, so an answer arrives as
+// several fragments that are often cut in the middle of a sentence
+type Assembler struct {
+	fragments  []string
+	words      []Word
+	confidence float64
+	languages  []string
+}
+
+// Add appends a final to the answer
+func (a *Assembler) Add(utterance Utterance) {
+	text := strings.TrimSpace(utterance.Text)
+	if text == "" {
+		return
+	}
+	a.fragments = append(a.fragments, text)
+	a.words = append(a.words, utterance.Words...)
+	if utterance.Confidence > 0 && (a.confidence == 0 || utterance.Confidence < a.confidence) {
+		a.confidence = utterance.Confidence
+	}
+	if utterance.Language != "" && !slices.Contains(a.languages, utterance.Language) {
+		a.languages = append(a.languages, utterance.Language)
+	}
+}
+
+// Empty reports whether no final was added
+func (a *Assembler) Empty() bool {
+	return len(a.fragments) == 0
+}
+
+// Text returns the fragments joined into punctuated sentences, see Punctuate
+func (a *Assembler) Text() string {
+	return Punctuate(a.fragments)
+}
+
+// Words returns the timings of the words of every fragment
+func (a *Assembler) Words() []Word {
+	return a.words
+}
+
+// Confidence returns the lowest confidence of the fragments, zero when the
+// recognizer reported none
+func (a *Assembler) Confidence() float64 {
+	return a.confidence
+}
+
+// Languages returns the detected languages in the order they were first spoken
+func (a *Assembler) Languages() []string {
+	return a.languages
+}
+
+// Words asking a question when they are followed by a word of a set: an
+// auxiliary verb before its subject, e.g. "do you", or a question word
+// before a verb, e.g. "how do", but not "when I was", which starts a clause
+var (
+	questionAuxiliaries = wordSet("is are was were do does did can could will would should have has")
+	questionSubjects    = wordSet("you i we they it there he she this that")
+	questionWords       = wordSet("what why how when where who which")
+	questionVerbs       = wordSet("is are was were do does did can could will would should many much long")
+
+	questionWordsRu    = wordSet("что почему зачем как когда где куда откуда кто какой какая какое какие сколько")
+	questionSubjectsRu = wordSet("вы ты у это")
+)
+
+// pronounPattern matches a lowercase standalone "i" with its contraction or
+// the word after it
+var pronounPattern = regexp.MustCompile(`\bi(?:'[a-z]+|\s+[\p{L}']+)`)
+
+// pronounVerbs are words that follow the pronoun "I" and not a variable named
+// i, as in "for i in range" or "index i". Past tenses ending in -ed and
+// negations ending in -n't are recognized without a list
+var pronounVerbs = wordSet("am was have had do did can could will would should must may might " +
+	"think thought know knew want need like love mean guess believe feel prefer hope " +
+	"work use build built write wrote lead led make made get got go went start join " +
+	"learn learnt left took take see saw say said agree enjoy spent " +
+	"also just really never always usually still actually personally mostly mainly only")
+
+// wordSet returns the set of the space-separated words
+func wordSet(words string) map[string]bool {
+	set := make(map[string]bool)
+	for _, word := range strings.Fields(words) {
+		set[word] = true
+	}
+	return set
+}
+
+// Punctuate joins the fragments of an answer into sentences. A fragment that
+// does not end a sentence continues into the next one, unless the next one
+// starts with a capital letter, as recognizers that punctuate start their
+// sentences, or opens like a question. Sentences start with a capital letter
+// and end with a question mark when they open like a question, with a period
+// otherwise. Punctuation already in the fragments is kept
+func Punctuate(fragments []string) string {
+	var sentences []string
+	current := ""
+	for _, fragment := range fragments {
+		fragment = strings.TrimSpace(fragment)
+		if fragment == "" {
+			continue
+		}
+		if current != "" && startsSentence(fragment) {
+			sentences = append(sentences, current)
+			current = ""
+		}
+		if current != "" {
+			current += " "
+		}
+		current += fragment
+		if endsSentence(current) {
+			sentences = append(sentences, current)
+			current = ""
+		}
+	}
+	if current != "" {
+		sentences = append(sentences, current)
+	}
+
+	for i, sentence := range sentences {
+		sentences[i] = finishSentence(sentence)
+	}
+	return strings.Join(sentences, " ")
+}
+
+// endsSentence reports whether the text ends with sentence punctuation
+func endsSentence(text string) bool {
+	last, _ := utf8.DecodeLastRuneInString(text)
+	return strings.ContainsRune(".?!…", last)
+}
+
+// startsSentence reports whether a fragment opens like a question or starts
+// with a capital letter other than the English pronoun "I", which is
+// capitalized mid-sentence
+func startsSentence(fragment string) bool {
+	words := strings.Fields(strings.ToLower(fragment))
+	if isQuestion(words) {
+		return true
+	}
+	first, _ := utf8.DecodeRuneInString(fragment)
+	return unicode.IsUpper(first) && words[0] != "i" && !strings.HasPrefix(words[0], "i'")
+}
+
+// finishSentence capitalizes the sentence and ends it with a question mark
+// when it asks a question, with a period otherwise
+func finishSentence(sentence string) string {
+	sentence = pronounPattern.ReplaceAllStringFunc(sentence, capitalizePronoun)
+	first, size := utf8.DecodeRuneInString(sentence)
+	sentence = string(unicode.ToUpper(first)) + sentence[size:]
+	if endsSentence(sentence) {
+		return sentence
+	}
+	sentence = strings.TrimRight(sentence, ",;:-– ")
+
+	if isQuestion(strings.Fields(strings.ToLower(sentence))) {
+		return sentence + "?"
+	}
+	return sentence + "."
+}
+
+// capitalizePronoun capitalizes a match of pronounPattern when the "i"
+// is followed by a contraction or a verb
+func capitalizePronoun(match string) string {
+	next := strings.TrimLeftFunc(match[1:], unicode.IsSpace)
+	if strings.HasPrefix(next, "'") || pronounVerbs[strings.ToLower(next)] ||
+		strings.HasSuffix(next, "ed") || strings.HasSuffix(next, "n't") {
+		return "I" + match[1:]
+	}
+	return match
+}
+
+// isQuestion reports whether the words of a sentence without punctuation
+// ask a question
+func isQuestion(words []string) bool {
+	if len(words) < 2 {
+		return false
+	}
+	first, second := strings.Trim(words[0], ","), strings.Trim(words[1], ",")
+	switch {
+	case questionAuxiliaries[first] && questionSubjects[second]:
+		return true
+	case questionWords[first] && questionVerbs[second]:
+		return true
+	case questionWordsRu[first] && questionSubjectsRu[second]:
+		return true
+	}
+	return second == "ли" || first == "разве" || first == "неужели"
+}
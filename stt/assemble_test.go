@@ -0,0 +1,47 @@
+package stt
+
+import "testing"
+
+func TestPunctuate(t *testing.T) {
+	tests := []struct {
+		fragments []string
+		want      string
+	}{
+		{[]string{"when i was at yandex", "i built payments"}, "When I was at yandex I built payments."},
+		{[]string{"i think", "what is the team size"}, "I think. What is the team size?"},
+		{[]string{"i'm a backend developer"}, "I'm a backend developer."},
+		{[]string{"i don't know", "i worked on it"}, "I don't know I worked on it."},
+		{[]string{"for i in range n we sum the items"}, "For i in range n we sum the items."},
+		{[]string{"the loop reads index i", "then writes it"}, "The loop reads index i then writes it."},
+		{[]string{"I write Go.", "It is fast."}, "I write Go. It is fast."},
+		{[]string{"я работал с го пять лет", "можно ли работать удаленно"}, "Я работал с го пять лет. Можно ли работать удаленно?"},
+		{[]string{"  ", "so,"}, "So."},
+		{nil, ""},
+	}
+
+	for _, test := range tests {
+		if got := Punctuate(test.fragments); got != test.want {
+			t.Errorf("Punctuate(%q) = %q, want %q", test.fragments, got, test.want)
+		}
+	}
+}
+
+func TestAssembler(t *testing.T) {
+	var answer Assembler
+	if !answer.Empty() {
+		t.Fatal("new assembler is not empty")
+	}
+	answer.Add(Utterance{Text: "i use go", Confidence: 0.9, Language: "en"})
+	answer.Add(Utterance{Text: " "})
+	answer.Add(Utterance{Text: "and rust", Confidence: 0.7, Language: "en"})
+
+	if got, want := answer.Text(), "I use go and rust."; got != want {
+		t.Errorf("Text() = %q, want %q", got, want)
+	}
+	if got := answer.Confidence(); got != 0.7 {
+		t.Errorf("Confidence() = %v, want 0.7", got)
+	}
+	if got := answer.Languages(); len(got) != 1 || got[0] != "en" {
+		t.Errorf("Languages() = %v, want [en]", got)
+	}
+}
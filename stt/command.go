@@ -0,0 +1,96 @@
+package stt
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// CommandRecognizer runs a local speech recognizer such as a Vosk or
+// whisper.cpp wrapper for every recognition stream. Raw 16-bit mono PCM is
+// written to the command's stdin and every line it prints to stdout is a final
+// result, so recognition works offline
+type CommandRecognizer struct {
+	program string
+	args    []string
+}
+
+// Ensure CommandRecognizer implements STTClient interface
+var _ STTClient = (*CommandRecognizer)(nil)
+
+// NewCommandRecognizer creates a recognizer from a command line. A {rate}
+// argument is replaced with the sample rate, e.g. "vosk-stream --rate {rate}"
+func NewCommandRecognizer(command string) (*CommandRecognizer, error) {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("STT command is empty")
+	}
+	if _, err := exec.LookPath(fields[0]); err != nil {
+		return nil, fmt.Errorf("STT command not found: %w", err)
+	}
+	return &CommandRecognizer{program: fields[0], args: fields[1:]}, nil
+}
+
+// StreamRecognize runs the command, feeds it the audio and sends its output lines to results
+func (c *CommandRecognizer) StreamRecognize(ctx context.Context, audioData <-chan []byte, results chan<- string, sampleRate int64) error {
+	defer close(results)
+
+	args := make([]string, len(c.args))
+	for i, arg := range c.args {
+		args[i] = strings.ReplaceAll(arg, "{rate}", strconv.FormatInt(sampleRate, 10))
+	}
+
+	cmd := exec.CommandContext(ctx, c.program, args...)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start %s: %w", c.program, err)
+	}
+
+	// The audio is written while the results are read, closing stdin ends the stream
+	go func() {
+		defer stdin.Close()
+		for chunk := range audioData {
+			if _, err := stdin.Write(chunk); err != nil {
+				return
+			}
+		}
+	}()
+
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		select {
+		case results <- text:
+		case <-ctx.Done():
+			cmd.Wait()
+			return ctx.Err()
+		}
+	}
+
+	err = scanner.Err()
+	if waitErr := cmd.Wait(); err == nil && waitErr != nil && ctx.Err() == nil {
+		return fmt.Errorf("%s failed: %w", c.program, waitErr)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read recognized text: %w", err)
+	}
+	return nil
+}
+
+// Close does nothing, a process is started per recognition stream
+func (c *CommandRecognizer) Close() error {
+	return nil
+}
@@ -0,0 +1,91 @@
+package stt
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Complexity expressions as recognizers spell them, e.g. "n log n" or "n squared plus m"
+const (
+	complexityVariable = `(?:[a-z]|\d+|one)`
+	complexityTerm     = `(?:2 to the (?:power of )?` + complexityVariable +
+		`|log (?:of )?` + complexityVariable +
+		`|` + complexityVariable + ` log ` + complexityVariable +
+		`|` + complexityVariable + `(?: squared| cubed| factorial)?)`
+	complexityExpression = complexityTerm + `(?: (?:plus|times) ` + complexityTerm + `)*`
+)
+
+var (
+	// bigOPattern matches "big o of n squared" and similar
+	bigOPattern = regexp.MustCompile(`(?i)\b(?:big[ -]?o|o) of (` + complexityExpression + `)\b`)
+
+	// powerPattern matches powers of single-letter variables outside big O notation
+	powerPattern = regexp.MustCompile(`(?i)\b([a-z]) (squared|cubed)\b`)
+
+	// spelledPattern matches three or more letters spelled out one by one, e.g. "h t t p"
+	spelledPattern = regexp.MustCompile(`(?i)\b[a-z](?: [a-z]\b){2,}`)
+)
+
+// complexityReplacer rewrites the words of a complexity expression as notation
+var complexityReplacer = strings.NewReplacer(
+	"2 to the power of ", "2^",
+	"2 to the ", "2^",
+	"log of ", "log ",
+	" squared", "²",
+	" cubed", "³",
+	" factorial", "!",
+	" plus ", " + ",
+	" times ", "·",
+	"one", "1",
+)
+
+// technicalTerms restores the spelling of terms recognizers split or lowercase
+var technicalTerms = []struct {
+	pattern *regexp.Regexp
+	term    string
+}{
+	{regexp.MustCompile(`(?i)\bgo ?routines\b`), "goroutines"},
+	{regexp.MustCompile(`(?i)\bgo ?routine\b`), "goroutine"},
+	{regexp.MustCompile(`(?i)\bjava ?script\b`), "JavaScript"},
+	{regexp.MustCompile(`(?i)\btype ?script\b`), "TypeScript"},
+	{regexp.MustCompile(`(?i)\bgit ?hub\b`), "GitHub"},
+	{regexp.MustCompile(`(?i)\bgit ?lab\b`), "GitLab"},
+	{regexp.MustCompile(`(?i)\bpost ?gres(?:ql)?\b`), "PostgreSQL"},
+	{regexp.MustCompile(`(?i)\bmy ?sql\b`), "MySQL"},
+	{regexp.MustCompile(`(?i)\bno ?sql\b`), "NoSQL"},
+	{regexp.MustCompile(`(?i)\bgraph ?ql\b`), "GraphQL"},
+	{regexp.MustCompile(`(?i)\bg ?rpc\b`), "gRPC"},
+	{regexp.MustCompile(`(?i)\bkubernetes\b`), "Kubernetes"},
+	{regexp.MustCompile(`(?i)\bdocker\b`), "Docker"},
+	{regexp.MustCompile(`(?i)\bredis\b`), "Redis"},
+	{regexp.MustCompile(`(?i)\bkafka\b`), "Kafka"},
+	{regexp.MustCompile(`(?i)\b(api|sql|json|yaml|http|https|tcp|udp|dns|cpu|gpu|ci|cd)\b`), ""},
+}
+
+// NormalizeTechnical rewrites technical content of a transcript in written
+// form: complexity like "big o of n squared" becomes "O(n²)", identifiers
+// spelled letter by letter are joined, and common terms get their usual
+// spelling. Words with inner capitals such as camelCase identifiers are
+// never changed, patterns only match whole words
+func NormalizeTechnical(text string) string {
+	text = bigOPattern.ReplaceAllStringFunc(text, func(match string) string {
+		expression := bigOPattern.FindStringSubmatch(match)[1]
+		return "O(" + complexityReplacer.Replace(strings.ToLower(expression)) + ")"
+	})
+	text = powerPattern.ReplaceAllStringFunc(text, func(match string) string {
+		return complexityReplacer.Replace(strings.ToLower(match))
+	})
+	text = spelledPattern.ReplaceAllStringFunc(text, func(match string) string {
+		return strings.ToUpper(strings.ReplaceAll(match, " ", ""))
+	})
+
+	for _, term := range technicalTerms {
+		if term.term == "" {
+			// Abbreviations are written in capitals
+			text = term.pattern.ReplaceAllStringFunc(text, strings.ToUpper)
+			continue
+		}
+		text = term.pattern.ReplaceAllString(text, term.term)
+	}
+	return text
+}
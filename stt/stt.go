@@ -1,6 +1,9 @@
 package stt
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // STTClient defines the interface for speech-to-text implementations
 type STTClient interface {
@@ -13,3 +16,48 @@ type STTClient interface {
 	// Close closes the STT client and cleans up resources
 	Close() error
 }
+
+// Word is a recognized word with its position from the start of the audio stream
+type Word struct {
+	Text  string
+	Start time.Duration
+	End   time.Duration
+}
+
+// Utterance is a final recognition result with optional word timings
+type Utterance struct {
+	Text       string
+	Words      []Word
+	Confidence float64 // From 0 to 1, zero when the recognizer does not report it
+	Language   string  // Detected language, e.g. "ru-RU", empty when the recognizer does not report it
+}
+
+// Warmer is implemented by recognizers that can open their connection before
+// the first answer, so recognition starts without a TLS handshake
+type Warmer interface {
+	// Warm connects to the service
+	Warm(ctx context.Context) error
+}
+
+// WordRecognizer is implemented by clients that report word timings
+type WordRecognizer interface {
+	// StreamRecognizeWords works like StreamRecognize but sends utterances
+	// with word timings. The results channel is closed when recognition ends
+	StreamRecognizeWords(ctx context.Context, audioData <-chan []byte, results chan<- Utterance, sampleRate int64) error
+}
+
+type partialsKey struct{}
+
+// WithPartials returns a context whose recognition streams pass the
+// hypotheses of the phrase being spoken to onPartial before it is final.
+// Recognizers that do not report partial results ignore it
+func WithPartials(ctx context.Context, onPartial func(text string)) context.Context {
+	return context.WithValue(ctx, partialsKey{}, onPartial)
+}
+
+// Partials returns the function receiving partial results of the context,
+// nil when it has none
+func Partials(ctx context.Context) func(text string) {
+	onPartial, _ := ctx.Value(partialsKey{}).(func(text string))
+	return onPartial
+}
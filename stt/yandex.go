@@ -6,8 +6,13 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"sync"
+	"time"
+
+	"github.com/d1nch8g/aihr/correlation"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/metadata"
 
@@ -15,11 +20,14 @@ import (
 )
 
 type YandexSTTClient struct {
-	client   speechkit.RecognizerClient
-	conn     *grpc.ClientConn
-	iamToken string
-	folderID string
-	language string
+	client    speechkit.RecognizerClient
+	conn      *grpc.ClientConn
+	iamToken  string
+	folderID  string
+	languages []string
+	options   YandexConfig // Recognition options, the current token is iamToken
+
+	tokenMutex sync.RWMutex
 }
 
 type YandexConfig struct {
@@ -27,6 +35,20 @@ type YandexConfig struct {
 	FolderID   string
 	Language   string
 	SampleRate int32
+
+	// Languages are recognized together, e.g. "ru-RU" and "en-US" for
+	// candidates mixing languages. Only Language is recognized when empty
+	Languages []string
+
+	// Model is the recognition model, e.g. "general" or "general:rc", empty for the service default
+	Model string
+
+	// Normalization writes numbers, dates and units in digits and symbols,
+	// LiteratureText rewrites transcripts in literary style with punctuation
+	// and ProfanityFilter masks profanity
+	Normalization   bool
+	ProfanityFilter bool
+	LiteratureText  bool
 }
 
 func NewYandexSTTClient(config YandexConfig) (*YandexSTTClient, error) {
@@ -38,25 +60,89 @@ func NewYandexSTTClient(config YandexConfig) (*YandexSTTClient, error) {
 
 	client := speechkit.NewRecognizerClient(conn)
 
+	languages := config.Languages
+	if len(languages) == 0 {
+		languages = []string{config.Language}
+	}
+
 	return &YandexSTTClient{
-		client:   client,
-		conn:     conn,
-		iamToken: config.IamToken,
-		folderID: config.FolderID,
-		language: config.Language,
+		client:    client,
+		conn:      conn,
+		iamToken:  config.IamToken,
+		folderID:  config.FolderID,
+		languages: languages,
+		options:   config,
 	}, nil
 }
 
+// Warm opens the gRPC channel and waits until it is ready
+func (s *YandexSTTClient) Warm(ctx context.Context) error {
+	s.conn.Connect()
+	for {
+		state := s.conn.GetState()
+		switch state {
+		case connectivity.Ready:
+			return nil
+		case connectivity.TransientFailure, connectivity.Shutdown:
+			return fmt.Errorf("failed to connect to Yandex STT: connection is %s", state)
+		}
+		if !s.conn.WaitForStateChange(ctx, state) {
+			return ctx.Err()
+		}
+	}
+}
+
+// SetIamToken replaces the IAM token used for new recognition streams
+func (s *YandexSTTClient) SetIamToken(iamToken string) {
+	s.tokenMutex.Lock()
+	defer s.tokenMutex.Unlock()
+	s.iamToken = iamToken
+}
+
 func (s *YandexSTTClient) Close() error {
 	return s.conn.Close()
 }
 
+// Ensure YandexSTTClient reports word timings
+var _ WordRecognizer = (*YandexSTTClient)(nil)
+
+// Ensure YandexSTTClient implements Warmer interface
+var _ Warmer = (*YandexSTTClient)(nil)
+
 func (s *YandexSTTClient) StreamRecognize(ctx context.Context, audioData <-chan []byte, results chan<- string, sampleRate int64) error {
+	utterances := make(chan Utterance, cap(results))
+	go func() {
+		defer close(results)
+		for utterance := range utterances {
+			results <- utterance.Text
+		}
+	}()
+
+	return s.StreamRecognizeWords(ctx, audioData, utterances, sampleRate)
+}
+
+// StreamRecognizeWords performs streaming speech recognition and reports word timings
+func (s *YandexSTTClient) StreamRecognizeWords(ctx context.Context, audioData <-chan []byte, results chan<- Utterance, sampleRate int64) error {
+	// results is closed by the receiving goroutine once it is started
+	receiving := false
+	defer func() {
+		if !receiving {
+			close(results)
+		}
+	}()
+
+	s.tokenMutex.RLock()
+	iamToken := s.iamToken
+	s.tokenMutex.RUnlock()
+
 	// Create metadata with authorization
 	md := metadata.Pairs(
-		"authorization", "Bearer "+s.iamToken,
+		"authorization", "Bearer "+iamToken,
 		"x-folder-id", s.folderID,
 	)
+	if id := correlation.Session(ctx); id != "" {
+		md.Set(correlation.RequestIDHeader, id)
+	}
 	ctx = metadata.NewOutgoingContext(ctx, md)
 
 	// Create streaming client
@@ -71,6 +157,7 @@ func (s *YandexSTTClient) StreamRecognize(ctx context.Context, audioData <-chan
 		Event: &speechkit.StreamingRequest_SessionOptions{
 			SessionOptions: &speechkit.StreamingOptions{
 				RecognitionModel: &speechkit.RecognitionModelOptions{
+					Model: s.options.Model,
 					AudioFormat: &speechkit.AudioFormatOptions{
 						AudioFormat: &speechkit.AudioFormatOptions_RawAudio{
 							RawAudio: &speechkit.RawAudio{
@@ -80,14 +167,10 @@ func (s *YandexSTTClient) StreamRecognize(ctx context.Context, audioData <-chan
 							},
 						},
 					},
-					TextNormalization: &speechkit.TextNormalizationOptions{
-						TextNormalization: speechkit.TextNormalizationOptions_TEXT_NORMALIZATION_ENABLED,
-						ProfanityFilter:   false,
-						LiteratureText:    false,
-					},
+					TextNormalization: s.textNormalization(),
 					LanguageRestriction: &speechkit.LanguageRestrictionOptions{
 						RestrictionType: speechkit.LanguageRestrictionOptions_WHITELIST,
-						LanguageCode:    []string{s.language},
+						LanguageCode:    s.languages,
 					},
 					AudioProcessingType: speechkit.RecognitionModelOptions_REAL_TIME,
 				},
@@ -100,6 +183,8 @@ func (s *YandexSTTClient) StreamRecognize(ctx context.Context, audioData <-chan
 	}
 
 	// Start goroutine to handle responses
+	onPartial := Partials(ctx)
+	receiving = true
 	go func() {
 		defer close(results)
 		for {
@@ -112,10 +197,20 @@ func (s *YandexSTTClient) StreamRecognize(ctx context.Context, audioData <-chan
 				return
 			}
 
+			if partial := resp.GetPartial(); partial != nil && onPartial != nil {
+				if alternatives := partial.GetAlternatives(); len(alternatives) > 0 && alternatives[0].GetText() != "" {
+					onPartial(alternatives[0].GetText())
+				}
+			}
 			if resp.GetFinal() != nil {
 				for _, alternative := range resp.GetFinal().GetAlternatives() {
 					if text := alternative.GetText(); text != "" {
-						results <- text
+						results <- Utterance{
+							Text:       text,
+							Words:      convertWords(alternative.GetWords()),
+							Confidence: alternative.GetConfidence(),
+							Language:   detectedLanguage(alternative.GetLanguages()),
+						}
 					}
 				}
 			}
@@ -139,3 +234,41 @@ func (s *YandexSTTClient) StreamRecognize(ctx context.Context, audioData <-chan
 
 	return nil
 }
+
+// textNormalization returns the normalization options of the recognition session
+func (s *YandexSTTClient) textNormalization() *speechkit.TextNormalizationOptions {
+	normalization := speechkit.TextNormalizationOptions_TEXT_NORMALIZATION_DISABLED
+	if s.options.Normalization {
+		normalization = speechkit.TextNormalizationOptions_TEXT_NORMALIZATION_ENABLED
+	}
+	return &speechkit.TextNormalizationOptions{
+		TextNormalization: normalization,
+		ProfanityFilter:   s.options.ProfanityFilter,
+		LiteratureText:    s.options.LiteratureText,
+	}
+}
+
+// detectedLanguage returns the most probable language of a result
+func detectedLanguage(estimations []*speechkit.LanguageEstimation) string {
+	var language string
+	var probability float64
+	for _, estimation := range estimations {
+		if estimation.GetProbability() > probability {
+			language, probability = estimation.GetLanguageCode(), estimation.GetProbability()
+		}
+	}
+	return language
+}
+
+// convertWords maps recognized words to word timings
+func convertWords(words []*speechkit.Word) []Word {
+	result := make([]Word, 0, len(words))
+	for _, word := range words {
+		result = append(result, Word{
+			Text:  word.GetText(),
+			Start: time.Duration(word.GetStartTimeMs()) * time.Millisecond,
+			End:   time.Duration(word.GetEndTimeMs()) * time.Millisecond,
+		})
+	}
+	return result
+}
@@ -0,0 +1,165 @@
+// Package subtitle writes the speech of an interview as WebVTT or SRT
+// captions, timed from the start of the session so they line up with its
+// recording
+package subtitle
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Caption formats, chosen by the extension of the file
+const (
+	FormatWebVTT = "vtt"
+	FormatSRT    = "srt"
+)
+
+// Cue is a phrase of a speaker between two offsets from the start of the session
+type Cue struct {
+	Start   time.Duration
+	End     time.Duration
+	Speaker string
+	Text    string
+}
+
+// FormatOf returns the caption format of a file by its extension
+func FormatOf(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".vtt":
+		return FormatWebVTT, nil
+	case ".srt":
+		return FormatSRT, nil
+	default:
+		return "", fmt.Errorf("unknown caption format of %s, use .vtt or .srt", path)
+	}
+}
+
+// Track writes the cues of a session to a file as they are spoken, so the
+// captions can be followed during the interview. Close rewrites the file
+// with the cues in the order they started. A track is safe for concurrent use
+type Track struct {
+	path   string
+	format string
+	file   *os.File
+	cues   []Cue
+	mutex  sync.Mutex
+}
+
+// Create creates the caption file, its format is chosen by the extension
+func Create(path string) (*Track, error) {
+	format, err := FormatOf(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create caption directory: %w", err)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create caption file: %w", err)
+	}
+	if err := writeHeader(file, format); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to write caption file: %w", err)
+	}
+	return &Track{path: path, format: format, file: file}, nil
+}
+
+// Add appends a cue to the file. Cues without text are skipped
+func (t *Track) Add(cue Cue) error {
+	cue.Text = strings.Join(strings.Fields(cue.Text), " ")
+	if cue.Text == "" {
+		return nil
+	}
+	if cue.End <= cue.Start {
+		cue.End = cue.Start + time.Second
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.cues = append(t.cues, cue)
+	if err := writeCue(t.file, t.format, len(t.cues), cue); err != nil {
+		return fmt.Errorf("failed to write caption: %w", err)
+	}
+	return nil
+}
+
+// Close rewrites the file with the cues sorted by their start and closes it
+func (t *Track) Close() error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	sort.SliceStable(t.cues, func(i, j int) bool {
+		return t.cues[i].Start < t.cues[j].Start
+	})
+	err := rewrite(t.file, t.format, t.cues)
+	if closeErr := t.file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write caption file %s: %w", t.path, err)
+	}
+	return nil
+}
+
+// rewrite replaces the content of the file with the cues in the format
+func rewrite(file *os.File, format string, cues []Cue) error {
+	if err := file.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if err := writeHeader(file, format); err != nil {
+		return err
+	}
+	for i, cue := range cues {
+		if err := writeCue(file, format, i+1, cue); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeHeader starts a caption file
+func writeHeader(w io.Writer, format string) error {
+	if format != FormatWebVTT {
+		return nil
+	}
+	_, err := io.WriteString(w, "WEBVTT\n\n")
+	return err
+}
+
+// writeCue writes the numbered cue, the speaker is a voice span in WebVTT
+// and a prefix in SRT
+func writeCue(w io.Writer, format string, number int, cue Cue) error {
+	var err error
+	if format == FormatWebVTT {
+		_, err = fmt.Fprintf(w, "%d\n%s --> %s\n<v %s>%s\n\n", number,
+			timestamp(cue.Start, "."), timestamp(cue.End, "."), cue.Speaker, escapeVTT(cue.Text))
+	} else {
+		_, err = fmt.Fprintf(w, "%d\n%s --> %s\n%s: %s\n\n", number,
+			timestamp(cue.Start, ","), timestamp(cue.End, ","), cue.Speaker, cue.Text)
+	}
+	return err
+}
+
+// timestamp formats an offset as hh:mm:ss with milliseconds after separator
+func timestamp(offset time.Duration, separator string) string {
+	offset = max(offset, 0)
+	milliseconds := offset.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", milliseconds/3600000, milliseconds/60000%60,
+		milliseconds/1000%60, separator, milliseconds%1000)
+}
+
+// escapeVTT escapes the characters WebVTT cue text reserves for markup
+func escapeVTT(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
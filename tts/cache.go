@@ -0,0 +1,57 @@
+package tts
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// AudioCache keeps the synthesized audio of recurring phrases, like the
+// greeting, on disk so they are synthesized once for all sessions
+type AudioCache struct {
+	dir string
+}
+
+// NewAudioCache creates the cache directory when it does not exist
+func NewAudioCache(dir string) (*AudioCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create speech cache: %w", err)
+	}
+	return &AudioCache{dir: dir}, nil
+}
+
+// Load returns the cached audio of the text spoken with the options
+func (c *AudioCache) Load(text string, options SynthesisOptions) ([]byte, bool) {
+	audio, err := os.ReadFile(c.path(text, options))
+	if err != nil || len(audio) == 0 {
+		return nil, false
+	}
+	return audio, true
+}
+
+// Store saves the audio of the text spoken with the options
+func (c *AudioCache) Store(text string, options SynthesisOptions, audio []byte) error {
+	path := c.path(text, options)
+	temp := path + ".tmp"
+	if err := os.WriteFile(temp, audio, 0o644); err != nil {
+		return fmt.Errorf("failed to write cached speech: %w", err)
+	}
+	if err := os.Rename(temp, path); err != nil {
+		os.Remove(temp)
+		return fmt.Errorf("failed to write cached speech: %w", err)
+	}
+	return nil
+}
+
+// path returns the file of the audio, named by a hash of everything that changes the sound
+func (c *AudioCache) path(text string, options SynthesisOptions) string {
+	sum := sha256.New()
+	for _, part := range []string{text, options.Voice, options.Role, strconv.FormatFloat(options.Speed, 'f', -1, 64)} {
+		sum.Write([]byte(part))
+		sum.Write([]byte{0})
+	}
+	return filepath.Join(c.dir, hex.EncodeToString(sum.Sum(nil))[:32]+".audio")
+}
@@ -0,0 +1,81 @@
+package tts
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+)
+
+// CommandSynthesizer runs a local speech synthesizer such as espeak-ng or
+// piper for every text. The text is written to the command's stdin and the
+// WAV or raw PCM audio it prints to stdout is streamed to the player, so
+// speech works offline
+type CommandSynthesizer struct {
+	program string
+	args    []string
+}
+
+// Ensure CommandSynthesizer implements Synthesizer interface
+var _ Synthesizer = (*CommandSynthesizer)(nil)
+
+// NewCommandSynthesizer creates a synthesizer from a command line, e.g. "espeak-ng --stdout"
+func NewCommandSynthesizer(command string) (*CommandSynthesizer, error) {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("TTS command is empty")
+	}
+	if _, err := exec.LookPath(fields[0]); err != nil {
+		return nil, fmt.Errorf("TTS command not found: %w", err)
+	}
+	return &CommandSynthesizer{program: fields[0], args: fields[1:]}, nil
+}
+
+// SynthesizeToStreamWithContext runs the command and streams its output. Voice and speed
+// options are not passed, they are part of the configured command line
+func (c *CommandSynthesizer) SynthesizeToStreamWithContext(ctx context.Context, text string, options SynthesisOptions, audioData chan<- []byte) error {
+	defer close(audioData)
+
+	cmd := exec.CommandContext(ctx, c.program, c.args...)
+	cmd.Stdin = strings.NewReader(text)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start %s: %w", c.program, err)
+	}
+
+	err = forward(ctx, stdout, audioData)
+	if waitErr := cmd.Wait(); err == nil && waitErr != nil {
+		return fmt.Errorf("%s failed: %w", c.program, waitErr)
+	}
+	return err
+}
+
+// forward sends everything read from r to audioData
+func forward(ctx context.Context, r io.Reader, audioData chan<- []byte) error {
+	for {
+		chunk := make([]byte, 4096)
+		n, err := r.Read(chunk)
+		if n > 0 {
+			select {
+			case audioData <- chunk[:n]:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read synthesized audio: %w", err)
+		}
+	}
+}
+
+// Close does nothing, a process is started per synthesis
+func (c *CommandSynthesizer) Close() error {
+	return nil
+}
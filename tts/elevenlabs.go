@@ -0,0 +1,131 @@
+package tts
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+const (
+	ElevenLabsEndpoint = "https://api.elevenlabs.io/v1/text-to-speech/"
+
+	// elevenLabsSampleRate is the rate of the PCM audio requested from ElevenLabs
+	elevenLabsSampleRate = 22050
+)
+
+// VoiceCloner is implemented by synthesizers that speak cloned or
+// company-branded voices by their ID
+type VoiceCloner interface {
+	SupportsClonedVoices() bool
+}
+
+// elevenLabsRequest is the body of the ElevenLabs text to speech API
+type elevenLabsRequest struct {
+	Text          string                   `json:"text"`
+	ModelID       string                   `json:"model_id"`
+	VoiceSettings *elevenLabsVoiceSettings `json:"voice_settings,omitempty"`
+}
+
+type elevenLabsVoiceSettings struct {
+	Speed float64 `json:"speed,omitempty"`
+}
+
+// ElevenLabsClient synthesizes speech with the ElevenLabs API. The voice of
+// the options is a voice ID, stock, cloned or designed in the account
+type ElevenLabsClient struct {
+	APIKey     string
+	Model      string // e.g. "eleven_multilingual_v2"
+	HTTPClient *http.Client
+}
+
+// Ensure ElevenLabsClient implements Synthesizer interface
+var _ Synthesizer = (*ElevenLabsClient)(nil)
+
+// Ensure ElevenLabsClient implements VoiceCloner interface
+var _ VoiceCloner = (*ElevenLabsClient)(nil)
+
+// NewElevenLabsClient creates an ElevenLabs client speaking with the model
+func NewElevenLabsClient(apiKey, model string) *ElevenLabsClient {
+	return &ElevenLabsClient{
+		APIKey:     apiKey,
+		Model:      model,
+		HTTPClient: &http.Client{},
+	}
+}
+
+// SupportsClonedVoices reports that cloned voices are spoken by their ID
+func (c *ElevenLabsClient) SupportsClonedVoices() bool {
+	return true
+}
+
+// SynthesizeToStreamWithContext streams the speech as WAV, a header with the
+// format followed by the PCM audio as it arrives. The role is not supported
+func (c *ElevenLabsClient) SynthesizeToStreamWithContext(ctx context.Context, text string, options SynthesisOptions, audioData chan<- []byte) error {
+	defer close(audioData)
+
+	request := elevenLabsRequest{Text: text, ModelID: c.Model}
+	if options.Speed > 0 && options.Speed != 1 {
+		request.VoiceSettings = &elevenLabsVoiceSettings{Speed: options.Speed}
+	}
+	reqBody, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s%s/stream?output_format=pcm_%d", ElevenLabsEndpoint, url.PathEscape(options.Voice), elevenLabsSampleRate)
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(reqBody))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("xi-api-key", c.APIKey)
+
+	resp, err := c.HTTPClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("ElevenLabs API error (status %d) for voice %s: %s", resp.StatusCode, options.Voice, string(body))
+	}
+
+	select {
+	case audioData <- streamingWAVHeader(AudioFormat{SampleRate: elevenLabsSampleRate, Channels: 1, BitsPerSample: 16}):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return forward(ctx, resp.Body, audioData)
+}
+
+// Close does nothing, a request is made per synthesis
+func (c *ElevenLabsClient) Close() error {
+	return nil
+}
+
+// streamingWAVHeader returns a WAV header of PCM audio whose length is not
+// known, the data runs to the end of the stream
+func streamingWAVHeader(format AudioFormat) []byte {
+	blockAlign := format.Channels * format.BitsPerSample / 8
+	header := make([]byte, 44)
+	copy(header[0:4], "RIFF")
+	binary.LittleEndian.PutUint32(header[4:8], 0xFFFFFFFF)
+	copy(header[8:12], "WAVE")
+	copy(header[12:16], "fmt ")
+	binary.LittleEndian.PutUint32(header[16:20], 16)
+	binary.LittleEndian.PutUint16(header[20:22], 1) // PCM
+	binary.LittleEndian.PutUint16(header[22:24], uint16(format.Channels))
+	binary.LittleEndian.PutUint32(header[24:28], uint32(format.SampleRate))
+	binary.LittleEndian.PutUint32(header[28:32], uint32(format.SampleRate*blockAlign))
+	binary.LittleEndian.PutUint16(header[32:34], uint16(blockAlign))
+	binary.LittleEndian.PutUint16(header[34:36], uint16(format.BitsPerSample))
+	copy(header[36:40], "data")
+	binary.LittleEndian.PutUint32(header[40:44], 0xFFFFFFFF)
+	return header
+}
@@ -0,0 +1,153 @@
+package tts
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Prosody markup lets the language model shape how a response is spoken:
+// "[pause]" or "[pause 800ms]" inserts a pause and "*word*" emphasizes a word
+// or a short phrase. Providers translate it with MarkupRenderer, others speak
+// the plain text
+const (
+	DefaultPause = 300 * time.Millisecond
+	MaxPause     = 5 * time.Second
+)
+
+// Span is a piece of marked up text: a pause, or text that may be emphasized
+type Span struct {
+	Text     string
+	Emphasis bool
+	Pause    time.Duration
+}
+
+// Markup is a validated marked up text
+type Markup struct {
+	Spans []Span
+}
+
+// MarkupRenderer is implemented by providers that support prosody, it returns
+// the text to synthesize in the provider's own markup
+type MarkupRenderer interface {
+	RenderMarkup(markup Markup) string
+}
+
+// strayMarkup matches the markup characters removed when a text cannot be parsed
+var strayMarkup = regexp.MustCompile(`\[[^\]]*\]?|[*\]]`)
+
+// ParseMarkup validates and parses a marked up text. Unknown directives,
+// unclosed brackets or emphasis, empty or multi-line emphasis and pauses
+// outside (0, MaxPause] are errors
+func ParseMarkup(text string) (Markup, error) {
+	var markup Markup
+	var current strings.Builder
+	flush := func(emphasis bool) {
+		if current.Len() > 0 {
+			markup.Spans = append(markup.Spans, Span{Text: current.String(), Emphasis: emphasis})
+			current.Reset()
+		}
+	}
+
+	emphasis := false
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '*':
+			if emphasis && strings.TrimSpace(current.String()) == "" {
+				return Markup{}, fmt.Errorf("empty emphasis at offset %d", i)
+			}
+			flush(emphasis)
+			emphasis = !emphasis
+
+		case '[':
+			if emphasis {
+				return Markup{}, fmt.Errorf("directive inside emphasis at offset %d", i)
+			}
+			end := strings.IndexByte(text[i:], ']')
+			if end < 0 {
+				return Markup{}, fmt.Errorf("unclosed directive at offset %d", i)
+			}
+			pause, err := parsePause(text[i+1 : i+end])
+			if err != nil {
+				return Markup{}, fmt.Errorf("invalid directive at offset %d: %w", i, err)
+			}
+			flush(false)
+			markup.Spans = append(markup.Spans, Span{Pause: pause})
+			i += end
+
+		case ']':
+			return Markup{}, fmt.Errorf("unexpected ] at offset %d", i)
+
+		case '\n':
+			if emphasis {
+				return Markup{}, fmt.Errorf("emphasis spans lines at offset %d", i)
+			}
+			current.WriteByte(text[i])
+
+		default:
+			current.WriteByte(text[i])
+		}
+	}
+	if emphasis {
+		return Markup{}, fmt.Errorf("unclosed emphasis")
+	}
+	flush(false)
+	return markup, nil
+}
+
+// parsePause parses the inside of a "[pause]" or "[pause 800ms]" directive
+func parsePause(directive string) (time.Duration, error) {
+	fields := strings.Fields(directive)
+	if len(fields) == 0 || fields[0] != "pause" || len(fields) > 2 {
+		return 0, fmt.Errorf("unknown directive %q", directive)
+	}
+	if len(fields) == 1 {
+		return DefaultPause, nil
+	}
+
+	pause, err := time.ParseDuration(fields[1])
+	if err != nil {
+		return 0, err
+	}
+	if pause <= 0 || pause > MaxPause {
+		return 0, fmt.Errorf("pause %s is out of range", pause)
+	}
+	return pause, nil
+}
+
+// Plain returns the text without markup
+func (m Markup) Plain() string {
+	var text strings.Builder
+	for _, span := range m.Spans {
+		if span.Pause > 0 {
+			text.WriteByte(' ')
+			continue
+		}
+		text.WriteString(span.Text)
+	}
+	return strings.Join(strings.Fields(text.String()), " ")
+}
+
+// StripMarkup removes anything that looks like markup, for texts that fail validation
+func StripMarkup(text string) string {
+	return strings.Join(strings.Fields(strayMarkup.ReplaceAllString(text, " ")), " ")
+}
+
+// PrepareSpeech parses a marked up response and returns the plain text for
+// logs and history, and the text to synthesize in the markup of the
+// synthesizer. Invalid markup is reported in err and both texts are the
+// stripped plain text, so synthesis never fails because of it
+func PrepareSpeech(synthesizer Synthesizer, text string) (plain, spoken string, err error) {
+	markup, err := ParseMarkup(text)
+	if err != nil {
+		plain = StripMarkup(text)
+		return plain, plain, err
+	}
+
+	plain = markup.Plain()
+	if renderer, ok := synthesizer.(MarkupRenderer); ok {
+		return plain, renderer.RenderMarkup(markup), nil
+	}
+	return plain, plain, nil
+}
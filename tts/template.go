@@ -0,0 +1,59 @@
+package tts
+
+import (
+	"context"
+	"regexp"
+	"sort"
+)
+
+// slotPattern matches the variable slots of a template, e.g. {candidate}
+var slotPattern = regexp.MustCompile(`\{(\w+)\}`)
+
+// Template is a fixed phrase with variable slots written as {name}, e.g.
+// "Nice to meet you, {candidate}". Providers with pattern-based synthesis
+// reuse the fixed part of recurring phrases, others synthesize the rendered text.
+// A template without variables is plain text, braces in it are kept
+type Template struct {
+	Text      string
+	Variables map[string]string
+}
+
+// TemplateSynthesizer is implemented by providers that synthesize templates natively
+type TemplateSynthesizer interface {
+	// SynthesizeTemplateToStream sends the synthesized audio of the template to
+	// audioData. The channel is closed when synthesis finishes or fails
+	SynthesizeTemplateToStream(ctx context.Context, template Template, options SynthesisOptions, audioData chan<- []byte) error
+}
+
+// Render returns the text with every slot replaced by its value, slots without a value are removed
+func (t Template) Render() string {
+	if len(t.Variables) == 0 {
+		return t.Text
+	}
+	return slotPattern.ReplaceAllStringFunc(t.Text, func(slot string) string {
+		return t.Variables[slot[1:len(slot)-1]]
+	})
+}
+
+// Slots returns the names of the slots used in the text, sorted and without duplicates
+func (t Template) Slots() []string {
+	seen := make(map[string]bool)
+	var slots []string
+	for _, match := range slotPattern.FindAllStringSubmatch(t.Text, -1) {
+		if !seen[match[1]] {
+			seen[match[1]] = true
+			slots = append(slots, match[1])
+		}
+	}
+	sort.Strings(slots)
+	return slots
+}
+
+// SynthesizeTemplate synthesizes the template natively when the synthesizer
+// supports it and the text has variables, otherwise the rendered text is synthesized
+func SynthesizeTemplate(ctx context.Context, synthesizer Synthesizer, template Template, options SynthesisOptions, audioData chan<- []byte) error {
+	if templater, ok := synthesizer.(TemplateSynthesizer); ok && len(template.Variables) > 0 && len(template.Slots()) > 0 {
+		return templater.SynthesizeTemplateToStream(ctx, template, options, audioData)
+	}
+	return synthesizer.SynthesizeToStreamWithContext(ctx, template.Render(), options, audioData)
+}
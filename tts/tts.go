@@ -2,8 +2,17 @@ package tts
 
 import "context"
 
+// Warmer is implemented by synthesizers that can connect and wake the voice
+// model before the first response is spoken
+type Warmer interface {
+	// Warm connects to the service, it may synthesize a short phrase
+	Warm(ctx context.Context) error
+}
+
 // Synthesizer defines the interface for text-to-speech synthesis
 type Synthesizer interface {
+	// SynthesizeToStreamWithContext sends synthesized audio chunks to audioData.
+	// The channel is closed when synthesis finishes or fails
 	SynthesizeToStreamWithContext(ctx context.Context, text string, options SynthesisOptions, audioData chan<- []byte) error
 	Close() error
 }
@@ -11,6 +20,7 @@ type Synthesizer interface {
 // SynthesisOptions represents the configuration for speech synthesis
 type SynthesisOptions struct {
 	Voice                 string
+	Role                  string // Emotion of the voice, e.g. neutral, good or strict, empty for the voice default
 	Speed                 float64
 	Volume                float64
 	Model                 string
@@ -0,0 +1,120 @@
+package tts
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// AudioFormat describes the PCM audio produced by a synthesizer
+type AudioFormat struct {
+	SampleRate    int
+	Channels      int
+	BitsPerSample int
+}
+
+var errIncompleteHeader = errors.New("incomplete WAV header")
+
+// ParseWAVHeader parses a RIFF/WAVE header and returns the audio format
+// together with the offset where PCM data starts
+func ParseWAVHeader(data []byte) (AudioFormat, int, error) {
+	if len(data) < 12 {
+		return AudioFormat{}, 0, errIncompleteHeader
+	}
+	if !bytes.Equal(data[0:4], []byte("RIFF")) || !bytes.Equal(data[8:12], []byte("WAVE")) {
+		return AudioFormat{}, 0, fmt.Errorf("not a WAV stream")
+	}
+
+	var format AudioFormat
+	offset := 12
+	for {
+		if len(data) < offset+8 {
+			return AudioFormat{}, 0, errIncompleteHeader
+		}
+		chunkID := string(data[offset : offset+4])
+		chunkSize := int(binary.LittleEndian.Uint32(data[offset+4 : offset+8]))
+		offset += 8
+
+		switch chunkID {
+		case "fmt ":
+			if len(data) < offset+16 {
+				return AudioFormat{}, 0, errIncompleteHeader
+			}
+			format.Channels = int(binary.LittleEndian.Uint16(data[offset+2 : offset+4]))
+			format.SampleRate = int(binary.LittleEndian.Uint32(data[offset+4 : offset+8]))
+			format.BitsPerSample = int(binary.LittleEndian.Uint16(data[offset+14 : offset+16]))
+		case "data":
+			// Streaming WAV may carry a placeholder size, the data runs to the end of the stream
+			if format.SampleRate == 0 {
+				return AudioFormat{}, 0, fmt.Errorf("WAV data chunk before fmt chunk")
+			}
+			return format, offset, nil
+		}
+
+		// Chunks are padded to an even size
+		offset += chunkSize + chunkSize%2
+	}
+}
+
+// DecodeWAVStream reads a WAV stream from in, returns its format as soon as the
+// header is parsed and forwards the remaining PCM data to out in the background.
+// Streams without a RIFF header are forwarded unchanged and reported with ok set to false.
+// The out channel is closed when the input is exhausted
+func DecodeWAVStream(ctx context.Context, in <-chan []byte, out chan<- []byte) (format AudioFormat, ok bool, err error) {
+	var header []byte
+	forward := func(first []byte) {
+		go func() {
+			defer close(out)
+			if len(first) > 0 {
+				select {
+				case out <- first:
+				case <-ctx.Done():
+					return
+				}
+			}
+			for chunk := range in {
+				select {
+				case out <- chunk:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			close(out)
+			return AudioFormat{}, false, ctx.Err()
+		case chunk, open := <-in:
+			if !open {
+				close(out)
+				if len(header) == 0 {
+					return AudioFormat{}, false, nil
+				}
+				return AudioFormat{}, false, errIncompleteHeader
+			}
+			header = append(header, chunk...)
+
+			if len(header) >= 4 && !bytes.Equal(header[:4], []byte("RIFF")) {
+				forward(header)
+				return AudioFormat{}, false, nil
+			}
+
+			format, offset, err := ParseWAVHeader(header)
+			if errors.Is(err, errIncompleteHeader) {
+				continue
+			}
+			if err != nil {
+				forward(nil)
+				return AudioFormat{}, false, err
+			}
+
+			forward(header[offset:])
+			return format, true, nil
+		}
+	}
+}
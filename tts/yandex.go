@@ -5,6 +5,10 @@ import (
 	"crypto/tls"
 	"fmt"
 	"io"
+	"strings"
+	"sync"
+
+	"github.com/d1nch8g/aihr/correlation"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
@@ -27,11 +31,25 @@ type YandexTTSClient struct {
 	conn     *grpc.ClientConn
 	apiKey   string
 	folderID string
+
+	tokenMutex sync.RWMutex
 }
 
 // Ensure YandexTTSClient implements Synthesizer interface
 var _ Synthesizer = (*YandexTTSClient)(nil)
 
+// Ensure YandexTTSClient implements TemplateSynthesizer interface
+var _ TemplateSynthesizer = (*YandexTTSClient)(nil)
+
+// Ensure YandexTTSClient implements MarkupRenderer interface
+var _ MarkupRenderer = (*YandexTTSClient)(nil)
+
+// Ensure YandexTTSClient implements Warmer interface
+var _ Warmer = (*YandexTTSClient)(nil)
+
+// warmUpText is synthesized by Warm, short enough to cost next to nothing
+const warmUpText = "ok"
+
 func GetDefaultSynthesisOptions() SynthesisOptions {
 	return SynthesisOptions{
 		Voice:                 "marina",
@@ -64,13 +82,75 @@ func NewYandexTTSClient(config YandexConfig) (*YandexTTSClient, error) {
 	}, nil
 }
 
+// Warm synthesizes a short phrase, which opens the gRPC channel and wakes the
+// voice model. The audio is discarded
+func (c *YandexTTSClient) Warm(ctx context.Context) error {
+	audioData := make(chan []byte)
+	go func() {
+		for range audioData {
+		}
+	}()
+	return c.SynthesizeToStreamWithContext(ctx, warmUpText, GetDefaultSynthesisOptions(), audioData)
+}
+
 func (c *YandexTTSClient) SynthesizeToStreamWithContext(ctx context.Context, text string, options SynthesisOptions, audioData chan<- []byte) error {
+	req := c.buildRequest(options)
+	req.SetText(text)
+	return c.synthesize(ctx, req, audioData)
+}
+
+// SynthesizeTemplateToStream synthesizes the template with SpeechKit pattern-based
+// synthesis, every slot is sent as a variable of the text template
+func (c *YandexTTSClient) SynthesizeTemplateToStream(ctx context.Context, template Template, options SynthesisOptions, audioData chan<- []byte) error {
+	var variables []*tts.TextVariable
+	for _, slot := range template.Slots() {
+		variable := &tts.TextVariable{}
+		variable.SetVariableName("{" + slot + "}")
+		variable.SetVariableValue(template.Variables[slot])
+		variables = append(variables, variable)
+	}
+
+	textTemplate := &tts.TextTemplate{}
+	textTemplate.SetTextTemplate(template.Text)
+	textTemplate.SetVariables(variables)
+
+	req := c.buildRequest(options)
+	req.SetTextTemplate(textTemplate)
+	return c.synthesize(ctx, req, audioData)
+}
+
+// RenderMarkup translates prosody markup to SpeechKit TTS markup, pauses
+// become sil<[ms]> and emphasized text is wrapped in **
+func (c *YandexTTSClient) RenderMarkup(markup Markup) string {
+	var text strings.Builder
+	for _, span := range markup.Spans {
+		switch {
+		case span.Pause > 0:
+			fmt.Fprintf(&text, " sil<[%d]> ", span.Pause.Milliseconds())
+		case span.Emphasis:
+			text.WriteString("**" + strings.TrimSpace(span.Text) + "**")
+		default:
+			text.WriteString(span.Text)
+		}
+	}
+	return strings.Join(strings.Fields(text.String()), " ")
+}
+
+// synthesize runs the request and streams the audio chunks to audioData
+func (c *YandexTTSClient) synthesize(ctx context.Context, req *tts.UtteranceSynthesisRequest, audioData chan<- []byte) error {
+	// The channel is always closed so consumers never wait on a failed synthesis
+	defer close(audioData)
+
+	c.tokenMutex.RLock()
+	apiKey := c.apiKey
+	c.tokenMutex.RUnlock()
+
 	// Create context with API key and folder ID
-	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Api-Key "+c.apiKey)
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Api-Key "+apiKey)
 	ctx = metadata.AppendToOutgoingContext(ctx, "x-folder-id", c.folderID)
-
-	// Prepare synthesis request
-	req := c.buildRequest(text, options)
+	if id := correlation.Session(ctx); id != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, correlation.RequestIDHeader, id)
+	}
 
 	// Call synthesis
 	stream, err := c.client.UtteranceSynthesis(ctx, req)
@@ -79,7 +159,6 @@ func (c *YandexTTSClient) SynthesizeToStreamWithContext(ctx context.Context, tex
 	}
 
 	// Read audio data from stream and send to channel
-	defer close(audioData)
 	for {
 		resp, err := stream.Recv()
 		if err == io.EOF {
@@ -102,15 +181,13 @@ func (c *YandexTTSClient) SynthesizeToStreamWithContext(ctx context.Context, tex
 	return nil
 }
 
-func (c *YandexTTSClient) buildRequest(text string, options SynthesisOptions) *tts.UtteranceSynthesisRequest {
+// buildRequest prepares a synthesis request without the utterance
+func (c *YandexTTSClient) buildRequest(options SynthesisOptions) *tts.UtteranceSynthesisRequest {
 	req := &tts.UtteranceSynthesisRequest{}
 
 	// Set model
 	req.SetModel(options.Model)
 
-	// Set text to synthesize
-	req.SetText(text)
-
 	// Set voice hints
 	voiceHint := &tts.Hints{}
 	voiceHint.SetVoice(options.Voice)
@@ -123,8 +200,17 @@ func (c *YandexTTSClient) buildRequest(text string, options SynthesisOptions) *t
 	volumeHint := &tts.Hints{}
 	volumeHint.SetVolume(options.Volume)
 
+	hints := []*tts.Hints{voiceHint, speedHint, volumeHint}
+
+	// Set role hint, the available roles depend on the voice
+	if options.Role != "" {
+		roleHint := &tts.Hints{}
+		roleHint.SetRole(options.Role)
+		hints = append(hints, roleHint)
+	}
+
 	// Add hints to request
-	req.SetHints([]*tts.Hints{voiceHint, speedHint, volumeHint})
+	req.SetHints(hints)
 
 	// Set output audio format
 	audioSpec := &tts.AudioFormatOptions{}
@@ -150,6 +236,13 @@ func (c *YandexTTSClient) buildRequest(text string, options SynthesisOptions) *t
 	return req
 }
 
+// SetIamToken replaces the credentials used for subsequent synthesis requests
+func (c *YandexTTSClient) SetIamToken(iamToken string) {
+	c.tokenMutex.Lock()
+	defer c.tokenMutex.Unlock()
+	c.apiKey = iamToken
+}
+
 func (c *YandexTTSClient) Close() error {
 	return c.conn.Close()
 }
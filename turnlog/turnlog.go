@@ -0,0 +1,100 @@
+// Package turnlog writes one structured record per interview turn, so
+// analytics pipelines can load timings, token usage and errors without
+// parsing free-form logs
+package turnlog
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/d1nch8g/aihr/gpt"
+)
+
+// Kinds of turns
+const (
+	KindAnswer   = "answer"   // The answer was sent to the model
+	KindCommand  = "command"  // The candidate said a voice command
+	KindUnclear  = "unclear"  // The answer was recognized with low confidence and had to be repeated
+	KindDeferred = "deferred" // The candidate asked a question left to the recruiter
+)
+
+// Stages a turn goes through, a failed turn reports the stage it failed in
+const (
+	StageListen   = "listen"
+	StageGenerate = "generate"
+	StageSpeak    = "speak"
+	StageDone     = "done"
+)
+
+// Turn is the record of one question and answer exchange
+type Turn struct {
+	Time       time.Time  `json:"time"`
+	Session    string     `json:"session"`
+	Turn       int        `json:"turn"`
+	Kind       string     `json:"kind"`
+	Stage      string     `json:"stage"`
+	Question   string     `json:"question,omitempty"`
+	Answer     string     `json:"answer,omitempty"`
+	Response   string     `json:"response,omitempty"`
+	Confidence float64    `json:"confidence,omitempty"`
+	Tokens     *gpt.Usage `json:"tokens,omitempty"`
+	Error      string     `json:"error,omitempty"`
+
+	// Durations of the stages in milliseconds. Listening covers capture and
+	// recognition until the end of the answer, speaking covers synthesis and playback
+	ListenMs   int64 `json:"listen_ms"`
+	GenerateMs int64 `json:"generate_ms"`
+	SpeakMs    int64 `json:"speak_ms"`
+
+	// TailMs is the measured delay of recognition after the end of speech,
+	// set with adaptive silence
+	TailMs int64 `json:"tail_ms,omitempty"`
+}
+
+// Logger defines the interface for turn record destinations
+type Logger interface {
+	// Log writes a turn record
+	Log(turn Turn) error
+}
+
+// FileLogger writes turn records as JSON lines
+type FileLogger struct {
+	writer io.Writer
+	mutex  sync.Mutex
+}
+
+// Ensure FileLogger implements Logger interface
+var _ Logger = (*FileLogger)(nil)
+
+// NewFileLogger appends turn records to the file at path, creating it if
+// needed. The path "-" writes them to stdout
+func NewFileLogger(path string) (*FileLogger, error) {
+	if path == "-" {
+		return &FileLogger{writer: os.Stdout}, nil
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open turn log: %w", err)
+	}
+	return &FileLogger{writer: file}, nil
+}
+
+// Log writes a turn record as one line
+func (l *FileLogger) Log(turn Turn) error {
+	data, err := json.Marshal(turn)
+	if err != nil {
+		return fmt.Errorf("failed to encode turn: %w", err)
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if _, err := l.writer.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write turn: %w", err)
+	}
+	return nil
+}
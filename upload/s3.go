@@ -0,0 +1,143 @@
+package upload
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/d1nch8g/aihr/sigv4"
+)
+
+const (
+	YandexStorageEndpoint = "https://storage.yandexcloud.net"
+	YandexStorageRegion   = "ru-central1"
+)
+
+// S3Config holds the settings of an S3-compatible bucket
+type S3Config struct {
+	Endpoint        string // e.g. https://storage.yandexcloud.net or https://s3.eu-west-1.amazonaws.com
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+
+	// Encryption requests server-side encryption, "AES256" or "aws:kms".
+	// KMSKeyID selects the key for "aws:kms", the bucket default is used without it
+	Encryption string
+	KMSKeyID   string
+
+	// Retries is the number of extra attempts after a failed upload
+	Retries int
+}
+
+// S3Uploader puts objects into a bucket using path-style URLs and SigV4 signing
+type S3Uploader struct {
+	config     S3Config
+	httpClient *http.Client
+}
+
+// Ensure S3Uploader implements Uploader interface
+var _ Uploader = (*S3Uploader)(nil)
+
+// NewS3Uploader creates an uploader for the bucket
+func NewS3Uploader(config S3Config) (*S3Uploader, error) {
+	if config.Bucket == "" {
+		return nil, fmt.Errorf("bucket is required")
+	}
+	if config.AccessKeyID == "" || config.SecretAccessKey == "" {
+		return nil, fmt.Errorf("access key ID and secret access key are required")
+	}
+	if config.Endpoint == "" {
+		config.Endpoint = YandexStorageEndpoint
+	}
+	if config.Region == "" {
+		config.Region = YandexStorageRegion
+	}
+	switch config.Encryption {
+	case "", "AES256", "aws:kms":
+	default:
+		return nil, fmt.Errorf("unsupported server-side encryption %q", config.Encryption)
+	}
+
+	return &S3Uploader{
+		config:     config,
+		httpClient: &http.Client{Timeout: time.Minute},
+	}, nil
+}
+
+// Upload puts the object, retrying with backoff on network and server errors
+func (u *S3Uploader) Upload(ctx context.Context, key string, data []byte, contentType string) (string, error) {
+	objectURL := strings.TrimSuffix(u.config.Endpoint, "/") + "/" + url.PathEscape(u.config.Bucket) + "/" + escapeKey(key)
+
+	backoff := time.Second
+	for attempt := 0; ; attempt++ {
+		retry, err := u.put(ctx, objectURL, data, contentType)
+		if err == nil {
+			return objectURL, nil
+		}
+		if !retry || attempt >= u.config.Retries {
+			return "", err
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+		backoff *= 2
+	}
+}
+
+// put sends one PUT request and reports whether a failure is worth retrying
+func (u *S3Uploader) put(ctx context.Context, objectURL string, data []byte, contentType string) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, objectURL, bytes.NewReader(data))
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	if u.config.Encryption != "" {
+		req.Header.Set("X-Amz-Server-Side-Encryption", u.config.Encryption)
+		if u.config.KMSKeyID != "" {
+			req.Header.Set("X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id", u.config.KMSKeyID)
+		}
+	}
+
+	sigv4.Sign(req, sigv4.HashHex(data), sigv4.Credentials{
+		AccessKeyID:     u.config.AccessKeyID,
+		SecretAccessKey: u.config.SecretAccessKey,
+	}, u.config.Region, "s3", time.Now())
+
+	resp, err := u.httpClient.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		retry := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+		return retry, fmt.Errorf("upload failed with status %d: %s", resp.StatusCode, string(body))
+	}
+	return false, nil
+}
+
+// escapeKey percent-encodes an object key as SigV4 expects, keeping only
+// unreserved characters and the slashes between segments
+func escapeKey(key string) string {
+	var escaped strings.Builder
+	for _, b := range []byte(key) {
+		switch {
+		case 'A' <= b && b <= 'Z', 'a' <= b && b <= 'z', '0' <= b && b <= '9',
+			b == '-', b == '_', b == '.', b == '~', b == '/':
+			escaped.WriteByte(b)
+		default:
+			fmt.Fprintf(&escaped, "%%%02X", b)
+		}
+	}
+	return escaped.String()
+}
@@ -0,0 +1,55 @@
+// Package upload copies session artifacts to S3-compatible object storage
+package upload
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+
+	"github.com/d1nch8g/aihr/session"
+)
+
+// Uploader stores objects and returns their URLs
+type Uploader interface {
+	Upload(ctx context.Context, key string, data []byte, contentType string) (string, error)
+}
+
+// Artifact is a file uploaded for a session
+type Artifact struct {
+	Name string
+	URL  string
+}
+
+// UploadSession uploads the record, report and transcript of a session under
+// prefix/<session ID>/ and returns the uploaded artifacts
+func UploadSession(ctx context.Context, uploader Uploader, prefix string, record session.Record) ([]Artifact, error) {
+	recordJSON, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode session: %w", err)
+	}
+	var report, transcript bytes.Buffer
+	session.WriteReport(&report, record)
+	session.WriteTranscript(&transcript, record)
+
+	files := []struct {
+		name        string
+		data        []byte
+		contentType string
+	}{
+		{"session.json", recordJSON, "application/json"},
+		{"report.txt", report.Bytes(), "text/plain; charset=utf-8"},
+		{"transcript.txt", transcript.Bytes(), "text/plain; charset=utf-8"},
+	}
+
+	var artifacts []Artifact
+	for _, file := range files {
+		url, err := uploader.Upload(ctx, path.Join(prefix, record.ID, file.name), file.data, file.contentType)
+		if err != nil {
+			return artifacts, fmt.Errorf("failed to upload %s: %w", file.name, err)
+		}
+		artifacts = append(artifacts, Artifact{Name: file.name, URL: url})
+	}
+	return artifacts, nil
+}